@@ -0,0 +1,286 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiKeyHeader carries a partner's API key
+const apiKeyHeader = "X-API-Key"
+
+// APIKey is one entry of the API key file. Either Key or KeySHA256 (hex of the key's
+// SHA-256) is set; storing only the hash keeps raw keys out of the Secret. Keys act as
+// viewers unless Role (or the Admin shorthand) says otherwise.
+type APIKey struct {
+	Name       string  `json:"name"`
+	Key        string  `json:"key,omitempty"`
+	KeySHA256  string  `json:"keySha256,omitempty"`
+	Rate       float64 `json:"rateLimit,omitempty"` // requests/s, default AccessConfig.KeyRate
+	Burst      int     `json:"burst,omitempty"`     // default AccessConfig.KeyBurst
+	DailyQuota int64   `json:"dailyQuota,omitempty"`
+	Role       string  `json:"role,omitempty"`  // viewer, operator or admin
+	Admin      bool    `json:"admin,omitempty"` // same as role admin
+
+	role Role
+}
+
+// apiKeyFile is the JSON layout of API_KEYS_FILE
+type apiKeyFile struct {
+	Keys []APIKey `json:"keys"`
+}
+
+// AccessConfig configures API key authentication and rate limiting
+type AccessConfig struct {
+	KeysFile         string  // JSON key file; empty means no keys
+	AllowAnonymous   bool    // requests without a key are limited per IP instead of rejected
+	IPRate           float64 // anonymous requests/s per client IP
+	IPBurst          int
+	KeyRate          float64 // default requests/s per API key
+	KeyBurst         int
+	TrustedProxyHops int // X-Forwarded-For entries appended by proxies we trust
+}
+
+var keyNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// loadAPIKeys reads the key file and indexes keys by the hex SHA-256 of the raw key.
+// A missing file means no keys, since the Secret behind it is optional.
+func loadAPIKeys(path string) (map[string]*APIKey, error) {
+	keys := make(map[string]*APIKey)
+	if path == "" {
+		return keys, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("API key file not found, no keys configured", "path", path)
+		return keys, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read API key file: %w", err)
+	}
+	var file apiKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse API key file: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for i := range file.Keys {
+		k := &file.Keys[i]
+		if !keyNamePattern.MatchString(k.Name) {
+			return nil, fmt.Errorf("API key %d: invalid name %q", i, k.Name)
+		}
+		if names[k.Name] {
+			return nil, fmt.Errorf("API key %q: duplicate name", k.Name)
+		}
+		names[k.Name] = true
+
+		hash := strings.ToLower(k.KeySHA256)
+		if k.Key != "" {
+			hash = hashAPIKey(k.Key)
+			k.Key = "" // only the hash is kept in memory
+		}
+		if len(hash) != sha256.Size*2 {
+			return nil, fmt.Errorf("API key %q: key or keySha256 required", k.Name)
+		}
+
+		k.role = RoleViewer
+		if k.Role != "" {
+			role, err := parseRole(k.Role)
+			if err != nil {
+				return nil, fmt.Errorf("API key %q: %w", k.Name, err)
+			}
+			k.role = role
+		}
+		if k.Admin {
+			k.role = RoleAdmin
+		}
+		keys[hash] = k
+	}
+	return keys, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// AccessControl authenticates API keys and applies per-key and per-IP rate limits
+type AccessControl struct {
+	config  AccessConfig
+	keys    map[string]*APIKey // by SHA-256 of the raw key
+	limiter *Limiter
+}
+
+func NewAccessControl(config AccessConfig, limiter *Limiter) (*AccessControl, error) {
+	keys, err := loadAPIKeys(config.KeysFile)
+	if err != nil {
+		return nil, err
+	}
+	if !config.AllowAnonymous && len(keys) == 0 {
+		return nil, errors.New("anonymous access disabled but no API keys configured")
+	}
+	return &AccessControl{config: config, keys: keys, limiter: limiter}, nil
+}
+
+// clientIP returns the client address: the X-Forwarded-For entry added by the outermost
+// trusted proxy, or the peer address when no proxy is trusted
+func clientIP(r *http.Request, trustedHops int) string {
+	if trustedHops > 0 {
+		var hops []string
+		for _, v := range r.Header.Values("X-Forwarded-For") {
+			for _, h := range strings.Split(v, ",") {
+				if h = strings.TrimSpace(h); h != "" {
+					hops = append(hops, h)
+				}
+			}
+		}
+		if i := len(hops) - trustedHops; i >= 0 && i < len(hops) {
+			return hops[i]
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (a *AccessControl) reject(w http.ResponseWriter, status int, message string) {
+	writeJSONError(w, status, message)
+}
+
+// Guard authenticates the API key and applies the caller's rate limit before calling
+// next: per key, per JWT subject (see Authenticator.Authenticate) or per client IP.
+// Preflight requests pass through since browsers send them without credentials.
+func (a *AccessControl) Guard(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+
+		var (
+			key     *APIKey
+			subject string
+			limit   Limit
+			client  = "anonymous"
+		)
+		if raw := r.Header.Get(apiKeyHeader); raw != "" {
+			key = a.keys[hashAPIKey(raw)]
+			if key == nil {
+				rateLimitRequests.WithLabelValues("key", "invalid_key").Inc()
+				a.reject(w, http.StatusUnauthorized, "invalid API key")
+				return
+			}
+			client = "key"
+			subject = "key:" + key.Name
+			limit = Limit{Rate: key.Rate, Burst: key.Burst, DailyQuota: key.DailyQuota}
+			if limit.Rate <= 0 {
+				limit.Rate = a.config.KeyRate
+			}
+			if limit.Burst <= 0 {
+				limit.Burst = a.config.KeyBurst
+			}
+		} else if id := identityFrom(r.Context()); id != nil {
+			client = "jwt"
+			subject = "user:" + id.Subject
+			limit = Limit{Rate: a.config.KeyRate, Burst: a.config.KeyBurst}
+		} else {
+			if !a.config.AllowAnonymous {
+				rateLimitRequests.WithLabelValues("anonymous", "missing_key").Inc()
+				a.reject(w, http.StatusUnauthorized, "API key required")
+				return
+			}
+			subject = "ip:" + clientIP(r, a.config.TrustedProxyHops)
+			limit = Limit{Rate: a.config.IPRate, Burst: a.config.IPBurst}
+		}
+
+		decision := a.limiter.Allow(r.Context(), subject, limit, key != nil)
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(decision.Remaining, 0)))
+		if !decision.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(decision.RetryAfter.Seconds()))))
+			if decision.OverQuota {
+				rateLimitRequests.WithLabelValues(client, "over_quota").Inc()
+				a.reject(w, http.StatusTooManyRequests, "daily quota exceeded")
+			} else {
+				rateLimitRequests.WithLabelValues(client, "throttled").Inc()
+				a.reject(w, http.StatusTooManyRequests, "rate limit exceeded")
+			}
+			return
+		}
+		rateLimitRequests.WithLabelValues(client, "allowed").Inc()
+
+		if key != nil {
+			r = r.WithContext(withIdentity(r.Context(), &Identity{
+				Subject: "apikey:" + key.Name,
+				Role:    key.role,
+				Method:  "api_key",
+			}))
+		}
+		next(w, r)
+	}
+}
+
+// KeyUsageReport is one key's entry in the /admin/usage response
+type KeyUsageReport struct {
+	Name       string  `json:"name"`
+	RateLimit  float64 `json:"rateLimit"`
+	Burst      int     `json:"burst"`
+	DailyQuota int64   `json:"dailyQuota,omitempty"`
+	Usage
+}
+
+// usageHandler serves GET /admin/usage[?day=YYYYMMDD]: per-key request, throttle and
+// quota counters for a KST day (today by default)
+func (a *AccessControl) usageHandler(w http.ResponseWriter, r *http.Request) {
+	day := r.URL.Query().Get("day")
+	if day == "" {
+		day = usageDay(time.Now())
+	} else if _, err := time.Parse("20060102", day); err != nil {
+		a.reject(w, http.StatusBadRequest, "day must be YYYYMMDD")
+		return
+	}
+
+	reports := make([]KeyUsageReport, 0, len(a.keys))
+	for _, k := range a.keys {
+		report := KeyUsageReport{
+			Name:       k.Name,
+			RateLimit:  k.Rate,
+			Burst:      k.Burst,
+			DailyQuota: k.DailyQuota,
+			Usage:      a.limiter.Usage(r.Context(), "key:"+k.Name, day),
+		}
+		if report.RateLimit <= 0 {
+			report.RateLimit = a.config.KeyRate
+		}
+		if report.Burst <= 0 {
+			report.Burst = a.config.KeyBurst
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Name < reports[j].Name })
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"day":  day,
+		"keys": reports,
+	}); err != nil {
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
+	}
+}
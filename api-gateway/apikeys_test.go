@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeKeyFile writes an API key file into the test's temp dir
+func writeKeyFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	partnerHash := hashAPIKey("partner-secret")
+
+	tests := []struct {
+		name     string
+		content  string
+		wantRole map[string]Role // by key hash
+		wantErr  string
+	}{
+		{
+			name: "raw and hashed keys",
+			content: `{"keys":[
+				{"name":"partner","key":"partner-secret","rateLimit":5},
+				{"name":"ops","keySha256":"` + strings.ToUpper(hashAPIKey("ops-secret")) + `","role":"operator"},
+				{"name":"root","key":"root-secret","admin":true}
+			]}`,
+			wantRole: map[string]Role{
+				partnerHash:               RoleViewer,
+				hashAPIKey("ops-secret"):  RoleOperator,
+				hashAPIKey("root-secret"): RoleAdmin,
+			},
+		},
+		{name: "invalid JSON", content: `{"keys":`, wantErr: "parse"},
+		{name: "invalid name", content: `{"keys":[{"name":"has space","key":"k"}]}`, wantErr: "invalid name"},
+		{name: "duplicate name", content: `{"keys":[{"name":"a","key":"k1"},{"name":"a","key":"k2"}]}`, wantErr: "duplicate"},
+		{name: "no key", content: `{"keys":[{"name":"a"}]}`, wantErr: "required"},
+		{name: "short hash", content: `{"keys":[{"name":"a","keySha256":"abcd"}]}`, wantErr: "required"},
+		{name: "unknown role", content: `{"keys":[{"name":"a","key":"k","role":"root"}]}`, wantErr: "role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := loadAPIKeys(writeKeyFile(t, tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(keys) != len(tt.wantRole) {
+				t.Fatalf("loaded %d keys, want %d", len(keys), len(tt.wantRole))
+			}
+			for hash, role := range tt.wantRole {
+				k := keys[hash]
+				if k == nil || k.role != role || k.Key != "" {
+					t.Errorf("key %s = %+v, want role %v and no raw key kept", hash, k, role)
+				}
+			}
+		})
+	}
+
+	if keys, err := loadAPIKeys(filepath.Join(t.TempDir(), "missing.json")); err != nil || len(keys) != 0 {
+		t.Errorf("missing file = %v, %v; want no keys", keys, err)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name        string
+		remote      string
+		forwarded   []string
+		trustedHops int
+		want        string
+	}{
+		{name: "peer address", remote: "10.0.0.5:4321", forwarded: []string{"1.2.3.4"}, want: "10.0.0.5"},
+		{name: "one trusted proxy", remote: "10.0.0.5:4321", forwarded: []string{"9.9.9.9, 1.2.3.4"}, trustedHops: 1, want: "1.2.3.4"},
+		{name: "two trusted proxies over two headers", remote: "10.0.0.5:4321", forwarded: []string{"9.9.9.9, 1.2.3.4", "10.0.0.1"}, trustedHops: 2, want: "1.2.3.4"},
+		{name: "fewer hops than trusted", remote: "10.0.0.5:4321", forwarded: []string{"1.2.3.4"}, trustedHops: 2, want: "10.0.0.5"},
+		{name: "no header", remote: "10.0.0.5:4321", trustedHops: 1, want: "10.0.0.5"},
+		{name: "remote without port", remote: "10.0.0.5", want: "10.0.0.5"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/accidents", nil)
+		r.RemoteAddr = tt.remote
+		for _, f := range tt.forwarded {
+			r.Header.Add("X-Forwarded-For", f)
+		}
+		if got := clientIP(r, tt.trustedHops); got != tt.want {
+			t.Errorf("%s: clientIP = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGuard(t *testing.T) {
+	path := writeKeyFile(t, `{"keys":[
+		{"name":"partner","key":"partner-secret","rateLimit":1,"burst":2,"dailyQuota":3,"role":"operator"}
+	]}`)
+
+	type request struct {
+		key     string
+		remote  string
+		jwt     bool
+		method  string
+		want    int
+		subject string // identity seen by next
+	}
+	tests := []struct {
+		name      string
+		anonymous bool
+		requests  []request
+	}{
+		{
+			name: "keys required",
+			requests: []request{
+				{want: http.StatusUnauthorized},
+				{key: "wrong", want: http.StatusUnauthorized},
+				{key: "partner-secret", want: http.StatusOK, subject: "apikey:partner"},
+				{method: http.MethodOptions, want: http.StatusOK},
+				{jwt: true, want: http.StatusOK, subject: "alice"},
+			},
+		},
+		{
+			name: "key burst then throttled",
+			requests: []request{
+				{key: "partner-secret", want: http.StatusOK, subject: "apikey:partner"},
+				{key: "partner-secret", want: http.StatusOK, subject: "apikey:partner"},
+				{key: "partner-secret", want: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name:      "anonymous limited per IP",
+			anonymous: true,
+			requests: []request{
+				{remote: "1.1.1.1:1", want: http.StatusOK},
+				{remote: "1.1.1.1:1", want: http.StatusTooManyRequests},
+				{remote: "2.2.2.2:1", want: http.StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAccessControl(AccessConfig{
+				KeysFile: path, AllowAnonymous: tt.anonymous,
+				IPRate: 0.001, IPBurst: 1, KeyRate: 100, KeyBurst: 100,
+			}, NewLimiter(nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, req := range tt.requests {
+				var subject string
+				h := a.Guard(func(w http.ResponseWriter, r *http.Request) {
+					if id := identityFrom(r.Context()); id != nil {
+						subject = id.Subject
+					}
+				})
+
+				method := req.method
+				if method == "" {
+					method = http.MethodGet
+				}
+				r := httptest.NewRequest(method, "/api/v1/accidents", nil)
+				if req.remote != "" {
+					r.RemoteAddr = req.remote
+				}
+				if req.key != "" {
+					r.Header.Set(apiKeyHeader, req.key)
+				}
+				if req.jwt {
+					r = r.WithContext(withIdentity(r.Context(), &Identity{Subject: "alice", Role: RoleViewer}))
+				}
+				w := httptest.NewRecorder()
+				h(w, r)
+
+				if w.Code != req.want || subject != req.subject {
+					t.Fatalf("request %d: status %d for %q, want %d for %q", i, w.Code, subject, req.want, req.subject)
+				}
+				if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+					t.Errorf("request %d: 429 without Retry-After", i)
+				}
+			}
+		})
+	}
+}
+
+func TestGuardDailyQuota(t *testing.T) {
+	path := writeKeyFile(t, `{"keys":[{"name":"partner","key":"partner-secret","rateLimit":1000,"burst":1000,"dailyQuota":2}]}`)
+	a, err := NewAccessControl(AccessConfig{KeysFile: path}, NewLimiter(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := a.Guard(func(w http.ResponseWriter, r *http.Request) {})
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/accidents", nil)
+		r.Header.Set(apiKeyHeader, "partner-secret")
+		w := httptest.NewRecorder()
+		h(w, r)
+		if w.Code != want {
+			t.Fatalf("request %d: status %d, want %d", i, w.Code, want)
+		}
+		if w.Code == http.StatusTooManyRequests && !strings.Contains(w.Body.String(), "quota") {
+			t.Errorf("body = %s, want the quota named", w.Body)
+		}
+	}
+
+	usage := a.limiter.Usage(context.Background(), "key:partner", usageDay(time.Now()))
+	if usage.Requests != 2 || usage.OverQuota != 1 {
+		t.Errorf("usage = %+v, want 2 requests and 1 over quota", usage)
+	}
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Identity headers passed to data-api-service. They are always stripped from the
+// incoming request first, so only the gateway can set them.
+const (
+	authSubjectHeader = "X-Auth-Subject"
+	authRoleHeader    = "X-Auth-Role"
+	authMethodHeader  = "X-Auth-Method"
+)
+
+// Role is a caller's access level; each role includes the ones below it
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleViewer
+	RoleOperator
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleOperator:
+		return "operator"
+	case RoleAdmin:
+		return "admin"
+	}
+	return "none"
+}
+
+func parseRole(s string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "viewer":
+		return RoleViewer, nil
+	case "operator":
+		return RoleOperator, nil
+	case "admin":
+		return RoleAdmin, nil
+	}
+	return RoleNone, fmt.Errorf("unknown role %q", s)
+}
+
+// Identity is a verified caller, from a JWT or an API key
+type Identity struct {
+	Subject string
+	Role    Role
+	Method  string // "jwt" or "api_key"
+}
+
+type identityCtxKey struct{}
+
+func withIdentity(ctx context.Context, id *Identity) context.Context {
+	ctx = context.WithValue(ctx, identityCtxKey{}, id)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("enduser.id", id.Subject),
+		attribute.String("enduser.role", id.Role.String()),
+	)
+	return context.WithValue(ctx, loggerKey{}, loggerFrom(ctx).With("subject", id.Subject, "role", id.Role.String()))
+}
+
+// identityFrom returns the request's verified identity, or nil when anonymous
+func identityFrom(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityCtxKey{}).(*Identity)
+	return id
+}
+
+// RoutePolicy requires Role for requests whose path starts with Prefix (and whose
+// method is Method, when set)
+type RoutePolicy struct {
+	Method string
+	Prefix string
+	Role   Role
+}
+
+// defaultPolicies protect the gateway's admin endpoints and data-api-service's bulk
+// exports, which stream up to ExportMaxRange of history per request
+const defaultPolicies = "/admin/=admin,/api/v1/export/=operator"
+
+// parsePolicies reads "[METHOD ]prefix=role" entries separated by commas, e.g.
+// "/admin/=admin,POST /api/=operator"
+func parsePolicies(list string) ([]RoutePolicy, error) {
+	var policies []RoutePolicy
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		target, roleStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid policy %q", entry)
+		}
+		role, err := parseRole(roleStr)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", entry, err)
+		}
+		policy := RoutePolicy{Prefix: strings.TrimSpace(target), Role: role}
+		if method, prefix, ok := strings.Cut(policy.Prefix, " "); ok {
+			policy.Method, policy.Prefix = strings.ToUpper(method), strings.TrimSpace(prefix)
+		}
+		if !strings.HasPrefix(policy.Prefix, "/") {
+			return nil, fmt.Errorf("policy %q: path must start with /", entry)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// parseRoleMap reads "claimValue=role" entries separated by commas, e.g.
+// "tf-admins=admin,tf-ops=operator"
+func parseRoleMap(list string) (map[string]Role, error) {
+	roles := make(map[string]Role)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		value, roleStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid role mapping %q", entry)
+		}
+		role, err := parseRole(roleStr)
+		if err != nil {
+			return nil, fmt.Errorf("role mapping %q: %w", entry, err)
+		}
+		roles[strings.TrimSpace(value)] = role
+	}
+	return roles, nil
+}
+
+// AuthConfig configures JWT verification and route policies
+type AuthConfig struct {
+	JWKSURL     string // http(s) URL or file path; empty disables JWT authentication
+	JWKSRefresh time.Duration
+	Issuer      string // required iss, when set
+	Audience    string // required aud, when set
+	RolesClaim  string // dotted path to the roles claim, e.g. "realm_access.roles"
+	RoleMap     string // "claimValue=role" mappings; role names are accepted as-is
+	Policies    string // "[METHOD ]prefix=role" route policies
+}
+
+// Authenticator verifies bearer tokens and enforces route policies
+type Authenticator struct {
+	config   AuthConfig
+	jwks     *JWKS // nil when JWT authentication is disabled
+	roleMap  map[string]Role
+	policies []RoutePolicy
+}
+
+func NewAuthenticator(config AuthConfig) (*Authenticator, error) {
+	roleMap, err := parseRoleMap(config.RoleMap)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := parsePolicies(config.Policies)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Authenticator{config: config, roleMap: roleMap, policies: policies}
+	if config.JWKSURL != "" {
+		a.jwks = NewJWKS(config.JWKSURL)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := a.jwks.Refresh(ctx); err != nil {
+			// Tokens are rejected until a refresh succeeds; the gateway still serves
+			// public routes
+			slog.Warn("initial JWKS load failed", "source", config.JWKSURL, "error", err)
+		}
+	}
+	return a, nil
+}
+
+// Run keeps the JWKS fresh until ctx is cancelled
+func (a *Authenticator) Run(ctx context.Context) {
+	if a.jwks != nil {
+		a.jwks.Run(ctx, a.config.JWKSRefresh)
+	}
+}
+
+// policyFor returns the most specific policy matching r, or nil for public routes
+func (a *Authenticator) policyFor(r *http.Request) *RoutePolicy {
+	var match *RoutePolicy
+	for i := range a.policies {
+		p := &a.policies[i]
+		if p.Method != "" && p.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, p.Prefix) {
+			continue
+		}
+		if match == nil || len(p.Prefix) > len(match.Prefix) ||
+			(len(p.Prefix) == len(match.Prefix) && p.Method != "") {
+			match = p
+		}
+	}
+	return match
+}
+
+// claimValues walks a dotted claim path and returns its string values; a string claim
+// is split on spaces and commas (as with "scope")
+func claimValues(claims jwt.MapClaims, path string) []string {
+	var v interface{} = map[string]interface{}(claims)
+	for _, part := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[part]
+	}
+
+	switch v := v.(type) {
+	case string:
+		return strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' })
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// verify checks the token's signature and registered claims and maps it to an identity.
+// Every verified caller is at least a viewer.
+func (a *Authenticator) verify(ctx context.Context, raw string) (*Identity, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if a.config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.config.Issuer))
+	}
+	if a.config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(a.config.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.jwks.Key(ctx, kid)
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, _ := claims.GetSubject()
+	if sub == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	id := &Identity{Subject: sub, Role: RoleViewer, Method: "jwt"}
+	for _, value := range claimValues(claims, a.config.RolesClaim) {
+		role, ok := a.roleMap[value]
+		if !ok {
+			role, _ = parseRole(value)
+		}
+		if role > id.Role {
+			id.Role = role
+		}
+	}
+	return id, nil
+}
+
+// stripIdentity drops identity headers a client may have forged; only the gateway sets them
+func stripIdentity(r *http.Request) {
+	r.Header.Del(authSubjectHeader)
+	r.Header.Del(authRoleHeader)
+	r.Header.Del(authMethodHeader)
+}
+
+// StripIdentity guards a route proxied without authentication, so upstreams never see
+// identity headers the gateway did not set
+func StripIdentity(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stripIdentity(r)
+		next(w, r)
+	}
+}
+
+// Authenticate verifies a bearer token when one is sent. Requests without a token
+// continue anonymously (API keys are checked later by AccessControl.Guard); a token
+// that fails verification is rejected rather than ignored.
+func (a *Authenticator) Authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stripIdentity(r)
+
+		raw, isBearer := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if a.jwks == nil || !isBearer || r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+
+		id, err := a.verify(r.Context(), strings.TrimSpace(raw))
+		if err != nil {
+			authRequests.WithLabelValues("invalid_token").Inc()
+			loggerFrom(r.Context()).Info("token rejected", "error", err)
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeJSONError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		next(w, r.WithContext(withIdentity(r.Context(), id)))
+	}
+}
+
+// Authorize enforces the route policy and passes the verified identity downstream. It
+// runs after Authenticate and Guard, which establish the identity.
+func (a *Authenticator) Authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+
+		id := identityFrom(r.Context())
+		if policy := a.policyFor(r); policy != nil {
+			switch {
+			case id == nil:
+				authRequests.WithLabelValues("unauthenticated").Inc()
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeJSONError(w, http.StatusUnauthorized, "authentication required")
+				return
+			case id.Role < policy.Role:
+				authRequests.WithLabelValues("forbidden").Inc()
+				writeJSONError(w, http.StatusForbidden, policy.Role.String()+" role required")
+				return
+			}
+			authRequests.WithLabelValues("allowed").Inc()
+		}
+
+		if id != nil {
+			r.Header.Set(authSubjectHeader, id.Subject)
+			r.Header.Set(authRoleHeader, id.Role.String())
+			r.Header.Set(authMethodHeader, id.Method)
+		}
+		// The key itself is the gateway's business only
+		r.Header.Del(apiKeyHeader)
+		next(w, r)
+	}
+}
+
+var authRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_auth_requests_total",
+	Help: "Authentication and route policy decisions by result.",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(authRequests)
+}
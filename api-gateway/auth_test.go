@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Role
+		wantErr bool
+	}{
+		{s: "viewer", want: RoleViewer},
+		{s: " Operator ", want: RoleOperator},
+		{s: "ADMIN", want: RoleAdmin},
+		{s: "root", wantErr: true},
+		{s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseRole(tt.s)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseRole(%q) = %v, %v; want %v, error %v", tt.s, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParsePolicies(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []RoutePolicy
+		wantErr bool
+	}{
+		{list: "", want: nil},
+		{list: "/admin/=admin, post /api/=operator", want: []RoutePolicy{
+			{Prefix: "/admin/", Role: RoleAdmin},
+			{Method: "POST", Prefix: "/api/", Role: RoleOperator},
+		}},
+		{list: "/admin/", wantErr: true},
+		{list: "/admin/=root", wantErr: true},
+		{list: "admin=admin", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parsePolicies(tt.list)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePolicies(%q) = %+v, %v; want %+v, error %v", tt.list, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseRoleMap(t *testing.T) {
+	got, err := parseRoleMap("tf-admins=admin, tf-ops = operator,")
+	if err != nil || !reflect.DeepEqual(got, map[string]Role{"tf-admins": RoleAdmin, "tf-ops": RoleOperator}) {
+		t.Errorf("parseRoleMap = %v, %v", got, err)
+	}
+	for _, list := range []string{"tf-admins", "tf-admins=root"} {
+		if _, err := parseRoleMap(list); err == nil {
+			t.Errorf("parseRoleMap(%q) accepted", list)
+		}
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	policies, err := parsePolicies(defaultPolicies + ",/api/v1/export/tollgate=admin,POST /api/v1/export/=admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &Authenticator{policies: policies}
+
+	tests := []struct {
+		method, path string
+		want         Role // RoleNone for public routes
+	}{
+		{method: "GET", path: "/api/v1/accidents", want: RoleNone},
+		{method: "GET", path: "/admin/usage", want: RoleAdmin},
+		{method: "GET", path: "/api/v1/export/accidents", want: RoleOperator},
+		{method: "POST", path: "/api/v1/export/accidents", want: RoleAdmin}, // method-specific beats same prefix
+		{method: "GET", path: "/api/v1/export/tollgate", want: RoleAdmin},   // longest prefix wins
+		{method: "GET", path: "/api/v1/exports", want: RoleNone},
+		{method: "GET", path: "/adminx", want: RoleNone},
+	}
+	for _, tt := range tests {
+		var got Role
+		if p := a.policyFor(httptest.NewRequest(tt.method, tt.path, nil)); p != nil {
+			got = p.Role
+		}
+		if got != tt.want {
+			t.Errorf("policyFor(%s %s) = %v, want %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestClaimValues(t *testing.T) {
+	claims := jwt.MapClaims{
+		"scope":        "read write,admin",
+		"roles":        []interface{}{"viewer", 7, "operator"},
+		"realm_access": map[string]interface{}{"roles": []interface{}{"tf-admins"}},
+		"nested":       "flat",
+	}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "scope", want: []string{"read", "write", "admin"}},
+		{path: "roles", want: []string{"viewer", "operator"}},
+		{path: "realm_access.roles", want: []string{"tf-admins"}},
+		{path: "nested.roles", want: nil},
+		{path: "missing", want: nil},
+		{path: "", want: nil},
+	}
+	for _, tt := range tests {
+		if got := claimValues(claims, tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("claimValues(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// testIssuer signs tokens with an EC key published through a JWKS file
+type testIssuer struct {
+	key  *ecdsa.PrivateKey
+	jwks string // file path
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding
+	set := map[string]interface{}{"keys": []map[string]string{{
+		"kty": "EC", "kid": "k1", "use": "sig", "crv": "P-256",
+		"x": b64.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y": b64.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return &testIssuer{key: key, jwks: path}
+}
+
+func (i *testIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = "k1"
+	raw, err := token.SignedString(i.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestVerifyClaims(t *testing.T) {
+	issuer := newTestIssuer(t)
+	a, err := NewAuthenticator(AuthConfig{
+		JWKSURL:    issuer.jwks,
+		Issuer:     "https://idp.example",
+		Audience:   "traffic-dashboard",
+		RolesClaim: "realm_access.roles",
+		RoleMap:    "tf-ops=operator",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	valid := func(modify func(c jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub": "alice",
+			"iss": "https://idp.example",
+			"aud": "traffic-dashboard",
+			"exp": now.Add(time.Hour).Unix(),
+		}
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+	hmac, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, valid(nil)).SignedString([]byte("secret"))
+
+	tests := []struct {
+		name     string
+		token    string
+		wantRole Role
+		wantErr  bool
+	}{
+		{name: "valid viewer", token: issuer.sign(t, valid(nil)), wantRole: RoleViewer},
+		{name: "mapped role", token: issuer.sign(t, valid(func(c jwt.MapClaims) {
+			c["realm_access"] = map[string]interface{}{"roles": []interface{}{"tf-ops", "unknown"}}
+		})), wantRole: RoleOperator},
+		{name: "role name as is", token: issuer.sign(t, valid(func(c jwt.MapClaims) {
+			c["realm_access"] = map[string]interface{}{"roles": []interface{}{"tf-ops", "admin"}}
+		})), wantRole: RoleAdmin},
+		{name: "expired", token: issuer.sign(t, valid(func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Minute).Unix() })), wantErr: true},
+		{name: "within leeway", token: issuer.sign(t, valid(func(c jwt.MapClaims) { c["exp"] = now.Add(-10 * time.Second).Unix() })), wantRole: RoleViewer},
+		{name: "no expiry", token: issuer.sign(t, valid(func(c jwt.MapClaims) { delete(c, "exp") })), wantErr: true},
+		{name: "wrong issuer", token: issuer.sign(t, valid(func(c jwt.MapClaims) { c["iss"] = "https://evil.example" })), wantErr: true},
+		{name: "wrong audience", token: issuer.sign(t, valid(func(c jwt.MapClaims) { c["aud"] = "other" })), wantErr: true},
+		{name: "no subject", token: issuer.sign(t, valid(func(c jwt.MapClaims) { delete(c, "sub") })), wantErr: true},
+		{name: "not yet valid", token: issuer.sign(t, valid(func(c jwt.MapClaims) { c["nbf"] = now.Add(time.Hour).Unix() })), wantErr: true},
+		{name: "HMAC rejected", token: hmac, wantErr: true},
+		{name: "garbage", token: "not.a.token", wantErr: true},
+	}
+	for _, tt := range tests {
+		id, err := a.verify(context.Background(), tt.token)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (id.Subject != "alice" || id.Role != tt.wantRole || id.Method != "jwt") {
+			t.Errorf("%s: identity = %+v, want alice as %v", tt.name, id, tt.wantRole)
+		}
+	}
+}
+
+func TestAuthenticateAndAuthorize(t *testing.T) {
+	issuer := newTestIssuer(t)
+	a, err := NewAuthenticator(AuthConfig{JWKSURL: issuer.jwks, RolesClaim: "roles", Policies: defaultPolicies})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := func(roles ...interface{}) string {
+		return issuer.sign(t, jwt.MapClaims{"sub": "alice", "roles": roles, "exp": time.Now().Add(time.Hour).Unix()})
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		auth        string
+		forged      bool // client sends its own identity headers
+		want        int
+		wantSubject string
+		wantRole    string
+	}{
+		{name: "public anonymous", path: "/api/v1/accidents", want: 200},
+		{name: "public with token", path: "/api/v1/accidents", auth: "Bearer " + token(), want: 200, wantSubject: "alice", wantRole: "viewer"},
+		{name: "forged headers stripped", path: "/api/v1/accidents", forged: true, want: 200},
+		{name: "invalid token rejected", path: "/api/v1/accidents", auth: "Bearer nope", want: 401},
+		{name: "admin anonymous", path: "/admin/usage", want: 401},
+		{name: "admin as viewer", path: "/admin/usage", auth: "Bearer " + token("viewer"), want: 403},
+		{name: "export as viewer", path: "/api/v1/export/accidents", auth: "Bearer " + token("viewer"), want: 403},
+		{name: "export as operator", path: "/api/v1/export/accidents", auth: "Bearer " + token("operator"), want: 200, wantSubject: "alice", wantRole: "operator"},
+		{name: "admin as admin", path: "/admin/usage", auth: "Bearer " + token("admin"), want: 200, wantSubject: "alice", wantRole: "admin"},
+		{name: "other schemes ignored", path: "/admin/usage", auth: "Basic YTpi", want: 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subject, role, apiKey string
+			h := a.Authenticate(a.Authorize(func(w http.ResponseWriter, r *http.Request) {
+				subject, role = r.Header.Get(authSubjectHeader), r.Header.Get(authRoleHeader)
+				apiKey = r.Header.Get(apiKeyHeader)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.auth != "" {
+				r.Header.Set("Authorization", tt.auth)
+			}
+			if tt.forged {
+				r.Header.Set(authSubjectHeader, "mallory")
+				r.Header.Set(authRoleHeader, "admin")
+			}
+			r.Header.Set(apiKeyHeader, "raw-key")
+			w := httptest.NewRecorder()
+			h(w, r)
+
+			if w.Code != tt.want || subject != tt.wantSubject || role != tt.wantRole {
+				t.Errorf("status %d as %q/%q, want %d as %q/%q", w.Code, subject, role, tt.want, tt.wantSubject, tt.wantRole)
+			}
+			if w.Code == http.StatusOK && apiKey != "" {
+				t.Error("API key forwarded upstream")
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestStripIdentity(t *testing.T) {
+	var got http.Header
+	h := StripIdentity(func(w http.ResponseWriter, r *http.Request) { got = r.Header.Clone() })
+
+	r := httptest.NewRequest(http.MethodGet, "/health/pipeline", nil)
+	r.Header.Set(authSubjectHeader, "mallory")
+	r.Header.Set(authRoleHeader, "admin")
+	r.Header.Set(authMethodHeader, "jwt")
+	r.Header.Set("Accept", "application/json")
+	h(httptest.NewRecorder(), r)
+
+	for _, name := range []string{authSubjectHeader, authRoleHeader, authMethodHeader} {
+		if v := got.Get(name); v != "" {
+			t.Errorf("%s = %q reached the upstream", name, v)
+		}
+	}
+	if got.Get("Accept") != "application/json" {
+		t.Error("unrelated headers were dropped")
+	}
+}
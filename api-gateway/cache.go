@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// cacheKeyPrefix namespaces shared entries in Redis
+const cacheKeyPrefix = "api-gateway:cache:"
+
+// defaultRouteTTLs are the dashboard's polled read endpoints; every tab refreshes them
+// every few seconds and the data only changes once per collection cycle
+var defaultRouteTTLs = map[string]time.Duration{
+	"/api/v1/accidents/latest": 5 * time.Second,
+	"/api/v1/accidents/stats":  10 * time.Second,
+	"/api/v1/tollgate/traffic": 10 * time.Second,
+	"/api/v1/road/status":      10 * time.Second,
+	"/api/v1/road/summary":     10 * time.Second,
+	"/api/v1/road/incidents":   10 * time.Second,
+	"/api/v1/geo/accidents":    5 * time.Second,
+	"/api/v1/geo/tollgates":    10 * time.Second,
+	"/api/v1/geo/conzones":     10 * time.Second,
+	// Deprecated unversioned aliases
+	"/api/accidents/latest": 5 * time.Second,
+	"/api/accidents/stats":  10 * time.Second,
+	"/api/tollgate/traffic": 10 * time.Second,
+	"/api/road/status":      10 * time.Second,
+	"/api/road/summary":     10 * time.Second,
+}
+
+// parseRouteTTLs reads "path=ttl" pairs separated by commas (e.g.
+// "/api/road/status=30s,/api/accidents/latest=0s"); a zero TTL disables caching for
+// the route. Entries override the defaults.
+func parseRouteTTLs(list string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration, len(defaultRouteTTLs))
+	for path, ttl := range defaultRouteTTLs {
+		ttls[path] = ttl
+	}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		path, ttlStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid cache TTL entry %q", entry)
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(ttlStr))
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("invalid cache TTL for %s: %q", path, ttlStr)
+		}
+		ttls[strings.TrimSpace(path)] = ttl
+	}
+	return ttls, nil
+}
+
+// cachedResponse is a stored upstream response
+type cachedResponse struct {
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	ETag     string      `json:"etag"`
+	StoredAt time.Time   `json:"storedAt"`
+	Expires  time.Time   `json:"expires"`
+}
+
+// ResponseCache keeps successful GET responses per route TTL in memory and, when a
+// Redis client is given, shares them across gateway replicas. Concurrent misses for
+// the same key are coalesced into one upstream request.
+type ResponseCache struct {
+	ttls       map[string]time.Duration
+	maxEntries int
+	redis      *redis.Client // nil for memory only
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+
+	group singleflight.Group
+}
+
+func NewResponseCache(ttls map[string]time.Duration, maxEntries int, rdb *redis.Client) *ResponseCache {
+	return &ResponseCache{
+		ttls:       ttls,
+		maxEntries: maxEntries,
+		redis:      rdb,
+		entries:    make(map[string]*cachedResponse),
+	}
+}
+
+// cacheKey identifies a response by path and normalised query
+func cacheKey(r *http.Request) string {
+	return r.URL.Path + "?" + r.URL.Query().Encode()
+}
+
+func (c *ResponseCache) get(ctx context.Context, key string) *cachedResponse {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.Expires) {
+		return entry
+	}
+
+	if c.redis == nil {
+		return nil
+	}
+	raw, err := c.redis.Get(ctx, cacheKeyPrefix+key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			slog.Warn("cache read failed", "key", key, "error", err)
+		}
+		return nil
+	}
+	entry = &cachedResponse{}
+	if err := json.Unmarshal(raw, entry); err != nil || !now.Before(entry.Expires) {
+		return nil
+	}
+	c.storeLocal(key, entry)
+	return entry
+}
+
+func (c *ResponseCache) set(ctx context.Context, key string, entry *cachedResponse) {
+	c.storeLocal(key, entry)
+
+	if c.redis == nil {
+		return
+	}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := c.redis.Set(ctx, cacheKeyPrefix+key, raw, time.Until(entry.Expires)).Err(); err != nil {
+		slog.Warn("cache write failed", "key", key, "error", err)
+	}
+}
+
+func (c *ResponseCache) storeLocal(key string, entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		now := time.Now()
+		for k, e := range c.entries {
+			if !now.Before(e.Expires) {
+				delete(c.entries, k)
+			}
+		}
+		// Still full: drop an arbitrary entry rather than grow without bound
+		for k := range c.entries {
+			if len(c.entries) < c.maxEntries {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry
+}
+
+// bodyRecorder captures a proxied response so it can be stored and replayed
+type bodyRecorder struct {
+	header http.Header
+	status int
+	body   []byte
+}
+
+func (b *bodyRecorder) Header() http.Header { return b.header }
+
+func (b *bodyRecorder) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bodyRecorder) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	b.body = append(b.body, p...)
+	return len(p), nil
+}
+
+// cacheable reports whether an upstream response may be stored
+func cacheable(rec *bodyRecorder) bool {
+	if rec.status != http.StatusOK || rec.header.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := rec.header.Get("Cache-Control")
+	return !strings.Contains(cc, "no-store") && !strings.Contains(cc, "private")
+}
+
+// etagFor derives a strong validator from the body, so replicas agree on it
+func etagFor(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches implements If-None-Match for a single strong ETag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrap serves cacheable routes from the cache and passes everything else to next
+func (c *ResponseCache) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ttl := c.ttls[r.URL.Path]
+		if r.Method != http.MethodGet || ttl <= 0 {
+			cacheRequests.WithLabelValues("bypass").Inc()
+			next(w, r)
+			return
+		}
+
+		key := cacheKey(r)
+		result := "hit"
+		entry := c.get(r.Context(), key)
+		if entry == nil {
+			// Only the caller that runs the function sees leader set; the others
+			// waited on its result
+			var leader, fetched bool
+			v, _, _ := c.group.Do(key, func() (interface{}, error) {
+				leader = true
+				// Another request may have filled the cache while this one waited
+				if entry := c.get(r.Context(), key); entry != nil {
+					return entry, nil
+				}
+				fetched = true
+				return c.fetch(r, key, ttl, next), nil
+			})
+			entry = v.(*cachedResponse)
+			switch {
+			case !leader:
+				result = "coalesced"
+			case fetched:
+				result = "miss"
+			}
+		}
+		cacheRequests.WithLabelValues(result).Inc()
+		c.serve(w, r, entry, result)
+	}
+}
+
+// fetch runs the upstream request detached from the caller's cancellation, since
+// coalesced requests are waiting on the same result
+func (c *ResponseCache) fetch(r *http.Request, key string, ttl time.Duration, next http.HandlerFunc) *cachedResponse {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 30*time.Second)
+	defer cancel()
+
+	out := r.Clone(ctx)
+	out.Header.Del("If-None-Match")
+	out.Header.Del("If-Modified-Since")
+	// Let the transport negotiate and decode compression, so stored bodies suit any client
+	out.Header.Del("Accept-Encoding")
+
+	rec := &bodyRecorder{header: make(http.Header)}
+	next(rec, out)
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	now := time.Now()
+	entry := &cachedResponse{
+		Status:   rec.status,
+		Header:   rec.header,
+		Body:     rec.body,
+		ETag:     etagFor(rec.body),
+		StoredAt: now,
+		Expires:  now.Add(ttl),
+	}
+	if cacheable(rec) {
+		c.set(ctx, key, entry)
+	} else {
+		// Errors are still shared with coalesced requests but never stored
+		entry.Expires = now
+	}
+	return entry
+}
+
+func (c *ResponseCache) serve(w http.ResponseWriter, r *http.Request, entry *cachedResponse, result string) {
+	h := w.Header()
+	for k, v := range entry.Header {
+		h[k] = v
+	}
+	h.Del("Content-Length")
+	h.Set("X-Cache", strings.ToUpper(result))
+
+	if entry.Status == http.StatusOK {
+		h.Set("ETag", entry.ETag)
+		h.Set("Age", strconv.Itoa(int(time.Since(entry.StoredAt).Seconds())))
+		// Browsers keep the copy but revalidate every poll, which costs a 304
+		if h.Get("Cache-Control") == "" {
+			h.Set("Cache-Control", "no-cache")
+		}
+		if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, entry.ETag) {
+			cacheNotModified.Inc()
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	h.Set("Content-Length", strconv.Itoa(len(entry.Body)))
+	w.WriteHeader(entry.Status)
+	w.Write(entry.Body)
+}
+
+var (
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_cache_requests_total",
+		Help: "Requests by cache result (hit, miss, coalesced, bypass).",
+	}, []string{"result"})
+
+	cacheNotModified = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gateway_cache_not_modified_total",
+		Help: "Cached responses answered with 304 Not Modified.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(cacheRequests, cacheNotModified)
+}
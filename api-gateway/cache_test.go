@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRouteTTLs(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    map[string]time.Duration // checked on top of the defaults
+		wantErr bool
+	}{
+		{list: "", want: map[string]time.Duration{"/api/v1/road/status": 10 * time.Second}},
+		{list: "/api/v1/road/status=30s", want: map[string]time.Duration{"/api/v1/road/status": 30 * time.Second}},
+		{list: " /api/v1/accidents/latest = 0s , /api/v1/custom=1m ", want: map[string]time.Duration{
+			"/api/v1/accidents/latest": 0,
+			"/api/v1/custom":           time.Minute,
+			"/api/v1/road/summary":     10 * time.Second,
+		}},
+		{list: "/api/v1/road/status", wantErr: true},
+		{list: "/api/v1/road/status=soon", wantErr: true},
+		{list: "/api/v1/road/status=-1s", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseRouteTTLs(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRouteTTLs(%q) error = %v, want error %v", tt.list, err, tt.wantErr)
+			continue
+		}
+		for path, ttl := range tt.want {
+			if got[path] != ttl {
+				t.Errorf("parseRouteTTLs(%q)[%s] = %v, want %v", tt.list, path, got[path], ttl)
+			}
+		}
+	}
+
+	if _, err := parseRouteTTLs("/api/v1/road/status=1s"); err != nil || defaultRouteTTLs["/api/v1/road/status"] != 10*time.Second {
+		t.Error("overrides modified the defaults")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	a := httptest.NewRequest(http.MethodGet, "/api/v1/road/status?b=2&a=1", nil)
+	b := httptest.NewRequest(http.MethodGet, "/api/v1/road/status?a=1&b=2", nil)
+	c := httptest.NewRequest(http.MethodGet, "/api/v1/road/status?a=1", nil)
+	if cacheKey(a) != cacheKey(b) || cacheKey(a) == cacheKey(c) {
+		t.Errorf("keys %q, %q, %q: want query order ignored and values kept", cacheKey(a), cacheKey(b), cacheKey(c))
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	tests := []struct {
+		status int
+		header map[string]string
+		want   bool
+	}{
+		{status: 200, want: true},
+		{status: 200, header: map[string]string{"Cache-Control": "max-age=5"}, want: true},
+		{status: 200, header: map[string]string{"Cache-Control": "no-store"}, want: false},
+		{status: 200, header: map[string]string{"Cache-Control": "private, max-age=5"}, want: false},
+		{status: 200, header: map[string]string{"Set-Cookie": "a=b"}, want: false},
+		{status: 404, want: false},
+		{status: 500, want: false},
+	}
+	for _, tt := range tests {
+		rec := &bodyRecorder{header: make(http.Header), status: tt.status}
+		for k, v := range tt.header {
+			rec.header.Set(k, v)
+		}
+		if got := cacheable(rec); got != tt.want {
+			t.Errorf("cacheable(%d, %v) = %v, want %v", tt.status, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := etagFor([]byte("body"))
+	if etag != etagFor([]byte("body")) || etag == etagFor([]byte("other")) {
+		t.Errorf("etagFor is not a stable content hash: %s", etag)
+	}
+
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{ifNoneMatch: etag, want: true},
+		{ifNoneMatch: "W/" + etag, want: true},
+		{ifNoneMatch: `"other", ` + etag, want: true},
+		{ifNoneMatch: "*", want: true},
+		{ifNoneMatch: `"other"`, want: false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.ifNoneMatch, etag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.ifNoneMatch, got, tt.want)
+		}
+	}
+}
+
+func TestStoreLocalBounded(t *testing.T) {
+	c := NewResponseCache(nil, 2, nil)
+	live := time.Now().Add(time.Minute)
+	c.storeLocal("expired", &cachedResponse{Expires: time.Now().Add(-time.Second)})
+	c.storeLocal("a", &cachedResponse{Expires: live})
+	c.storeLocal("b", &cachedResponse{Expires: live})
+
+	if len(c.entries) != 2 || c.entries["expired"] != nil || c.entries["b"] == nil {
+		t.Errorf("entries = %v, want the expired entry evicted first", c.entries)
+	}
+	c.storeLocal("c", &cachedResponse{Expires: live})
+	if len(c.entries) != 2 || c.entries["c"] == nil {
+		t.Errorf("entries = %v, want at most 2 including the new one", c.entries)
+	}
+}
+
+func TestCacheWrap(t *testing.T) {
+	const path = "/api/v1/road/status"
+	var calls atomic.Int32
+	status := http.StatusOK
+	upstream := func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("If-None-Match") != "" {
+			t.Error("conditional header forwarded upstream")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"ok":true}`))
+	}
+
+	steps := []struct {
+		name        string
+		method      string
+		path        string
+		ifNoneMatch bool
+		status      int // upstream status
+		wantStatus  int
+		wantCache   string
+		wantCalls   int32
+	}{
+		{name: "miss", method: http.MethodGet, path: path, status: 200, wantStatus: 200, wantCache: "MISS", wantCalls: 1},
+		{name: "hit", method: http.MethodGet, path: path, status: 200, wantStatus: 200, wantCache: "HIT", wantCalls: 1},
+		{name: "revalidated", method: http.MethodGet, path: path, ifNoneMatch: true, status: 200, wantStatus: 304, wantCache: "HIT", wantCalls: 1},
+		{name: "POST bypasses", method: http.MethodPost, path: path, status: 200, wantStatus: 200, wantCalls: 2},
+		{name: "unlisted route bypasses", method: http.MethodGet, path: "/api/v1/accidents", status: 200, wantStatus: 200, wantCalls: 3},
+		{name: "errors are not stored", method: http.MethodGet, path: path + "?x=1", status: 500, wantStatus: 500, wantCache: "MISS", wantCalls: 4},
+		{name: "errors are fetched again", method: http.MethodGet, path: path + "?x=1", status: 500, wantStatus: 500, wantCache: "MISS", wantCalls: 5},
+	}
+
+	c := NewResponseCache(map[string]time.Duration{path: time.Minute}, 100, nil)
+	h := c.Wrap(upstream)
+	var etag string
+	for _, s := range steps {
+		status = s.status
+		r := httptest.NewRequest(s.method, s.path, nil)
+		if s.ifNoneMatch {
+			r.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		h(w, r)
+
+		if w.Code != s.wantStatus || w.Header().Get("X-Cache") != s.wantCache || calls.Load() != s.wantCalls {
+			t.Fatalf("%s: status %d, X-Cache %q after %d upstream calls; want %d, %q, %d",
+				s.name, w.Code, w.Header().Get("X-Cache"), calls.Load(), s.wantStatus, s.wantCache, s.wantCalls)
+		}
+		if w.Code == http.StatusOK && s.wantCache != "" {
+			etag = w.Header().Get("ETag")
+			if etag == "" || w.Header().Get("Content-Type") != "application/json" || w.Body.String() != `{"ok":true}` {
+				t.Errorf("%s: replayed %v %q", s.name, w.Header(), w.Body)
+			}
+		}
+	}
+}
+
+func TestCacheCoalescesMisses(t *testing.T) {
+	const path = "/api/v1/road/status"
+	var calls atomic.Int32
+	release := make(chan struct{})
+	upstream := func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Write([]byte("ok"))
+	}
+	c := NewResponseCache(map[string]time.Duration{path: time.Minute}, 100, nil)
+	h := c.Wrap(upstream)
+
+	var wg sync.WaitGroup
+	results := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			w := httptest.NewRecorder()
+			h(w, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+			results <- w.Header().Get("X-Cache")
+		}()
+	}
+	// Give the requests time to queue up behind the first miss
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	counts := make(map[string]int)
+	for r := range results {
+		counts[r]++
+	}
+	if calls.Load() != 1 || counts["MISS"] != 1 || counts["MISS"]+counts["COALESCED"]+counts["HIT"] != 10 {
+		t.Errorf("%d upstream calls with results %v, want one miss serving every request", calls.Load(), counts)
+	}
+}
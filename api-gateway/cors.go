@@ -0,0 +1,241 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CORSConfig is the gateway's cross-origin policy. Origins are exact
+// ("https://dashboard.example.com"), subdomain wildcards ("https://*.example.com") or
+// "*" for any origin.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	MaxAge           time.Duration
+}
+
+// splitList parses a comma separated env value, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// originPattern is a parsed AllowedOrigins entry
+type originPattern struct {
+	scheme string
+	host   string // without the "*." for wildcards
+	port   string
+	suffix bool // host is a wildcard suffix
+}
+
+func parseOriginPattern(s string) (originPattern, error) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+		return originPattern{}, errors.New("invalid CORS origin " + strconv.Quote(s) + " (want scheme://host[:port])")
+	}
+	p := originPattern{scheme: strings.ToLower(u.Scheme), host: strings.ToLower(u.Hostname()), port: u.Port()}
+	if strings.HasPrefix(p.host, "*.") {
+		p.host, p.suffix = p.host[1:], true // keep the dot so "evilexample.com" never matches
+	}
+	if strings.Contains(p.host, "*") {
+		return originPattern{}, errors.New("invalid CORS origin " + strconv.Quote(s) + " (only a leading *. wildcard is allowed)")
+	}
+	return p, nil
+}
+
+func (p originPattern) matches(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if strings.ToLower(u.Scheme) != p.scheme || u.Port() != p.port {
+		return false
+	}
+	if p.suffix {
+		return strings.HasSuffix(host, p.host) && len(host) > len(p.host)
+	}
+	return host == p.host
+}
+
+// CORS applies CORSConfig to every response and answers preflight requests
+type CORS struct {
+	config   CORSConfig
+	anyOrig  bool
+	origins  []originPattern
+	methods  map[string]bool
+	headers  map[string]bool // lower-case
+	allowMet string
+	allowHdr string
+	expose   string
+	maxAge   string
+}
+
+func NewCORS(config CORSConfig) (*CORS, error) {
+	c := &CORS{
+		config:   config,
+		methods:  make(map[string]bool),
+		headers:  make(map[string]bool),
+		allowMet: strings.Join(config.AllowedMethods, ", "),
+		allowHdr: strings.Join(config.AllowedHeaders, ", "),
+		expose:   strings.Join(config.ExposedHeaders, ", "),
+		maxAge:   strconv.Itoa(int(config.MaxAge.Seconds())),
+	}
+	for _, o := range config.AllowedOrigins {
+		if o == "*" {
+			c.anyOrig = true
+			continue
+		}
+		p, err := parseOriginPattern(o)
+		if err != nil {
+			return nil, err
+		}
+		c.origins = append(c.origins, p)
+	}
+	if c.anyOrig && config.AllowCredentials {
+		return nil, errors.New("CORS credentials cannot be combined with the * origin; list the allowed origins")
+	}
+	for _, m := range config.AllowedMethods {
+		c.methods[strings.ToUpper(m)] = true
+	}
+	for _, h := range config.AllowedHeaders {
+		c.headers[strings.ToLower(h)] = true
+	}
+	return c, nil
+}
+
+// allowedOrigin reports whether origin may read responses
+func (c *CORS) allowedOrigin(origin string) bool {
+	if c.anyOrig {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, p := range c.origins {
+		if p.matches(u) {
+			return true
+		}
+	}
+	return false
+}
+
+// setOrigin writes the per-origin headers
+func (c *CORS) setOrigin(h http.Header, origin string) {
+	if c.anyOrig {
+		h.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	h.Set("Access-Control-Allow-Origin", origin)
+	if c.config.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// preflight answers an OPTIONS request carrying Access-Control-Request-Method. The
+// requested method and headers must all be allowed; otherwise the response carries no
+// CORS headers and the browser blocks the actual request.
+func (c *CORS) preflight(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	if !c.anyOrig {
+		h.Add("Vary", "Origin")
+	}
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	origin := r.Header.Get("Origin")
+	method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+	if !c.allowedOrigin(origin) {
+		corsRejected.WithLabelValues("origin").Inc()
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if !c.methods[method] {
+		corsRejected.WithLabelValues("method").Inc()
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	for _, name := range splitList(r.Header.Get("Access-Control-Request-Headers")) {
+		if !c.headers[strings.ToLower(name)] {
+			corsRejected.WithLabelValues("header").Inc()
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	c.setOrigin(h, origin)
+	h.Set("Access-Control-Allow-Methods", c.allowMet)
+	if c.allowHdr != "" {
+		h.Set("Access-Control-Allow-Headers", c.allowHdr)
+	}
+	if c.config.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", c.maxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Handler answers preflights and adds CORS headers to every other response from an
+// allowed origin, before next runs, so errors written anywhere in the gateway stay
+// readable by the browser. Requests without Origin (same-origin, curl) are untouched.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			c.preflight(w, r)
+			return
+		}
+
+		// Unless any origin gets the same "*", the response depends on Origin
+		if !c.anyOrig {
+			w.Header().Add("Vary", "Origin")
+		}
+		if c.allowedOrigin(origin) {
+			c.setOrigin(w.Header(), origin)
+			if c.expose != "" {
+				w.Header().Set("Access-Control-Expose-Headers", c.expose)
+			}
+		} else {
+			corsRejected.WithLabelValues("origin").Inc()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// stripUpstreamCORS removes data-api-service's own CORS headers so only the gateway's
+// policy reaches the browser
+func stripUpstreamCORS(h http.Header) {
+	for _, name := range []string{
+		"Access-Control-Allow-Origin",
+		"Access-Control-Allow-Methods",
+		"Access-Control-Allow-Headers",
+		"Access-Control-Allow-Credentials",
+		"Access-Control-Expose-Headers",
+		"Access-Control-Max-Age",
+	} {
+		h.Del(name)
+	}
+}
+
+var corsRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_cors_rejected_total",
+	Help: "Cross-origin requests and preflights refused, by reason (origin, method, header).",
+}, []string{"reason"})
+
+func init() {
+	prometheus.MustRegister(corsRejected)
+}
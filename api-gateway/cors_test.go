@@ -0,0 +1,296 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "", want: nil},
+		{in: " , ,", want: nil},
+		{in: "GET", want: []string{"GET"}},
+		{in: " GET , POST,,OPTIONS ", want: []string{"GET", "POST", "OPTIONS"}},
+	}
+	for _, tt := range tests {
+		if got := splitList(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitList(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestOriginPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{pattern: "https://dashboard.example.com", origin: "https://dashboard.example.com", want: true},
+		{pattern: "https://dashboard.example.com", origin: "https://DASHBOARD.example.com", want: true},
+		{pattern: "https://dashboard.example.com/", origin: "https://dashboard.example.com", want: true},
+		{pattern: "https://dashboard.example.com", origin: "http://dashboard.example.com", want: false},
+		{pattern: "https://dashboard.example.com", origin: "https://dashboard.example.com:8443", want: false},
+		{pattern: "http://localhost:3000", origin: "http://localhost:3000", want: true},
+		{pattern: "http://localhost:3000", origin: "http://localhost:3001", want: false},
+		{pattern: "https://*.example.com", origin: "https://a.example.com", want: true},
+		{pattern: "https://*.example.com", origin: "https://a.b.example.com", want: true},
+		{pattern: "https://*.example.com", origin: "https://example.com", want: false},
+		{pattern: "https://*.example.com", origin: "https://evilexample.com", want: false},
+		{pattern: "https://*.example.com", origin: "https://a.example.com.evil.com", want: false},
+	}
+	for _, tt := range tests {
+		p, err := parseOriginPattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("parseOriginPattern(%q): %v", tt.pattern, err)
+		}
+		u, err := url.Parse(tt.origin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.matches(u); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestParseOriginPatternInvalid(t *testing.T) {
+	for _, s := range []string{
+		"dashboard.example.com",
+		"https://",
+		"https://dashboard.example.com/app",
+		"https://a.*.example.com",
+		"https://*example.com",
+		"://bad",
+	} {
+		if _, err := parseOriginPattern(s); err == nil {
+			t.Errorf("parseOriginPattern(%q) accepted", s)
+		}
+	}
+}
+
+func TestNewCORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CORSConfig
+		wantErr bool
+	}{
+		{name: "listed origins", config: CORSConfig{AllowedOrigins: []string{"https://a.example.com", "https://*.example.org"}, AllowCredentials: true}},
+		{name: "any origin", config: CORSConfig{AllowedOrigins: []string{"*"}}},
+		{name: "any origin with credentials", config: CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, wantErr: true},
+		{name: "invalid origin", config: CORSConfig{AllowedOrigins: []string{"example.com"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if _, err := NewCORS(tt.config); (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCORSHandler(t *testing.T) {
+	listed := CORSConfig{
+		AllowedOrigins:   []string{"https://dashboard.example.com", "https://*.example.org"},
+		AllowCredentials: true,
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Content-Type", "X-API-Key"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		MaxAge:           10 * time.Minute,
+	}
+	anyOrigin := CORSConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}
+	noOrigin := CORSConfig{AllowedMethods: []string{"GET"}} // CORS_ALLOWED_ORIGINS unset
+
+	tests := []struct {
+		name       string
+		config     CORSConfig
+		method     string
+		header     map[string]string
+		wantStatus int
+		wantNext   bool
+		wantHeader map[string]string // "" means the header must be absent
+		wantVary   []string
+	}{
+		{
+			name:       "no Origin is untouched",
+			config:     listed,
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:       "allowed origin",
+			config:     listed,
+			method:     http.MethodGet,
+			header:     map[string]string{"Origin": "https://dashboard.example.com"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":      "https://dashboard.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-ID",
+			},
+			wantVary: []string{"Origin"},
+		},
+		{
+			name:       "disallowed origin still reaches next without CORS headers",
+			config:     listed,
+			method:     http.MethodGet,
+			header:     map[string]string{"Origin": "https://evil.example.com"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Credentials": ""},
+			wantVary:   []string{"Origin"},
+		},
+		{
+			name:   "preflight",
+			config: listed,
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                         "https://app.example.org",
+				"Access-Control-Request-Method":  "post",
+				"Access-Control-Request-Headers": "content-type, x-api-key",
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.org",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "Content-Type, X-API-Key",
+				"Access-Control-Max-Age":       "600",
+			},
+			wantVary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:       "preflight from a disallowed origin",
+			config:     listed,
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://example.org", "Access-Control-Request-Method": "GET"},
+			wantStatus: http.StatusForbidden,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:       "preflight for a disallowed method",
+			config:     listed,
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://dashboard.example.com", "Access-Control-Request-Method": "DELETE"},
+			wantStatus: http.StatusForbidden,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:   "preflight for a disallowed header",
+			config: listed,
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                         "https://dashboard.example.com",
+				"Access-Control-Request-Method":  "GET",
+				"Access-Control-Request-Headers": "Authorization",
+			},
+			wantStatus: http.StatusForbidden,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:       "plain OPTIONS is passed on",
+			config:     listed,
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://dashboard.example.com"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantVary:   []string{"Origin"},
+		},
+		{
+			name:       "unset origins refuse cross-origin reads",
+			config:     noOrigin,
+			method:     http.MethodGet,
+			header:     map[string]string{"Origin": "https://anything.example"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin"},
+		},
+		{
+			name:       "unset origins refuse preflights",
+			config:     noOrigin,
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://anything.example", "Access-Control-Request-Method": "GET"},
+			wantStatus: http.StatusForbidden,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:       "any origin",
+			config:     anyOrigin,
+			method:     http.MethodGet,
+			header:     map[string]string{"Origin": "https://anything.example"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Allow-Credentials": ""},
+		},
+		{
+			name:       "any origin preflight has no max age when unset",
+			config:     anyOrigin,
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://anything.example", "Access-Control-Request-Method": "GET"},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Max-Age": "", "Access-Control-Allow-Headers": ""},
+			wantVary:   []string{"Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCORS(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var called bool
+			h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+
+			r := httptest.NewRequest(tt.method, "/api/v1/accidents", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus || called != tt.wantNext {
+				t.Fatalf("status %d, next called %v; want %d, %v", w.Code, called, tt.wantStatus, tt.wantNext)
+			}
+			for name, want := range tt.wantHeader {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			if got := w.Header().Values("Vary"); len(got)+len(tt.wantVary) > 0 && !reflect.DeepEqual(got, tt.wantVary) {
+				t.Errorf("Vary = %q, want %q", got, tt.wantVary)
+			}
+		})
+	}
+}
+
+func TestStripUpstreamCORS(t *testing.T) {
+	h := http.Header{}
+	h.Set("Access-Control-Allow-Origin", "*")
+	h.Set("Access-Control-Allow-Methods", "GET")
+	h.Set("Access-Control-Allow-Headers", "Content-Type")
+	h.Set("Access-Control-Allow-Credentials", "true")
+	h.Set("Access-Control-Expose-Headers", "X-Total")
+	h.Set("Access-Control-Max-Age", "60")
+	h.Set("Content-Type", "application/json")
+	h.Set("Vary", "Origin")
+
+	stripUpstreamCORS(h)
+	want := http.Header{"Content-Type": {"application/json"}, "Vary": {"Origin"}}
+	if !reflect.DeepEqual(h, want) {
+		t.Errorf("headers = %v, want %v", h, want)
+	}
+}
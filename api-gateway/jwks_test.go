@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestJSONWebKeyPublicKey(t *testing.T) {
+	b64 := base64.RawURLEncoding
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecX, ecY := b64.EncodeToString(ecKey.X.Bytes()), b64.EncodeToString(ecKey.Y.Bytes())
+
+	tests := []struct {
+		name    string
+		key     jsonWebKey
+		check   func(pub interface{}) bool
+		wantErr bool
+	}{
+		{
+			name: "RSA",
+			key:  jsonWebKey{Kty: "RSA", N: b64.EncodeToString(rsaKey.N.Bytes()), E: b64.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes())},
+			check: func(pub interface{}) bool {
+				k, ok := pub.(*rsa.PublicKey)
+				return ok && k.Equal(&rsaKey.PublicKey)
+			},
+		},
+		{
+			name: "EC",
+			key:  jsonWebKey{Kty: "EC", Crv: "P-256", X: ecX, Y: ecY},
+			check: func(pub interface{}) bool {
+				k, ok := pub.(*ecdsa.PublicKey)
+				return ok && k.Equal(&ecKey.PublicKey)
+			},
+		},
+		{
+			name: "Ed25519",
+			key:  jsonWebKey{Kty: "OKP", Crv: "Ed25519", X: b64.EncodeToString(edKey)},
+			check: func(pub interface{}) bool {
+				k, ok := pub.(ed25519.PublicKey)
+				return ok && k.Equal(edKey)
+			},
+		},
+		{name: "EC point off the curve", key: jsonWebKey{Kty: "EC", Crv: "P-256", X: ecX, Y: ecX}, wantErr: true},
+		{name: "unknown curve", key: jsonWebKey{Kty: "EC", Crv: "secp256k1", X: ecX, Y: ecY}, wantErr: true},
+		{name: "short Ed25519 key", key: jsonWebKey{Kty: "OKP", Crv: "Ed25519", X: "AAAA"}, wantErr: true},
+		{name: "invalid base64", key: jsonWebKey{Kty: "RSA", N: "!!", E: "AQAB"}, wantErr: true},
+		{name: "symmetric key", key: jsonWebKey{Kty: "oct"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		pub, err := tt.key.publicKey()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !tt.check(pub) {
+			t.Errorf("%s: decoded %T does not match the source key", tt.name, pub)
+		}
+	}
+}
+
+func TestJWKSRefresh(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding
+	sigKey := `{"kty":"EC","kid":"k1","use":"sig","crv":"P-256","x":"` + b64.EncodeToString(ecKey.X.Bytes()) +
+		`","y":"` + b64.EncodeToString(ecKey.Y.Bytes()) + `"}`
+	encKey := `{"kty":"EC","kid":"k2","use":"enc","crv":"P-256","x":"` + b64.EncodeToString(ecKey.X.Bytes()) +
+		`","y":"` + b64.EncodeToString(ecKey.Y.Bytes()) + `"}`
+
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantKids []string
+		wantErr  bool
+	}{
+		{name: "signing keys only", status: 200, body: `{"keys":[` + sigKey + `,` + encKey + `,{"kty":"oct","kid":"k3"}]}`, wantKids: []string{"k1"}},
+		{name: "no usable keys", status: 200, body: `{"keys":[` + encKey + `]}`, wantErr: true},
+		{name: "invalid JSON", status: 200, body: `{`, wantErr: true},
+		{name: "HTTP error", status: 500, body: `{"keys":[` + sigKey + `]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			j := NewJWKS(srv.URL)
+			err := j.Refresh(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if len(j.keys) != len(tt.wantKids) {
+				t.Errorf("keys = %v, want %v", j.keys, tt.wantKids)
+			}
+			for _, kid := range tt.wantKids {
+				if _, ok := j.lookup(kid); !ok {
+					t.Errorf("kid %s missing", kid)
+				}
+			}
+		})
+	}
+}
+
+func TestJWKSKeyLookup(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding
+	body := `{"keys":[{"kty":"EC","kid":"k1","crv":"P-256","x":"` + b64.EncodeToString(ecKey.X.Bytes()) +
+		`","y":"` + b64.EncodeToString(ecKey.Y.Bytes()) + `"}]}`
+
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	j := NewJWKS(srv.URL)
+	ctx := context.Background()
+
+	// The first lookup fetches the set; a kid-less token matches the only key
+	if _, err := j.Key(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Key(ctx, "k1"); err != nil {
+		t.Fatal(err)
+	}
+	// Unknown kids refetch at most once per jwksRefetchMinInterval
+	for i := 0; i < 3; i++ {
+		if _, err := j.Key(ctx, "forged"); err == nil {
+			t.Fatal("unknown kid accepted")
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "4bf92f3577b34da6a3ce929d0e0e4736", want: true},
+		{id: "client-req_01:a/b", want: true},
+		{id: "", want: false},
+		{id: "has space", want: false},
+		{id: "line\nbreak", want: false},
+		{id: "요청", want: false},
+		{id: strings.Repeat("a", 128), want: true},
+		{id: strings.Repeat("a", 129), want: false},
+	}
+	for _, tt := range tests {
+		if got := validRequestID(tt.id); got != tt.want {
+			t.Errorf("validRequestID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "caller ID is kept", incoming: "client-id-1", keep: true},
+		{name: "missing ID is assigned"},
+		{name: "unsafe ID is replaced", incoming: "bad id\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			var logger *slog.Logger
+			h := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = r.Header.Get(requestIDHeader)
+				logger = loggerFrom(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/accidents", nil)
+			if tt.incoming != "" {
+				r.Header.Set(requestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			echoed := w.Header().Get(requestIDHeader)
+			if echoed != seen || !validRequestID(echoed) {
+				t.Errorf("handler saw %q, response echoed %q", seen, echoed)
+			}
+			if tt.keep && echoed != tt.incoming {
+				t.Errorf("request ID = %q, want the caller's %q", echoed, tt.incoming)
+			}
+			if !tt.keep && len(echoed) != 32 {
+				t.Errorf("request ID = %q, want a new 32 character ID", echoed)
+			}
+			if logger == nil || logger == slog.Default() {
+				t.Error("handler did not get a request-scoped logger")
+			}
+		})
+	}
+
+	if loggerFrom(context.Background()) != slog.Default() {
+		t.Error("loggerFrom without a request logger should return the default logger")
+	}
+}
@@ -1,19 +1,57 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httputil"
-	"net/url"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Default CORS request headers the browser may send and response headers it may read
+const (
+	defaultCORSMethods        = "GET, POST, PUT, DELETE, OPTIONS"
+	defaultCORSAllowedHeaders = "Content-Type, Authorization, X-Request-ID, X-API-Key, If-None-Match"
+	defaultCORSExposedHeaders = "X-Request-ID, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, ETag, X-Cache"
 )
 
+// shutdownTimeout bounds draining in-flight requests after SIGTERM; it stays below the
+// default 30s Kubernetes termination grace period
+const shutdownTimeout = 25 * time.Second
+
 type Config struct {
 	Port              string
 	DataAPIServiceURL string
+	// DataAPIServiceURLs lists every data-api-service upstream ("url|weight|cluster",
+	// comma separated); empty means DataAPIServiceURL alone
+	DataAPIServiceURLs  string
+	HealthCheckPath     string
+	HealthCheckInterval time.Duration
+	EjectAfter          int
+	EjectFor            time.Duration
+	UpstreamRetries     int
+	UpstreamTimeout     time.Duration
+	UpstreamFailures    string // status codes that count as upstream failures, e.g. "500,502,503,504"
+	CacheEnabled        bool
+	CacheRouteTTLs      string // "path=ttl" overrides of defaultRouteTTLs
+	CacheMaxEntries     int
+	RedisAddr           string // shares cached responses and rate limits across replicas when set
+	Access              AccessConfig
+	Auth                AuthConfig
+	CORS                CORSConfig
 }
 
 func loadConfig() Config {
@@ -27,9 +65,153 @@ func loadConfig() Config {
 		dataAPIServiceURL = "http://data-api-service.default.svc.cluster.local:8080"
 	}
 
+	healthCheckPath := os.Getenv("HEALTH_CHECK_PATH")
+	if healthCheckPath == "" {
+		healthCheckPath = "/health"
+	}
+
+	healthCheckInterval := 5 * time.Second
+	if env := os.Getenv("HEALTH_CHECK_INTERVAL"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d > 0 {
+			healthCheckInterval = d
+		}
+	}
+
+	ejectAfter := 3
+	if env := os.Getenv("OUTLIER_CONSECUTIVE_FAILURES"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v > 0 {
+			ejectAfter = v
+		}
+	}
+
+	ejectFor := 30 * time.Second
+	if env := os.Getenv("OUTLIER_EJECTION_TIME"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d > 0 {
+			ejectFor = d
+		}
+	}
+
+	upstreamRetries := 2
+	if env := os.Getenv("UPSTREAM_RETRIES"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v >= 0 {
+			upstreamRetries = v
+		}
+	}
+
+	// Per-attempt wait for response headers, so a hung upstream fails over instead of
+	// holding the request
+	upstreamTimeout := 10 * time.Second
+	if env := os.Getenv("UPSTREAM_TIMEOUT"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d > 0 {
+			upstreamTimeout = d
+		}
+	}
+
+	cacheMaxEntries := 1000
+	if env := os.Getenv("CACHE_MAX_ENTRIES"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v > 0 {
+			cacheMaxEntries = v
+		}
+	}
+
+	// The dashboard polls about one request per second per open tab, and a venue's
+	// viewers may share one NAT address, so the anonymous per-IP limit is generous
+	access := AccessConfig{
+		KeysFile:       os.Getenv("API_KEYS_FILE"),
+		AllowAnonymous: os.Getenv("ALLOW_ANONYMOUS") != "false",
+		IPRate:         20,
+		IPBurst:        100,
+		KeyRate:        50,
+		KeyBurst:       100,
+	}
+	if env := os.Getenv("RATE_LIMIT_IP_RPS"); env != "" {
+		if v, err := strconv.ParseFloat(env, 64); err == nil && v > 0 {
+			access.IPRate = v
+		}
+	}
+	if env := os.Getenv("RATE_LIMIT_IP_BURST"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v > 0 {
+			access.IPBurst = v
+		}
+	}
+	if env := os.Getenv("RATE_LIMIT_KEY_RPS"); env != "" {
+		if v, err := strconv.ParseFloat(env, 64); err == nil && v > 0 {
+			access.KeyRate = v
+		}
+	}
+	if env := os.Getenv("RATE_LIMIT_KEY_BURST"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v > 0 {
+			access.KeyBurst = v
+		}
+	}
+	if env := os.Getenv("TRUSTED_PROXY_HOPS"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v >= 0 {
+			access.TrustedProxyHops = v
+		}
+	}
+
+	auth := AuthConfig{
+		JWKSURL:     os.Getenv("JWT_JWKS_URL"),
+		JWKSRefresh: 10 * time.Minute,
+		Issuer:      os.Getenv("JWT_ISSUER"),
+		Audience:    os.Getenv("JWT_AUDIENCE"),
+		RolesClaim:  os.Getenv("JWT_ROLES_CLAIM"),
+		RoleMap:     os.Getenv("JWT_ROLE_MAP"),
+		Policies:    os.Getenv("ACCESS_POLICIES"),
+	}
+	if env := os.Getenv("JWT_JWKS_REFRESH"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d > 0 {
+			auth.JWKSRefresh = d
+		}
+	}
+	if auth.RolesClaim == "" {
+		auth.RolesClaim = "roles"
+	}
+	if auth.Policies == "" {
+		auth.Policies = defaultPolicies
+	}
+
+	cors := CORSConfig{
+		AllowedOrigins:   splitList(os.Getenv("CORS_ALLOWED_ORIGINS")),
+		AllowCredentials: os.Getenv("CORS_ALLOW_CREDENTIALS") == "true",
+		AllowedMethods:   splitList(defaultCORSMethods),
+		AllowedHeaders:   splitList(defaultCORSAllowedHeaders),
+		ExposedHeaders:   splitList(defaultCORSExposedHeaders),
+		MaxAge:           10 * time.Minute,
+	}
+	if env := os.Getenv("CORS_ALLOWED_METHODS"); env != "" {
+		cors.AllowedMethods = splitList(env)
+	}
+	if env := os.Getenv("CORS_ALLOWED_HEADERS"); env != "" {
+		cors.AllowedHeaders = splitList(env)
+	}
+	if env := os.Getenv("CORS_EXPOSED_HEADERS"); env != "" {
+		cors.ExposedHeaders = splitList(env)
+	}
+	if env := os.Getenv("CORS_MAX_AGE"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d >= 0 {
+			cors.MaxAge = d
+		}
+	}
+
 	return Config{
-		Port:              port,
-		DataAPIServiceURL: dataAPIServiceURL,
+		Port:                port,
+		DataAPIServiceURL:   dataAPIServiceURL,
+		DataAPIServiceURLs:  os.Getenv("DATA_API_SERVICE_URLS"),
+		HealthCheckPath:     healthCheckPath,
+		HealthCheckInterval: healthCheckInterval,
+		EjectAfter:          ejectAfter,
+		EjectFor:            ejectFor,
+		UpstreamRetries:     upstreamRetries,
+		UpstreamTimeout:     upstreamTimeout,
+		UpstreamFailures:    os.Getenv("UPSTREAM_FAILURE_CODES"),
+		CacheEnabled:        os.Getenv("CACHE_ENABLED") != "false",
+		CacheRouteTTLs:      os.Getenv("CACHE_ROUTE_TTLS"),
+		CacheMaxEntries:     cacheMaxEntries,
+		RedisAddr:           os.Getenv("REDIS_ADDR"),
+		Access:              access,
+		Auth:                auth,
+		CORS:                cors,
 	}
 }
 
@@ -37,78 +219,154 @@ type Gateway struct {
 	config      Config
 	dataAPIProxy *httputil.ReverseProxy
 	httpClient   *http.Client
+	balancer     *Balancer
+	cache        *ResponseCache // nil when caching is disabled
+	redisClient  *redis.Client  // cache and rate limit sharing; nil when not configured
+	access       *AccessControl
+	limiter      *Limiter
+	auth         *Authenticator
+	cors         *CORS
 }
 
 func NewGateway(config Config) (*Gateway, error) {
-	dataAPIURL, err := url.Parse(config.DataAPIServiceURL)
+	upstreamList := config.DataAPIServiceURLs
+	if upstreamList == "" {
+		upstreamList = config.DataAPIServiceURL
+	}
+	upstreams, err := parseUpstreams(upstreamList)
 	if err != nil {
 		return nil, fmt.Errorf("invalid data API service URL: %w", err)
 	}
+	failureCodes, err := parseStatusCodes(config.UpstreamFailures)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UPSTREAM_FAILURE_CODES: %w", err)
+	}
 
-	dataAPIProxy := httputil.NewSingleHostReverseProxy(dataAPIURL)
+	// otelhttp injects traceparent so data-api-service spans join the request's trace
+	balancer := NewBalancer(BalancerConfig{
+		Upstreams:           upstreams,
+		LocalCluster:        os.Getenv("CLUSTER_NAME"),
+		HealthCheckPath:     config.HealthCheckPath,
+		HealthCheckInterval: config.HealthCheckInterval,
+		EjectAfter:          config.EjectAfter,
+		EjectFor:            config.EjectFor,
+		Retries:             config.UpstreamRetries,
+		FailureCodes:        failureCodes,
+	}, promhttp.InstrumentRoundTripperDuration(upstreamDuration,
+		otelhttp.NewTransport(&http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   100,
+			IdleConnTimeout:       90 * time.Second,
+			ResponseHeaderTimeout: config.UpstreamTimeout,
+		})))
 
-	// Configure proxy with custom transport
-	dataAPIProxy.Transport = &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 100,
-		IdleConnTimeout:     90 * time.Second,
-	}
+	// The balancer picks the upstream host per request, so the proxy only needs the
+	// path of the first upstream (all upstreams serve the same API)
+	dataAPIProxy := httputil.NewSingleHostReverseProxy(upstreams[0].URL)
+	dataAPIProxy.Transport = balancer
 
-	// Remove upstream CORS headers and set our own
+	// CORS is answered by the gateway's policy (see CORS.Handler), never by the upstream
 	dataAPIProxy.ModifyResponse = func(resp *http.Response) error {
-		// Delete all CORS headers from upstream
-		resp.Header.Del("Access-Control-Allow-Origin")
-		resp.Header.Del("Access-Control-Allow-Methods")
-		resp.Header.Del("Access-Control-Allow-Headers")
-		resp.Header.Del("Access-Control-Allow-Credentials")
-
-		// Set our own CORS headers
-		resp.Header.Set("Access-Control-Allow-Origin", "*")
-		resp.Header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		resp.Header.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		stripUpstreamCORS(resp.Header)
+
+		// withRequestID already set the (same) request ID on the response
+		resp.Header.Del(requestIDHeader)
 		return nil
 	}
 
 	// Custom error handler
 	dataAPIProxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-		log.Printf("Proxy error for %s: %v", r.URL.Path, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		w.Write([]byte(`{"error": "Service temporarily unavailable"}`))
+		loggerFrom(r.Context()).Error("proxy error", "path", r.URL.Path, "error", err)
+		upstreamErrors.Inc()
+		span := trace.SpanFromContext(r.Context())
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		writeJSONError(w, http.StatusBadGateway, "service temporarily unavailable")
 	}
 
-	return &Gateway{
+	g := &Gateway{
 		config:       config,
 		dataAPIProxy: dataAPIProxy,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: balancer,
 		},
-	}, nil
+		balancer: balancer,
+	}
+
+	if config.RedisAddr != "" {
+		g.redisClient = redis.NewClient(&redis.Options{Addr: config.RedisAddr})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := g.redisClient.Ping(ctx).Err(); err != nil {
+			// The cache and limiter fall back to local state; Redis is retried on every use
+			slog.Warn("redis unreachable, continuing with local cache and rate limits", "addr", config.RedisAddr, "error", err)
+		}
+		cancel()
+	}
+
+	if config.CacheEnabled {
+		ttls, err := parseRouteTTLs(config.CacheRouteTTLs)
+		if err != nil {
+			return nil, err
+		}
+		g.cache = NewResponseCache(ttls, config.CacheMaxEntries, g.redisClient)
+	}
+
+	g.limiter = NewLimiter(g.redisClient)
+	if g.access, err = NewAccessControl(config.Access, g.limiter); err != nil {
+		return nil, err
+	}
+	if g.auth, err = NewAuthenticator(config.Auth); err != nil {
+		return nil, err
+	}
+	if g.cors, err = NewCORS(config.CORS); err != nil {
+		return nil, err
+	}
+	// Cross-origin access is opt-in: unset allows none, "*" must be asked for
+	switch {
+	case len(config.CORS.AllowedOrigins) == 0:
+		slog.Warn("CORS_ALLOWED_ORIGINS is unset; browsers on other origins cannot read responses")
+	case g.cors.anyOrig:
+		slog.Warn("CORS allows any origin (CORS_ALLOWED_ORIGINS=*); list the dashboard origins in production")
+	}
+
+	return g, nil
 }
 
-func (g *Gateway) enableCORS(w http.ResponseWriter) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// writeJSONError writes the gateway's error body, shaped like data-api-service's
+// APIError so clients handle both alike
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"code": errorCode(status), "message": message})
 }
 
-func (g *Gateway) handleDataAPI(w http.ResponseWriter, r *http.Request) {
-	// Handle OPTIONS preflight
-	if r.Method == http.MethodOptions {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.WriteHeader(http.StatusOK)
-		return
+// errorCode maps a status to the APIError code
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return "unavailable"
 	}
+	return "internal"
+}
 
-	log.Printf("Proxying request: %s %s to %s", r.Method, r.URL.Path, g.config.DataAPIServiceURL)
-
+func (g *Gateway) handleDataAPI(w http.ResponseWriter, r *http.Request) {
 	// Add forwarding headers
 	r.Header.Set("X-Forwarded-Host", r.Host)
 	r.Header.Set("X-Forwarded-Proto", "http")
 
-	// Proxy the request (CORS handled in ModifyResponse)
+	// Proxy the request
 	g.dataAPIProxy.ServeHTTP(w, r)
 }
 
@@ -120,55 +378,133 @@ func (g *Gateway) healthHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (g *Gateway) infoHandler(w http.ResponseWriter, r *http.Request) {
-	g.enableCORS(w)
-
 	hostname, _ := os.Hostname()
+	upstreams, _ := json.MarshalIndent(g.balancer.State(), "    ", "  ")
 	info := fmt.Sprintf(`{
   "service": "api-gateway",
   "version": "1.0.0",
   "hostname": "%s",
+  "cluster": "%s",
   "endpoints": {
-    "accidents": "/api/accidents/latest",
-    "stats": "/api/accidents/stats",
-    "health": "/health"
+    "accidents": "/api/v1/accidents/latest",
+    "stats": "/api/v1/accidents/stats",
+    "clusterStatus": "/api/v1/cluster/status",
+    "openapi": "/api/v1/openapi.json",
+    "health": "/health",
+    "pipelineHealth": "/health/pipeline",
+    "metrics": "/metrics",
+    "usage": "/admin/usage"
   },
   "upstreamServices": {
-    "dataAPI": "%s"
+    "dataAPI": %s
   }
-}`, hostname, g.config.DataAPIServiceURL)
+}`, hostname, os.Getenv("CLUSTER_NAME"), upstreams)
 
 	w.Header().Set("Content-Type", "application/json")
 	io.WriteString(w, info)
 }
 
-func (g *Gateway) Start() error {
-	// Route /api/* to data-api-service
-	http.HandleFunc("/api/", g.handleDataAPI)
+// protect runs h behind authentication, rate limiting and the route policy, in that
+// order: a verified token or API key selects the caller's rate limit and role
+func (g *Gateway) protect(h http.HandlerFunc) http.HandlerFunc {
+	return g.auth.Authenticate(g.access.Guard(g.auth.Authorize(h)))
+}
+
+// Start serves until ctx is cancelled, then drains in-flight requests
+func (g *Gateway) Start(ctx context.Context) error {
+	// Route /api/* to data-api-service, answering polled reads from the cache. Rate
+	// limits apply before the cache so cached answers count too.
+	dataAPI := g.handleDataAPI
+	if g.cache != nil {
+		dataAPI = g.cache.Wrap(g.handleDataAPI)
+	}
+	http.Handle("/api/", instrument("/api/", g.protect(dataAPI)))
+	http.Handle("/api/v1/cluster/status", instrument("/api/v1/cluster/status", g.protect(g.clusterStatusHandler)))
+	http.Handle("/api/cluster/status", instrument("/api/cluster/status", g.protect(g.clusterStatusHandler)))
+
+	// Per-key usage (admin role by the default policy)
+	http.Handle("/admin/usage", instrument("/admin/usage", g.protect(g.access.usageHandler)))
+	go g.limiter.Sweep(ctx, 10*time.Minute)
+	go g.auth.Run(ctx)
 
-	// Health and info endpoints
-	http.HandleFunc("/health", g.healthHandler)
-	http.HandleFunc("/info", g.infoHandler)
+	// Health, info and metrics endpoints; pipeline health is answered by data-api-service
+	http.Handle("/health", instrument("/health", g.healthHandler))
+	http.Handle("/health/pipeline", instrument("/health/pipeline", StripIdentity(g.handleDataAPI)))
+	http.Handle("/info", instrument("/info", g.infoHandler))
+	http.Handle("/metrics", promhttp.Handler())
+
+	go g.balancer.RunHealthChecks(ctx)
 
 	addr := ":" + g.config.Port
-	log.Printf("API Gateway starting on %s", addr)
-	log.Printf("Routing /api/* to %s", g.config.DataAPIServiceURL)
+	slog.Info("API Gateway starting", "addr", addr, "upstreams", len(g.balancer.upstreams))
+
+	// CORS wraps every route so preflights and rejections carry the same policy
+	server := &http.Server{Addr: addr, Handler: g.cors.Handler(http.DefaultServeMux)}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.ListenAndServe()
+	}()
 
-	return http.ListenAndServe(addr, nil)
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, draining in-flight requests", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	if g.redisClient != nil {
+		g.redisClient.Close()
+	}
+	return err
 }
 
 func main() {
+	setupLogging("api-gateway")
 	config := loadConfig()
 
-	log.Printf("Configuration:")
-	log.Printf("  Port: %s", config.Port)
-	log.Printf("  Data API Service URL: %s", config.DataAPIServiceURL)
+	slog.Info("configuration", "port", config.Port, "data_api_service_url", config.DataAPIServiceURL,
+		"data_api_service_urls", config.DataAPIServiceURLs, "upstream_retries", config.UpstreamRetries,
+		"upstream_timeout", config.UpstreamTimeout, "cache_enabled", config.CacheEnabled,
+		"redis_addr", config.RedisAddr, "api_keys_file", config.Access.KeysFile,
+		"allow_anonymous", config.Access.AllowAnonymous, "jwt_jwks_url", config.Auth.JWKSURL,
+		"access_policies", config.Auth.Policies,
+		"cors_allowed_origins", config.CORS.AllowedOrigins)
 
 	gateway, err := NewGateway(config)
 	if err != nil {
-		log.Fatalf("Failed to create gateway: %v", err)
+		fatal("failed to create gateway", "error", err)
 	}
 
-	if err := gateway.Start(); err != nil {
-		log.Fatalf("Gateway failed: %v", err)
+	shutdownTracing, err := initTracing(context.Background(), "api-gateway")
+	if err != nil {
+		fatal("failed to initialise tracing", "error", err)
 	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle shutdown gracefully
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		slog.Info("shutdown signal received")
+		cancel()
+	}()
+
+	if err := gateway.Start(ctx); err != nil {
+		fatal("gateway failed", "error", err)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("tracing shutdown", "error", err)
+	}
+
+	slog.Info("API Gateway stopped")
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+var (
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency per handler, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"handler", "method", "code"})
+
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests per handler, method and status code.",
+	}, []string{"handler", "method", "code"})
+
+	upstreamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gateway_upstream_request_duration_seconds",
+		Help:    "Latency of proxied requests to data-api-service per method and upstream status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	upstreamErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gateway_upstream_errors_total",
+		Help: "Proxied requests that failed without an upstream response.",
+	})
+)
+
+// instrument records latency and status codes of h under the given handler label and
+// serves it in a server span continuing the caller's trace (health probes are not traced),
+// tagged with the request ID
+func instrument(handler string, h http.HandlerFunc) http.Handler {
+	labels := prometheus.Labels{"handler": handler}
+	return otelhttp.NewHandler(
+		withRequestID(promhttp.InstrumentHandlerDuration(httpDuration.MustCurryWith(labels),
+			promhttp.InstrumentHandlerCounter(httpRequests.MustCurryWith(labels), h))),
+		handler,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/health"
+		}),
+	)
+}
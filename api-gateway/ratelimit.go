@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// limiterKeyPrefix namespaces the gateway's buckets and usage counters in Redis
+const limiterKeyPrefix = "api-gateway:ratelimit"
+
+// usageRetention keeps daily usage long enough for the admin endpoint's history
+const usageRetention = 8 * 24 * time.Hour
+
+// limiterScript is an atomic token bucket plus daily usage hash.
+// KEYS[1] bucket hash, KEYS[2] daily usage hash (fields requests, throttled, overQuota)
+// ARGV rate (tokens/s), burst, now (ms), daily quota (0 = unlimited), usage TTL (s),
+// track usage (1/0)
+// Returns {1, used, remaining} when allowed, {0, waitMs, 0} when throttled and
+// {-1, used, 0} when over quota.
+var limiterScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local quota = tonumber(ARGV[4])
+local ttl = tonumber(ARGV[5])
+local track = ARGV[6] == '1'
+
+local function count(field)
+	if not track then
+		return 0
+	end
+	local n = redis.call('HINCRBY', KEYS[2], field, 1)
+	redis.call('EXPIRE', KEYS[2], ttl)
+	return n
+end
+
+local used = tonumber(redis.call('HGET', KEYS[2], 'requests') or '0')
+if quota > 0 and used >= quota then
+	count('overQuota')
+	return {-1, used, 0}
+end
+
+local b = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(b[1])
+local ts = tonumber(b[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+if tokens < 1 then
+	redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+	redis.call('PEXPIRE', KEYS[1], 60000)
+	count('throttled')
+	return {0, math.ceil((1 - tokens) * 1000 / rate), 0}
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens - 1, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], 60000)
+return {1, count('requests'), math.floor(tokens - 1)}
+`)
+
+// Limit is the token bucket and quota applied to one subject (an API key or client IP)
+type Limit struct {
+	Rate       float64 // requests per second
+	Burst      int     // bucket size
+	DailyQuota int64   // requests per KST day (0 = unlimited)
+}
+
+// Decision is the outcome of Limiter.Allow
+type Decision struct {
+	Allowed    bool
+	OverQuota  bool
+	Remaining  int           // tokens left after this request
+	RetryAfter time.Duration // when throttled or over quota
+}
+
+// Usage is one subject's counters for a day
+type Usage struct {
+	Requests  int64 `json:"requests"`
+	Throttled int64 `json:"throttled"`
+	OverQuota int64 `json:"overQuota"`
+}
+
+// localBucket is the in-process fallback used when Redis is not reachable
+type localBucket struct {
+	day    string
+	usage  Usage
+	tokens float64
+	ts     time.Time
+}
+
+// Limiter enforces per-subject token buckets and daily quotas. State lives in Redis so
+// every gateway replica shares one budget per key; without Redis, or while it is
+// unreachable, it fails open to process-local buckets and counters.
+type Limiter struct {
+	rdb *redis.Client // nil for local only
+
+	mu    sync.Mutex
+	local map[string]*localBucket
+}
+
+func NewLimiter(rdb *redis.Client) *Limiter {
+	return &Limiter{rdb: rdb, local: make(map[string]*localBucket)}
+}
+
+// usageDay returns the current quota day in KST, when partner quotas reset
+func usageDay(now time.Time) string {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		loc = time.FixedZone("KST", 9*60*60)
+	}
+	return now.In(loc).Format("20060102")
+}
+
+// untilNextDay is the Retry-After for an exhausted daily quota
+func untilNextDay(now time.Time) time.Duration {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		loc = time.FixedZone("KST", 9*60*60)
+	}
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+	return midnight.Sub(now)
+}
+
+func bucketKey(subject string) string {
+	return fmt.Sprintf("%s:%s:bucket", limiterKeyPrefix, subject)
+}
+
+func usageKey(subject, day string) string {
+	return fmt.Sprintf("%s:%s:usage:%s", limiterKeyPrefix, subject, day)
+}
+
+// Allow takes one token for subject; trackUsage records daily counters (API keys only,
+// so per-IP limiting does not leave a hash per client behind)
+func (l *Limiter) Allow(ctx context.Context, subject string, limit Limit, trackUsage bool) Decision {
+	now := time.Now()
+	day := usageDay(now)
+
+	if l.rdb != nil {
+		track := "0"
+		if trackUsage {
+			track = "1"
+		}
+		res, err := limiterScript.Run(ctx, l.rdb,
+			[]string{bucketKey(subject), usageKey(subject, day)},
+			limit.Rate, limit.Burst, now.UnixMilli(), limit.DailyQuota, int(usageRetention.Seconds()), track,
+		).Int64Slice()
+		if err == nil && len(res) == 3 {
+			switch res[0] {
+			case 1:
+				return Decision{Allowed: true, Remaining: int(res[2])}
+			case -1:
+				return Decision{OverQuota: true, RetryAfter: untilNextDay(now)}
+			default:
+				return Decision{RetryAfter: time.Duration(res[1]) * time.Millisecond}
+			}
+		}
+		if err == nil {
+			err = fmt.Errorf("unexpected script result %v", res)
+		}
+		slog.Warn("Redis unavailable, using local rate limiter", "error", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.local[subject]
+	if !ok {
+		b = &localBucket{tokens: float64(limit.Burst), ts: now}
+		l.local[subject] = b
+	}
+	if b.day != day {
+		b.day = day
+		b.usage = Usage{}
+	}
+	if limit.DailyQuota > 0 && b.usage.Requests >= limit.DailyQuota {
+		if trackUsage {
+			b.usage.OverQuota++
+		}
+		return Decision{OverQuota: true, RetryAfter: untilNextDay(now)}
+	}
+
+	elapsed := now.Sub(b.ts).Seconds()
+	b.ts = now
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+math.Max(0, elapsed)*limit.Rate)
+	if b.tokens < 1 {
+		if trackUsage {
+			b.usage.Throttled++
+		}
+		return Decision{RetryAfter: time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))}
+	}
+
+	b.tokens--
+	if trackUsage {
+		b.usage.Requests++
+	}
+	return Decision{Allowed: true, Remaining: int(b.tokens)}
+}
+
+// Usage returns subject's counters for day (YYYYMMDD, KST); the local counters only
+// cover today
+func (l *Limiter) Usage(ctx context.Context, subject, day string) Usage {
+	if l.rdb != nil {
+		values, err := l.rdb.HGetAll(ctx, usageKey(subject, day)).Result()
+		if err == nil {
+			var u Usage
+			u.Requests, _ = strconv.ParseInt(values["requests"], 10, 64)
+			u.Throttled, _ = strconv.ParseInt(values["throttled"], 10, 64)
+			u.OverQuota, _ = strconv.ParseInt(values["overQuota"], 10, 64)
+			return u
+		}
+		slog.Warn("Redis unavailable, reporting local usage", "error", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.local[subject]; ok && b.day == day {
+		return b.usage
+	}
+	return Usage{}
+}
+
+// Sweep drops idle local buckets (mostly per-IP) until ctx is cancelled
+func (l *Limiter) Sweep(ctx context.Context, idle time.Duration) {
+	ticker := time.NewTicker(idle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cutoff := time.Now().Add(-idle)
+		day := usageDay(time.Now())
+		l.mu.Lock()
+		for subject, b := range l.local {
+			// Keep today's counters for subjects that have any, so usage stays reportable
+			if b.ts.Before(cutoff) && (b.day != day || b.usage == (Usage{})) {
+				delete(l.local, subject)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+var rateLimitRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_access_requests_total",
+	Help: "Access control decisions by client type (key, anonymous) and result.",
+}, []string{"client", "result"})
+
+func init() {
+	prometheus.MustRegister(rateLimitRequests)
+}
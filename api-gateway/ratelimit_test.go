@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestUsageDay(t *testing.T) {
+	tests := []struct {
+		now       time.Time
+		want      string
+		wantUntil time.Duration
+	}{
+		{now: time.Date(2025, 11, 17, 14, 0, 0, 0, time.UTC), want: "20251117", wantUntil: time.Hour},
+		{now: time.Date(2025, 11, 17, 15, 0, 0, 0, time.UTC), want: "20251118", wantUntil: 24 * time.Hour}, // KST midnight
+		{now: time.Date(2025, 12, 31, 14, 59, 30, 0, time.UTC), want: "20251231", wantUntil: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := usageDay(tt.now); got != tt.want {
+			t.Errorf("usageDay(%v) = %s, want %s", tt.now, got, tt.want)
+		}
+		if got := untilNextDay(tt.now); got != tt.wantUntil {
+			t.Errorf("untilNextDay(%v) = %v, want %v", tt.now, got, tt.wantUntil)
+		}
+	}
+}
+
+// The local buckets are what runs without Redis; the Lua script implements the same
+// bucket in Redis
+func TestLimiterLocal(t *testing.T) {
+	type step struct {
+		allowed   bool
+		overQuota bool
+		remaining int
+	}
+	tests := []struct {
+		name  string
+		limit Limit
+		track bool
+		steps []step
+		usage Usage
+	}{
+		{
+			name:  "burst then throttle",
+			limit: Limit{Rate: 0.001, Burst: 2},
+			track: true,
+			steps: []step{{allowed: true, remaining: 1}, {allowed: true, remaining: 0}, {}, {}},
+			usage: Usage{Requests: 2, Throttled: 2},
+		},
+		{
+			name:  "daily quota",
+			limit: Limit{Rate: 1000, Burst: 1000, DailyQuota: 2},
+			track: true,
+			steps: []step{{allowed: true, remaining: 999}, {allowed: true, remaining: 998}, {overQuota: true}},
+			usage: Usage{Requests: 2, OverQuota: 1},
+		},
+		{
+			name:  "untracked subjects keep no usage",
+			limit: Limit{Rate: 0.001, Burst: 1},
+			steps: []step{{allowed: true}, {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(nil)
+			ctx := context.Background()
+			for i, s := range tt.steps {
+				d := l.Allow(ctx, "subject", tt.limit, tt.track)
+				if d.Allowed != s.allowed || d.OverQuota != s.overQuota || (d.Allowed && d.Remaining != s.remaining) {
+					t.Fatalf("step %d: decision = %+v, want %+v", i, d, s)
+				}
+				if !d.Allowed && d.RetryAfter <= 0 {
+					t.Errorf("step %d: refused without Retry-After", i)
+				}
+			}
+			if got := l.Usage(ctx, "subject", usageDay(time.Now())); got != tt.usage {
+				t.Errorf("usage = %+v, want %+v", got, tt.usage)
+			}
+			if got := l.Usage(ctx, "subject", "20000101"); got != (Usage{}) {
+				t.Errorf("usage of another day = %+v", got)
+			}
+		})
+	}
+}
+
+func TestLimiterRefills(t *testing.T) {
+	l := NewLimiter(nil)
+	limit := Limit{Rate: 10, Burst: 1}
+	ctx := context.Background()
+
+	if !l.Allow(ctx, "subject", limit, false).Allowed {
+		t.Fatal("first request refused")
+	}
+	d := l.Allow(ctx, "subject", limit, false)
+	if d.Allowed || d.RetryAfter > 100*time.Millisecond {
+		t.Fatalf("second request = %+v, want a retry within 100ms", d)
+	}
+	time.Sleep(d.RetryAfter + 10*time.Millisecond)
+	if !l.Allow(ctx, "subject", limit, false).Allowed {
+		t.Error("request after Retry-After refused")
+	}
+}
+
+func TestLimiterFailsOpenWithoutRedis(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	defer rdb.Close()
+
+	l := NewLimiter(rdb)
+	ctx := context.Background()
+	if d := l.Allow(ctx, "key:partner", Limit{Rate: 1, Burst: 1}, true); !d.Allowed {
+		t.Fatalf("decision = %+v, want the local bucket to allow", d)
+	}
+	if d := l.Allow(ctx, "key:partner", Limit{Rate: 1, Burst: 1}, true); d.Allowed {
+		t.Fatalf("decision = %+v, want the local bucket to throttle", d)
+	}
+	if u := l.Usage(ctx, "key:partner", usageDay(time.Now())); u.Requests != 1 || u.Throttled != 1 {
+		t.Errorf("usage = %+v, want the local counters", u)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+)
+
+// gatewayStarted is reported as the gateway instance's start time
+var gatewayStarted = time.Now()
+
+// clusterStatusHandler serves GET /api/v1/cluster/status (and its deprecated
+// /api/cluster/status alias): data-api-service's view of the
+// deployment (instances, collector leader, processor consumers, Redis/MariaDB) plus the
+// gateway instance that answered, which has no heartbeat of its own
+func (g *Gateway) clusterStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/cluster/status" {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", `</api/v1/cluster/status>; rel="successor-version"`)
+	}
+
+	// The balancer picks which data-api-service answers
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet,
+		g.balancer.config.Upstreams[0].URL.JoinPath("/api/v1/cluster/status").String(), nil)
+	if err != nil {
+		g.dataAPIProxy.ErrorHandler(w, r, err)
+		return
+	}
+	req.Header.Set(requestIDHeader, r.Header.Get(requestIDHeader))
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		g.dataAPIProxy.ErrorHandler(w, r, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var status map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		g.dataAPIProxy.ErrorHandler(w, r, err)
+		return
+	}
+
+	pod, _ := os.Hostname()
+	instances, _ := status["instances"].([]interface{})
+	status["instances"] = append(instances, map[string]interface{}{
+		"service":   "api-gateway",
+		"cluster":   os.Getenv("CLUSTER_NAME"),
+		"pod":       pod,
+		"startedAt": gatewayStarted,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
+	}
+}
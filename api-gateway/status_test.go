@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClusterStatusAddsGateway(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/cluster/status" || r.Header.Get(requestIDHeader) != "req-1" {
+			t.Errorf("upstream got %s with request ID %q", r.URL.Path, r.Header.Get(requestIDHeader))
+		}
+		w.Write([]byte(`{"instances":[{"service":"data-api-service","cluster":"member1"}],"leader":null}`))
+	}))
+	defer upstream.Close()
+
+	t.Setenv("CLUSTER_NAME", "member2")
+	g, err := NewGateway(Config{DataAPIServiceURL: upstream.URL, Access: AccessConfig{AllowAnonymous: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path       string
+		deprecated bool
+	}{
+		{path: "/api/v1/cluster/status"},
+		{path: "/api/cluster/status", deprecated: true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		r.Header.Set(requestIDHeader, "req-1")
+		w := httptest.NewRecorder()
+		g.clusterStatusHandler(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", tt.path, w.Code, w.Body)
+		}
+		if got := w.Header().Get("Deprecation") == "true"; got != tt.deprecated {
+			t.Errorf("%s: deprecated = %v, want %v", tt.path, got, tt.deprecated)
+		}
+
+		var status struct {
+			Instances []map[string]interface{} `json:"instances"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		if len(status.Instances) != 2 {
+			t.Fatalf("%s: instances = %v, want the upstream's plus the gateway", tt.path, status.Instances)
+		}
+		if gw := status.Instances[1]; gw["service"] != "api-gateway" || gw["cluster"] != "member2" {
+			t.Errorf("%s: gateway instance = %v", tt.path, gw)
+		}
+	}
+}
+
+func TestClusterStatusUpstreamDown(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer upstream.Close()
+
+	g, err := NewGateway(Config{DataAPIServiceURL: upstream.URL, Access: AccessConfig{AllowAnonymous: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	g.clusterStatusHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/cluster/status", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", w.Code)
+	}
+}
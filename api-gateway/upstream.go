@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// errNoUpstream is returned when every upstream has been tried for a request
+var errNoUpstream = errors.New("no upstream available")
+
+// UpstreamConfig is one data-api-service instance behind the gateway
+type UpstreamConfig struct {
+	URL     *url.URL
+	Weight  int
+	Cluster string // upstreams in the gateway's own cluster are preferred
+}
+
+// BalancerConfig configures health checking, outlier ejection and retries
+type BalancerConfig struct {
+	Upstreams           []UpstreamConfig
+	LocalCluster        string
+	HealthCheckPath     string
+	HealthCheckInterval time.Duration
+	EjectAfter          int           // consecutive failures before ejection
+	EjectFor            time.Duration // how long an ejected upstream is skipped
+	Retries             int           // extra attempts on other upstreams for GET/HEAD
+	FailureCodes        []int         // response codes that count as failures and are retried
+}
+
+// defaultFailureCodes are the responses of a broken or overloaded upstream. 500 is
+// included because data-api-service answers 500 when its database is unreachable.
+var defaultFailureCodes = []int{
+	http.StatusInternalServerError, http.StatusBadGateway,
+	http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+}
+
+// parseStatusCodes reads comma-separated HTTP status codes; an empty list gives
+// defaultFailureCodes
+func parseStatusCodes(list string) ([]int, error) {
+	var codes []int
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		code, err := strconv.Atoi(field)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid status code %q", field)
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return defaultFailureCodes, nil
+	}
+	return codes, nil
+}
+
+// parseUpstreams reads "url|weight|cluster" entries separated by commas; weight and
+// cluster are optional (e.g. "http://a:8080|10|cp-plugfest-member1,http://b:8080")
+func parseUpstreams(list string) ([]UpstreamConfig, error) {
+	var upstreams []UpstreamConfig
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		fields := strings.Split(entry, "|")
+
+		u, err := url.Parse(strings.TrimSpace(fields[0]))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid upstream URL %q", fields[0])
+		}
+		upstream := UpstreamConfig{URL: u, Weight: 1}
+		if len(fields) > 1 && strings.TrimSpace(fields[1]) != "" {
+			w, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+			if err != nil || w < 1 {
+				return nil, fmt.Errorf("invalid weight for upstream %s: %q", u, fields[1])
+			}
+			upstream.Weight = w
+		}
+		if len(fields) > 2 {
+			upstream.Cluster = strings.TrimSpace(fields[2])
+		}
+		upstreams = append(upstreams, upstream)
+	}
+	if len(upstreams) == 0 {
+		return nil, errors.New("no upstreams configured")
+	}
+	return upstreams, nil
+}
+
+// upstream is the runtime state of one UpstreamConfig
+type upstream struct {
+	UpstreamConfig
+
+	mu           sync.Mutex
+	healthy      bool // last active health check result
+	failures     int  // consecutive passive failures
+	ejectedUntil time.Time
+}
+
+func (u *upstream) available(now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.healthy && now.After(u.ejectedUntil)
+}
+
+// UpstreamState is the /info view of an upstream
+type UpstreamState struct {
+	URL          string     `json:"url"`
+	Cluster      string     `json:"cluster,omitempty"`
+	Weight       int        `json:"weight"`
+	Healthy      bool       `json:"healthy"`
+	EjectedUntil *time.Time `json:"ejectedUntil,omitempty"`
+}
+
+// Balancer is an http.RoundTripper that spreads requests over several data-api-service
+// instances. It prefers healthy upstreams in the local cluster, picks among them by
+// weight, ejects upstreams that keep failing and retries idempotent requests on another
+// upstream, so the gateway fails over on its own without relying on the mesh.
+type Balancer struct {
+	config    BalancerConfig
+	upstreams []*upstream
+	transport http.RoundTripper
+	checker   *http.Client
+
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+func NewBalancer(config BalancerConfig, transport http.RoundTripper) *Balancer {
+	if len(config.FailureCodes) == 0 {
+		config.FailureCodes = defaultFailureCodes
+	}
+	b := &Balancer{
+		config:    config,
+		transport: transport,
+		checker:   &http.Client{Timeout: 2 * time.Second},
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, cfg := range config.Upstreams {
+		// Upstreams start healthy so traffic flows before the first check completes
+		b.upstreams = append(b.upstreams, &upstream{UpstreamConfig: cfg, healthy: true})
+		upstreamHealthy.WithLabelValues(cfg.URL.String()).Set(1)
+	}
+	return b
+}
+
+// pick returns an upstream not in tried: the local cluster's available upstreams first,
+// then any available upstream, and when all are down or ejected any untried upstream
+// (trying a suspect upstream beats failing outright)
+func (b *Balancer) pick(tried map[*upstream]bool) *upstream {
+	now := time.Now()
+	var local, remote, fallback []*upstream
+	for _, u := range b.upstreams {
+		if tried[u] {
+			continue
+		}
+		switch {
+		case !u.available(now):
+			fallback = append(fallback, u)
+		case b.config.LocalCluster != "" && u.Cluster == b.config.LocalCluster:
+			local = append(local, u)
+		default:
+			remote = append(remote, u)
+		}
+	}
+
+	for _, tier := range [][]*upstream{local, remote, fallback} {
+		if len(tier) > 0 {
+			return b.weighted(tier)
+		}
+	}
+	return nil
+}
+
+// weighted picks one upstream with probability proportional to its weight
+func (b *Balancer) weighted(candidates []*upstream) *upstream {
+	total := 0
+	for _, u := range candidates {
+		total += u.Weight
+	}
+
+	b.randMu.Lock()
+	n := b.rand.Intn(total)
+	b.randMu.Unlock()
+
+	for _, u := range candidates {
+		if n < u.Weight {
+			return u
+		}
+		n -= u.Weight
+	}
+	return candidates[len(candidates)-1]
+}
+
+// observe feeds a request outcome into passive outlier detection
+func (b *Balancer) observe(u *upstream, failed bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !failed {
+		u.failures = 0
+		return
+	}
+	u.failures++
+	if u.failures >= b.config.EjectAfter && time.Now().After(u.ejectedUntil) {
+		u.ejectedUntil = time.Now().Add(b.config.EjectFor)
+		u.failures = 0
+		upstreamEjections.WithLabelValues(u.URL.String()).Inc()
+		slog.Warn("upstream ejected", "upstream", u.URL.String(), "for", b.config.EjectFor)
+	}
+}
+
+// retryable reports whether a request may be sent again to another upstream
+func retryable(req *http.Request) bool {
+	return (req.Method == http.MethodGet || req.Method == http.MethodHead) &&
+		(req.Body == nil || req.Body == http.NoBody)
+}
+
+// failedStatus marks responses that count against an upstream
+func (c BalancerConfig) failedStatus(code int) bool {
+	return slices.Contains(c.FailureCodes, code)
+}
+
+// RoundTrip implements http.RoundTripper. Only the scheme and host of req.URL are
+// replaced; the path and query are kept.
+func (b *Balancer) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := 1
+	if retryable(req) {
+		attempts += b.config.Retries
+	}
+
+	tried := make(map[*upstream]bool)
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		u := b.pick(tried)
+		if u == nil {
+			break
+		}
+		tried[u] = true
+		if attempt > 0 {
+			upstreamRetries.Inc()
+		}
+
+		out := req.Clone(req.Context())
+		out.URL.Scheme = u.URL.Scheme
+		out.URL.Host = u.URL.Host
+		out.Host = u.URL.Host
+
+		resp, err := b.transport.RoundTrip(out)
+		if err != nil {
+			// A cancelled client is not the upstream's fault
+			if req.Context().Err() != nil {
+				return nil, err
+			}
+			b.observe(u, true)
+			lastErr = fmt.Errorf("%s: %w", u.URL.Host, err)
+			continue
+		}
+
+		failed := b.config.failedStatus(resp.StatusCode)
+		b.observe(u, failed)
+		if failed && attempt < attempts-1 && len(tried) < len(b.upstreams) {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("%s: %s", u.URL.Host, resp.Status)
+			continue
+		}
+		return resp, nil
+	}
+
+	if lastErr == nil {
+		lastErr = errNoUpstream
+	}
+	return nil, lastErr
+}
+
+// RunHealthChecks probes every upstream until ctx is cancelled
+func (b *Balancer) RunHealthChecks(ctx context.Context) {
+	ticker := time.NewTicker(b.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, u := range b.upstreams {
+			go b.check(ctx, u)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Balancer) check(ctx context.Context, u *upstream) {
+	healthy := false
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.URL.JoinPath(b.config.HealthCheckPath).String(), nil)
+	if err == nil {
+		if resp, err := b.checker.Do(req); err == nil {
+			resp.Body.Close()
+			healthy = resp.StatusCode == http.StatusOK
+		}
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	u.mu.Lock()
+	changed := u.healthy != healthy
+	u.healthy = healthy
+	u.mu.Unlock()
+
+	if healthy {
+		upstreamHealthy.WithLabelValues(u.URL.String()).Set(1)
+	} else {
+		upstreamHealthy.WithLabelValues(u.URL.String()).Set(0)
+	}
+	if changed {
+		slog.Warn("upstream health changed", "upstream", u.URL.String(), "healthy", healthy)
+	}
+}
+
+// State returns the current view of every upstream
+func (b *Balancer) State() []UpstreamState {
+	now := time.Now()
+	states := make([]UpstreamState, 0, len(b.upstreams))
+	for _, u := range b.upstreams {
+		u.mu.Lock()
+		state := UpstreamState{
+			URL:     u.URL.String(),
+			Cluster: u.Cluster,
+			Weight:  u.Weight,
+			Healthy: u.healthy,
+		}
+		if now.Before(u.ejectedUntil) {
+			until := u.ejectedUntil
+			state.EjectedUntil = &until
+		}
+		u.mu.Unlock()
+		states = append(states, state)
+	}
+	return states
+}
+
+var (
+	upstreamHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_upstream_healthy",
+		Help: "Result of the last active health check per upstream (1 healthy, 0 unhealthy).",
+	}, []string{"upstream"})
+
+	upstreamEjections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_upstream_ejections_total",
+		Help: "Upstreams ejected after consecutive failures.",
+	}, []string{"upstream"})
+
+	upstreamRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gateway_upstream_retries_total",
+		Help: "Requests retried on another upstream.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(upstreamHealthy, upstreamEjections, upstreamRetries)
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func mustURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestParseUpstreams(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []string // "url|weight|cluster"
+		wantErr bool
+	}{
+		{list: "http://a:8080", want: []string{"http://a:8080|1|"}},
+		{list: " http://a:8080|10|member1 , http://b:8080||member2,", want: []string{"http://a:8080|10|member1", "http://b:8080|1|member2"}},
+		{list: "http://a:8080|3", want: []string{"http://a:8080|3|"}},
+		{list: "", wantErr: true},
+		{list: "a:8080", wantErr: true},
+		{list: "http://a:8080|0", wantErr: true},
+		{list: "http://a:8080|heavy", wantErr: true},
+	}
+	for _, tt := range tests {
+		upstreams, err := parseUpstreams(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseUpstreams(%q) error = %v, want error %v", tt.list, err, tt.wantErr)
+			continue
+		}
+		var got []string
+		for _, u := range upstreams {
+			got = append(got, strings.Join([]string{u.URL.String(), strconv.Itoa(u.Weight), u.Cluster}, "|"))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseUpstreams(%q) = %v, want %v", tt.list, got, tt.want)
+		}
+	}
+}
+
+func TestParseStatusCodes(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []int
+		wantErr bool
+	}{
+		{list: "", want: defaultFailureCodes},
+		{list: " , ", want: defaultFailureCodes},
+		{list: "502, 503", want: []int{502, 503}},
+		{list: "429,500", want: []int{429, 500}},
+		{list: "99", wantErr: true},
+		{list: "600", wantErr: true},
+		{list: "5xx", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseStatusCodes(tt.list)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseStatusCodes(%q) = %v, %v; want %v, error %v", tt.list, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFailedStatus(t *testing.T) {
+	tests := []struct {
+		codes []int
+		code  int
+		want  bool
+	}{
+		{codes: defaultFailureCodes, code: 500, want: true},
+		{codes: defaultFailureCodes, code: 503, want: true},
+		{codes: defaultFailureCodes, code: 404, want: false},
+		{codes: defaultFailureCodes, code: 200, want: false},
+		{codes: []int{502}, code: 500, want: false},
+		{codes: []int{429}, code: 429, want: true},
+	}
+	for _, tt := range tests {
+		if got := (BalancerConfig{FailureCodes: tt.codes}).failedStatus(tt.code); got != tt.want {
+			t.Errorf("failedStatus(%d) with %v = %v, want %v", tt.code, tt.codes, got, tt.want)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	newBalancer := func() *Balancer {
+		return NewBalancer(BalancerConfig{
+			LocalCluster: "member1",
+			Upstreams: []UpstreamConfig{
+				{URL: mustURL(t, "http://local:8080"), Weight: 1, Cluster: "member1"},
+				{URL: mustURL(t, "http://remote:8080"), Weight: 1, Cluster: "member2"},
+			},
+		}, nil)
+	}
+	host := func(u *upstream) string {
+		if u == nil {
+			return ""
+		}
+		return u.URL.Host
+	}
+
+	tests := []struct {
+		name  string
+		setup func(b *Balancer) map[*upstream]bool // returns the upstreams already tried
+		want  string
+	}{
+		{name: "local first", setup: func(b *Balancer) map[*upstream]bool { return nil }, want: "local:8080"},
+		{name: "remote after local was tried", setup: func(b *Balancer) map[*upstream]bool {
+			return map[*upstream]bool{b.upstreams[0]: true}
+		}, want: "remote:8080"},
+		{name: "remote while local is ejected", setup: func(b *Balancer) map[*upstream]bool {
+			b.upstreams[0].ejectedUntil = time.Now().Add(time.Minute)
+			return nil
+		}, want: "remote:8080"},
+		{name: "remote while local is unhealthy", setup: func(b *Balancer) map[*upstream]bool {
+			b.upstreams[0].healthy = false
+			return nil
+		}, want: "remote:8080"},
+		{name: "suspect upstream beats none", setup: func(b *Balancer) map[*upstream]bool {
+			b.upstreams[0].healthy = false
+			return map[*upstream]bool{b.upstreams[1]: true}
+		}, want: "local:8080"},
+		{name: "all tried", setup: func(b *Balancer) map[*upstream]bool {
+			return map[*upstream]bool{b.upstreams[0]: true, b.upstreams[1]: true}
+		}, want: ""},
+	}
+	for _, tt := range tests {
+		b := newBalancer()
+		tried := tt.setup(b)
+		if got := host(b.pick(tried)); got != tt.want {
+			t.Errorf("%s: picked %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	b := NewBalancer(BalancerConfig{Upstreams: []UpstreamConfig{
+		{URL: mustURL(t, "http://a:8080"), Weight: 9},
+		{URL: mustURL(t, "http://b:8080"), Weight: 1},
+	}}, nil)
+
+	picks := make(map[string]int)
+	for i := 0; i < 2000; i++ {
+		picks[b.weighted(b.upstreams).URL.Host]++
+	}
+	if a := picks["a:8080"]; a < 1700 || a > 1900 {
+		t.Errorf("a picked %d/2000 times, want about 1800", a)
+	}
+}
+
+func TestObserveEjects(t *testing.T) {
+	b := NewBalancer(BalancerConfig{
+		Upstreams:  []UpstreamConfig{{URL: mustURL(t, "http://a:8080"), Weight: 1}},
+		EjectAfter: 3, EjectFor: time.Minute,
+	}, nil)
+	u := b.upstreams[0]
+
+	outcomes := []struct {
+		failed    bool
+		available bool
+	}{
+		{failed: true, available: true},
+		{failed: true, available: true},
+		{failed: false, available: true}, // a success resets the run
+		{failed: true, available: true},
+		{failed: true, available: true},
+		{failed: true, available: false},
+	}
+	for i, o := range outcomes {
+		b.observe(u, o.failed)
+		if got := u.available(time.Now()); got != o.available {
+			t.Fatalf("after outcome %d: available = %v, want %v", i, got, o.available)
+		}
+	}
+	if !u.available(time.Now().Add(2 * time.Minute)) {
+		t.Error("upstream still ejected after EjectFor")
+	}
+	if state := b.State()[0]; state.EjectedUntil == nil {
+		t.Errorf("state = %+v, want the ejection shown", state)
+	}
+}
+
+func TestRoundTripRetries(t *testing.T) {
+	var badHits, goodHits atomic.Int32
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		badHits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		goodHits.Add(1)
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer good.Close()
+
+	tests := []struct {
+		name     string
+		method   string
+		retries  int
+		codes    []int
+		want     int
+		wantBad  int32
+		wantGood int32
+	}{
+		{name: "GET retried on another upstream", method: http.MethodGet, retries: 1, want: 200, wantBad: 1, wantGood: 1},
+		{name: "POST not retried", method: http.MethodPost, retries: 1, want: 503, wantBad: 1},
+		{name: "no retries configured", method: http.MethodGet, want: 503, wantBad: 1},
+		{name: "503 not a failure code", method: http.MethodGet, retries: 1, codes: []int{500}, want: 503, wantBad: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			badHits.Store(0)
+			goodHits.Store(0)
+			b := NewBalancer(BalancerConfig{
+				LocalCluster: "member1",
+				Upstreams: []UpstreamConfig{
+					{URL: mustURL(t, bad.URL), Weight: 1, Cluster: "member1"},
+					{URL: mustURL(t, good.URL), Weight: 1, Cluster: "member2"},
+				},
+				Retries: tt.retries, FailureCodes: tt.codes, EjectAfter: 5, EjectFor: time.Minute,
+			}, http.DefaultTransport)
+
+			req := httptest.NewRequest(tt.method, "http://placeholder/api/v1/accidents", nil)
+			req.RequestURI = ""
+			resp, err := b.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want || badHits.Load() != tt.wantBad || goodHits.Load() != tt.wantGood {
+				t.Errorf("status = %d with %d/%d hits on bad/good, want %d with %d/%d",
+					resp.StatusCode, badHits.Load(), goodHits.Load(), tt.want, tt.wantBad, tt.wantGood)
+			}
+		})
+	}
+}
+
+func TestRoundTripAllDown(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	b := NewBalancer(BalancerConfig{
+		Upstreams: []UpstreamConfig{{URL: mustURL(t, down.URL), Weight: 1}},
+		Retries:   2, EjectAfter: 1, EjectFor: time.Minute,
+	}, http.DefaultTransport)
+
+	req := httptest.NewRequest(http.MethodGet, "http://placeholder/health", nil)
+	req.RequestURI = ""
+	if _, err := b.RoundTrip(req); err == nil || !strings.Contains(err.Error(), mustURL(t, down.URL).Host) {
+		t.Errorf("error = %v, want the failing upstream named", err)
+	}
+	if b.upstreams[0].available(time.Now()) {
+		t.Error("failing upstream not ejected")
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("health check path = %s", r.URL.Path)
+		}
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer srv.Close()
+
+	b := NewBalancer(BalancerConfig{
+		Upstreams:       []UpstreamConfig{{URL: mustURL(t, srv.URL), Weight: 1}},
+		HealthCheckPath: "/health",
+	}, nil)
+	u := b.upstreams[0]
+
+	for _, tt := range []struct {
+		status int32
+		want   bool
+	}{
+		{status: http.StatusServiceUnavailable, want: false},
+		{status: http.StatusOK, want: true},
+	} {
+		status.Store(tt.status)
+		b.check(context.Background(), u)
+		if got := u.available(time.Now()); got != tt.want {
+			t.Errorf("after a %d check: available = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
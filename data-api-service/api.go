@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// apiV1 prefixes the versioned public API. The unversioned /api paths stay as
+// deprecated aliases of v1 until the frontend and partners have moved.
+const apiV1 = "/api/v1"
+
+// Error codes of APIError; clients branch on these, not on Message
+const (
+	errBadRequest       = "bad_request"
+	errNotFound         = "not_found"
+	errMethodNotAllowed = "method_not_allowed"
+	errInternal         = "internal"
+)
+
+// APIError is the body of every error response of the public API
+type APIError struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// writeError writes an APIError; details is omitted when nil
+func writeError(w http.ResponseWriter, status int, code, message string, details interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(APIError{Code: code, Message: message, Details: details})
+}
+
+// allowGet answers preflights and rejects methods other than GET; it reports whether the
+// handler should go on
+func allowGet(w http.ResponseWriter, r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet:
+		return true
+	case http.MethodOptions:
+		// CORS is handled by the API Gateway
+		w.WriteHeader(http.StatusOK)
+		return false
+	}
+	w.Header().Set("Allow", "GET, OPTIONS")
+	writeError(w, http.StatusMethodNotAllowed, errMethodNotAllowed, "method not allowed",
+		map[string]interface{}{"method": r.Method, "allowed": []string{http.MethodGet}})
+	return false
+}
+
+// apiParam is a query or path parameter of an apiRoute, for the OpenAPI document
+type apiParam struct {
+	Name        string
+	Type        string // OpenAPI type: integer, string, ...
+	Description string
+	// InPath marks a {name} segment of the route's Path
+	InPath bool
+}
+
+// apiRoute is one public endpoint, served at apiV1+Path and at the deprecated
+// "/api"+Path alias. Response is the JSON body type; the OpenAPI document is generated
+// from it, so the contract follows the Go types. A Path with {param} segments is served
+// by the subtree up to the first of them, and the handler parses the rest.
+type apiRoute struct {
+	Path     string
+	Summary  string
+	Handler  http.HandlerFunc
+	Response reflect.Type
+	// MediaType of the JSON Response (default application/json)
+	MediaType string
+	Params    []apiParam
+	// Files lists the media types of a file download, instead of a JSON Response
+	Files []string
+	// NoAlias skips the unversioned alias; endpoints added after v1 have none
+	NoAlias bool
+}
+
+func (s *Server) apiRoutes() []apiRoute {
+	routes := []apiRoute{
+		{
+			Path:     "/accidents/latest",
+			Summary:  "Accidents of the last 24 hours, newest first",
+			Handler:  s.getLatestAccidents,
+			Response: reflect.TypeOf([]Accident{}),
+			Params:   []apiParam{{Name: "limit", Type: "integer", Description: "Maximum number of accidents (1-1000, default 100)"}},
+		},
+		{
+			Path:     "/accidents/stats",
+			Summary:  "Total and today's accident counts and counts by type over the last 3 hours",
+			Handler:  s.getAccidentStats,
+			Response: reflect.TypeOf(AccidentStats{}),
+		},
+		{
+			Path:     "/tollgate/traffic",
+			Summary:  "Tollgate traffic of the 3 hours up to the latest collection, per tollgate",
+			Handler:  s.getTollgateTraffic,
+			Response: reflect.TypeOf([]TollgateTraffic{}),
+		},
+		{
+			Path:     "/road/status",
+			Summary:  "Latest traffic status per route and conzone",
+			Handler:  s.getRoadStatus,
+			Response: reflect.TypeOf([]LocatedRoadStatus{}),
+		},
+		{
+			Path:     "/road/summary",
+			Summary:  "Latest congestion summary per route",
+			Handler:  s.getRoadRouteSummary,
+			Response: reflect.TypeOf([]RoadRouteSummary{}),
+		},
+		{
+			Path:     "/cluster/status",
+			Summary:  "Running instances, collector leader, processor consumers and backend reachability",
+			Handler:  s.clusterStatusHandler,
+			Response: reflect.TypeOf(ClusterStatus{}),
+		},
+	}
+	routes = append(routes,
+		apiRoute{
+			Path:      "/geo/accidents",
+			Summary:   "Accidents of the last 24 hours with coordinates, as GeoJSON points",
+			Handler:   s.getAccidentFeatures,
+			Response:  reflect.TypeOf(FeatureCollection{}),
+			MediaType: geoJSONContentType,
+			NoAlias:   true,
+			Params:    []apiParam{{Name: "limit", Type: "integer", Description: "Maximum number of accidents (1-1000, default 1000)"}},
+		},
+		apiRoute{
+			Path:      "/geo/tollgates",
+			Summary:   "Active tollgates with coordinates and their latest traffic, as GeoJSON points",
+			Handler:   s.getTollgateFeatures,
+			Response:  reflect.TypeOf(FeatureCollection{}),
+			MediaType: geoJSONContentType,
+			NoAlias:   true,
+		},
+		apiRoute{
+			Path:      "/geo/conzones",
+			Summary:   "Latest traffic status per conzone and direction as GeoJSON lines, colored by grade",
+			Handler:   s.getConzoneFeatures,
+			Response:  reflect.TypeOf(FeatureCollection{}),
+			MediaType: geoJSONContentType,
+			NoAlias:   true,
+			Params:    []apiParam{{Name: "route", Type: "string", Description: "Route numbers, comma separated (default: all)"}},
+		},
+		apiRoute{
+			Path:     "/road/incidents",
+			Summary:  "Sections at or above a congestion grade with the accidents matched to them and the speed change around each",
+			Handler:  s.getRoadIncidents,
+			Response: reflect.TypeOf([]SectionIncidents{}),
+			NoAlias:  true,
+			Params: []apiParam{
+				{Name: "route", Type: "string", Description: "Route numbers, comma separated (default: all)"},
+				{Name: "minGrade", Type: "integer", Description: "Lowest grade of the sections, 0-3 (default 2: 서행 and 정체)"},
+			},
+		},
+		apiRoute{
+			Path:    "/tiles/{z}/{x}/{y}.mvt",
+			Summary: "Mapbox vector tile of the " + congestionLayer + " layer: conzone lines with status, grade and color",
+			Handler: s.getCongestionTile,
+			Files:   []string{mvtContentType},
+			NoAlias: true,
+			Params: []apiParam{
+				{Name: "z", Type: "integer", Description: "Zoom (0-22)", InPath: true},
+				{Name: "x", Type: "integer", Description: "Tile column", InPath: true},
+				{Name: "y", Type: "integer", Description: "Tile row, from the north", InPath: true},
+			},
+		},
+	)
+	for _, d := range exportDatasets {
+		routes = append(routes, apiRoute{
+			Path:    "/export/" + d.Name,
+			Summary: d.Summary + ", as a file download streamed row by row",
+			Handler: s.exportHandler(d),
+			Files:   exportMediaTypes(),
+			NoAlias: true,
+			Params: []apiParam{
+				{Name: "from", Type: "string", Description: "Range start, RFC 3339 or YYYY-MM-DD in KST (default: to - 24h)"},
+				{Name: "to", Type: "string", Description: "Range end (exclusive), RFC 3339 or YYYY-MM-DD in KST, inclusive as a date (default: now)"},
+				{Name: "format", Type: "string", Description: "csv (default), xlsx or parquet"},
+			},
+		})
+	}
+	return routes
+}
+
+var deprecatedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "api_deprecated_requests_total",
+	Help: "Requests to unversioned /api aliases per path, to track migration to /api/v1.",
+}, []string{"path"})
+
+// deprecated serves h at an unversioned alias, pointing clients at the v1 successor
+// (RFC 8594 style Deprecation and Link headers)
+func deprecated(alias, successor string, h http.HandlerFunc) http.HandlerFunc {
+	requests := deprecatedRequests.WithLabelValues(alias)
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Inc()
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+		h(w, r)
+	}
+}
+
+// apiNotFound answers unknown /api/v1 paths with an APIError rather than the mux's text
+func apiNotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, errNotFound, "no such endpoint",
+		map[string]string{"path": r.URL.Path})
+}
+
+// registerAPI serves every apiRoute under /api/v1 and its deprecated alias, plus the
+// OpenAPI document and the GraphQL endpoint
+func (s *Server) registerAPI() error {
+	schema, err := s.graphqlSchema()
+	if err != nil {
+		return fmt.Errorf("graphql schema: %w", err)
+	}
+
+	routes := s.apiRoutes()
+	for _, route := range routes {
+		path, alias := apiV1+route.Path, "/api"+route.Path
+		pattern := path
+		if i := strings.Index(pattern, "{"); i >= 0 {
+			pattern = pattern[:i]
+		}
+		http.Handle(pattern, instrument(path, route.Handler))
+		if !route.NoAlias {
+			http.Handle(alias, instrument(alias, deprecated(alias, path, route.Handler)))
+		}
+	}
+	http.Handle(apiV1+"/openapi.json", instrument(apiV1+"/openapi.json", openAPIHandler(routes)))
+	http.Handle(graphqlPath, instrument(graphqlPath, s.graphqlHandler(schema)))
+	http.Handle(apiV1+"/", instrument(apiV1+"/", apiNotFound))
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name    string
+		details interface{}
+		want    string
+	}{
+		{name: "without details", want: `{"code":"bad_request","message":"invalid limit"}`},
+		{name: "with details", details: map[string]string{"param": "limit"}, want: `{"code":"bad_request","message":"invalid limit","details":{"param":"limit"}}`},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		writeError(w, http.StatusBadRequest, errBadRequest, "invalid limit", tt.details)
+		if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != "application/json" || w.Header().Get("Cache-Control") != "no-store" {
+			t.Errorf("%s: status %d, headers %v", tt.name, w.Code, w.Header())
+		}
+		if got := w.Body.String(); got != tt.want+"\n" {
+			t.Errorf("%s: body = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAllowGet(t *testing.T) {
+	tests := []struct {
+		method     string
+		want       bool
+		wantStatus int
+		wantCode   string
+	}{
+		{method: http.MethodGet, want: true, wantStatus: http.StatusOK},
+		{method: http.MethodOptions, wantStatus: http.StatusOK},
+		{method: http.MethodPost, wantStatus: http.StatusMethodNotAllowed, wantCode: errMethodNotAllowed},
+		{method: http.MethodDelete, wantStatus: http.StatusMethodNotAllowed, wantCode: errMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		got := allowGet(w, httptest.NewRequest(tt.method, apiV1+"/road/status", nil))
+		if got != tt.want || w.Code != tt.wantStatus {
+			t.Errorf("%s: allowGet = %v with status %d, want %v with %d", tt.method, got, w.Code, tt.want, tt.wantStatus)
+			continue
+		}
+		if tt.wantCode == "" {
+			continue
+		}
+		var body APIError
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != tt.wantCode {
+			t.Errorf("%s: body %s, want code %s", tt.method, w.Body, tt.wantCode)
+		}
+		if w.Header().Get("Allow") != "GET, OPTIONS" {
+			t.Errorf("%s: Allow = %q", tt.method, w.Header().Get("Allow"))
+		}
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	const alias, successor = "/api/test/deprecated", apiV1 + "/test/deprecated"
+	series := `api_deprecated_requests_total{path="` + alias + `"}`
+	before := metricValue(t, series)
+
+	var called bool
+	h := deprecated(alias, successor, func(w http.ResponseWriter, r *http.Request) { called = true })
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, alias, nil))
+
+	if !called {
+		t.Fatal("handler not called")
+	}
+	if w.Header().Get("Deprecation") != "true" || w.Header().Get("Link") != "<"+successor+`>; rel="successor-version"` {
+		t.Errorf("headers = %v", w.Header())
+	}
+	if got := metricValue(t, series) - before; got != 1 {
+		t.Errorf("%s grew by %v, want 1", series, got)
+	}
+}
+
+func TestAPINotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	apiNotFound(w, httptest.NewRequest(http.MethodGet, apiV1+"/nope", nil))
+
+	var body struct {
+		Code    string            `json:"code"`
+		Details map[string]string `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusNotFound || body.Code != errNotFound || body.Details["path"] != apiV1+"/nope" {
+		t.Errorf("status %d, body %s", w.Code, w.Body)
+	}
+}
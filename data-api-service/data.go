@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// The queries below are shared by the JSON handlers, GraphQL and gRPC. handler labels
+// the query metrics and spans with the calling endpoint.
+
+const accidentColumns = `id, acc_date, acc_hour, acc_point_nm, road_nm, nosun_nm, sms_text, acc_type,
+	latitude, altitude, created_at`
+
+// scanAccident scans accidentColumns followed by the extra destinations
+func scanAccident(rows *sql.Rows, extra ...interface{}) (Accident, error) {
+	var acc Accident
+	dest := append([]interface{}{&acc.ID, &acc.AccDate, &acc.AccHour, &acc.AccPointNM, &acc.RoadNM, &acc.NosunNM,
+		&acc.SmsText, &acc.AccType, &acc.Latitude, &acc.Altitude, &acc.CreatedAt}, extra...)
+	err := rows.Scan(dest...)
+	return acc, err
+}
+
+// scanAccidents collects accident rows, skipping rows that fail to scan
+func scanAccidents(ctx context.Context, rows *sql.Rows) ([]Accident, error) {
+	defer rows.Close()
+
+	accidents := []Accident{}
+	for rows.Next() {
+		acc, err := scanAccident(rows)
+		if err != nil {
+			loggerFrom(ctx).Error("scan failed", "error", err)
+			continue
+		}
+		accidents = append(accidents, acc)
+	}
+	return accidents, rows.Err()
+}
+
+// latestAccidents returns the newest accidents of the last 24 hours
+func (s *Server) latestAccidents(ctx context.Context, handler string, limit int) ([]Accident, error) {
+	// Only show accidents from the last 24 hours (increased from 3 hours for better visibility)
+	twentyFourHoursAgo := time.Now().Add(-24 * time.Hour)
+
+	loggerFrom(ctx).Debug("querying accidents", "since", twentyFourHoursAgo)
+	rows, err := s.query(ctx, handler, `SELECT `+accidentColumns+`
+		FROM traffic_accidents
+		WHERE created_at >= ?
+		ORDER BY acc_date DESC, acc_hour DESC, created_at DESC
+		LIMIT ?`, twentyFourHoursAgo, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAccidents(ctx, rows)
+}
+
+// accidentsAfter returns accidents of the last 24 hours with an ID above afterID, oldest
+// first. Accidents are upserted on every collection, so a new ID means a new accident.
+func (s *Server) accidentsAfter(ctx context.Context, handler string, afterID int) ([]Accident, error) {
+	rows, err := s.query(ctx, handler, `SELECT `+accidentColumns+`
+		FROM traffic_accidents
+		WHERE id > ? AND created_at >= ?
+		ORDER BY id`, afterID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	return scanAccidents(ctx, rows)
+}
+
+// tollgateTraffic returns every tollgate's traffic of the 3 hours up to the latest
+// collection, ordered by unit code
+func (s *Server) tollgateTraffic(ctx context.Context, handler string) ([]TollgateTraffic, error) {
+	// First, get the most recent collected_at time
+	var latestTime sql.NullTime
+	if err := s.queryRow(ctx, handler, `
+		SELECT MAX(collected_at) FROM tollgate_traffic_history
+	`).Scan(&latestTime); err != nil {
+		return nil, err
+	}
+
+	// If no data exists, return empty array
+	if !latestTime.Valid {
+		return []TollgateTraffic{}, nil
+	}
+
+	// Get traffic data from 3 hours before the latest collection time
+	threeHoursBeforeLatest := latestTime.Time.Add(-3 * time.Hour)
+
+	rows, err := s.query(ctx, handler, `
+		SELECT unit_code, unit_name, ex_div_name, collected_at, traffic_amount
+		FROM tollgate_traffic_history
+		WHERE collected_at >= ?
+		ORDER BY unit_code, collected_at DESC`, threeHoursBeforeLatest)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Group by unit_code
+	tollgateMap := make(map[string]*TollgateTraffic)
+
+	for rows.Next() {
+		var unitCode, unitName, exDivName string
+		var collectedAt time.Time
+		var trafficAmount int
+
+		if err := rows.Scan(&unitCode, &unitName, &exDivName, &collectedAt, &trafficAmount); err != nil {
+			loggerFrom(ctx).Error("scan failed", "error", err)
+			continue
+		}
+
+		if _, exists := tollgateMap[unitCode]; !exists {
+			tollgateMap[unitCode] = &TollgateTraffic{
+				UnitCode:    unitCode,
+				UnitName:    unitName,
+				ExDivName:   exDivName,
+				TrafficData: []TollgateTrafficData{},
+				LastUpdated: collectedAt,
+			}
+		}
+
+		tollgateMap[unitCode].TrafficData = append(tollgateMap[unitCode].TrafficData, TollgateTrafficData{
+			CollectedAt:   collectedAt,
+			TrafficAmount: trafficAmount,
+		})
+
+		if collectedAt.After(tollgateMap[unitCode].LastUpdated) {
+			tollgateMap[unitCode].LastUpdated = collectedAt
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tollgates := make([]TollgateTraffic, 0, len(tollgateMap))
+	for _, tg := range tollgateMap {
+		tollgates = append(tollgates, *tg)
+	}
+	sort.Slice(tollgates, func(i, j int) bool { return tollgates[i].UnitCode < tollgates[j].UnitCode })
+	return tollgates, nil
+}
+
+// roadStatuses returns the latest status per conzone and direction of every route, or
+// of routeNos when given
+func (s *Server) roadStatuses(ctx context.Context, handler string, routeNos ...string) ([]RoadStatus, error) {
+	// Get the most recent data per route, aggregating by conzone
+	// Shows latest available data for each route (no time limit)
+	// This ensures all routes are displayed even if external API has gaps
+	routeFilter := ""
+	if len(routeNos) > 0 {
+		routeFilter = "WHERE route_no IN (" + placeholders(len(routeNos)) + ")"
+	}
+	query := `
+		WITH LatestByRoute AS (
+			SELECT route_no, MAX(collected_at) as max_collected
+			FROM road_traffic_status
+			` + routeFilter + `
+			GROUP BY route_no
+		)
+		SELECT
+			r.route_no,
+			r.route_name,
+			r.conzone_id,
+			r.conzone_name,
+			MIN(r.vds_id) as vds_id,
+			r.updown_type_code,
+			ROUND(AVG(CASE WHEN r.traffic_amount >= 0 THEN r.traffic_amount END)) as traffic_amount,
+			ROUND(AVG(CASE WHEN r.speed >= 0 THEN r.speed END)) as speed,
+			ROUND(AVG(CASE WHEN r.share_ratio >= 0 THEN r.share_ratio END)) as share_ratio,
+			ROUND(AVG(CASE WHEN r.time_avg >= 0 THEN r.time_avg END)) as time_avg,
+			MAX(r.grade) as grade,
+			MAX(r.collected_at) as collected_at
+		FROM road_traffic_status r
+		INNER JOIN LatestByRoute l ON r.route_no = l.route_no AND r.collected_at = l.max_collected
+		WHERE r.speed >= 0 AND r.grade > 0
+		GROUP BY r.route_no, r.route_name, r.conzone_id, r.conzone_name, r.updown_type_code
+		ORDER BY r.route_no, r.conzone_id`
+
+	rows, err := s.query(ctx, handler, query, anyArgs(routeNos)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roadStatuses := []RoadStatus{}
+	for rows.Next() {
+		var rs RoadStatus
+		if err := rows.Scan(
+			&rs.RouteNo, &rs.RouteName, &rs.ConzoneID, &rs.ConzoneName,
+			&rs.VdsID, &rs.UpdownTypeCode,
+			&rs.TrafficAmount, &rs.Speed, &rs.ShareRatio, &rs.TimeAvg,
+			&rs.Grade, &rs.CollectedAt,
+		); err != nil {
+			loggerFrom(ctx).Error("scan failed", "error", err)
+			continue
+		}
+		roadStatuses = append(roadStatuses, rs)
+	}
+	return roadStatuses, rows.Err()
+}
+
+// routeSummaries returns the latest aggregated summary of every route, or of routeNo
+// when set
+func (s *Server) routeSummaries(ctx context.Context, handler, routeNo string) ([]RoadRouteSummary, error) {
+	rows, err := s.query(ctx, handler, `
+		SELECT
+			route_no,
+			route_name,
+			total_sections,
+			smooth_sections,
+			slow_sections,
+			congested_sections,
+			avg_speed,
+			avg_traffic_amount,
+			collected_at
+		FROM road_route_summary
+		WHERE collected_at = (SELECT MAX(collected_at) FROM road_route_summary)
+		  AND (? = '' OR route_no = ?)
+		ORDER BY route_no`, routeNo, routeNo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []RoadRouteSummary{}
+	for rows.Next() {
+		var summary RoadRouteSummary
+		if err := rows.Scan(
+			&summary.RouteNo, &summary.RouteName,
+			&summary.TotalSections, &summary.SmoothSections,
+			&summary.SlowSections, &summary.CongestedSections,
+			&summary.AvgSpeed, &summary.AvgTrafficAmount,
+			&summary.CollectedAt,
+		); err != nil {
+			loggerFrom(ctx).Error("scan failed", "error", err)
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// latestCollection returns the newest collected_at of table (road_traffic_status,
+// road_route_summary or tollgate_traffic_history), zero when empty. Watch streams poll it
+// to send a snapshot only after new data lands.
+func (s *Server) latestCollection(ctx context.Context, handler, table string) (time.Time, error) {
+	var latest sql.NullTime
+	if err := s.queryRow(ctx, handler, "SELECT MAX(collected_at) FROM "+table).Scan(&latest); err != nil {
+		return time.Time{}, err
+	}
+	return latest.Time, nil
+}
+
+// tollgateLocations returns the active tollgates that have coordinates, with their total
+// traffic at the latest collection
+func (s *Server) tollgateLocations(ctx context.Context, handler string) ([]TollgateLocation, error) {
+	rows, err := s.query(ctx, handler, `
+		SELECT m.latitude, m.longitude, COALESCE(t.traffic_amount, 0),
+			m.unit_code, m.unit_name, m.ex_div_name, m.last_collected_at
+		FROM tollgate_master m
+		LEFT JOIN (
+			SELECT unit_code, SUM(traffic_amount) as traffic_amount
+			FROM tollgate_traffic_history
+			WHERE collected_at = (SELECT MAX(collected_at) FROM tollgate_traffic_history)
+			GROUP BY unit_code
+		) t ON t.unit_code = m.unit_code
+		WHERE m.is_active AND m.latitude IS NOT NULL AND m.longitude IS NOT NULL
+		ORDER BY m.unit_code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []TollgateLocation{}
+	for rows.Next() {
+		var loc TollgateLocation
+		loc.Tollgate, err = scanTollgate(rows, &loc.Latitude, &loc.Longitude, &loc.TrafficAmount)
+		if err != nil {
+			loggerFrom(ctx).Error("scan failed", "error", err)
+			continue
+		}
+		locations = append(locations, loc)
+	}
+	return locations, rows.Err()
+}
+
+// conzoneGeometries returns the polylines of road_conzone_geometry by conzone ID, only
+// those whose bounding box meets bounds when given
+func (s *Server) conzoneGeometries(ctx context.Context, handler string, bounds *lonLatBounds) (map[string][][2]float64, error) {
+	query := `SELECT conzone_id, ST_AsGeoJSON(geom) FROM road_conzone_geometry`
+	var args []interface{}
+	if bounds != nil {
+		// Served by the spatial index on geom
+		query += ` WHERE MBRIntersects(geom, ST_GeomFromText(?))`
+		args = append(args, bounds.wkt())
+	}
+	rows, err := s.query(ctx, handler, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := make(map[string][][2]float64)
+	for rows.Next() {
+		var conzoneID string
+		var geometry []byte
+		if err := rows.Scan(&conzoneID, &geometry); err != nil {
+			loggerFrom(ctx).Error("scan failed", "error", err)
+			continue
+		}
+		var line struct {
+			Coordinates [][2]float64 `json:"coordinates"`
+		}
+		if err := json.Unmarshal(geometry, &line); err != nil || len(line.Coordinates) < 2 {
+			loggerFrom(ctx).Warn("invalid conzone geometry", "conzoneId", conzoneID, "error", err)
+			continue
+		}
+		lines[conzoneID] = line.Coordinates
+	}
+	return lines, rows.Err()
+}
+
+// matchedAccidents returns the accidents of the last 24 hours that data-processor placed
+// on a conzone, with the conzone's average speed (judged grades only) in the 30 minutes
+// before and after each accident
+func (s *Server) matchedAccidents(ctx context.Context, handler string) ([]MatchedAccident, error) {
+	rows, err := s.query(ctx, handler, `SELECT `+accidentColumns+`,
+		m.conzone_id, m.updown_type_code, m.match_method, m.distance_m,
+		(SELECT AVG(r.speed) FROM road_traffic_status r
+		 WHERE r.conzone_id = m.conzone_id AND r.grade > 0
+		   AND (m.updown_type_code = '' OR r.updown_type_code = m.updown_type_code)
+		   AND r.collected_at >= m.occurred_at - INTERVAL 30 MINUTE AND r.collected_at < m.occurred_at),
+		(SELECT AVG(r.speed) FROM road_traffic_status r
+		 WHERE r.conzone_id = m.conzone_id AND r.grade > 0
+		   AND (m.updown_type_code = '' OR r.updown_type_code = m.updown_type_code)
+		   AND r.collected_at >= m.occurred_at AND r.collected_at < m.occurred_at + INTERVAL 30 MINUTE)
+		FROM accident_road_match m
+		JOIN traffic_accidents a ON a.id = m.accident_id
+		WHERE a.created_at >= ? AND m.conzone_id <> ''
+		ORDER BY acc_date DESC, acc_hour DESC`, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accidents := []MatchedAccident{}
+	for rows.Next() {
+		var acc MatchedAccident
+		acc.Accident, err = scanAccident(rows, &acc.ConzoneID, &acc.UpdownTypeCode, &acc.MatchMethod,
+			&acc.MatchDistance, &acc.SpeedBefore, &acc.SpeedAfter)
+		if err != nil {
+			loggerFrom(ctx).Error("scan failed", "error", err)
+			continue
+		}
+		if acc.SpeedBefore != nil && acc.SpeedAfter != nil {
+			drop := *acc.SpeedBefore - *acc.SpeedAfter
+			acc.SpeedDrop = &drop
+		}
+		accidents = append(accidents, acc)
+	}
+	return accidents, rows.Err()
+}
+
+// vdsLocations returns the road_vds_location coordinates by VDS ID as [longitude, latitude]
+func (s *Server) vdsLocations(ctx context.Context, handler string) (map[string][2]float64, error) {
+	rows, err := s.query(ctx, handler, `SELECT vds_id, longitude, latitude FROM road_vds_location`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := make(map[string][2]float64)
+	for rows.Next() {
+		var vdsID string
+		var lon, lat float64
+		if err := rows.Scan(&vdsID, &lon, &lat); err != nil {
+			loggerFrom(ctx).Error("scan failed", "error", err)
+			continue
+		}
+		locations[vdsID] = [2]float64{lon, lat}
+	}
+	return locations, rows.Err()
+}
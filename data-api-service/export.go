@@ -0,0 +1,499 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/xuri/excelize/v2"
+)
+
+// exportTimeout bounds one export, query and transfer included
+const exportTimeout = 10 * time.Minute
+
+// xlsxMaxRows is the row limit of an Excel worksheet, header included
+const xlsxMaxRows = 1048576
+
+var exportRows = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "export_rows_total",
+	Help: "Rows written by export endpoints per dataset and format.",
+}, []string{"dataset", "format"})
+
+// kst is the time zone of the stored timestamps (see the DSN) and of range dates and
+// filenames
+var kst = func() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Seoul"); err == nil {
+		return loc
+	}
+	return time.FixedZone("KST", 9*60*60)
+}()
+
+type columnType int
+
+const (
+	textColumn columnType = iota
+	intColumn
+	floatColumn
+	timeColumn
+)
+
+type exportColumn struct {
+	Name     string
+	Type     columnType
+	Nullable bool
+}
+
+// exportDataset is a table exported row by row over a range of TimeColumn
+type exportDataset struct {
+	Name       string // path segment, sheet name and filename prefix
+	Summary    string
+	Table      string
+	TimeColumn string
+	Columns    []exportColumn
+}
+
+var exportDatasets = []exportDataset{
+	{
+		Name:       "accidents",
+		Summary:    "Raw accidents recorded in the range (by created_at)",
+		Table:      "traffic_accidents",
+		TimeColumn: "created_at",
+		Columns: []exportColumn{
+			{Name: "id", Type: intColumn},
+			{Name: "acc_date", Type: textColumn},
+			{Name: "acc_hour", Type: textColumn},
+			{Name: "acc_type_code", Type: textColumn, Nullable: true},
+			{Name: "acc_type", Type: textColumn, Nullable: true},
+			{Name: "acc_point_nm", Type: textColumn, Nullable: true},
+			{Name: "nosun_nm", Type: textColumn, Nullable: true},
+			{Name: "road_nm", Type: textColumn, Nullable: true},
+			{Name: "start_end_type_code", Type: textColumn, Nullable: true},
+			{Name: "sms_text", Type: textColumn},
+			{Name: "acc_process_code", Type: textColumn, Nullable: true},
+			{Name: "acc_process_nm", Type: textColumn, Nullable: true},
+			{Name: "late_length", Type: textColumn, Nullable: true},
+			{Name: "latitude", Type: floatColumn, Nullable: true},
+			{Name: "altitude", Type: floatColumn, Nullable: true},
+			{Name: "created_at", Type: timeColumn, Nullable: true},
+			{Name: "updated_at", Type: timeColumn, Nullable: true},
+		},
+	},
+	{
+		Name:       "road-status",
+		Summary:    "Raw road traffic status history per VDS collected in the range",
+		Table:      "road_traffic_status",
+		TimeColumn: "collected_at",
+		Columns: []exportColumn{
+			{Name: "collected_at", Type: timeColumn},
+			{Name: "route_no", Type: textColumn},
+			{Name: "route_name", Type: textColumn},
+			{Name: "conzone_id", Type: textColumn},
+			{Name: "conzone_name", Type: textColumn},
+			{Name: "vds_id", Type: textColumn},
+			{Name: "updown_type_code", Type: textColumn},
+			{Name: "traffic_amount", Type: intColumn},
+			{Name: "speed", Type: intColumn},
+			{Name: "share_ratio", Type: intColumn},
+			{Name: "time_avg", Type: intColumn},
+			{Name: "grade", Type: intColumn},
+			{Name: "std_date", Type: textColumn},
+			{Name: "std_hour", Type: textColumn},
+		},
+	},
+	{
+		Name:       "tollgate",
+		Summary:    "Raw tollgate traffic history aggregated in the range",
+		Table:      "tollgate_traffic_history",
+		TimeColumn: "collected_at",
+		Columns: []exportColumn{
+			{Name: "collected_at", Type: timeColumn},
+			{Name: "unit_code", Type: textColumn},
+			{Name: "unit_name", Type: textColumn},
+			{Name: "ex_div_code", Type: textColumn},
+			{Name: "ex_div_name", Type: textColumn},
+			{Name: "inout_type", Type: textColumn},
+			{Name: "inout_name", Type: textColumn},
+			{Name: "tm_type", Type: textColumn},
+			{Name: "tm_name", Type: textColumn},
+			{Name: "tcs_type", Type: textColumn},
+			{Name: "tcs_name", Type: textColumn},
+			{Name: "car_type", Type: textColumn},
+			{Name: "traffic_amount", Type: intColumn},
+			{Name: "sum_date", Type: textColumn},
+			{Name: "sum_tm", Type: textColumn},
+		},
+	},
+}
+
+func (d exportDataset) columnNames() []string {
+	names := make([]string, len(d.Columns))
+	for i, c := range d.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func (d exportDataset) query() string {
+	return "SELECT " + strings.Join(d.columnNames(), ", ") + " FROM " + d.Table +
+		" WHERE " + d.TimeColumn + " >= ? AND " + d.TimeColumn + " < ? ORDER BY " + d.TimeColumn + ", id"
+}
+
+func (d exportDataset) countQuery() string {
+	return "SELECT COUNT(*) FROM " + d.Table + " WHERE " + d.TimeColumn + " >= ? AND " + d.TimeColumn + " < ?"
+}
+
+// rowScanner scans rows into reused holders and returns each row as string, int64,
+// float64, time.Time or nil values
+type rowScanner struct {
+	columns []exportColumn
+	dest    []interface{}
+	values  []interface{}
+}
+
+func newRowScanner(columns []exportColumn) *rowScanner {
+	s := &rowScanner{columns: columns, dest: make([]interface{}, len(columns)), values: make([]interface{}, len(columns))}
+	for i, c := range columns {
+		switch c.Type {
+		case intColumn:
+			s.dest[i] = new(sql.NullInt64)
+		case floatColumn:
+			s.dest[i] = new(sql.NullFloat64)
+		case timeColumn:
+			s.dest[i] = new(sql.NullTime)
+		default:
+			s.dest[i] = new(sql.NullString)
+		}
+	}
+	return s
+}
+
+func (s *rowScanner) scan(rows *sql.Rows) ([]interface{}, error) {
+	if err := rows.Scan(s.dest...); err != nil {
+		return nil, err
+	}
+	for i, d := range s.dest {
+		s.values[i] = nil
+		switch v := d.(type) {
+		case *sql.NullInt64:
+			if v.Valid {
+				s.values[i] = v.Int64
+			}
+		case *sql.NullFloat64:
+			if v.Valid {
+				s.values[i] = v.Float64
+			}
+		case *sql.NullTime:
+			if v.Valid {
+				s.values[i] = v.Time.In(kst)
+			}
+		case *sql.NullString:
+			if v.Valid {
+				s.values[i] = v.String
+			}
+		}
+	}
+	return s.values, nil
+}
+
+// exportWriter encodes rows of one dataset; Close completes the file. Discard releases
+// the writer's resources without completing the file, so a failed export is never
+// finished with a valid trailer (Parquet footer, XLSX zip directory).
+type exportWriter interface {
+	Write(values []interface{}) error
+	Close() error
+	Discard()
+}
+
+type exportFormat struct {
+	ContentType string
+	New         func(w io.Writer, d exportDataset) (exportWriter, error)
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv":     {ContentType: "text/csv; charset=utf-8", New: newCSVExport},
+	"xlsx":    {ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", New: newXLSXExport},
+	"parquet": {ContentType: "application/vnd.apache.parquet", New: newParquetExport},
+}
+
+// exportMediaTypes lists the content types of exportFormats, for the OpenAPI document
+func exportMediaTypes() []string {
+	var types []string
+	for _, format := range []string{"csv", "xlsx", "parquet"} {
+		types = append(types, strings.Split(exportFormats[format].ContentType, ";")[0])
+	}
+	return types
+}
+
+type csvExport struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVExport(w io.Writer, d exportDataset) (exportWriter, error) {
+	// The BOM makes Excel read the Korean text as UTF-8
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	e := &csvExport{w: csv.NewWriter(w), record: make([]string, len(d.Columns))}
+	return e, e.w.Write(d.columnNames())
+}
+
+func (e *csvExport) Write(values []interface{}) error {
+	for i, v := range values {
+		switch v := v.(type) {
+		case string:
+			e.record[i] = v
+		case int64:
+			e.record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			e.record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case time.Time:
+			e.record[i] = v.Format(time.RFC3339)
+		default:
+			e.record[i] = ""
+		}
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvExport) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExport) Discard() {}
+
+// xlsxExport writes rows through excelize's stream writer, which spills to a temporary
+// file past 16MB; the zip is only written out on Close
+type xlsxExport struct {
+	out       io.Writer
+	file      *excelize.File
+	sheet     *excelize.StreamWriter
+	row       int
+	timeStyle int
+	cells     []interface{}
+}
+
+func newXLSXExport(w io.Writer, d exportDataset) (_ exportWriter, err error) {
+	f := excelize.NewFile()
+	defer func() {
+		if err != nil {
+			f.Close()
+		}
+	}()
+	if err := f.SetSheetName("Sheet1", d.Name); err != nil {
+		return nil, err
+	}
+	sheet, err := f.NewStreamWriter(d.Name)
+	if err != nil {
+		return nil, err
+	}
+	dateTime := "yyyy-mm-dd hh:mm:ss"
+	timeStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateTime})
+	if err != nil {
+		return nil, err
+	}
+	boldStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(d.Columns))
+	for i, name := range d.columnNames() {
+		header[i] = excelize.Cell{StyleID: boldStyle, Value: name}
+	}
+	if err := sheet.SetRow("A1", header); err != nil {
+		return nil, err
+	}
+	return &xlsxExport{out: w, file: f, sheet: sheet, row: 1, timeStyle: timeStyle,
+		cells: make([]interface{}, len(d.Columns))}, nil
+}
+
+func (e *xlsxExport) Write(values []interface{}) error {
+	if e.row >= xlsxMaxRows {
+		return fmt.Errorf("more than %d rows do not fit in a worksheet", xlsxMaxRows-1)
+	}
+	e.row++
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			// Excel has no time zones; keep the KST wall clock
+			wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+			e.cells[i] = excelize.Cell{StyleID: e.timeStyle, Value: wall}
+			continue
+		}
+		e.cells[i] = v
+	}
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	return e.sheet.SetRow(cell, e.cells)
+}
+
+func (e *xlsxExport) Close() error {
+	defer e.file.Close()
+	if err := e.sheet.Flush(); err != nil {
+		return err
+	}
+	return e.file.Write(e.out)
+}
+
+// Discard removes the stream writer's temporary files
+func (e *xlsxExport) Discard() {
+	e.file.Close()
+}
+
+// exportRange parses the from and to query parameters, RFC 3339 timestamps or KST dates
+// (YYYY-MM-DD, to inclusive). The default is the last 24 hours.
+func exportRange(r *http.Request, maxRange time.Duration) (from, to time.Time, err error) {
+	parse := func(name string, endOfDay bool) (time.Time, error) {
+		v := r.URL.Query().Get(name)
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t.In(kst), nil
+		}
+		d, err := time.ParseInLocation("2006-01-02", v, kst)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+		}
+		if endOfDay {
+			d = d.AddDate(0, 0, 1)
+		}
+		return d, nil
+	}
+
+	to = time.Now().In(kst).Truncate(time.Minute)
+	if r.URL.Query().Get("to") != "" {
+		if to, err = parse("to", true); err != nil {
+			return
+		}
+	}
+	from = to.Add(-24 * time.Hour)
+	if r.URL.Query().Get("from") != "" {
+		if from, err = parse("from", false); err != nil {
+			return
+		}
+	}
+
+	switch {
+	case !from.Before(to):
+		err = fmt.Errorf("from must be before to")
+	case to.Sub(from) > maxRange:
+		err = fmt.Errorf("range must not exceed %s", maxRange)
+	}
+	return
+}
+
+// exportFilename encodes the dataset and range, e.g. accidents_20261001T0000_20261018T0000.csv
+func exportFilename(d exportDataset, from, to time.Time, format string) string {
+	const layout = "20060102T1504"
+	return d.Name + "_" + from.In(kst).Format(layout) + "_" + to.In(kst).Format(layout) + "." + format
+}
+
+// exportHandler streams d over the requested range in the requested format straight
+// from rows.Next(), so memory stays bounded regardless of the row count
+func (s *Server) exportHandler(d exportDataset) http.HandlerFunc {
+	handler := apiV1 + "/export/" + d.Name
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowGet(w, r) {
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "csv"
+		}
+		f, ok := exportFormats[format]
+		if !ok {
+			writeError(w, http.StatusBadRequest, errBadRequest, "unsupported format",
+				map[string]interface{}{"format": format, "supported": []string{"csv", "xlsx", "parquet"}})
+			return
+		}
+		from, to, err := exportRange(r, s.config.ExportMaxRange)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errBadRequest, err.Error(), nil)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), exportTimeout)
+		defer cancel()
+
+		// An XLSX file is only written once complete, so reject oversized ones up front
+		if format == "xlsx" {
+			var count int
+			if err := s.queryRow(ctx, handler, d.countQuery(), from, to).Scan(&count); err != nil {
+				loggerFrom(r.Context()).Error("count failed", "error", err)
+				writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+				return
+			}
+			if count >= xlsxMaxRows {
+				writeError(w, http.StatusRequestEntityTooLarge, errBadRequest, "too many rows for xlsx, use csv or parquet or a shorter range",
+					map[string]int{"rows": count, "max": xlsxMaxRows - 1})
+				return
+			}
+		}
+
+		rows, err := s.query(ctx, handler, d.query(), from, to)
+		if err != nil {
+			loggerFrom(r.Context()).Error("query failed", "error", err)
+			writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+			return
+		}
+		defer rows.Close()
+
+		filename := exportFilename(d, from, to, format)
+		w.Header().Set("Content-Type", f.ContentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.Header().Set("Cache-Control", "no-store")
+		// Send the headers now so proxies waiting on them do not time out on slow queries
+		w.WriteHeader(http.StatusOK)
+		http.NewResponseController(w).Flush()
+
+		count, err := s.writeExport(rows, d, f, w)
+		exportRows.WithLabelValues(d.Name, format).Add(float64(count))
+		if err != nil {
+			// The status is already sent; cut the connection so the client sees an
+			// incomplete download rather than a truncated file that looks complete
+			loggerFrom(r.Context()).Error("export failed", "dataset", d.Name, "format", format, "rows", count, "error", err)
+			panic(http.ErrAbortHandler)
+		}
+		loggerFrom(r.Context()).Info("export completed", "dataset", d.Name, "format", format,
+			"from", from, "to", to, "rows", count)
+	}
+}
+
+func (s *Server) writeExport(rows *sql.Rows, d exportDataset, f exportFormat, w io.Writer) (int, error) {
+	out, err := f.New(w, d)
+	if err != nil {
+		return 0, err
+	}
+	count, err := writeExportRows(rows, d, out)
+	if err != nil {
+		out.Discard()
+		return count, err
+	}
+	return count, out.Close()
+}
+
+func writeExportRows(rows *sql.Rows, d exportDataset, out exportWriter) (int, error) {
+	scanner := newRowScanner(d.Columns)
+	count := 0
+	for rows.Next() {
+		values, err := scanner.scan(rows)
+		if err != nil {
+			return count, err
+		}
+		if err := out.Write(values); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExportRange(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, kst) }
+
+	tests := []struct {
+		name     string
+		query    string
+		maxRange time.Duration
+		from, to time.Time
+		wantErr  bool
+	}{
+		{name: "dates in KST, to inclusive", query: "from=2026-10-01&to=2026-10-17", maxRange: 31 * 24 * time.Hour,
+			from: day(2026, 10, 1), to: day(2026, 10, 18)},
+		{name: "RFC 3339 from after to", query: "from=2026-10-17T00:00:00Z&to=2026-10-17T06:00:00%2B09:00", maxRange: 24 * time.Hour,
+			from: time.Date(2026, 10, 17, 9, 0, 0, 0, kst), to: time.Date(2026, 10, 17, 6, 0, 0, 0, kst), wantErr: true},
+		{name: "RFC 3339 converted to KST", query: "from=2026-10-16T15:00:00Z&to=2026-10-17T12:00:00%2B09:00", maxRange: 24 * time.Hour,
+			from: day(2026, 10, 17), to: time.Date(2026, 10, 17, 12, 0, 0, 0, kst)},
+		{name: "from defaults to 24 hours before to", query: "to=2026-10-17", maxRange: 24 * time.Hour,
+			from: day(2026, 10, 17), to: day(2026, 10, 18)},
+		{name: "single day", query: "from=2026-10-17&to=2026-10-17", maxRange: 24 * time.Hour,
+			from: day(2026, 10, 17), to: day(2026, 10, 18)},
+		{name: "range too long", query: "from=2026-10-01&to=2026-10-17", maxRange: 7 * 24 * time.Hour, wantErr: true},
+		{name: "from after to", query: "from=2026-10-18&to=2026-10-17", maxRange: 24 * time.Hour, wantErr: true},
+		{name: "invalid from", query: "from=yesterday", maxRange: 24 * time.Hour, wantErr: true},
+		{name: "invalid to", query: "to=2026-13-01", maxRange: 24 * time.Hour, wantErr: true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, apiV1+"/export/accidents?"+tt.query, nil)
+		from, to, err := exportRange(r, tt.maxRange)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (!from.Equal(tt.from) || !to.Equal(tt.to)) {
+			t.Errorf("%s: range = %v - %v, want %v - %v", tt.name, from, to, tt.from, tt.to)
+		}
+	}
+
+	// Without parameters the range is the last 24 hours
+	from, to, err := exportRange(httptest.NewRequest(http.MethodGet, apiV1+"/export/accidents", nil), 24*time.Hour)
+	if err != nil || to.Sub(from) != 24*time.Hour || time.Since(to) > time.Minute || to.Location() != kst {
+		t.Errorf("default range = %v - %v, %v", from, to, err)
+	}
+}
+
+func TestExportFilename(t *testing.T) {
+	d := exportDataset{Name: "road-status"}
+	tests := []struct {
+		from, to time.Time
+		format   string
+		want     string
+	}{
+		{from: time.Date(2026, 10, 1, 0, 0, 0, 0, kst), to: time.Date(2026, 10, 18, 0, 0, 0, 0, kst), format: "csv",
+			want: "road-status_20261001T0000_20261018T0000.csv"},
+		{from: time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC), to: time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC), format: "xlsx",
+			want: "road-status_20261018T0030_20261018T0300.xlsx"},
+	}
+	for _, tt := range tests {
+		if got := exportFilename(d, tt.from, tt.to, tt.format); got != tt.want {
+			t.Errorf("exportFilename = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestExportDatasetQueries(t *testing.T) {
+	d := exportDataset{Table: "t", TimeColumn: "collected_at", Columns: []exportColumn{{Name: "id"}, {Name: "collected_at"}}}
+	if got, want := d.query(), "SELECT id, collected_at FROM t WHERE collected_at >= ? AND collected_at < ? ORDER BY collected_at, id"; got != want {
+		t.Errorf("query = %s, want %s", got, want)
+	}
+	if got, want := d.countQuery(), "SELECT COUNT(*) FROM t WHERE collected_at >= ? AND collected_at < ?"; got != want {
+		t.Errorf("countQuery = %s, want %s", got, want)
+	}
+	if got, want := exportMediaTypes(), []string{"text/csv", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "application/vnd.apache.parquet"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("exportMediaTypes = %v, want %v", got, want)
+	}
+}
+
+// testDataset has one column of each type
+var testDataset = exportDataset{
+	Name: "sample",
+	Columns: []exportColumn{
+		{Name: "id", Type: intColumn},
+		{Name: "name", Type: textColumn, Nullable: true},
+		{Name: "speed", Type: floatColumn, Nullable: true},
+		{Name: "collected_at", Type: timeColumn},
+	},
+}
+
+var testRows = [][]interface{}{
+	{int64(1), "서울TG, \"본선\"", 87.5, time.Date(2026, 10, 17, 18, 30, 5, 0, kst)},
+	{int64(2), nil, nil, time.Date(2026, 10, 18, 0, 0, 0, 0, kst)},
+}
+
+func TestCSVExport(t *testing.T) {
+	var buf bytes.Buffer
+	e, err := newCSVExport(&buf, testDataset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range testRows {
+		if err := e.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	body, ok := strings.CutPrefix(buf.String(), "\ufeff")
+	if !ok {
+		t.Error("missing UTF-8 BOM")
+	}
+	records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"id", "name", "speed", "collected_at"},
+		{"1", "서울TG, \"본선\"", "87.5", "2026-10-17T18:30:05+09:00"},
+		{"2", "", "", "2026-10-18T00:00:00+09:00"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
+
+func TestXLSXExport(t *testing.T) {
+	var buf bytes.Buffer
+	e, err := newXLSXExport(&buf, testDataset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range testRows {
+		if err := e.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); !reflect.DeepEqual(sheets, []string{"sample"}) {
+		t.Fatalf("sheets = %v", sheets)
+	}
+	rows, err := f.GetRows("sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"id", "name", "speed", "collected_at"},
+		{"1", "서울TG, \"본선\"", "87.5", "2026-10-17 18:30:05"},
+		{"2", "", "", "2026-10-18 00:00:00"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %q, want %q", rows, want)
+	}
+	for i := range want {
+		// GetRows drops trailing empty cells
+		for j, cell := range want[i] {
+			var got string
+			if j < len(rows[i]) {
+				got = rows[i][j]
+			}
+			if got != cell {
+				t.Errorf("row %d column %d = %q, want %q", i, j, got, cell)
+			}
+		}
+	}
+
+	// Times are stored as numbers with the KST wall clock, not as text
+	raw, err := f.GetCellValue("sample", "D2", excelize.Options{RawCellValue: true})
+	if err != nil || strings.Contains(raw, "-") {
+		t.Errorf("D2 raw value = %q, %v; want an Excel serial date", raw, err)
+	}
+}
+
+func TestXLSXExportRowLimit(t *testing.T) {
+	e, err := newXLSXExport(&bytes.Buffer{}, testDataset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Discard()
+	e.(*xlsxExport).row = xlsxMaxRows
+	if err := e.Write(testRows[0]); err == nil {
+		t.Error("row past the worksheet limit accepted")
+	}
+}
+
+func TestExportHandlerRejects(t *testing.T) {
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/traffic?timeout=100ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s := &Server{db: db, config: Config{ExportMaxRange: 7 * 24 * time.Hour}}
+	h := s.exportHandler(exportDatasets[0])
+
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		want   int
+	}{
+		{name: "method", method: http.MethodPost, want: http.StatusMethodNotAllowed},
+		{name: "format", method: http.MethodGet, query: "format=json", want: http.StatusBadRequest},
+		{name: "range", method: http.MethodGet, query: "from=2026-01-01&to=2026-10-01", want: http.StatusBadRequest},
+		{name: "database down before any byte is sent", method: http.MethodGet, query: "format=csv", want: http.StatusInternalServerError},
+		{name: "xlsx count fails", method: http.MethodGet, query: "format=xlsx", want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(tt.method, apiV1+"/export/accidents?"+tt.query, nil))
+		if w.Code != tt.want || w.Header().Get("Content-Disposition") != "" {
+			t.Errorf("%s: status %d, headers %v; want %d and no download", tt.name, w.Code, w.Header(), tt.want)
+		}
+	}
+}
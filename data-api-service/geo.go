@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GeoJSON (RFC 7946) map layers. Coordinates are WGS84 [longitude, latitude]. Features
+// without a location are left out: accidents without coordinates, tollgates whose
+// tollgate_master row has no latitude/longitude and sections missing from
+// road_conzone_geometry.
+
+const geoJSONContentType = "application/geo+json"
+
+// Geometry is a GeoJSON Point ([lon, lat]) or LineString ([[lon, lat], ...])
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type Feature struct {
+	Type       string      `json:"type"`
+	ID         string      `json:"id"`
+	Geometry   Geometry    `json:"geometry"`
+	Properties interface{} `json:"properties"`
+}
+
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+func newFeatureCollection() FeatureCollection {
+	return FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+}
+
+func (fc *FeatureCollection) add(id string, geometry Geometry, properties interface{}) {
+	fc.Features = append(fc.Features, Feature{Type: "Feature", ID: id, Geometry: geometry, Properties: properties})
+}
+
+func point(lon, lat float64) Geometry {
+	return Geometry{Type: "Point", Coordinates: [2]float64{lon, lat}}
+}
+
+func lineString(coordinates [][2]float64) Geometry {
+	return Geometry{Type: "LineString", Coordinates: coordinates}
+}
+
+// LocatedRoadStatus is a road status of /road/status with the section's polyline and the
+// VDS location, when loaded by the data-processor geometry import
+type LocatedRoadStatus struct {
+	RoadStatus
+	Geometry     *Geometry `json:"geometry,omitempty"` // LineString of the conzone
+	VdsLatitude  *float64  `json:"vdsLatitude,omitempty"`
+	VdsLongitude *float64  `json:"vdsLongitude,omitempty"`
+}
+
+// roadGeometryCheck is how often the cached road network is compared with its tables;
+// it only changes when data-processor imports a new network
+const roadGeometryCheck = time.Minute
+
+// roadGeometryCache keeps the conzone polylines and VDS locations in memory so polling
+// /road/status does not read both tables every time. They are reloaded when the row
+// counts or last updates of the tables change.
+type roadGeometryCache struct {
+	mu      sync.Mutex
+	lines   map[string][][2]float64
+	vds     map[string][2]float64
+	version string
+	checked time.Time
+}
+
+// roadGeometry returns the cached conzone polylines and VDS locations; callers must not
+// modify the maps
+func (s *Server) roadGeometry(ctx context.Context, handler string) (map[string][][2]float64, map[string][2]float64, error) {
+	c := &s.geometry
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lines != nil && time.Since(c.checked) < roadGeometryCheck {
+		return c.lines, c.vds, nil
+	}
+
+	var version string
+	err := s.queryRow(ctx, handler, `SELECT CONCAT_WS('/',
+		(SELECT COUNT(*) FROM road_conzone_geometry), (SELECT MAX(updated_at) FROM road_conzone_geometry),
+		(SELECT COUNT(*) FROM road_vds_location), (SELECT MAX(updated_at) FROM road_vds_location))`).Scan(&version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.lines != nil && version == c.version {
+		c.checked = time.Now()
+		return c.lines, c.vds, nil
+	}
+
+	lines, err := s.conzoneGeometries(ctx, handler, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	vds, err := s.vdsLocations(ctx, handler)
+	if err != nil {
+		return nil, nil, err
+	}
+	loggerFrom(ctx).Info("loaded road geometry", "conzones", len(lines), "vds", len(vds))
+	c.lines, c.vds, c.version, c.checked = lines, vds, version, time.Now()
+	return lines, vds, nil
+}
+
+// locatedRoadStatuses joins the latest road statuses with the cached road geometry;
+// sections and VDSs missing from the geometry tables are returned without location
+func (s *Server) locatedRoadStatuses(ctx context.Context, handler string, routeNos ...string) ([]LocatedRoadStatus, error) {
+	statuses, err := s.roadStatuses(ctx, handler, routeNos...)
+	if err != nil {
+		return nil, err
+	}
+	lines, vdsLocations, err := s.roadGeometry(ctx, handler)
+	if err != nil {
+		return nil, err
+	}
+
+	located := make([]LocatedRoadStatus, len(statuses))
+	for i, rs := range statuses {
+		located[i].RoadStatus = rs
+		if line, ok := lines[rs.ConzoneID]; ok {
+			geometry := lineString(line)
+			located[i].Geometry = &geometry
+		}
+		if loc, ok := vdsLocations[rs.VdsID]; ok {
+			located[i].VdsLongitude, located[i].VdsLatitude = &loc[0], &loc[1]
+		}
+	}
+	return located, nil
+}
+
+// TollgateLocation is a tollgate feature's properties
+type TollgateLocation struct {
+	Tollgate
+	TrafficAmount int     `json:"trafficAmount"` // all lanes and vehicle types at the latest collection
+	Latitude      float64 `json:"-"`
+	Longitude     float64 `json:"-"`
+}
+
+// ConzoneSection is a road section feature's properties; Color follows the grade
+type ConzoneSection struct {
+	RoadStatus
+	Color string `json:"color"`
+}
+
+// gradeColor is the map color of a congestion grade (1:원활, 2:서행, 3:정체), gray when
+// the grade is unknown
+func gradeColor(grade int) string {
+	switch grade {
+	case 1:
+		return "#22c55e"
+	case 2:
+		return "#eab308"
+	case 3:
+		return "#ef4444"
+	}
+	return "#9ca3af"
+}
+
+// inKorea drops coordinates that are missing (0) or garbled, with the same bounds as the
+// dashboard map
+func inKorea(lon, lat float64) bool {
+	return lat >= 33 && lat <= 39 && lon >= 124 && lon <= 132
+}
+
+// lonLatBounds is a WGS84 bounding box
+type lonLatBounds struct {
+	West, South, East, North float64
+}
+
+func (b lonLatBounds) wkt() string {
+	return fmt.Sprintf("POLYGON((%[1]f %[2]f, %[3]f %[2]f, %[3]f %[4]f, %[1]f %[4]f, %[1]f %[2]f))",
+		b.West, b.South, b.East, b.North)
+}
+
+// routeParam reads the comma separated route numbers of the route query parameter
+func routeParam(r *http.Request) []string {
+	var routeNos []string
+	for _, no := range strings.Split(r.URL.Query().Get("route"), ",") {
+		if no = strings.TrimSpace(no); no != "" {
+			routeNos = append(routeNos, no)
+		}
+	}
+	return routeNos
+}
+
+func writeGeoJSON(w http.ResponseWriter, r *http.Request, fc FeatureCollection) {
+	w.Header().Set("Content-Type", geoJSONContentType)
+	if err := json.NewEncoder(w).Encode(fc); err != nil {
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
+	} else {
+		loggerFrom(r.Context()).Debug("returned features", "count", len(fc.Features))
+	}
+}
+
+func (s *Server) getAccidentFeatures(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	// The map shows every accident of the window by default
+	limit := 1000
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 1000 {
+			limit = l
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	accidents, err := s.latestAccidents(ctx, apiV1+"/geo/accidents", limit)
+	if err != nil {
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+		return
+	}
+
+	fc := newFeatureCollection()
+	for _, acc := range accidents {
+		// altitude is longitude in Korean highway API
+		if acc.Latitude == nil || acc.Altitude == nil || !inKorea(*acc.Altitude, *acc.Latitude) {
+			continue
+		}
+		fc.add(strconv.Itoa(acc.ID), point(*acc.Altitude, *acc.Latitude), acc)
+	}
+	writeGeoJSON(w, r, fc)
+}
+
+func (s *Server) getTollgateFeatures(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	tollgates, err := s.tollgateLocations(ctx, apiV1+"/geo/tollgates")
+	if err != nil {
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+		return
+	}
+
+	fc := newFeatureCollection()
+	for _, t := range tollgates {
+		fc.add(t.UnitCode, point(t.Longitude, t.Latitude), t)
+	}
+	writeGeoJSON(w, r, fc)
+}
+
+func (s *Server) getConzoneFeatures(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+	defer cancel()
+
+	handler := apiV1 + "/geo/conzones"
+	statuses, err := s.roadStatuses(ctx, handler, routeParam(r)...)
+	if err != nil {
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+		return
+	}
+	lines, _, err := s.roadGeometry(ctx, handler)
+	if err != nil {
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+		return
+	}
+
+	fc := newFeatureCollection()
+	for _, rs := range statuses {
+		line, ok := lines[rs.ConzoneID]
+		if !ok {
+			continue
+		}
+		fc.add(rs.ConzoneID+"-"+rs.UpdownTypeCode, lineString(line), ConzoneSection{RoadStatus: rs, Color: gradeColor(rs.Grade)})
+	}
+	writeGeoJSON(w, r, fc)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGradeColor(t *testing.T) {
+	tests := []struct {
+		grade int
+		want  string
+	}{
+		{grade: 1, want: "#22c55e"},
+		{grade: 2, want: "#eab308"},
+		{grade: 3, want: "#ef4444"},
+		{grade: 0, want: "#9ca3af"},
+		{grade: 9, want: "#9ca3af"},
+	}
+	for _, tt := range tests {
+		if got := gradeColor(tt.grade); got != tt.want {
+			t.Errorf("gradeColor(%d) = %s, want %s", tt.grade, got, tt.want)
+		}
+	}
+}
+
+func TestInKorea(t *testing.T) {
+	tests := []struct {
+		lon, lat float64
+		want     bool
+	}{
+		{lon: 126.978, lat: 37.5665, want: true}, // Seoul
+		{lon: 126.531, lat: 33.4996, want: true}, // Jeju
+		{lon: 0, lat: 0, want: false},
+		{lon: 37.5665, lat: 126.978, want: false}, // swapped
+		{lon: 132.5, lat: 37, want: false},
+		{lon: 127, lat: 39.5, want: false},
+	}
+	for _, tt := range tests {
+		if got := inKorea(tt.lon, tt.lat); got != tt.want {
+			t.Errorf("inKorea(%v, %v) = %v, want %v", tt.lon, tt.lat, got, tt.want)
+		}
+	}
+}
+
+func TestLonLatBoundsWKT(t *testing.T) {
+	b := lonLatBounds{West: 126.5, South: 37, East: 127.25, North: 37.75}
+	want := "POLYGON((126.500000 37.000000, 127.250000 37.000000, 127.250000 37.750000, 126.500000 37.750000, 126.500000 37.000000))"
+	if got := b.wkt(); got != want {
+		t.Errorf("wkt = %s, want %s", got, want)
+	}
+}
+
+func TestRouteParam(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: nil},
+		{query: "route=", want: nil},
+		{query: "route=0010", want: []string{"0010"}},
+		{query: "route=0010,%200500%20,,1000", want: []string{"0010", "0500", "1000"}},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, apiV1+"/geo/conzones?"+tt.query, nil)
+		if got := routeParam(r); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("routeParam(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestWriteGeoJSON(t *testing.T) {
+	fc := newFeatureCollection()
+	w := httptest.NewRecorder()
+	writeGeoJSON(w, httptest.NewRequest(http.MethodGet, apiV1+"/geo/accidents", nil), fc)
+	if got := w.Body.String(); got != `{"type":"FeatureCollection","features":[]}`+"\n" {
+		t.Errorf("empty collection = %s, want an empty features array", got)
+	}
+
+	fc.add("7", point(127, 37.5), map[string]int{"id": 7})
+	fc.add("0010-1-E", lineString([][2]float64{{127, 37.5}, {127.1, 37.6}}), nil)
+	w = httptest.NewRecorder()
+	writeGeoJSON(w, httptest.NewRequest(http.MethodGet, apiV1+"/geo/accidents", nil), fc)
+	want := `{"type":"FeatureCollection","features":[` +
+		`{"type":"Feature","id":"7","geometry":{"type":"Point","coordinates":[127,37.5]},"properties":{"id":7}},` +
+		`{"type":"Feature","id":"0010-1-E","geometry":{"type":"LineString","coordinates":[[127,37.5],[127.1,37.6]]},"properties":null}]}` + "\n"
+	if got := w.Body.String(); got != want || w.Header().Get("Content-Type") != geoJSONContentType {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
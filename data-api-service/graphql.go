@@ -0,0 +1,466 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// graphqlPath serves the GraphQL API, for clients that want a route together with its
+// sections, accidents and tollgates in one request
+const graphqlPath = apiV1 + "/graphql"
+
+// Tollgate is a tollgate from tollgate_master
+type Tollgate struct {
+	UnitCode    string     `json:"unitCode"`
+	UnitName    string     `json:"unitName"`
+	ExDivName   string     `json:"exDivName"`
+	LastUpdated *time.Time `json:"lastUpdated"`
+}
+
+// graphqlLoaders batch the nested lookups of one request
+type graphqlLoaders struct {
+	sections  *loader[string, []RoadStatus]          // by route_no
+	accidents *loader[routeAccidentsKey, []Accident] // by route_no and limit
+	tollgates *loader[string, []Tollgate]            // by route_no
+	samples   *loader[string, []TollgateTrafficData] // by unit_code
+}
+
+type loadersKey struct{}
+
+func (s *Server) withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &graphqlLoaders{
+		sections:  newLoader("sections", s.sectionsByRoute),
+		accidents: newLoader("accidents", s.accidentsByRoute),
+		tollgates: newLoader("tollgates", s.tollgatesByRoute),
+		samples:   newLoader("samples", s.samplesByTollgate),
+	})
+}
+
+func loadersFrom(ctx context.Context) *graphqlLoaders {
+	return ctx.Value(loadersKey{}).(*graphqlLoaders)
+}
+
+// graphqlSchema builds the schema. It has no cycles (a route's tollgates do not link
+// back to routes), so query depth is bounded by the schema itself.
+func (s *Server) graphqlSchema() (graphql.Schema, error) {
+	accidentType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Accident",
+		Description: "An accident reported in the last 24 hours",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"accDate":    &graphql.Field{Type: graphql.String, Description: "YYYYMMDD"},
+			"accHour":    &graphql.Field{Type: graphql.String, Description: "HHMM"},
+			"accPointNM": &graphql.Field{Type: graphql.String},
+			"roadNM":     &graphql.Field{Type: graphql.String},
+			"nosunNM":    &graphql.Field{Type: graphql.String, Description: "Route name"},
+			"smsText":    &graphql.Field{Type: graphql.String},
+			"accType":    &graphql.Field{Type: graphql.String},
+			"latitude":   &graphql.Field{Type: graphql.Float},
+			"altitude":   &graphql.Field{Type: graphql.Float, Description: "Longitude (named after the source API)"},
+			"createdAt":  &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	roadSectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RoadSection",
+		Description: "Latest traffic status of one conzone and direction",
+		Fields: graphql.Fields{
+			"routeNo":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"routeName":      &graphql.Field{Type: graphql.String},
+			"conzoneId":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"conzoneName":    &graphql.Field{Type: graphql.String},
+			"vdsId":          &graphql.Field{Type: graphql.String},
+			"updownTypeCode": &graphql.Field{Type: graphql.String, Description: "S: towards the start, E: towards the end"},
+			"trafficAmount":  &graphql.Field{Type: graphql.Int},
+			"speed":          &graphql.Field{Type: graphql.Int, Description: "km/h"},
+			"shareRatio":     &graphql.Field{Type: graphql.Int},
+			"timeAvg":        &graphql.Field{Type: graphql.Int},
+			"grade":          &graphql.Field{Type: graphql.Int, Description: "0: unknown, 1: smooth, 2: slow, 3: congested"},
+			"collectedAt":    &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	tollgateSampleType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TollgateSample",
+		Description: "Traffic through a tollgate in one 15-minute interval, all lanes and vehicle types",
+		Fields: graphql.Fields{
+			"collectedAt":   &graphql.Field{Type: graphql.DateTime},
+			"trafficAmount": &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	tollgateType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Tollgate",
+		Fields: graphql.Fields{
+			"unitCode":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"unitName":    &graphql.Field{Type: graphql.String},
+			"exDivName":   &graphql.Field{Type: graphql.String},
+			"lastUpdated": &graphql.Field{Type: graphql.DateTime},
+			"samples": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tollgateSampleType))),
+				Description: "Samples of the 3 hours up to the latest collection, newest first",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).samples.load(p.Context, p.Source.(Tollgate).UnitCode), nil
+				},
+			},
+		},
+	})
+
+	routeSummaryType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RouteSummary",
+		Description: "Latest congestion summary of a route",
+		Fields: graphql.Fields{
+			"routeNo":           &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"routeName":         &graphql.Field{Type: graphql.String},
+			"totalSections":     &graphql.Field{Type: graphql.Int},
+			"smoothSections":    &graphql.Field{Type: graphql.Int},
+			"slowSections":      &graphql.Field{Type: graphql.Int},
+			"congestedSections": &graphql.Field{Type: graphql.Int},
+			"avgSpeed":          &graphql.Field{Type: graphql.Float},
+			"avgTrafficAmount":  &graphql.Field{Type: graphql.Float},
+			"collectedAt":       &graphql.Field{Type: graphql.DateTime},
+			"sections": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(roadSectionType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).sections.load(p.Context, p.Source.(RoadRouteSummary).RouteNo), nil
+				},
+			},
+			"accidents": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(accidentType))),
+				Description: "Accidents of the last 24 hours matched to the route (accident_road_match), newest first",
+				Args: graphql.FieldConfigArgument{
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 20},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, _ := p.Args["limit"].(int)
+					if limit <= 0 || limit > 1000 {
+						return nil, fmt.Errorf("limit must be between 1 and 1000")
+					}
+					key := routeAccidentsKey{RouteNo: p.Source.(RoadRouteSummary).RouteNo, Limit: limit}
+					return loadersFrom(p.Context).accidents.load(p.Context, key), nil
+				},
+			},
+			"tollgates": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tollgateType))),
+				Description: "Tollgates on the route, in route order (tollgate_route)",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).tollgates.load(p.Context, p.Source.(RoadRouteSummary).RouteNo), nil
+				},
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"routes": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(routeSummaryType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return s.routeSummaries(p.Context, graphqlPath, "")
+				},
+			},
+			"route": &graphql.Field{
+				Type: routeSummaryType,
+				Args: graphql.FieldConfigArgument{
+					"routeNo": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					summaries, err := s.routeSummaries(p.Context, graphqlPath, p.Args["routeNo"].(string))
+					if err != nil || len(summaries) == 0 {
+						return nil, err
+					}
+					return summaries[0], nil
+				},
+			},
+			"accidents": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(accidentType))),
+				Description: "Accidents of the last 24 hours, newest first",
+				Args: graphql.FieldConfigArgument{
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 100},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					limit, _ := p.Args["limit"].(int)
+					if limit <= 0 || limit > 1000 {
+						return nil, fmt.Errorf("limit must be between 1 and 1000")
+					}
+					return s.latestAccidents(p.Context, graphqlPath, limit)
+				},
+			},
+			"tollgates": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tollgateType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return s.activeTollgates(p.Context, "")
+				},
+			},
+			"tollgate": &graphql.Field{
+				Type: tollgateType,
+				Args: graphql.FieldConfigArgument{
+					"unitCode": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					tollgates, err := s.activeTollgates(p.Context, p.Args["unitCode"].(string))
+					if err != nil || len(tollgates) == 0 {
+						return nil, err
+					}
+					return tollgates[0], nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// routeAccidentsKey asks for the newest Limit accidents of a route
+type routeAccidentsKey struct {
+	RouteNo string
+	Limit   int
+}
+
+// accidentsByRoute loads the last 24 hours of accidents of several routes at once. Routes
+// come from data-processor's map matching rather than the free-text nosun_nm, and each
+// route is cut to the largest requested limit in SQL so one busy route cannot flood the
+// batch.
+func (s *Server) accidentsByRoute(ctx context.Context, keys []routeAccidentsKey) (map[routeAccidentsKey][]Accident, error) {
+	out := make(map[routeAccidentsKey][]Accident, len(keys))
+	var routeNos []string
+	seen := make(map[string]bool)
+	maxLimit := 0
+	for _, key := range keys {
+		out[key] = []Accident{}
+		if !seen[key.RouteNo] {
+			seen[key.RouteNo] = true
+			routeNos = append(routeNos, key.RouteNo)
+		}
+		maxLimit = max(maxLimit, key.Limit)
+	}
+
+	args := append([]interface{}{time.Now().Add(-24 * time.Hour)}, anyArgs(routeNos)...)
+	args = append(args, maxLimit)
+	rows, err := s.query(ctx, graphqlPath, `SELECT `+accidentColumns+`, route_no
+		FROM (
+			SELECT a.*, m.route_no, ROW_NUMBER() OVER (
+				PARTITION BY m.route_no ORDER BY a.acc_date DESC, a.acc_hour DESC, a.created_at DESC) AS rn
+			FROM traffic_accidents a
+			JOIN accident_road_match m ON m.accident_id = a.id
+			WHERE a.created_at >= ? AND m.route_no IN (`+placeholders(len(routeNos))+`)
+		) ranked
+		WHERE rn <= ?
+		ORDER BY route_no, rn`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byRoute := make(map[string][]Accident, len(routeNos))
+	for rows.Next() {
+		var routeNo string
+		acc, err := scanAccident(rows, &routeNo)
+		if err != nil {
+			loggerFrom(ctx).Error("scan failed", "error", err)
+			continue
+		}
+		byRoute[routeNo] = append(byRoute[routeNo], acc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		accidents := byRoute[key.RouteNo]
+		if len(accidents) > key.Limit {
+			accidents = accidents[:key.Limit]
+		}
+		if accidents != nil {
+			out[key] = accidents
+		}
+	}
+	return out, nil
+}
+
+// sectionsByRoute loads the latest conzone status of several routes at once, aggregated
+// like /api/v1/road/status
+func (s *Server) sectionsByRoute(ctx context.Context, routeNos []string) (map[string][]RoadStatus, error) {
+	out := make(map[string][]RoadStatus, len(routeNos))
+	for _, no := range routeNos {
+		out[no] = []RoadStatus{}
+	}
+
+	statuses, err := s.roadStatuses(ctx, graphqlPath, routeNos...)
+	if err != nil {
+		return nil, err
+	}
+	for _, rs := range statuses {
+		out[rs.RouteNo] = append(out[rs.RouteNo], rs)
+	}
+	return out, nil
+}
+
+// activeTollgates lists active tollgates, or the one with unitCode when set
+func (s *Server) activeTollgates(ctx context.Context, unitCode string) ([]Tollgate, error) {
+	rows, err := s.query(ctx, graphqlPath, `
+		SELECT unit_code, unit_name, ex_div_name, last_collected_at
+		FROM tollgate_master
+		WHERE is_active AND (? = '' OR unit_code = ?)
+		ORDER BY unit_code`, unitCode, unitCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tollgates := []Tollgate{}
+	for rows.Next() {
+		t, err := scanTollgate(rows, nil)
+		if err != nil {
+			return nil, err
+		}
+		tollgates = append(tollgates, t)
+	}
+	return tollgates, rows.Err()
+}
+
+// scanTollgate scans a tollgate row, preceded by the columns in dest when given
+func scanTollgate(rows *sql.Rows, dest ...interface{}) (Tollgate, error) {
+	var t Tollgate
+	var last sql.NullTime
+	if err := rows.Scan(append(dest, &t.UnitCode, &t.UnitName, &t.ExDivName, &last)...); err != nil {
+		return t, err
+	}
+	if last.Valid {
+		t.LastUpdated = &last.Time
+	}
+	return t, nil
+}
+
+// tollgatesByRoute loads the tollgates of several routes at once from the tollgate_route
+// mapping
+func (s *Server) tollgatesByRoute(ctx context.Context, routeNos []string) (map[string][]Tollgate, error) {
+	out := make(map[string][]Tollgate, len(routeNos))
+	for _, no := range routeNos {
+		out[no] = []Tollgate{}
+	}
+
+	rows, err := s.query(ctx, graphqlPath, `
+		SELECT tr.route_no, m.unit_code, m.unit_name, m.ex_div_name, m.last_collected_at
+		FROM tollgate_route tr
+		JOIN tollgate_master m ON m.unit_code = tr.unit_code
+		WHERE tr.route_no IN (`+placeholders(len(routeNos))+`)
+		ORDER BY tr.route_no, tr.seq`, anyArgs(routeNos)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var routeNo string
+		t, err := scanTollgate(rows, &routeNo)
+		if err != nil {
+			return nil, err
+		}
+		out[routeNo] = append(out[routeNo], t)
+	}
+	return out, rows.Err()
+}
+
+// samplesByTollgate loads the samples of several tollgates at once, covering the 3 hours
+// up to the latest collection like /api/v1/tollgate/traffic
+func (s *Server) samplesByTollgate(ctx context.Context, unitCodes []string) (map[string][]TollgateTrafficData, error) {
+	out := make(map[string][]TollgateTrafficData, len(unitCodes))
+	for _, code := range unitCodes {
+		out[code] = []TollgateTrafficData{}
+	}
+
+	rows, err := s.query(ctx, graphqlPath, `
+		SELECT unit_code, collected_at, SUM(traffic_amount)
+		FROM tollgate_traffic_history
+		WHERE unit_code IN (`+placeholders(len(unitCodes))+`)
+		  AND collected_at >= (SELECT MAX(collected_at) FROM tollgate_traffic_history) - INTERVAL 3 HOUR
+		GROUP BY unit_code, collected_at
+		ORDER BY unit_code, collected_at DESC`, anyArgs(unitCodes)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code string
+		var sample TollgateTrafficData
+		if err := rows.Scan(&code, &sample.CollectedAt, &sample.TrafficAmount); err != nil {
+			return nil, err
+		}
+		out[code] = append(out[code], sample)
+	}
+	return out, rows.Err()
+}
+
+// graphqlRequest is a GraphQL-over-HTTP request body
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphqlHandler serves GraphQL over HTTP: POST with a JSON body, or GET with query,
+// operationName and variables parameters. Field errors are reported in the result's
+// errors with status 200, as GraphQL clients expect; malformed requests get an APIError.
+func (s *Server) graphqlHandler(schema graphql.Schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		switch r.Method {
+		case http.MethodGet:
+			q := r.URL.Query()
+			req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+			if vars := q.Get("variables"); vars != "" {
+				if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+					writeError(w, http.StatusBadRequest, errBadRequest, "variables must be a JSON object", nil)
+					return
+				}
+			}
+		case http.MethodPost:
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, errBadRequest, "invalid GraphQL request body",
+					map[string]string{"error": err.Error()})
+				return
+			}
+		case http.MethodOptions:
+			// CORS is handled by the API Gateway
+			w.WriteHeader(http.StatusOK)
+			return
+		default:
+			w.Header().Set("Allow", "GET, POST, OPTIONS")
+			writeError(w, http.StatusMethodNotAllowed, errMethodNotAllowed, "method not allowed",
+				map[string]interface{}{"method": r.Method, "allowed": []string{http.MethodGet, http.MethodPost}})
+			return
+		}
+		if req.Query == "" {
+			writeError(w, http.StatusBadRequest, errBadRequest, "query is required", nil)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+		defer cancel()
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			OperationName:  req.OperationName,
+			VariableValues: req.Variables,
+			Context:        s.withLoaders(ctx),
+		})
+		if len(result.Errors) > 0 {
+			loggerFrom(r.Context()).Info("graphql errors", "count", len(result.Errors), "first", result.Errors[0].Message)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			loggerFrom(r.Context()).Error("encode failed", "error", err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLHandler(t *testing.T) {
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/traffic?timeout=100ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s := &Server{db: db}
+	schema, err := s.graphqlSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := s.graphqlHandler(schema)
+
+	get := func(params url.Values) *http.Request {
+		return httptest.NewRequest(http.MethodGet, graphqlPath+"?"+params.Encode(), nil)
+	}
+	post := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, graphqlPath, strings.NewReader(body))
+	}
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+		wantCode   string // APIError code of malformed requests
+		wantData   string // a key of data that must be set
+		wantErrors bool   // GraphQL errors in the result
+	}{
+		{name: "introspection over GET", req: get(url.Values{"query": {"{ __schema { queryType { name } } }"}}), wantStatus: 200, wantData: "__schema"},
+		{name: "introspection over POST", req: post(`{"query":"query Q { __type(name: \"RouteSummary\") { name } }","operationName":"Q"}`), wantStatus: 200, wantData: "__type"},
+		{name: "variables over GET", req: get(url.Values{"query": {"query($n: String!) { __type(name: $n) { name } }"}, "variables": {`{"n":"Tollgate"}`}}), wantStatus: 200, wantData: "__type"},
+		{name: "unknown field", req: post(`{"query":"{ nope }"}`), wantStatus: 200, wantErrors: true},
+		{name: "resolver errors are GraphQL errors", req: post(`{"query":"{ routes { routeNo } }"}`), wantStatus: 200, wantErrors: true},
+		{name: "missing query", req: get(url.Values{}), wantStatus: 400, wantCode: errBadRequest},
+		{name: "invalid variables", req: get(url.Values{"query": {"{ routes { routeNo } }"}, "variables": {"[1]"}}), wantStatus: 400, wantCode: errBadRequest},
+		{name: "invalid body", req: post(`{"query":`), wantStatus: 400, wantCode: errBadRequest},
+		{name: "method not allowed", req: httptest.NewRequest(http.MethodPut, graphqlPath, nil), wantStatus: 405, wantCode: errMethodNotAllowed},
+		{name: "preflight", req: httptest.NewRequest(http.MethodOptions, graphqlPath, nil), wantStatus: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h(w, tt.req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode != "" {
+				var body APIError
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Code != tt.wantCode {
+					t.Errorf("body %s, want code %s", w.Body, tt.wantCode)
+				}
+				return
+			}
+			if tt.req.Method == http.MethodOptions {
+				return
+			}
+
+			var result struct {
+				Data   map[string]interface{} `json:"data"`
+				Errors []interface{}          `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatal(err)
+			}
+			if (len(result.Errors) > 0) != tt.wantErrors {
+				t.Errorf("errors = %v, want errors %v", result.Errors, tt.wantErrors)
+			}
+			if tt.wantData != "" && result.Data[tt.wantData] == nil {
+				t.Errorf("data = %v, want %s set", result.Data, tt.wantData)
+			}
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"time"
+
+	"data-api-service/trafficpb"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	grpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_request_duration_seconds",
+		Help:    "gRPC call latency per method and status code; streams are observed when they end.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_requests_total",
+		Help: "gRPC calls per method and status code.",
+	}, []string{"method", "code"})
+
+	grpcActiveStreams = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "grpc_active_streams",
+		Help: "Open Watch streams per method.",
+	}, []string{"method"})
+)
+
+// grpcServer serves trafficpb.TrafficData from the same queries as the JSON API; the
+// full method name labels the query metrics
+type grpcServer struct {
+	trafficpb.UnimplementedTrafficDataServer
+	s *Server
+	// stopping is closed on shutdown to end Watch streams, which GracefulStop would
+	// otherwise wait on forever
+	stopping <-chan struct{}
+}
+
+// newGRPCServer builds the gRPC server with health checks and reflection (for grpcurl);
+// Watch streams end when stopping is closed
+func (s *Server) newGRPCServer(stopping <-chan struct{}) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryObserver),
+		grpc.ChainStreamInterceptor(streamObserver),
+	)
+	trafficpb.RegisterTrafficDataServer(server, &grpcServer{s: s, stopping: stopping})
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	return server
+}
+
+// serveGRPC serves gRPC until ctx is cancelled, then ends Watch streams with UNAVAILABLE
+// so clients reconnect elsewhere and lets unary calls finish
+func (s *Server) serveGRPC(ctx context.Context) error {
+	addr := ":" + s.config.GRPCPort
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := s.newGRPCServer(ctx.Done())
+	slog.Info("gRPC server starting", "addr", addr)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(lis)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		server.Stop()
+	}
+	return nil
+}
+
+// grpcLogger returns a logger carrying the caller's X-Request-ID metadata (or a new ID)
+// and trace ID, like withRequestID does for HTTP
+func grpcLogger(ctx context.Context) *slog.Logger {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(requestIDHeader); len(v) > 0 && validRequestID(v[0]) {
+			id = v[0]
+		}
+	}
+	if id == "" {
+		id = newRequestID()
+	}
+	logger := slog.Default().With("request_id", id)
+	if sc := trace.SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+		logger = logger.With("trace_id", sc.TraceID().String())
+	}
+	return logger
+}
+
+// observe records the outcome of one call and logs it
+func observe(logger *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	grpcRequests.WithLabelValues(method, code.String()).Inc()
+	grpcDuration.WithLabelValues(method, code.String()).Observe(time.Since(start).Seconds())
+	logger.Info("request", "method", method, "code", code.String(), "duration", time.Since(start))
+}
+
+func unaryObserver(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	// Health checks would drown out everything else, like /health for HTTP
+	if info.FullMethod == healthpb.Health_Check_FullMethodName {
+		return handler(ctx, req)
+	}
+	start := time.Now()
+	logger := grpcLogger(ctx)
+	resp, err := handler(context.WithValue(ctx, loggerKey{}, logger), req)
+	observe(logger, info.FullMethod, start, err)
+	return resp, err
+}
+
+// loggedStream hands the request-scoped logger to stream handlers
+type loggedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *loggedStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamObserver(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	logger := grpcLogger(ss.Context())
+	active := grpcActiveStreams.WithLabelValues(info.FullMethod)
+	active.Inc()
+	defer active.Dec()
+
+	err := handler(srv, &loggedStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), loggerKey{}, logger)})
+	observe(logger, info.FullMethod, start, err)
+	return err
+}
+
+// queryError logs a failed query and hides its details from the caller
+func queryError(ctx context.Context, err error) error {
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	loggerFrom(ctx).Error("query failed", "error", err)
+	return status.Error(codes.Internal, "internal server error")
+}
+
+func (g *grpcServer) GetLatestAccidents(ctx context.Context, req *trafficpb.GetLatestAccidentsRequest) (*trafficpb.GetLatestAccidentsResponse, error) {
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = 100
+	}
+	if limit < 1 || limit > 1000 {
+		return nil, status.Error(codes.InvalidArgument, "limit must be between 1 and 1000")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	accidents, err := g.s.latestAccidents(ctx, trafficpb.TrafficData_GetLatestAccidents_FullMethodName, limit)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	resp := &trafficpb.GetLatestAccidentsResponse{Accidents: make([]*trafficpb.Accident, len(accidents))}
+	for i, acc := range accidents {
+		resp.Accidents[i] = accidentProto(acc)
+	}
+	return resp, nil
+}
+
+func (g *grpcServer) GetRoadStatus(ctx context.Context, _ *trafficpb.GetRoadStatusRequest) (*trafficpb.GetRoadStatusResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	return g.roadStatus(ctx, trafficpb.TrafficData_GetRoadStatus_FullMethodName)
+}
+
+func (g *grpcServer) GetRoadRouteSummary(ctx context.Context, _ *trafficpb.GetRoadRouteSummaryRequest) (*trafficpb.GetRoadRouteSummaryResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return g.routeSummary(ctx, trafficpb.TrafficData_GetRoadRouteSummary_FullMethodName)
+}
+
+func (g *grpcServer) GetTollgateTraffic(ctx context.Context, _ *trafficpb.GetTollgateTrafficRequest) (*trafficpb.GetTollgateTrafficResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return g.tollgateTraffic(ctx, trafficpb.TrafficData_GetTollgateTraffic_FullMethodName)
+}
+
+func (g *grpcServer) roadStatus(ctx context.Context, method string) (*trafficpb.GetRoadStatusResponse, error) {
+	statuses, err := g.s.locatedRoadStatuses(ctx, method)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	resp := &trafficpb.GetRoadStatusResponse{RoadStatuses: make([]*trafficpb.RoadStatus, len(statuses))}
+	for i, rs := range statuses {
+		var geometry []*trafficpb.LonLat
+		if rs.Geometry != nil {
+			for _, c := range rs.Geometry.Coordinates.([][2]float64) {
+				geometry = append(geometry, &trafficpb.LonLat{Longitude: c[0], Latitude: c[1]})
+			}
+		}
+		resp.RoadStatuses[i] = &trafficpb.RoadStatus{
+			RouteNo:        rs.RouteNo,
+			RouteName:      rs.RouteName,
+			ConzoneId:      rs.ConzoneID,
+			ConzoneName:    rs.ConzoneName,
+			VdsId:          rs.VdsID,
+			UpdownTypeCode: rs.UpdownTypeCode,
+			TrafficAmount:  int32(rs.TrafficAmount),
+			Speed:          int32(rs.Speed),
+			ShareRatio:     int32(rs.ShareRatio),
+			TimeAvg:        int32(rs.TimeAvg),
+			Grade:          int32(rs.Grade),
+			CollectedAt:    timestamppb.New(rs.CollectedAt),
+			Geometry:       geometry,
+			VdsLatitude:    rs.VdsLatitude,
+			VdsLongitude:   rs.VdsLongitude,
+		}
+	}
+	return resp, nil
+}
+
+func (g *grpcServer) routeSummary(ctx context.Context, method string) (*trafficpb.GetRoadRouteSummaryResponse, error) {
+	summaries, err := g.s.routeSummaries(ctx, method, "")
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	resp := &trafficpb.GetRoadRouteSummaryResponse{Summaries: make([]*trafficpb.RoadRouteSummary, len(summaries))}
+	for i, rs := range summaries {
+		resp.Summaries[i] = &trafficpb.RoadRouteSummary{
+			RouteNo:           rs.RouteNo,
+			RouteName:         rs.RouteName,
+			TotalSections:     int32(rs.TotalSections),
+			SmoothSections:    int32(rs.SmoothSections),
+			SlowSections:      int32(rs.SlowSections),
+			CongestedSections: int32(rs.CongestedSections),
+			AvgSpeed:          rs.AvgSpeed,
+			AvgTrafficAmount:  rs.AvgTrafficAmount,
+			CollectedAt:       timestamppb.New(rs.CollectedAt),
+		}
+	}
+	return resp, nil
+}
+
+func (g *grpcServer) tollgateTraffic(ctx context.Context, method string) (*trafficpb.GetTollgateTrafficResponse, error) {
+	tollgates, err := g.s.tollgateTraffic(ctx, method)
+	if err != nil {
+		return nil, queryError(ctx, err)
+	}
+	resp := &trafficpb.GetTollgateTrafficResponse{Tollgates: make([]*trafficpb.TollgateTraffic, len(tollgates))}
+	for i, tg := range tollgates {
+		data := make([]*trafficpb.TollgateTrafficData, len(tg.TrafficData))
+		for j, d := range tg.TrafficData {
+			data[j] = &trafficpb.TollgateTrafficData{
+				CollectedAt:   timestamppb.New(d.CollectedAt),
+				TrafficAmount: int32(d.TrafficAmount),
+			}
+		}
+		resp.Tollgates[i] = &trafficpb.TollgateTraffic{
+			UnitCode:    tg.UnitCode,
+			UnitName:    tg.UnitName,
+			ExDivName:   tg.ExDivName,
+			TrafficData: data,
+			LastUpdated: timestamppb.New(tg.LastUpdated),
+		}
+	}
+	return resp, nil
+}
+
+func accidentProto(acc Accident) *trafficpb.Accident {
+	return &trafficpb.Accident{
+		Id:         int64(acc.ID),
+		AccDate:    acc.AccDate,
+		AccHour:    acc.AccHour,
+		AccPointNm: acc.AccPointNM,
+		RoadNm:     acc.RoadNM,
+		NosunNm:    acc.NosunNM,
+		SmsText:    acc.SmsText,
+		AccType:    acc.AccType,
+		Latitude:   acc.Latitude,
+		Longitude:  acc.Altitude,
+		CreatedAt:  timestamppb.New(acc.CreatedAt),
+	}
+}
+
+// poll calls fn right away and then every GRPC_WATCH_INTERVAL until the stream ends.
+// Query failures are logged and retried on the next tick so a database blip does not
+// drop every subscriber; only send failures end the stream.
+func (g *grpcServer) poll(ctx context.Context, fn func(ctx context.Context) error) error {
+	ticker := time.NewTicker(g.s.config.GRPCWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		tickCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+		err := fn(tickCtx)
+		cancel()
+		var sendErr *sendError
+		if errors.As(err, &sendErr) {
+			return sendErr.err
+		}
+		if err != nil && ctx.Err() == nil {
+			loggerFrom(ctx).Warn("watch poll failed, retrying", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-g.stopping:
+			return status.Error(codes.Unavailable, "server shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendError marks a failed stream Send, which ends a watch
+type sendError struct{ err error }
+
+func (e *sendError) Error() string { return e.err.Error() }
+
+// watchSnapshots sends a snapshot whenever the newest collected_at of table moves
+func (g *grpcServer) watchSnapshots(ctx context.Context, method, table string, send func(ctx context.Context) error) error {
+	var sent time.Time
+	first := true
+	return g.poll(ctx, func(ctx context.Context) error {
+		latest, err := g.s.latestCollection(ctx, method, table)
+		if err != nil {
+			return err
+		}
+		if !first && latest.Equal(sent) {
+			return nil
+		}
+		if err := send(ctx); err != nil {
+			return err
+		}
+		sent, first = latest, false
+		return nil
+	})
+}
+
+func (g *grpcServer) WatchAccidents(_ *trafficpb.WatchAccidentsRequest, stream grpc.ServerStreamingServer[trafficpb.Accident]) error {
+	method := trafficpb.TrafficData_WatchAccidents_FullMethodName
+	lastID := 0
+	return g.poll(stream.Context(), func(ctx context.Context) error {
+		accidents, err := g.s.accidentsAfter(ctx, method, lastID)
+		if err != nil {
+			return err
+		}
+		for _, acc := range accidents {
+			if err := stream.Send(accidentProto(acc)); err != nil {
+				return &sendError{err}
+			}
+			lastID = acc.ID
+		}
+		return nil
+	})
+}
+
+func (g *grpcServer) WatchRoadStatus(_ *trafficpb.WatchRoadStatusRequest, stream grpc.ServerStreamingServer[trafficpb.GetRoadStatusResponse]) error {
+	method := trafficpb.TrafficData_WatchRoadStatus_FullMethodName
+	return g.watchSnapshots(stream.Context(), method, "road_traffic_status", func(ctx context.Context) error {
+		resp, err := g.roadStatus(ctx, method)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return &sendError{err}
+		}
+		return nil
+	})
+}
+
+func (g *grpcServer) WatchRoadRouteSummary(_ *trafficpb.WatchRoadRouteSummaryRequest, stream grpc.ServerStreamingServer[trafficpb.GetRoadRouteSummaryResponse]) error {
+	method := trafficpb.TrafficData_WatchRoadRouteSummary_FullMethodName
+	return g.watchSnapshots(stream.Context(), method, "road_route_summary", func(ctx context.Context) error {
+		resp, err := g.routeSummary(ctx, method)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return &sendError{err}
+		}
+		return nil
+	})
+}
+
+func (g *grpcServer) WatchTollgateTraffic(_ *trafficpb.WatchTollgateTrafficRequest, stream grpc.ServerStreamingServer[trafficpb.GetTollgateTrafficResponse]) error {
+	method := trafficpb.TrafficData_WatchTollgateTraffic_FullMethodName
+	return g.watchSnapshots(stream.Context(), method, "tollgate_traffic_history", func(ctx context.Context) error {
+		resp, err := g.tollgateTraffic(ctx, method)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return &sendError{err}
+		}
+		return nil
+	})
+}
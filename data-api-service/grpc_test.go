@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"data-api-service/trafficpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAccidentProto(t *testing.T) {
+	lat, lon := 37.5, 127.0
+	created := time.Date(2025, 11, 17, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		acc  Accident
+	}{
+		{name: "with coordinates", acc: Accident{ID: 7, AccDate: "20251117", AccHour: "1830", AccPointNM: "서울TG", RoadNM: "경부선",
+			NosunNM: "0010", SmsText: "사고", AccType: "사고", Latitude: &lat, Altitude: &lon, CreatedAt: created}},
+		{name: "without coordinates", acc: Accident{ID: 8, CreatedAt: created}},
+	}
+	for _, tt := range tests {
+		p := accidentProto(tt.acc)
+		if p.Id != int64(tt.acc.ID) || p.AccDate != tt.acc.AccDate || p.AccHour != tt.acc.AccHour ||
+			p.AccPointNm != tt.acc.AccPointNM || p.RoadNm != tt.acc.RoadNM || p.NosunNm != tt.acc.NosunNM ||
+			p.SmsText != tt.acc.SmsText || p.AccType != tt.acc.AccType || !p.CreatedAt.AsTime().Equal(created) {
+			t.Errorf("%s: proto = %v", tt.name, p)
+		}
+		// Altitude holds the longitude in traffic_accidents
+		if (p.Latitude == nil) != (tt.acc.Latitude == nil) || (p.Latitude != nil && (*p.Latitude != lat || *p.Longitude != lon)) {
+			t.Errorf("%s: coordinates = %v, %v", tt.name, p.Latitude, p.Longitude)
+		}
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "status kept", err: status.Error(codes.NotFound, "no route"), want: codes.NotFound},
+		{name: "cancelled", err: fmt.Errorf("query: %w", context.Canceled), want: codes.Canceled},
+		{name: "deadline", err: context.DeadlineExceeded, want: codes.DeadlineExceeded},
+		{name: "database error hidden", err: errors.New("dial tcp 10.0.0.1:3306: refused"), want: codes.Internal},
+	}
+	for _, tt := range tests {
+		err := queryError(context.Background(), tt.err)
+		if status.Code(err) != tt.want {
+			t.Errorf("%s: code = %v, want %v", tt.name, status.Code(err), tt.want)
+		}
+		if tt.want == codes.Internal && status.Convert(err).Message() != "internal server error" {
+			t.Errorf("%s: message %q leaks the cause", tt.name, status.Convert(err).Message())
+		}
+	}
+}
+
+func TestGRPCLogger(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "forwarded ID is kept", incoming: "gateway-id-1", keep: true},
+		{name: "missing ID is assigned"},
+		{name: "unsafe ID is replaced", incoming: "bad id"},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.incoming != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(requestIDHeader, tt.incoming))
+		}
+		buf.Reset()
+		grpcLogger(ctx).Info("test")
+
+		var line struct {
+			RequestID string `json:"request_id"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+		if tt.keep && line.RequestID != tt.incoming || !tt.keep && len(line.RequestID) != 32 {
+			t.Errorf("%s: request_id = %q", tt.name, line.RequestID)
+		}
+	}
+}
+
+func TestUnaryObserver(t *testing.T) {
+	tests := []struct {
+		method     string
+		wantLogger bool
+	}{
+		{method: trafficpb.TrafficData_GetRoadStatus_FullMethodName, wantLogger: true},
+		{method: healthpb.Health_Check_FullMethodName},
+	}
+	for _, tt := range tests {
+		var logger *slog.Logger
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			logger = loggerFrom(ctx)
+			return "ok", nil
+		}
+		resp, err := unaryObserver(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+		if resp != "ok" || err != nil {
+			t.Errorf("%s: handler result = %v, %v", tt.method, resp, err)
+		}
+		if got := logger != slog.Default(); got != tt.wantLogger {
+			t.Errorf("%s: request logger = %v, want %v", tt.method, got, tt.wantLogger)
+		}
+	}
+}
+
+func TestGetLatestAccidentsLimit(t *testing.T) {
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/traffic?timeout=100ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	g := &grpcServer{s: &Server{db: db}}
+
+	tests := []struct {
+		limit int32
+		want  codes.Code
+	}{
+		{limit: -1, want: codes.InvalidArgument},
+		{limit: 1001, want: codes.InvalidArgument},
+		{limit: 0, want: codes.Internal}, // default limit, then the unreachable database
+		{limit: 1000, want: codes.Internal},
+	}
+	for _, tt := range tests {
+		_, err := g.GetLatestAccidents(context.Background(), &trafficpb.GetLatestAccidentsRequest{Limit: tt.limit})
+		if status.Code(err) != tt.want {
+			t.Errorf("limit %d: code = %v, want %v", tt.limit, status.Code(err), tt.want)
+		}
+	}
+}
+
+func TestPoll(t *testing.T) {
+	sendFailed := errors.New("client gone")
+	tests := []struct {
+		name      string
+		stop      bool
+		cancel    bool
+		fn        func(calls int) error
+		want      codes.Code
+		wantErr   error
+		wantCalls int // minimum
+	}{
+		{
+			name: "send failure ends the stream",
+			fn: func(calls int) error {
+				if calls == 3 {
+					return &sendError{err: sendFailed}
+				}
+				return nil
+			},
+			wantErr:   sendFailed,
+			wantCalls: 3,
+		},
+		{
+			name: "query failures are retried",
+			fn: func(calls int) error {
+				if calls < 3 {
+					return errors.New("db down")
+				}
+				return &sendError{err: sendFailed}
+			},
+			wantErr:   sendFailed,
+			wantCalls: 3,
+		},
+		{name: "shutdown", stop: true, fn: func(int) error { return nil }, want: codes.Unavailable, wantCalls: 1},
+		{name: "client cancel", cancel: true, fn: func(int) error { return nil }, want: codes.Canceled, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stopping := make(chan struct{})
+			if tt.stop {
+				close(stopping)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+			g := &grpcServer{s: &Server{config: Config{GRPCWatchInterval: time.Millisecond}}, stopping: stopping}
+
+			calls := 0
+			err := g.poll(ctx, func(ctx context.Context) error {
+				calls++
+				return tt.fn(calls)
+			})
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) || tt.wantErr == nil && status.Code(err) != tt.want {
+				t.Errorf("error = %v", err)
+			}
+			if calls < tt.wantCalls {
+				t.Errorf("%d calls, want at least %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
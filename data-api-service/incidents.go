@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Road incidents: congested sections with the accidents data-processor matched to them
+// (accident_road_match), and how the section's speed changed around each accident.
+
+// MatchedAccident is an accident placed on a conzone, with the conzone's average speed in
+// the 30 minutes before and after it
+type MatchedAccident struct {
+	Accident
+	ConzoneID      string   `json:"conzoneId"`
+	UpdownTypeCode string   `json:"updownTypeCode"` // S, E or empty when the direction is unknown
+	MatchMethod    string   `json:"matchMethod"`    // geometry or name
+	MatchDistance  *float64 `json:"matchDistance"`  // metres from the conzone line, geometry matches only
+	SpeedBefore    *float64 `json:"speedBefore"`    // km/h
+	SpeedAfter     *float64 `json:"speedAfter"`     // km/h
+	SpeedDrop      *float64 `json:"speedDrop"`      // speedBefore - speedAfter
+}
+
+// SectionIncidents is a section's latest status with the accidents matched to it
+type SectionIncidents struct {
+	RoadStatus
+	Accidents []MatchedAccident `json:"accidents"`
+}
+
+func (s *Server) getRoadIncidents(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	// Congested sections by default (2:서행, 3:정체); 0 includes every section
+	minGrade := 2
+	if v := r.URL.Query().Get("minGrade"); v != "" {
+		g, err := strconv.Atoi(v)
+		if err != nil || g < 0 || g > 3 {
+			writeError(w, http.StatusBadRequest, errBadRequest, "minGrade must be 0-3", map[string]string{"minGrade": v})
+			return
+		}
+		minGrade = g
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
+	defer cancel()
+
+	handler := apiV1 + "/road/incidents"
+	statuses, err := s.roadStatuses(ctx, handler, routeParam(r)...)
+	if err != nil {
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+		return
+	}
+	accidents, err := s.matchedAccidents(ctx, handler)
+	if err != nil {
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+		return
+	}
+
+	byConzone := make(map[string][]MatchedAccident)
+	for _, acc := range accidents {
+		byConzone[acc.ConzoneID] = append(byConzone[acc.ConzoneID], acc)
+	}
+
+	incidents := []SectionIncidents{}
+	for _, rs := range statuses {
+		if rs.Grade < minGrade {
+			continue
+		}
+		section := SectionIncidents{RoadStatus: rs, Accidents: []MatchedAccident{}}
+		for _, acc := range byConzone[rs.ConzoneID] {
+			// Accidents of unknown direction count for both directions
+			if acc.UpdownTypeCode == "" || acc.UpdownTypeCode == rs.UpdownTypeCode {
+				section.Accidents = append(section.Accidents, acc)
+			}
+		}
+		if len(section.Accidents) > 0 {
+			incidents = append(incidents, section)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(incidents); err != nil {
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
+	} else {
+		loggerFrom(r.Context()).Debug("returned road incidents", "sections", len(incidents))
+	}
+}
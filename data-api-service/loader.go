@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var loaderBatches = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "graphql_loader_batches_total",
+	Help: "Batched GraphQL lookups per loader; compare with graphql_loader_keys_total for the batching ratio.",
+}, []string{"loader"})
+
+var loaderKeys = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "graphql_loader_keys_total",
+	Help: "Keys resolved by GraphQL loaders per loader.",
+}, []string{"loader"})
+
+// loader batches lookups by key within one GraphQL request, dataloader style. Resolvers
+// queue their key and return a thunk; graphql-go runs thunks only after resolving all
+// sibling fields, so the first thunk fetches every key queued by then in one query.
+type loader[K comparable, V any] struct {
+	name  string
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	seen    map[K]bool
+	pending []K
+	results map[K]V
+	errs    map[K]error
+}
+
+func newLoader[K comparable, V any](name string, fetch func(context.Context, []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		name:    name,
+		fetch:   fetch,
+		seen:    make(map[K]bool),
+		results: make(map[K]V),
+		errs:    make(map[K]error),
+	}
+}
+
+// load queues key and returns a thunk resolving to its value; keys missing from the
+// fetch result resolve to the zero value
+func (l *loader[K, V]) load(ctx context.Context, key K) func() (interface{}, error) {
+	l.mu.Lock()
+	if !l.seen[key] {
+		l.seen[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+			loaderBatches.WithLabelValues(l.name).Inc()
+			loaderKeys.WithLabelValues(l.name).Add(float64(len(keys)))
+
+			values, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					l.errs[k] = err
+				} else {
+					l.results[k] = values[k]
+				}
+			}
+		}
+		if err := l.errs[key]; err != nil {
+			return nil, err
+		}
+		return l.results[key], nil
+	}
+}
+
+// placeholders returns "?, ?, ?" for an IN clause of n values
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// anyArgs converts keys to query arguments
+func anyArgs(keys []string) []interface{} {
+	args := make([]interface{}, len(keys))
+	for i, k := range keys {
+		args[i] = k
+	}
+	return args
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLoaderBatches(t *testing.T) {
+	var batches [][]string
+	l := newLoader("test", func(ctx context.Context, keys []string) (map[string]int, error) {
+		batches = append(batches, append([]string(nil), keys...))
+		values := make(map[string]int)
+		for _, k := range keys {
+			if k != "missing" {
+				values[k] = len(k)
+			}
+		}
+		return values, nil
+	})
+	ctx := context.Background()
+
+	// Resolvers queue every key before graphql-go runs the first thunk
+	thunks := map[string]func() (interface{}, error){}
+	for _, key := range []string{"a", "bb", "a", "missing"} {
+		thunks[key] = l.load(ctx, key)
+	}
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{key: "bb", want: 2},
+		{key: "a", want: 1},
+		{key: "missing", want: 0},
+	}
+	for _, tt := range tests {
+		got, err := thunks[tt.key]()
+		if err != nil || got != tt.want {
+			t.Errorf("%s = %v, %v; want %d", tt.key, got, err, tt.want)
+		}
+	}
+	sort.Strings(batches[0])
+	if want := [][]string{{"a", "bb", "missing"}}; !reflect.DeepEqual(batches, want) {
+		t.Errorf("batches = %v, want %v", batches, want)
+	}
+
+	// A key queued later is fetched in a batch of its own, cached keys are not refetched
+	if got, _ := l.load(ctx, "ccc")(); got != 3 {
+		t.Errorf("ccc = %v, want 3", got)
+	}
+	if got, _ := l.load(ctx, "a")(); got != 1 {
+		t.Errorf("cached a = %v, want 1", got)
+	}
+	if want := [][]string{{"a", "bb", "missing"}, {"ccc"}}; !reflect.DeepEqual(batches, want) {
+		t.Errorf("batches = %v, want %v", batches, want)
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	fetchErr := errors.New("db down")
+	calls := 0
+	l := newLoader("test", func(ctx context.Context, keys []string) (map[string]int, error) {
+		calls++
+		return nil, fetchErr
+	})
+	ctx := context.Background()
+	a, b := l.load(ctx, "a"), l.load(ctx, "b")
+	for _, thunk := range []func() (interface{}, error){a, b, l.load(ctx, "a")} {
+		if _, err := thunk(); !errors.Is(err, fetchErr) {
+			t.Errorf("error = %v, want %v", err, fetchErr)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetched %d times, want the failed batch not retried", calls)
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{n: 0, want: ""},
+		{n: 1, want: "?"},
+		{n: 3, want: "?, ?, ?"},
+	}
+	for _, tt := range tests {
+		if got := placeholders(tt.n); got != tt.want {
+			t.Errorf("placeholders(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+	if got := anyArgs([]string{"0010", "0100"}); !reflect.DeepEqual(got, []interface{}{"0010", "0100"}) {
+		t.Errorf("anyArgs = %v", got)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDHeader carries the request ID assigned by api-gateway
+const requestIDHeader = "X-Request-ID"
+
+type loggerKey struct{}
+
+// setupLogging installs a structured logger as the slog default (and behind the log package).
+// LOG_LEVEL selects the minimum level (debug, info, warn, error; default info) and LOG_FORMAT
+// selects json (default) or text. Every record carries the service, cluster and pod so logs
+// from member1 and member2 can be merged and filtered in one place.
+func setupLogging(service string) {
+	level := slog.LevelInfo
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		if err := level.UnmarshalText([]byte(v)); err != nil {
+			level = slog.LevelInfo
+		}
+	}
+
+	opts := &slog.HandlerOptions{
+		Level: level,
+		// Durations read better as "15m0s" than as nanoseconds
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Value.Kind() == slog.KindDuration {
+				return slog.String(a.Key, a.Value.Duration().String())
+			}
+			return a
+		},
+	}
+	var handler slog.Handler
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "text") {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
+
+	// Pod hostnames are the pod names; CLUSTER_NAME is set per member cluster by Karmada
+	pod, _ := os.Hostname()
+	slog.SetDefault(slog.New(handler).With(
+		"service", service,
+		"cluster", os.Getenv("CLUSTER_NAME"),
+		"pod", pod,
+	))
+}
+
+// fatal logs at error level and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// loggerFrom returns the request-scoped logger stored by withRequestID, or the default logger
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// newRequestID returns 16 random bytes, hex encoded
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// validRequestID accepts client-supplied IDs that are short and free of spaces and
+// control characters, so they are safe to echo and log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer (e.g. to flush)
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// withRequestID keeps the X-Request-ID forwarded by api-gateway (or assigns one for direct
+// calls), echoes it on the response and stores a logger carrying the request and trace IDs
+// in the request context; it also logs one line per request
+func withRequestID(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		r.Header.Set(requestIDHeader, id)
+		w.Header().Set(requestIDHeader, id)
+
+		logger := slog.Default().With("request_id", id)
+		span := trace.SpanFromContext(r.Context())
+		if sc := span.SpanContext(); sc.IsValid() {
+			logger = logger.With("trace_id", sc.TraceID().String())
+		}
+		// Verified caller identity set by api-gateway (see its auth.go)
+		if subject := r.Header.Get("X-Auth-Subject"); subject != "" {
+			logger = logger.With("subject", subject, "role", r.Header.Get("X-Auth-Role"))
+		}
+		span.SetAttributes(attribute.String("http.request_id", id))
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), loggerKey{}, logger)))
+
+		// Health probes would drown out everything else at info level
+		level := slog.LevelInfo
+		if r.URL.Path == "/health" {
+			level = slog.LevelDebug
+		}
+		logger.Log(r.Context(), level, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration", time.Since(start),
+			"remote_addr", r.RemoteAddr,
+		)
+	})
+}
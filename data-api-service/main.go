@@ -5,13 +5,19 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 )
 
 type Accident struct {
@@ -28,6 +34,13 @@ type Accident struct {
 	CreatedAt  time.Time `json:"createdAt"`
 }
 
+// AccidentStats is the /api/v1/accidents/stats response
+type AccidentStats struct {
+	TotalAccidents int            `json:"totalAccidents"`
+	TodayAccidents int            `json:"todayAccidents"`
+	ByType         map[string]int `json:"byType"`
+}
+
 type TollgateTrafficData struct {
 	CollectedAt   time.Time `json:"collectedAt"`
 	TrafficAmount int       `json:"trafficAmount"`
@@ -68,17 +81,34 @@ type RoadRouteSummary struct {
 	CollectedAt      time.Time `json:"collectedAt"`
 }
 
+// shutdownTimeout bounds draining in-flight requests after SIGTERM; it stays below the
+// default 30s Kubernetes termination grace period
+const shutdownTimeout = 25 * time.Second
+
 type Config struct {
-	DBHost     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	Port       string
+	DBHost        string
+	DBUser        string
+	DBPassword    string
+	DBName        string
+	Port          string
+	GRPCPort      string
+	RedisAddr     string
+	StreamKey     string
+	ConsumerGroup string
+	LeaderKey     string
+	Pipeline      PipelineConfig
+	// GRPCWatchInterval is how often Watch streams poll for new collections
+	GRPCWatchInterval time.Duration
+	// ExportMaxRange bounds the time range of one export request
+	ExportMaxRange time.Duration
 }
 
 type Server struct {
-	config Config
-	db     *sql.DB
+	config      Config
+	db          *sql.DB
+	redisClient *redis.Client
+	congestion  congestionCache
+	geometry    roadGeometryCache
 }
 
 func loadConfig() Config {
@@ -107,12 +137,64 @@ func loadConfig() Config {
 		port = "8080"
 	}
 
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+
+	watchInterval := 10 * time.Second
+	if v, err := time.ParseDuration(os.Getenv("GRPC_WATCH_INTERVAL")); err == nil && v >= time.Second {
+		watchInterval = v
+	}
+
+	exportMaxRange := 31 * 24 * time.Hour
+	if v, err := time.ParseDuration(os.Getenv("EXPORT_MAX_RANGE")); err == nil && v > 0 {
+		exportMaxRange = v
+	}
+
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "redis-central.default.svc.cluster.local:6379"
+	}
+
+	leaderKey := os.Getenv("LEADER_KEY")
+	if leaderKey == "" {
+		leaderKey = "data-collector:leader"
+	}
+
+	expectedSourceMode := os.Getenv("PIPELINE_EXPECTED_SOURCE_MODE")
+	if expectedSourceMode == "" {
+		expectedSourceMode = "real"
+	}
+
 	return Config{
-		DBHost:     dbHost,
-		DBUser:     dbUser,
-		DBPassword: dbPassword,
-		DBName:     dbName,
-		Port:       port,
+		DBHost:        dbHost,
+		DBUser:        dbUser,
+		DBPassword:    dbPassword,
+		DBName:        dbName,
+		Port:          port,
+		GRPCPort:      grpcPort,
+		RedisAddr:     redisAddr,
+		StreamKey:     "traffic-stream",
+		ConsumerGroup: "processor-group",
+		LeaderKey:     leaderKey,
+		Pipeline: PipelineConfig{
+			// Accidents are re-upserted on every collection, so created_at tracks the collector
+			Accidents: parseDurationThreshold("PIPELINE_STALE_ACCIDENTS",
+				DurationThreshold{Warn: 5 * time.Minute, Crit: 30 * time.Minute}),
+			// Tollgate intervals are 15 minutes and published upstream with a delay
+			Tollgate: parseDurationThreshold("PIPELINE_STALE_TOLLGATE",
+				DurationThreshold{Warn: 45 * time.Minute, Crit: 2 * time.Hour}),
+			RoadStatus: parseDurationThreshold("PIPELINE_STALE_ROAD_STATUS",
+				DurationThreshold{Warn: 15 * time.Minute, Crit: time.Hour}),
+			RouteSummary: parseDurationThreshold("PIPELINE_STALE_ROUTE_SUMMARY",
+				DurationThreshold{Warn: 15 * time.Minute, Crit: time.Hour}),
+			StreamLag: parseCountThreshold("PIPELINE_STREAM_LAG",
+				CountThreshold{Warn: 100, Crit: 1000}),
+			ExpectedSourceMode: expectedSourceMode,
+		},
+		GRPCWatchInterval: watchInterval,
+		ExportMaxRange:    exportMaxRange,
 	}
 }
 
@@ -136,24 +218,24 @@ func NewServer(config Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Printf("Connected to MariaDB at %s", config.DBHost)
+	slog.Info("connected to MariaDB", "host", config.DBHost)
+
+	// Redis is only needed for pipeline and cluster status, so an unreachable Redis is not fatal
+	rdb := redis.NewClient(&redis.Options{Addr: config.RedisAddr})
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		slog.Warn("Redis not reachable, /health/pipeline will report the stream as red",
+			"addr", config.RedisAddr, "error", err)
+	}
 
 	return &Server{
-		config: config,
-		db:     db,
+		config:      config,
+		db:          db,
+		redisClient: rdb,
 	}, nil
 }
 
 func (s *Server) getLatestAccidents(w http.ResponseWriter, r *http.Request) {
-	// CORS handled by API Gateway
-
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if !allowGet(w, r) {
 		return
 	}
 
@@ -168,78 +250,30 @@ func (s *Server) getLatestAccidents(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	// Only show accidents from the last 24 hours (increased from 3 hours for better visibility)
-	twentyFourHoursAgo := time.Now().Add(-24 * time.Hour)
-
-	query := `SELECT id, acc_date, acc_hour, acc_point_nm, road_nm, nosun_nm, sms_text, acc_type,
-	                 latitude, altitude, created_at
-	          FROM traffic_accidents
-	          WHERE created_at >= ?
-	          ORDER BY acc_date DESC, acc_hour DESC, created_at DESC
-	          LIMIT ?`
-
-	log.Printf("Querying accidents with time threshold: %v", twentyFourHoursAgo)
-	rows, err := s.db.QueryContext(ctx, query, twentyFourHoursAgo, limit)
+	accidents, err := s.latestAccidents(ctx, apiV1+"/accidents/latest", limit)
 	if err != nil {
-		log.Printf("Query error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer rows.Close()
-
-	var accidents []Accident
-
-	for rows.Next() {
-		var acc Accident
-		err := rows.Scan(
-			&acc.ID, &acc.AccDate, &acc.AccHour, &acc.AccPointNM,
-			&acc.RoadNM, &acc.NosunNM, &acc.SmsText, &acc.AccType,
-			&acc.Latitude, &acc.Altitude, &acc.CreatedAt,
-		)
-		if err != nil {
-			log.Printf("Scan error: %v", err)
-			continue
-		}
-		accidents = append(accidents, acc)
-	}
-
-	if err = rows.Err(); err != nil {
-		log.Printf("Rows error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(accidents); err != nil {
-		log.Printf("Encode error: %v", err)
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
 	} else {
-		log.Printf("Returned %d accidents (limit: %d)", len(accidents), limit)
+		loggerFrom(r.Context()).Debug("returned accidents", "count", len(accidents), "limit", limit)
 	}
 }
 
 func (s *Server) getAccidentStats(w http.ResponseWriter, r *http.Request) {
-	// CORS handled by API Gateway
-
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if !allowGet(w, r) {
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	type Stats struct {
-		TotalAccidents int            `json:"totalAccidents"`
-		TodayAccidents int            `json:"todayAccidents"`
-		ByType         map[string]int `json:"byType"`
-	}
-
-	var stats Stats
+	var stats AccidentStats
 	stats.ByType = make(map[string]int)
 
 	now := time.Now()
@@ -247,22 +281,23 @@ func (s *Server) getAccidentStats(w http.ResponseWriter, r *http.Request) {
 
 	// Total accidents - sum from daily_accident_stats for all dates before today
 	// plus today's real-time count
+	handler := apiV1 + "/accidents/stats"
 	var totalFromHistory int
-	err := s.db.QueryRowContext(ctx,
+	err := s.queryRow(ctx, handler,
 		"SELECT COALESCE(SUM(accident_count), 0) FROM daily_accident_stats WHERE stat_date < ?",
 		today).Scan(&totalFromHistory)
 	if err != nil {
-		log.Printf("Error getting historical total: %v", err)
+		loggerFrom(r.Context()).Error("failed to get historical total", "error", err)
 	}
 
 	// Today's accidents - real-time count from traffic_accidents
 	var todayCount int
-	err = s.db.QueryRowContext(ctx,
+	err = s.queryRow(ctx, handler,
 		`SELECT COUNT(*) FROM traffic_accidents
 		 WHERE created_at >= ? AND created_at < ?`,
 		today+" 00:00:00", today+" 23:59:59").Scan(&todayCount)
 	if err != nil {
-		log.Printf("Error getting today's accidents: %v", err)
+		loggerFrom(r.Context()).Error("failed to get today's accidents", "error", err)
 	}
 
 	stats.TodayAccidents = todayCount
@@ -270,14 +305,14 @@ func (s *Server) getAccidentStats(w http.ResponseWriter, r *http.Request) {
 
 	// By type - for last 3 hours (current accidents only)
 	threeHoursAgo := now.Add(-3 * time.Hour)
-	rows, err := s.db.QueryContext(ctx,
+	rows, err := s.query(ctx, handler,
 		`SELECT acc_type, COUNT(*) as count
 		 FROM traffic_accidents
 		 WHERE created_at >= ?
 		 GROUP BY acc_type`,
 		threeHoursAgo)
 	if err != nil {
-		log.Printf("Error getting accidents by type: %v", err)
+		loggerFrom(r.Context()).Error("failed to get accidents by type", "error", err)
 	} else {
 		defer rows.Close()
 		for rows.Next() {
@@ -294,267 +329,71 @@ func (s *Server) getAccidentStats(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) getTollgateTraffic(w http.ResponseWriter, r *http.Request) {
-	// CORS handled by API Gateway
-
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if !allowGet(w, r) {
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	// First, get the most recent collected_at time
-	var latestTime sql.NullTime
-	err := s.db.QueryRowContext(ctx, `
-		SELECT MAX(collected_at) FROM tollgate_traffic_history
-	`).Scan(&latestTime)
-
+	tollgates, err := s.tollgateTraffic(ctx, apiV1+"/tollgate/traffic")
 	if err != nil {
-		log.Printf("Error getting latest time: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
 		return
 	}
 
-	// If no data exists, return empty array
-	if !latestTime.Valid {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode([]TollgateTraffic{})
-		return
-	}
-
-	// Get traffic data from 3 hours before the latest collection time
-	threeHoursBeforeLatest := latestTime.Time.Add(-3 * time.Hour)
-
-	query := `
-		SELECT unit_code, unit_name, ex_div_name, collected_at, traffic_amount
-		FROM tollgate_traffic_history
-		WHERE collected_at >= ?
-		ORDER BY unit_code, collected_at DESC`
-
-	rows, err := s.db.QueryContext(ctx, query, threeHoursBeforeLatest)
-	if err != nil {
-		log.Printf("Query error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer rows.Close()
-
-	// Group by unit_code
-	tollgateMap := make(map[string]*TollgateTraffic)
-
-	for rows.Next() {
-		var unitCode, unitName, exDivName string
-		var collectedAt time.Time
-		var trafficAmount int
-
-		err := rows.Scan(&unitCode, &unitName, &exDivName, &collectedAt, &trafficAmount)
-		if err != nil {
-			log.Printf("Scan error: %v", err)
-			continue
-		}
-
-		if _, exists := tollgateMap[unitCode]; !exists {
-			tollgateMap[unitCode] = &TollgateTraffic{
-				UnitCode:    unitCode,
-				UnitName:    unitName,
-				ExDivName:   exDivName,
-				TrafficData: []TollgateTrafficData{},
-				LastUpdated: collectedAt,
-			}
-		}
-
-		tollgateMap[unitCode].TrafficData = append(tollgateMap[unitCode].TrafficData, TollgateTrafficData{
-			CollectedAt:   collectedAt,
-			TrafficAmount: trafficAmount,
-		})
-
-		if collectedAt.After(tollgateMap[unitCode].LastUpdated) {
-			tollgateMap[unitCode].LastUpdated = collectedAt
-		}
-	}
-
-	if err = rows.Err(); err != nil {
-		log.Printf("Rows error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-
-	// Convert map to slice
-	var tollgates []TollgateTraffic
-	for _, tg := range tollgateMap {
-		tollgates = append(tollgates, *tg)
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(tollgates); err != nil {
-		log.Printf("Encode error: %v", err)
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
 	} else {
-		log.Printf("Returned %d tollgates with traffic data", len(tollgates))
+		loggerFrom(r.Context()).Debug("returned tollgates", "count", len(tollgates))
 	}
 }
 
 func (s *Server) getRoadStatus(w http.ResponseWriter, r *http.Request) {
-	// CORS handled by API Gateway
-
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if !allowGet(w, r) {
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Second)
 	defer cancel()
 
-	// Get the most recent data per route, aggregating by conzone
-	// Shows latest available data for each route (no time limit)
-	// This ensures all routes are displayed even if external API has gaps
-	query := `
-		WITH LatestByRoute AS (
-			SELECT route_no, MAX(collected_at) as max_collected
-			FROM road_traffic_status
-			GROUP BY route_no
-		)
-		SELECT
-			r.route_no,
-			r.route_name,
-			r.conzone_id,
-			r.conzone_name,
-			MIN(r.vds_id) as vds_id,
-			r.updown_type_code,
-			ROUND(AVG(CASE WHEN r.traffic_amount >= 0 THEN r.traffic_amount END)) as traffic_amount,
-			ROUND(AVG(CASE WHEN r.speed >= 0 THEN r.speed END)) as speed,
-			ROUND(AVG(CASE WHEN r.share_ratio >= 0 THEN r.share_ratio END)) as share_ratio,
-			ROUND(AVG(CASE WHEN r.time_avg >= 0 THEN r.time_avg END)) as time_avg,
-			MAX(r.grade) as grade,
-			MAX(r.collected_at) as collected_at
-		FROM road_traffic_status r
-		INNER JOIN LatestByRoute l ON r.route_no = l.route_no AND r.collected_at = l.max_collected
-		WHERE r.speed >= 0 AND r.grade > 0
-		GROUP BY r.route_no, r.route_name, r.conzone_id, r.conzone_name, r.updown_type_code
-		ORDER BY r.route_no, r.conzone_id`
-
-	rows, err := s.db.QueryContext(ctx, query)
+	located, err := s.locatedRoadStatuses(ctx, apiV1+"/road/status")
 	if err != nil {
-		log.Printf("Query error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer rows.Close()
-
-	var roadStatuses []RoadStatus
-
-	for rows.Next() {
-		var rs RoadStatus
-
-		err := rows.Scan(
-			&rs.RouteNo, &rs.RouteName, &rs.ConzoneID, &rs.ConzoneName,
-			&rs.VdsID, &rs.UpdownTypeCode,
-			&rs.TrafficAmount, &rs.Speed, &rs.ShareRatio, &rs.TimeAvg,
-			&rs.Grade, &rs.CollectedAt,
-		)
-		if err != nil {
-			log.Printf("Scan error: %v", err)
-			continue
-		}
-
-		roadStatuses = append(roadStatuses, rs)
-	}
-
-	if err = rows.Err(); err != nil {
-		log.Printf("Rows error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(roadStatuses); err != nil {
-		log.Printf("Encode error: %v", err)
+	if err := json.NewEncoder(w).Encode(located); err != nil {
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
 	} else {
-		log.Printf("Returned %d road status records", len(roadStatuses))
+		loggerFrom(r.Context()).Debug("returned road status records", "count", len(located))
 	}
 }
 
 func (s *Server) getRoadRouteSummary(w http.ResponseWriter, r *http.Request) {
-	// CORS handled by API Gateway
-
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if !allowGet(w, r) {
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	// Get the latest aggregated route summary data
-	query := `
-		SELECT
-			route_no,
-			route_name,
-			total_sections,
-			smooth_sections,
-			slow_sections,
-			congested_sections,
-			avg_speed,
-			avg_traffic_amount,
-			collected_at
-		FROM road_route_summary
-		WHERE collected_at = (SELECT MAX(collected_at) FROM road_route_summary)
-		ORDER BY route_no`
-
-	rows, err := s.db.QueryContext(ctx, query)
+	summaries, err := s.routeSummaries(ctx, apiV1+"/road/summary", "")
 	if err != nil {
-		log.Printf("Query error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
-	defer rows.Close()
-
-	var summaries []RoadRouteSummary
-
-	for rows.Next() {
-		var summary RoadRouteSummary
-
-		err := rows.Scan(
-			&summary.RouteNo, &summary.RouteName,
-			&summary.TotalSections, &summary.SmoothSections,
-			&summary.SlowSections, &summary.CongestedSections,
-			&summary.AvgSpeed, &summary.AvgTrafficAmount,
-			&summary.CollectedAt,
-		)
-		if err != nil {
-			log.Printf("Scan error: %v", err)
-			continue
-		}
-
-		summaries = append(summaries, summary)
-	}
-
-	if err = rows.Err(); err != nil {
-		log.Printf("Rows error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(summaries); err != nil {
-		log.Printf("Encode error: %v", err)
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
 	} else {
-		log.Printf("Returned %d route summary records", len(summaries))
+		loggerFrom(r.Context()).Debug("returned route summary records", "count", len(summaries))
 	}
 }
 
@@ -563,7 +402,7 @@ func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	if err := s.db.PingContext(ctx); err != nil {
-		log.Printf("Health check failed: %v", err)
+		loggerFrom(r.Context()).Warn("health check failed", "error", err)
 		http.Error(w, "Unhealthy", http.StatusServiceUnavailable)
 		return
 	}
@@ -572,39 +411,99 @@ func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
-func (s *Server) Start() error {
-	http.HandleFunc("/api/accidents/latest", s.getLatestAccidents)
-	http.HandleFunc("/api/accidents/stats", s.getAccidentStats)
-	http.HandleFunc("/api/tollgate/traffic", s.getTollgateTraffic)
-	http.HandleFunc("/api/road/status", s.getRoadStatus)
-	http.HandleFunc("/api/road/summary", s.getRoadRouteSummary)
-	http.HandleFunc("/health", s.healthHandler)
+// Start serves HTTP and gRPC until ctx is cancelled, then drains in-flight requests
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.registerAPI(); err != nil {
+		return err
+	}
+	http.Handle("/health", instrument("/health", s.healthHandler))
+	http.Handle("/health/pipeline", instrument("/health/pipeline", s.pipelineHealthHandler))
+	http.Handle("/metrics", promhttp.Handler())
+	prometheus.MustRegister(collectors.NewDBStatsCollector(s.db, s.config.DBName))
 
 	addr := ":" + s.config.Port
-	log.Printf("Data API Service starting on %s", addr)
-
-	return http.ListenAndServe(addr, nil)
+	slog.Info("Data API Service starting", "addr", addr)
+
+	server := &http.Server{Addr: addr}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.ListenAndServe()
+	}()
+
+	grpcCtx, stopGRPC := context.WithCancel(ctx)
+	defer stopGRPC()
+	grpcDone := make(chan error, 1)
+	go func() {
+		grpcDone <- s.serveGRPC(grpcCtx)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case err := <-grpcDone:
+		return fmt.Errorf("gRPC server: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, draining in-flight requests", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	if grpcErr := <-grpcDone; err == nil {
+		err = grpcErr
+	}
+	return err
 }
 
 func (s *Server) Close() error {
+	s.redisClient.Close()
 	return s.db.Close()
 }
 
 func main() {
+	setupLogging("data-api-service")
 	config := loadConfig()
 
-	log.Printf("Configuration:")
-	log.Printf("  DB Host: %s", config.DBHost)
-	log.Printf("  DB Name: %s", config.DBName)
-	log.Printf("  Port: %s", config.Port)
+	slog.Info("configuration", "db_host", config.DBHost, "db_name", config.DBName, "port", config.Port,
+		"grpc_port", config.GRPCPort, "redis_addr", config.RedisAddr)
 
 	server, err := NewServer(config)
 	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
+		fatal("failed to create server", "error", err)
 	}
 	defer server.Close()
 
-	if err := server.Start(); err != nil {
-		log.Fatalf("Server failed: %v", err)
+	shutdownTracing, err := initTracing(context.Background(), "data-api-service")
+	if err != nil {
+		fatal("failed to initialise tracing", "error", err)
 	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle shutdown gracefully
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		slog.Info("shutdown signal received")
+		cancel()
+	}()
+
+	// Report this instance to the cluster status API
+	waitPresence := startPresence(ctx, server.redisClient, newPresence("data-api-service", ""))
+
+	if err := server.Start(ctx); err != nil {
+		fatal("server failed", "error", err)
+	}
+	waitPresence()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("tracing shutdown", "error", err)
+	}
+
+	slog.Info("Data API Service stopped")
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency per handler, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"handler", "method", "code"})
+
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests per handler, method and status code.",
+	}, []string{"handler", "method", "code"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Database query latency per handler.",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"handler"})
+
+	dbQueryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Failed database queries per handler.",
+	}, []string{"handler"})
+)
+
+// instrument records latency and status codes of h under the given handler label and
+// serves it in a server span continuing the caller's trace (health probes are not traced),
+// tagged with the request ID
+func instrument(handler string, h http.HandlerFunc) http.Handler {
+	labels := prometheus.Labels{"handler": handler}
+	return otelhttp.NewHandler(
+		withRequestID(promhttp.InstrumentHandlerDuration(httpDuration.MustCurryWith(labels),
+			promhttp.InstrumentHandlerCounter(httpRequests.MustCurryWith(labels), h))),
+		handler,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/health"
+		}),
+	)
+}
+
+// startQuery opens a client span for one query; the returned func records its latency
+// and outcome under handler
+func startQuery(ctx context.Context, handler string) (context.Context, func(err error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "db.query "+handler,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "mysql")))
+
+	return ctx, func(err error) {
+		dbQueryDuration.WithLabelValues(handler).Observe(time.Since(start).Seconds())
+		if err != nil {
+			dbQueryErrors.WithLabelValues(handler).Inc()
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// query runs QueryContext and records its latency under handler
+func (s *Server) query(ctx context.Context, handler, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := startQuery(ctx, handler)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+// queryRow runs QueryRowContext and records its latency under handler
+func (s *Server) queryRow(ctx context.Context, handler, query string, args ...interface{}) *sql.Row {
+	ctx, done := startQuery(ctx, handler)
+	row := s.db.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
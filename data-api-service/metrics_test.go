@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricValue scrapes the default registry and returns the value of one series, or 0
+// when it has not been recorded yet
+func metricValue(t *testing.T, series string) float64 {
+	t.Helper()
+	w := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), series+" "); ok {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return v
+		}
+	}
+	return 0
+}
+
+func TestInstrumentCountsStatusCodes(t *testing.T) {
+	tests := []struct {
+		handler string
+		status  int
+		series  string
+	}{
+		{handler: "/test/ok", status: http.StatusOK,
+			series: `http_requests_total{code="200",handler="/test/ok",method="get"}`},
+		{handler: "/test/missing", status: http.StatusNotFound,
+			series: `http_requests_total{code="404",handler="/test/missing",method="get"}`},
+		{handler: "/test/broken", status: http.StatusInternalServerError,
+			series: `http_requests_total{code="500",handler="/test/broken",method="get"}`},
+	}
+	for _, tt := range tests {
+		before := metricValue(t, tt.series)
+		h := instrument(tt.handler, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		})
+		for i := 0; i < 2; i++ {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.handler, nil))
+		}
+
+		if n := metricValue(t, tt.series) - before; n != 2 {
+			t.Errorf("%s counted %v times, want 2", tt.series, n)
+		}
+	}
+}
+
+func TestStartQueryCountsErrors(t *testing.T) {
+	const (
+		errorSeries   = `db_query_errors_total{handler="/test/query"}`
+		latencySeries = `db_query_duration_seconds_count{handler="/test/query"}`
+	)
+	errorsBefore, latenciesBefore := metricValue(t, errorSeries), metricValue(t, latencySeries)
+
+	_, done := startQuery(context.Background(), "/test/query")
+	done(nil)
+	_, done = startQuery(context.Background(), "/test/query")
+	done(errors.New("connection refused"))
+
+	if n := metricValue(t, errorSeries) - errorsBefore; n != 1 {
+		t.Errorf("query errors = %v, want 1", n)
+	}
+	if n := metricValue(t, latencySeries) - latenciesBefore; n != 2 {
+		t.Errorf("query latencies recorded = %v, want 2", n)
+	}
+}
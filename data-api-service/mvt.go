@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Mapbox Vector Tiles (specification 2.1) of the road congestion layer, for maps that
+// draw thousands of sections and for GIS tools. Tiles use the Web Mercator XYZ scheme
+// and are encoded by hand with protowire, like parquet.go, since the tile protobuf is
+// small and stable.
+
+const (
+	mvtContentType = "application/vnd.mapbox-vector-tile"
+	tilesPath      = apiV1 + "/tiles/{z}/{x}/{y}.mvt"
+	tileExtent     = 4096
+	// tileBuffer keeps lines a little past the tile edge so joins render seamlessly
+	tileBuffer  = 64
+	tileMaxZoom = 22
+	// congestionLayer is the layer name clients style against
+	congestionLayer = "road_congestion"
+	// congestionTTL is how long tiles reuse one road status query; a map view requests
+	// dozens of tiles at once and the data only changes once per collection
+	congestionTTL = 10 * time.Second
+)
+
+type tileID struct {
+	Z, X, Y int
+}
+
+// parseTile reads "{z}/{x}/{y}.mvt"
+func parseTile(path string) (tileID, error) {
+	parts := strings.Split(strings.TrimSuffix(path, ".mvt"), "/")
+	if len(parts) != 3 || !strings.HasSuffix(path, ".mvt") {
+		return tileID{}, fmt.Errorf("tile path must be {z}/{x}/{y}.mvt")
+	}
+	var n [3]int
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil || v < 0 {
+			return tileID{}, fmt.Errorf("invalid tile coordinate %q", p)
+		}
+		n[i] = v
+	}
+	t := tileID{Z: n[0], X: n[1], Y: n[2]}
+	if t.Z > tileMaxZoom {
+		return tileID{}, fmt.Errorf("zoom must be at most %d", tileMaxZoom)
+	}
+	if max := 1 << t.Z; t.X >= max || t.Y >= max {
+		return tileID{}, fmt.Errorf("x and y must be below %d at zoom %d", max, t.Z)
+	}
+	return t, nil
+}
+
+// project returns the tile pixel coordinates (0..tileExtent inside the tile) of a point
+func (t tileID) project(lon, lat float64) [2]float64 {
+	n := float64(int(1) << t.Z)
+	sin := math.Sin(lat * math.Pi / 180)
+	x := (lon + 180) / 360 * n
+	y := (0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)) * n
+	return [2]float64{(x - float64(t.X)) * tileExtent, (y - float64(t.Y)) * tileExtent}
+}
+
+// bounds returns the WGS84 box of the tile and its buffer
+func (t tileID) bounds() lonLatBounds {
+	n := float64(int(1) << t.Z)
+	buffer := float64(tileBuffer) / tileExtent
+	lon := func(x float64) float64 { return x/n*360 - 180 }
+	lat := func(y float64) float64 { return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi }
+	return lonLatBounds{
+		West:  lon(float64(t.X) - buffer),
+		South: lat(float64(t.Y+1) + buffer),
+		East:  lon(float64(t.X+1) + buffer),
+		North: lat(float64(t.Y) - buffer),
+	}
+}
+
+// clipLine cuts a polyline in tile pixels to the square [lo, hi], returning the parts
+// inside
+func clipLine(line [][2]float64, lo, hi float64) [][][2]float64 {
+	var parts [][][2]float64
+	var part [][2]float64
+	for i := 0; i+1 < len(line); i++ {
+		a, b, ok := clipSegment(line[i], line[i+1], lo, hi)
+		if !ok {
+			if len(part) > 0 {
+				parts, part = append(parts, part), nil
+			}
+			continue
+		}
+		if len(part) > 0 && part[len(part)-1] != a {
+			// Re-entered the tile
+			parts, part = append(parts, part), nil
+		}
+		if len(part) == 0 {
+			part = append(part, a)
+		}
+		part = append(part, b)
+	}
+	if len(part) > 0 {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// clipSegment clips a segment with Liang-Barsky; unclipped ends are returned unchanged
+// so consecutive segments still join exactly
+func clipSegment(a, b [2]float64, lo, hi float64) ([2]float64, [2]float64, bool) {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t0, t1 := 0.0, 1.0
+	for _, edge := range [4][2]float64{
+		{-dx, a[0] - lo}, {dx, hi - a[0]},
+		{-dy, a[1] - lo}, {dy, hi - a[1]},
+	} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return a, b, false // parallel and outside
+			}
+			continue
+		}
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return a, b, false
+			}
+			t0 = math.Max(t0, r)
+		} else {
+			if r < t0 {
+				return a, b, false
+			}
+			t1 = math.Min(t1, r)
+		}
+	}
+	// Both ends are measured from the original a
+	start, end := a, b
+	if t0 > 0 {
+		start = [2]float64{a[0] + t0*dx, a[1] + t0*dy}
+	}
+	if t1 < 1 {
+		end = [2]float64{a[0] + t1*dx, a[1] + t1*dy}
+	}
+	return start, end, true
+}
+
+// mvtTag is one feature property; Value is a string or an int64
+type mvtTag struct {
+	Key   string
+	Value interface{}
+}
+
+// mvtLayer collects features, sharing the key and value tables between them
+type mvtLayer struct {
+	name     string
+	keys     []string
+	values   []interface{}
+	keyIdx   map[string]uint64
+	valueIdx map[interface{}]uint64
+	features [][]byte
+}
+
+func newMVTLayer(name string) *mvtLayer {
+	return &mvtLayer{name: name, keyIdx: map[string]uint64{}, valueIdx: map[interface{}]uint64{}}
+}
+
+// addLine adds a (multi) line string feature given in tile pixels; parts that collapse
+// to a point once snapped to the grid are dropped, and so is a feature left without any
+func (l *mvtLayer) addLine(parts [][][2]float64, tags []mvtTag) {
+	var geometry []byte
+	var cx, cy int64 // the cursor carries over between parts
+	for _, part := range parts {
+		var points [][2]int64
+		for _, p := range part {
+			q := [2]int64{int64(math.Round(p[0])), int64(math.Round(p[1]))}
+			if len(points) == 0 || points[len(points)-1] != q {
+				points = append(points, q)
+			}
+		}
+		if len(points) < 2 {
+			continue
+		}
+		for i, p := range points {
+			switch i {
+			case 0:
+				geometry = protowire.AppendVarint(geometry, mvtCommand(1, 1)) // MoveTo
+			case 1:
+				geometry = protowire.AppendVarint(geometry, mvtCommand(2, len(points)-1)) // LineTo
+			}
+			geometry = protowire.AppendVarint(geometry, protowire.EncodeZigZag(p[0]-cx))
+			geometry = protowire.AppendVarint(geometry, protowire.EncodeZigZag(p[1]-cy))
+			cx, cy = p[0], p[1]
+		}
+	}
+	if len(geometry) == 0 {
+		return
+	}
+
+	var packedTags []byte
+	for _, tag := range tags {
+		packedTags = protowire.AppendVarint(packedTags, l.key(tag.Key))
+		packedTags = protowire.AppendVarint(packedTags, l.value(tag.Value))
+	}
+	var feature []byte
+	feature = protowire.AppendTag(feature, 2, protowire.BytesType)
+	feature = protowire.AppendBytes(feature, packedTags)
+	feature = protowire.AppendTag(feature, 3, protowire.VarintType)
+	feature = protowire.AppendVarint(feature, 2) // LINESTRING
+	feature = protowire.AppendTag(feature, 4, protowire.BytesType)
+	feature = protowire.AppendBytes(feature, geometry)
+	l.features = append(l.features, feature)
+}
+
+func mvtCommand(id, count int) uint64 {
+	return uint64(id&0x7) | uint64(count)<<3
+}
+
+func (l *mvtLayer) key(k string) uint64 {
+	i, ok := l.keyIdx[k]
+	if !ok {
+		i = uint64(len(l.keys))
+		l.keyIdx[k] = i
+		l.keys = append(l.keys, k)
+	}
+	return i
+}
+
+func (l *mvtLayer) value(v interface{}) uint64 {
+	i, ok := l.valueIdx[v]
+	if !ok {
+		i = uint64(len(l.values))
+		l.valueIdx[v] = i
+		l.values = append(l.values, v)
+	}
+	return i
+}
+
+// encode appends the layer as field 3 of a Tile message
+func (l *mvtLayer) encode(tile []byte) []byte {
+	var layer []byte
+	layer = protowire.AppendTag(layer, 15, protowire.VarintType)
+	layer = protowire.AppendVarint(layer, 2) // version
+	layer = protowire.AppendTag(layer, 1, protowire.BytesType)
+	layer = protowire.AppendString(layer, l.name)
+	for _, f := range l.features {
+		layer = protowire.AppendTag(layer, 2, protowire.BytesType)
+		layer = protowire.AppendBytes(layer, f)
+	}
+	for _, k := range l.keys {
+		layer = protowire.AppendTag(layer, 3, protowire.BytesType)
+		layer = protowire.AppendString(layer, k)
+	}
+	for _, v := range l.values {
+		var value []byte
+		switch v := v.(type) {
+		case string:
+			value = protowire.AppendTag(value, 1, protowire.BytesType)
+			value = protowire.AppendString(value, v)
+		case int64:
+			value = protowire.AppendTag(value, 4, protowire.VarintType)
+			value = protowire.AppendVarint(value, uint64(v))
+		}
+		layer = protowire.AppendTag(layer, 4, protowire.BytesType)
+		layer = protowire.AppendBytes(layer, value)
+	}
+	layer = protowire.AppendTag(layer, 5, protowire.VarintType)
+	layer = protowire.AppendVarint(layer, tileExtent)
+
+	tile = protowire.AppendTag(tile, 3, protowire.BytesType)
+	return protowire.AppendBytes(tile, layer)
+}
+
+// congestionCache shares the latest road statuses between the tiles of a map view
+type congestionCache struct {
+	mu       sync.Mutex
+	statuses []RoadStatus
+	fetched  time.Time
+}
+
+func (s *Server) congestionStatuses(ctx context.Context) ([]RoadStatus, error) {
+	c := &s.congestion
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.fetched) < congestionTTL {
+		return c.statuses, nil
+	}
+	statuses, err := s.roadStatuses(ctx, tilesPath)
+	if err != nil {
+		return nil, err
+	}
+	c.statuses, c.fetched = statuses, time.Now()
+	return statuses, nil
+}
+
+// getCongestionTile serves the road_congestion layer: one line feature per section and
+// direction with geometry in road_conzone_geometry, tagged with its status and color
+func (s *Server) getCongestionTile(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	tile, err := parseTile(strings.TrimPrefix(r.URL.Path, apiV1+"/tiles/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errBadRequest, err.Error(),
+			map[string]string{"path": r.URL.Path})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	statuses, err := s.congestionStatuses(ctx)
+	if err != nil {
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+		return
+	}
+	bounds := tile.bounds()
+	lines, err := s.conzoneGeometries(ctx, tilesPath, &bounds)
+	if err != nil {
+		loggerFrom(r.Context()).Error("query failed", "error", err)
+		writeError(w, http.StatusInternalServerError, errInternal, "internal server error", nil)
+		return
+	}
+
+	layer := newMVTLayer(congestionLayer)
+	for _, rs := range statuses {
+		line, ok := lines[rs.ConzoneID]
+		if !ok {
+			continue
+		}
+		projected := make([][2]float64, len(line))
+		for i, p := range line {
+			projected[i] = tile.project(p[0], p[1])
+		}
+		layer.addLine(clipLine(projected, -tileBuffer, tileExtent+tileBuffer), []mvtTag{
+			{"routeNo", rs.RouteNo},
+			{"routeName", rs.RouteName},
+			{"conzoneId", rs.ConzoneID},
+			{"conzoneName", rs.ConzoneName},
+			{"updownTypeCode", rs.UpdownTypeCode},
+			{"speed", int64(rs.Speed)},
+			{"trafficAmount", int64(rs.TrafficAmount)},
+			{"grade", int64(rs.Grade)},
+			{"color", gradeColor(rs.Grade)},
+		})
+	}
+
+	var body []byte
+	if len(layer.features) > 0 {
+		body = layer.encode(body)
+	}
+	w.Header().Set("Content-Type", mvtContentType)
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.Write(body)
+	loggerFrom(r.Context()).Debug("returned tile", "z", tile.Z, "x", tile.X, "y", tile.Y,
+		"features", len(layer.features), "bytes", len(body))
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestParseTile(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    tileID
+		wantErr bool
+	}{
+		{path: "0/0/0.mvt", want: tileID{}},
+		{path: "7/109/49.mvt", want: tileID{Z: 7, X: 109, Y: 49}},
+		{path: "22/4194303/0.mvt", want: tileID{Z: 22, X: 4194303}},
+		{path: "7/109/49", wantErr: true},
+		{path: "7/109/49.png", wantErr: true},
+		{path: "7/109.mvt", wantErr: true},
+		{path: "7/109/49/1.mvt", wantErr: true},
+		{path: "7/-1/49.mvt", wantErr: true},
+		{path: "7/x/49.mvt", wantErr: true},
+		{path: "23/0/0.mvt", wantErr: true},
+		{path: "1/2/0.mvt", wantErr: true},
+		{path: "1/0/2.mvt", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseTile(tt.path)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseTile(%q) = %+v, %v; want %+v, error %v", tt.path, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestTileProject(t *testing.T) {
+	const eps = 1e-6
+	tests := []struct {
+		name     string
+		tile     tileID
+		lon, lat float64
+		want     [2]float64
+	}{
+		{name: "center of the world tile", tile: tileID{}, lon: 0, lat: 0, want: [2]float64{2048, 2048}},
+		{name: "west edge", tile: tileID{}, lon: -180, lat: 0, want: [2]float64{0, 2048}},
+		{name: "north edge of the Mercator square", tile: tileID{}, lon: 0, lat: 85.0511287798, want: [2]float64{2048, 0}},
+		{name: "top left of a zoom 1 tile", tile: tileID{Z: 1, X: 1, Y: 1}, lon: 0, lat: 0, want: [2]float64{0, 0}},
+		{name: "outside the tile is negative", tile: tileID{Z: 1, X: 1, Y: 1}, lon: -90, lat: 0, want: [2]float64{-2048, 0}},
+	}
+	for _, tt := range tests {
+		got := tt.tile.project(tt.lon, tt.lat)
+		if math.Abs(got[0]-tt.want[0]) > eps || math.Abs(got[1]-tt.want[1]) > 1e-3 {
+			t.Errorf("%s: project = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTileBounds(t *testing.T) {
+	const eps = 1e-9
+	for _, tile := range []tileID{{}, {Z: 7, X: 109, Y: 49}, {Z: 14, X: 13970, Y: 6344}} {
+		b := tile.bounds()
+		if b.West >= b.East || b.South >= b.North {
+			t.Errorf("%+v: bounds %+v are not a box", tile, b)
+			continue
+		}
+		// The corners of the box are the tile corners pushed out by the buffer
+		lo, hi := -float64(tileBuffer), float64(tileExtent+tileBuffer)
+		nw, se := tile.project(b.West, b.North), tile.project(b.East, b.South)
+		for _, c := range []struct{ got, want float64 }{{nw[0], lo}, {nw[1], lo}, {se[0], hi}, {se[1], hi}} {
+			if math.Abs(c.got-c.want) > 1e-6 {
+				t.Errorf("%+v: corners %v %v, want %v and %v", tile, nw, se, lo, hi)
+				break
+			}
+		}
+	}
+	if b := (tileID{}).bounds(); math.Abs(b.West+180+360*float64(tileBuffer)/tileExtent) > eps {
+		t.Errorf("world tile west = %v", b.West)
+	}
+}
+
+func TestClipLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line [][2]float64
+		want [][][2]float64
+	}{
+		{name: "inside", line: [][2]float64{{1, 1}, {5, 5}, {9, 1}}, want: [][][2]float64{{{1, 1}, {5, 5}, {9, 1}}}},
+		{name: "outside", line: [][2]float64{{-5, -5}, {-1, 20}}, want: nil},
+		{name: "crossing one edge", line: [][2]float64{{5, 5}, {15, 5}}, want: [][][2]float64{{{5, 5}, {10, 5}}}},
+		{name: "crossing through", line: [][2]float64{{-10, 5}, {20, 5}}, want: [][][2]float64{{{0, 5}, {10, 5}}}},
+		{name: "diagonal corner to corner", line: [][2]float64{{-5, -5}, {15, 15}}, want: [][][2]float64{{{0, 0}, {10, 10}}}},
+		{
+			name: "leaving and re-entering splits the line",
+			line: [][2]float64{{2, 2}, {2, 20}, {8, 20}, {8, 2}},
+			want: [][][2]float64{{{2, 2}, {2, 10}}, {{8, 10}, {8, 2}}},
+		},
+		{
+			name: "grazing outside then back in splits the line",
+			line: [][2]float64{{2, 5}, {12, 5}, {12, 6}, {2, 6}},
+			want: [][][2]float64{{{2, 5}, {10, 5}}, {{10, 6}, {2, 6}}},
+		},
+		{name: "single point", line: [][2]float64{{5, 5}}, want: nil},
+	}
+	for _, tt := range tests {
+		if got := clipLine(tt.line, 0, 10); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: clipLine = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// decodeFields splits a protobuf message into its fields, keeping varints as uint64 and
+// length-delimited fields as []byte
+func decodeFields(t *testing.T, b []byte) [][2]interface{} {
+	t.Helper()
+	var fields [][2]interface{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("bad tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var v interface{}
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+		if n < 0 {
+			t.Fatalf("bad field %d: %v", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+		fields = append(fields, [2]interface{}{num, v})
+	}
+	return fields
+}
+
+func decodePacked(t *testing.T, b []byte) []uint64 {
+	t.Helper()
+	var out []uint64
+	for len(b) > 0 {
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+		out, b = append(out, v), b[n:]
+	}
+	return out
+}
+
+func TestMVTLayerEncode(t *testing.T) {
+	l := newMVTLayer(congestionLayer)
+	l.addLine([][][2]float64{{{1, 1}, {3.4, 1}, {3, 1.2}, {3, 4}}, {{10, 10}, {10.2, 10.3}}, {{5, 5}, {8, 5}}},
+		[]mvtTag{{Key: "routeNo", Value: "0010"}, {Key: "grade", Value: int64(3)}})
+	l.addLine([][][2]float64{{{1, 1}, {1.2, 1.1}}}, []mvtTag{{Key: "routeNo", Value: "0500"}}) // collapses to a point
+	l.addLine([][][2]float64{{{0, 0}, {0, 2}}}, []mvtTag{{Key: "grade", Value: int64(3)}, {Key: "routeNo", Value: "0500"}})
+
+	tile := decodeFields(t, l.encode(nil))
+	if len(tile) != 1 || tile[0][0] != protowire.Number(3) {
+		t.Fatalf("tile fields = %v, want one layer", tile)
+	}
+
+	var name string
+	var keys []string
+	var values []interface{}
+	var features [][]byte
+	var version, extent uint64
+	for _, f := range decodeFields(t, tile[0][1].([]byte)) {
+		switch f[0] {
+		case protowire.Number(15):
+			version = f[1].(uint64)
+		case protowire.Number(1):
+			name = string(f[1].([]byte))
+		case protowire.Number(2):
+			features = append(features, f[1].([]byte))
+		case protowire.Number(3):
+			keys = append(keys, string(f[1].([]byte)))
+		case protowire.Number(4):
+			v := decodeFields(t, f[1].([]byte))[0]
+			if v[0] == protowire.Number(1) {
+				values = append(values, string(v[1].([]byte)))
+			} else {
+				values = append(values, int64(v[1].(uint64)))
+			}
+		case protowire.Number(5):
+			extent = f[1].(uint64)
+		}
+	}
+	if version != 2 || name != congestionLayer || extent != tileExtent {
+		t.Errorf("layer version %d, name %q, extent %d", version, name, extent)
+	}
+	if want := []string{"routeNo", "grade"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	if want := []interface{}{"0010", int64(3), "0500"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	if len(features) != 2 {
+		t.Fatalf("%d features, want the collapsed one dropped", len(features))
+	}
+
+	tests := []struct {
+		tags     []uint64
+		geometry []uint64
+	}{
+		{
+			tags: []uint64{0, 0, 1, 1},
+			// MoveTo(1,1) LineTo(3,1)(3,4); the 10,10 part collapses; MoveTo(5,5) LineTo(8,5)
+			geometry: []uint64{
+				mvtCommand(1, 1), protowire.EncodeZigZag(1), protowire.EncodeZigZag(1),
+				mvtCommand(2, 2), protowire.EncodeZigZag(2), protowire.EncodeZigZag(0), protowire.EncodeZigZag(0), protowire.EncodeZigZag(3),
+				mvtCommand(1, 1), protowire.EncodeZigZag(2), protowire.EncodeZigZag(1),
+				mvtCommand(2, 1), protowire.EncodeZigZag(3), protowire.EncodeZigZag(0),
+			},
+		},
+		{
+			tags:     []uint64{1, 1, 0, 2},
+			geometry: []uint64{mvtCommand(1, 1), 0, 0, mvtCommand(2, 1), 0, protowire.EncodeZigZag(2)},
+		},
+	}
+	for i, tt := range tests {
+		var tags, geometry []uint64
+		var geomType uint64
+		for _, f := range decodeFields(t, features[i]) {
+			switch f[0] {
+			case protowire.Number(2):
+				tags = decodePacked(t, f[1].([]byte))
+			case protowire.Number(3):
+				geomType = f[1].(uint64)
+			case protowire.Number(4):
+				geometry = decodePacked(t, f[1].([]byte))
+			}
+		}
+		if geomType != 2 || !reflect.DeepEqual(tags, tt.tags) || !reflect.DeepEqual(geometry, tt.geometry) {
+			t.Errorf("feature %d: type %d, tags %v, geometry %v; want tags %v, geometry %v", i, geomType, tags, geometry, tt.tags, tt.geometry)
+		}
+	}
+}
+
+func TestCongestionTileRejects(t *testing.T) {
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/traffic?timeout=100ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s := &Server{db: db}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: apiV1 + "/tiles/7/109/49.png", want: http.StatusBadRequest},
+		{path: apiV1 + "/tiles/30/0/0.mvt", want: http.StatusBadRequest},
+		{path: apiV1 + "/tiles/7/109/49.mvt", want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		s.getCongestionTile(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.path, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// openAPIVersion is the version of the v1 contract; bump the minor version for
+// additive changes. Breaking changes go to /api/v2.
+const openAPIVersion = "1.0.0"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaBuilder derives OpenAPI 3.0 schemas from Go types following encoding/json's
+// rules, collecting named structs under components/schemas
+type schemaBuilder struct {
+	components map[string]interface{}
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Ptr:
+		s := b.schema(t.Elem())
+		if _, isRef := s["$ref"]; isRef {
+			// Siblings of $ref are ignored in OpenAPI 3.0
+			return map[string]interface{}{"allOf": []interface{}{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	case t.Kind() == reflect.Struct:
+		if _, seen := b.components[t.Name()]; !seen {
+			b.components[t.Name()] = nil // reserve before recursing
+			b.components[t.Name()] = b.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case t.Kind() == reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		format := "int32"
+		if t.Kind() == reflect.Int64 || t.Kind() == reflect.Int {
+			format = "int64"
+		}
+		return map[string]interface{}{"type": "integer", "format": format}
+	case t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	}
+	return map[string]interface{}{} // interface{}: any value
+}
+
+// object lists a struct's JSON properties; embedded structs are flattened and fields
+// without omitempty are required
+func (b *schemaBuilder) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	b.fields(t, properties, &required)
+	s := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func (b *schemaBuilder) fields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			// Like encoding/json, promote fields of unexported embedded structs too
+			b.fields(f.Type, properties, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = b.schema(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// operationID turns a route path into an operationId, e.g. tiles/{z}/{x}/{y}.mvt into
+// tiles_z_x_y_mvt
+var operationID = strings.NewReplacer("/", "_", ".", "_", "{", "", "}", "")
+
+// buildOpenAPI generates the OpenAPI 3 document of the v1 API from routes. The
+// unversioned aliases are listed as deprecated.
+func buildOpenAPI(routes []apiRoute) map[string]interface{} {
+	b := &schemaBuilder{components: map[string]interface{}{}}
+	errorRef := b.schema(reflect.TypeOf(APIError{}))
+	errorResponse := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": description,
+			"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": errorRef}},
+		}
+	}
+
+	paths := map[string]interface{}{}
+	for _, route := range routes {
+		var params []interface{}
+		for _, p := range route.Params {
+			param := map[string]interface{}{
+				"name":        p.Name,
+				"in":          "query",
+				"description": p.Description,
+				"schema":      map[string]interface{}{"type": p.Type},
+			}
+			if p.InPath {
+				param["in"] = "path"
+				param["required"] = true
+			}
+			params = append(params, param)
+		}
+		content := map[string]interface{}{}
+		if len(route.Files) > 0 {
+			for _, mediaType := range route.Files {
+				content[mediaType] = map[string]interface{}{
+					"schema": map[string]interface{}{"type": "string", "format": "binary"},
+				}
+			}
+		} else {
+			mediaType := route.MediaType
+			if mediaType == "" {
+				mediaType = "application/json"
+			}
+			content[mediaType] = map[string]interface{}{"schema": b.schema(route.Response)}
+		}
+		operation := func(deprecated bool) map[string]interface{} {
+			op := map[string]interface{}{
+				"summary":     route.Summary,
+				"operationId": operationID.Replace(strings.Trim(route.Path, "/")),
+				"responses": map[string]interface{}{
+					"200":     map[string]interface{}{"description": "OK", "content": content},
+					"405":     errorResponse("Method not allowed"),
+					"default": errorResponse("Error"),
+				},
+			}
+			if len(params) > 0 {
+				op["parameters"] = params
+			}
+			if deprecated {
+				op["deprecated"] = true
+				op["operationId"] = op["operationId"].(string) + "_deprecated"
+				op["description"] = "Deprecated alias of " + apiV1 + route.Path + "."
+			}
+			return op
+		}
+		paths[apiV1+route.Path] = map[string]interface{}{"get": operation(false)}
+		if !route.NoAlias {
+			paths["/api"+route.Path] = map[string]interface{}{"get": operation(true)}
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Traffic Monitor Data API",
+			"version":     openAPIVersion,
+			"description": "Read API of the traffic monitoring dashboard. Errors use the APIError body.",
+		},
+		"servers":    []interface{}{map[string]interface{}{"url": "/"}},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": b.components},
+	}
+}
+
+// openAPIHandler serves the generated document; it is built once since the routes and
+// types are fixed at startup
+func openAPIHandler(routes []apiRoute) http.HandlerFunc {
+	doc, err := json.MarshalIndent(buildOpenAPI(routes), "", "  ")
+	if err != nil {
+		panic("openapi: " + err.Error())
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowGet(w, r) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Write(doc)
+	}
+}
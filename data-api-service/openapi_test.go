@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchemaBuilder(t *testing.T) {
+	type inner struct {
+		Name string `json:"name"`
+	}
+	type embedded struct {
+		Extra string `json:"extra,omitempty"`
+	}
+	type sample struct {
+		embedded
+		ID       int            `json:"id"`
+		Count    int32          `json:"count"`
+		Speed    float64        `json:"speed"`
+		Active   bool           `json:"active"`
+		At       time.Time      `json:"at"`
+		Lat      *float64       `json:"lat"`
+		Tags     []string       `json:"tags,omitempty"`
+		ByType   map[string]int `json:"byType"`
+		Child    *inner         `json:"child,omitempty"`
+		Any      interface{}    `json:"any,omitempty"`
+		Skipped  string         `json:"-"`
+		hidden   string
+		Untagged uint8
+	}
+
+	b := &schemaBuilder{components: map[string]interface{}{}}
+	if ref := b.schema(reflect.TypeOf(sample{})); ref["$ref"] != "#/components/schemas/sample" {
+		t.Fatalf("schema = %v, want a $ref", ref)
+	}
+	obj := b.components["sample"].(map[string]interface{})
+	props := obj["properties"].(map[string]interface{})
+
+	tests := []struct {
+		property string
+		want     map[string]interface{}
+	}{
+		{property: "extra", want: map[string]interface{}{"type": "string"}},
+		{property: "id", want: map[string]interface{}{"type": "integer", "format": "int64"}},
+		{property: "count", want: map[string]interface{}{"type": "integer", "format": "int32"}},
+		{property: "speed", want: map[string]interface{}{"type": "number", "format": "double"}},
+		{property: "active", want: map[string]interface{}{"type": "boolean"}},
+		{property: "at", want: map[string]interface{}{"type": "string", "format": "date-time"}},
+		{property: "lat", want: map[string]interface{}{"type": "number", "format": "double", "nullable": true}},
+		{property: "tags", want: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+		{property: "byType", want: map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "integer", "format": "int64"}}},
+		{property: "child", want: map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"$ref": "#/components/schemas/inner"}}, "nullable": true}},
+		{property: "any", want: map[string]interface{}{}},
+		{property: "Untagged", want: map[string]interface{}{"type": "integer", "minimum": 0}},
+	}
+	for _, tt := range tests {
+		if got := props[tt.property]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.property, got, tt.want)
+		}
+	}
+	if len(props) != len(tests) {
+		t.Errorf("properties = %v, want exactly %d", props, len(tests))
+	}
+	wantRequired := []string{"id", "count", "speed", "active", "at", "lat", "byType", "Untagged"}
+	if !reflect.DeepEqual(obj["required"], wantRequired) {
+		t.Errorf("required = %v, want %v", obj["required"], wantRequired)
+	}
+	if b.components["inner"] == nil {
+		t.Error("nested struct not collected under components")
+	}
+}
+
+func TestBuildOpenAPI(t *testing.T) {
+	s := &Server{}
+	doc := buildOpenAPI(s.apiRoutes())
+	paths := doc["paths"].(map[string]interface{})
+
+	tests := []struct {
+		path       string
+		deprecated bool
+		params     int
+	}{
+		{path: apiV1 + "/accidents/latest", params: 1},
+		{path: "/api/accidents/latest", deprecated: true, params: 1},
+		{path: apiV1 + "/road/summary"},
+		{path: "/api/road/summary", deprecated: true},
+		{path: apiV1 + "/tiles/{z}/{x}/{y}.mvt", params: 3},
+		{path: apiV1 + "/export/accidents", params: 3},
+	}
+	for _, tt := range tests {
+		item, ok := paths[tt.path].(map[string]interface{})
+		if !ok {
+			t.Errorf("%s missing from the document", tt.path)
+			continue
+		}
+		op := item["get"].(map[string]interface{})
+		if dep, _ := op["deprecated"].(bool); dep != tt.deprecated {
+			t.Errorf("%s: deprecated = %v, want %v", tt.path, dep, tt.deprecated)
+		}
+		params, _ := op["parameters"].([]interface{})
+		if len(params) != tt.params {
+			t.Errorf("%s: %d parameters, want %d", tt.path, len(params), tt.params)
+		}
+	}
+	for _, path := range []string{"/api/geo/accidents", "/api/export/accidents"} {
+		if _, ok := paths[path]; ok {
+			t.Errorf("%s: endpoints added after v1 must have no alias", path)
+		}
+	}
+
+	// operationIds must be unique across the document
+	seen := map[string]string{}
+	for path, item := range paths {
+		id := item.(map[string]interface{})["get"].(map[string]interface{})["operationId"].(string)
+		if other, dup := seen[id]; dup {
+			t.Errorf("operationId %s used by %s and %s", id, path, other)
+		}
+		seen[id] = path
+	}
+	if got := seen["tiles_z_x_y_mvt"]; got != apiV1+"/tiles/{z}/{x}/{y}.mvt" {
+		t.Errorf("tiles operationId on %q", got)
+	}
+
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for _, name := range []string{"Accident", "RoadRouteSummary", "APIError"} {
+		if schemas[name] == nil {
+			t.Errorf("component %s missing", name)
+		}
+	}
+	accident := schemas["Accident"].(map[string]interface{})["properties"].(map[string]interface{})
+	if _, ok := accident["accPointNM"]; !ok {
+		t.Errorf("Accident properties = %v, want the JSON field names", accident)
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	h := openAPIHandler((&Server{}).apiRoutes())
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, apiV1+"/openapi.json", nil))
+	var doc map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || doc["openapi"] != "3.0.3" || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("status %d, openapi %v, headers %v", w.Code, doc["openapi"], w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodPost, apiV1+"/openapi.json", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d", w.Code)
+	}
+}
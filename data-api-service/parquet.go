@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// A minimal Apache Parquet writer for flat exports: one PLAIN encoded, gzip compressed
+// data page per column chunk, and row groups flushed whenever parquetRowGroupSize bytes
+// are buffered so memory stays bounded. The footer is Thrift compact encoded by hand
+// to avoid pulling a Thrift runtime into the service.
+
+// parquetRowGroupSize bounds the encoded values buffered before a row group is written
+const parquetRowGroupSize = 8 << 20
+
+// Parquet enum values (parquet.thrift)
+const (
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+
+	parquetUTF8            = 0
+	parquetTimestampMillis = 9
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetGzip     = 2
+	parquetDataPage = 0
+)
+
+type parquetColumn struct {
+	exportColumn
+	values  bytes.Buffer // PLAIN encoded non-null values of the current row group
+	defined []bool       // definition levels of the current row group (optional columns)
+}
+
+func (c *parquetColumn) physicalType() int32 {
+	switch c.Type {
+	case intColumn, timeColumn:
+		return parquetInt64
+	case floatColumn:
+		return parquetDouble
+	}
+	return parquetByteArray
+}
+
+// countingWriter tracks the file offset for the footer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type parquetExport struct {
+	out       *countingWriter
+	columns   []*parquetColumn
+	rows      int64 // rows of the current row group
+	totalRows int64
+	rowGroups []interface{}
+}
+
+func newParquetExport(w io.Writer, d exportDataset) (exportWriter, error) {
+	e := &parquetExport{out: &countingWriter{w: w}}
+	for _, c := range d.Columns {
+		e.columns = append(e.columns, &parquetColumn{exportColumn: c})
+	}
+	_, err := io.WriteString(e.out, "PAR1")
+	return e, err
+}
+
+func (e *parquetExport) Write(values []interface{}) error {
+	var buffered int
+	for i, v := range values {
+		c := e.columns[i]
+		if c.Nullable {
+			c.defined = append(c.defined, v != nil)
+		}
+		switch v := v.(type) {
+		case int64:
+			binary.Write(&c.values, binary.LittleEndian, v)
+		case float64:
+			binary.Write(&c.values, binary.LittleEndian, math.Float64bits(v))
+		case string:
+			binary.Write(&c.values, binary.LittleEndian, uint32(len(v)))
+			c.values.WriteString(v)
+		case time.Time:
+			binary.Write(&c.values, binary.LittleEndian, v.UnixMilli())
+		case nil:
+			// Only definition levels record nulls
+			if !c.Nullable {
+				return fmt.Errorf("column %s: NULL in a required column", c.Name)
+			}
+		}
+		buffered += c.values.Len()
+	}
+	e.rows++
+	if buffered >= parquetRowGroupSize {
+		return e.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered rows as one row group
+func (e *parquetExport) flush() error {
+	if e.rows == 0 {
+		return nil
+	}
+	var chunks []interface{}
+	var groupSize int64
+	for _, c := range e.columns {
+		var page bytes.Buffer
+		if c.Nullable {
+			levels := encodeDefinitionLevels(c.defined)
+			binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+			page.Write(levels)
+		}
+		page.Write(c.values.Bytes())
+
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		zw.Write(page.Bytes())
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		header := thriftStruct(
+			thriftField{1, int32(parquetDataPage)},
+			thriftField{2, int32(page.Len())},
+			thriftField{3, int32(compressed.Len())},
+			thriftField{5, thriftStruct(
+				thriftField{1, int32(e.rows)},
+				thriftField{2, int32(parquetPlain)},
+				thriftField{3, int32(parquetRLE)},
+				thriftField{4, int32(parquetRLE)},
+			)},
+		)
+		offset := e.out.n
+		if _, err := e.out.Write(header); err != nil {
+			return err
+		}
+		if _, err := e.out.Write(compressed.Bytes()); err != nil {
+			return err
+		}
+
+		uncompressedSize := int64(len(header) + page.Len())
+		groupSize += uncompressedSize
+		chunks = append(chunks, thriftStruct(
+			thriftField{2, offset},
+			thriftField{3, thriftStruct(
+				thriftField{1, c.physicalType()},
+				thriftField{2, thriftList{int32(parquetPlain), int32(parquetRLE)}},
+				thriftField{3, thriftList{c.Name}},
+				thriftField{4, int32(parquetGzip)},
+				thriftField{5, e.rows},
+				thriftField{6, uncompressedSize},
+				thriftField{7, int64(len(header) + compressed.Len())},
+				thriftField{9, offset},
+			)},
+		))
+
+		c.values.Reset()
+		c.defined = c.defined[:0]
+	}
+
+	e.rowGroups = append(e.rowGroups, thriftStruct(
+		thriftField{1, thriftList(chunks)},
+		thriftField{2, groupSize},
+		thriftField{3, e.rows},
+	))
+	e.totalRows += e.rows
+	e.rows = 0
+	return nil
+}
+
+func (e *parquetExport) Close() error {
+	if err := e.flush(); err != nil {
+		return err
+	}
+
+	schema := thriftList{thriftStruct(
+		thriftField{4, "schema"},
+		thriftField{5, int32(len(e.columns))},
+	)}
+	for _, c := range e.columns {
+		repetition := int32(parquetRequired)
+		if c.Nullable {
+			repetition = parquetOptional
+		}
+		fields := []thriftField{
+			{1, c.physicalType()},
+			{3, repetition},
+			{4, c.Name},
+		}
+		switch c.Type {
+		case textColumn:
+			fields = append(fields, thriftField{6, int32(parquetUTF8)})
+		case timeColumn:
+			fields = append(fields, thriftField{6, int32(parquetTimestampMillis)})
+		}
+		schema = append(schema, thriftStruct(fields...))
+	}
+
+	footer := thriftStruct(
+		thriftField{1, int32(1)},
+		thriftField{2, schema},
+		thriftField{3, e.totalRows},
+		thriftField{4, thriftList(e.rowGroups)},
+		thriftField{6, "data-api-service"},
+	)
+	if _, err := e.out.Write(footer); err != nil {
+		return err
+	}
+	if err := binary.Write(e.out, binary.LittleEndian, uint32(len(footer))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.out, "PAR1")
+	return err
+}
+
+// Discard drops the buffered row group; nothing else is held outside the response
+func (e *parquetExport) Discard() {
+	for _, c := range e.columns {
+		c.values.Reset()
+		c.defined = nil
+	}
+}
+
+// encodeDefinitionLevels encodes 0/1 levels as a single bit-packed run of the
+// RLE/bit-packing hybrid encoding (bit width 1)
+func encodeDefinitionLevels(defined []bool) []byte {
+	groups := (len(defined) + 7) / 8
+	out := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+	packed := make([]byte, groups)
+	for i, d := range defined {
+		if d {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return append(out, packed...)
+}
+
+// Thrift compact protocol, limited to what the Parquet footer and page headers use:
+// i32, i64, string, struct and list fields
+
+type thriftField struct {
+	ID    int16
+	Value interface{} // int32, int64, string, thriftStruct (encoded) or thriftList
+}
+
+type thriftList []interface{}
+
+// thriftStruct encodes fields, which must be in ascending ID order
+func thriftStruct(fields ...thriftField) []byte {
+	var buf []byte
+	var last int16
+	for _, f := range fields {
+		typ := thriftType(f.Value)
+		if delta := f.ID - last; delta > 0 && delta <= 15 {
+			buf = append(buf, byte(delta)<<4|typ)
+		} else {
+			buf = append(buf, typ)
+			buf = binary.AppendVarint(buf, int64(f.ID))
+		}
+		buf = appendThriftValue(buf, f.Value)
+		last = f.ID
+	}
+	return append(buf, 0) // stop
+}
+
+func thriftType(v interface{}) byte {
+	switch v.(type) {
+	case int32:
+		return 5
+	case int64:
+		return 6
+	case string:
+		return 8
+	case thriftList:
+		return 9
+	}
+	return 12 // struct
+}
+
+func appendThriftValue(buf []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case int32:
+		return binary.AppendVarint(buf, int64(v))
+	case int64:
+		return binary.AppendVarint(buf, v)
+	case string:
+		buf = binary.AppendUvarint(buf, uint64(len(v)))
+		return append(buf, v...)
+	case thriftList:
+		var elem byte = 12
+		if len(v) > 0 {
+			elem = thriftType(v[0])
+		}
+		if len(v) < 15 {
+			buf = append(buf, byte(len(v))<<4|elem)
+		} else {
+			buf = append(buf, 0xf0|elem)
+			buf = binary.AppendUvarint(buf, uint64(len(v)))
+		}
+		for _, item := range v {
+			buf = appendThriftValue(buf, item)
+		}
+		return buf
+	case []byte:
+		return append(buf, v...) // struct encoded by thriftStruct
+	}
+	return buf
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// The reader below decodes the files independently of the writer's Thrift helpers, from
+// the Parquet format spec: compact protocol footer and page headers, gzip pages,
+// RLE/bit-packed definition levels and PLAIN values.
+
+type compactReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *compactReader) byte() byte {
+	b := r.buf[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		panic(fmt.Sprintf("bad varint at %d", r.pos))
+	}
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(typ byte) interface{} {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 5, 6:
+		return r.zigzag()
+	case 8:
+		n := int(r.uvarint())
+		s := string(r.buf[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case 9:
+		header := r.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case 12:
+		return r.structure()
+	}
+	panic(fmt.Sprintf("unsupported compact type %d", typ))
+}
+
+func (r *compactReader) structure() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+}
+
+type decodedColumn struct {
+	Name          string
+	Type          int64
+	Optional      bool
+	ConvertedType int64 // -1 when unset
+}
+
+type decodedFile struct {
+	Columns   []decodedColumn
+	NumRows   int64
+	RowGroups int
+	Rows      [][]interface{}
+}
+
+func readParquet(t *testing.T, data []byte) decodedFile {
+	t.Helper()
+	if len(data) < 12 || string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+		t.Fatalf("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footerStart := len(data) - 8 - footerLen
+	meta := (&compactReader{buf: data[footerStart : len(data)-8]}).structure()
+
+	var file decodedFile
+	file.NumRows = meta[3].(int64)
+	schema := meta[2].([]interface{})
+	if children := schema[0].(map[int16]interface{})[5].(int64); int(children) != len(schema)-1 {
+		t.Fatalf("root num_children = %d, want %d", children, len(schema)-1)
+	}
+	for _, s := range schema[1:] {
+		el := s.(map[int16]interface{})
+		col := decodedColumn{Name: el[4].(string), Type: el[1].(int64), Optional: el[3].(int64) == 1, ConvertedType: -1}
+		if ct, ok := el[6]; ok {
+			col.ConvertedType = ct.(int64)
+		}
+		file.Columns = append(file.Columns, col)
+	}
+
+	for _, g := range meta[4].([]interface{}) {
+		group := g.(map[int16]interface{})
+		file.RowGroups++
+		numRows := int(group[3].(int64))
+		columns := make([][]interface{}, len(file.Columns))
+		for i, c := range group[1].([]interface{}) {
+			chunk := c.(map[int16]interface{})[3].(map[int16]interface{})
+			if codec := chunk[4].(int64); codec != 2 {
+				t.Fatalf("codec = %d, want GZIP", codec)
+			}
+			if path := chunk[3].([]interface{}); path[0] != file.Columns[i].Name {
+				t.Fatalf("chunk %d path = %v, want %s", i, path, file.Columns[i].Name)
+			}
+			columns[i] = readColumnChunk(t, data, int(chunk[9].(int64)), file.Columns[i], numRows)
+		}
+		for row := 0; row < numRows; row++ {
+			values := make([]interface{}, len(columns))
+			for i := range columns {
+				values[i] = columns[i][row]
+			}
+			file.Rows = append(file.Rows, values)
+		}
+	}
+	return file
+}
+
+func readColumnChunk(t *testing.T, data []byte, offset int, col decodedColumn, numRows int) []interface{} {
+	t.Helper()
+	r := &compactReader{buf: data, pos: offset}
+	header := r.structure()
+	if typ := header[1].(int64); typ != 0 {
+		t.Fatalf("page type = %d, want DATA_PAGE", typ)
+	}
+	compressed := data[r.pos : r.pos+int(header[3].(int64))]
+	if n := int(header[5].(map[int16]interface{})[1].(int64)); n != numRows {
+		t.Fatalf("page num_values = %d, want %d", n, numRows)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != int(header[2].(int64)) {
+		t.Fatalf("uncompressed size = %d, header says %d", len(page), header[2])
+	}
+
+	defined := make([]bool, numRows)
+	for i := range defined {
+		defined[i] = true
+	}
+	if col.Optional {
+		n := int(binary.LittleEndian.Uint32(page))
+		defined = decodeLevels(page[4:4+n], numRows)
+		page = page[4+n:]
+	}
+
+	values := make([]interface{}, numRows)
+	for i := range values {
+		if !defined[i] {
+			continue
+		}
+		switch col.Type {
+		case 2:
+			v := int64(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+			if col.ConvertedType == 9 {
+				values[i] = time.UnixMilli(v)
+			} else {
+				values[i] = v
+			}
+		case 5:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+		case 6:
+			n := int(binary.LittleEndian.Uint32(page))
+			values[i] = string(page[4 : 4+n])
+			page = page[4+n:]
+		default:
+			t.Fatalf("unexpected physical type %d", col.Type)
+		}
+	}
+	if len(page) != 0 {
+		t.Fatalf("column %s: %d trailing bytes", col.Name, len(page))
+	}
+	return values
+}
+
+// decodeLevels decodes bit width 1 levels of the RLE/bit-packing hybrid encoding
+func decodeLevels(buf []byte, n int) []bool {
+	r := &compactReader{buf: buf}
+	var levels []bool
+	for len(levels) < n {
+		header := r.uvarint()
+		if header&1 == 1 {
+			for i := 0; i < int(header>>1); i++ {
+				b := r.byte()
+				for bit := 0; bit < 8; bit++ {
+					levels = append(levels, b&(1<<bit) != 0)
+				}
+			}
+		} else {
+			v := r.byte() == 1
+			for i := 0; i < int(header>>1); i++ {
+				levels = append(levels, v)
+			}
+		}
+	}
+	return levels[:n]
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	columns := []exportColumn{
+		{Name: "collected_at", Type: timeColumn},
+		{Name: "route_name", Type: textColumn, Nullable: true},
+		{Name: "speed", Type: intColumn, Nullable: true},
+		{Name: "latitude", Type: floatColumn, Nullable: true},
+		{Name: "grade", Type: intColumn},
+	}
+	base := time.Date(2024, 3, 1, 9, 30, 0, 0, kst)
+
+	row := func(i int, name interface{}, speed interface{}, lat interface{}) []interface{} {
+		return []interface{}{base.Add(time.Duration(i)*time.Minute + 123*time.Millisecond), name, speed, lat, int64(i % 4)}
+	}
+	many := make([][]interface{}, 21)
+	for i := range many {
+		var name, speed, lat interface{}
+		if i%3 != 0 {
+			name = fmt.Sprintf("경부선 %d", i)
+		}
+		if i%2 == 0 {
+			speed = int64(80 + i)
+		}
+		if i%5 != 4 {
+			lat = 37.5 + float64(i)/100
+		}
+		many[i] = row(i, name, speed, lat)
+	}
+
+	tests := []struct {
+		name       string
+		rows       [][]interface{}
+		flushEvery int // start a new row group after this many rows; 0 writes one group
+		wantGroups int
+	}{
+		{name: "empty", rows: nil, wantGroups: 0},
+		{name: "single row", rows: [][]interface{}{row(0, "영동선", int64(95), 37.27)}, wantGroups: 1},
+		{name: "all nulls", rows: [][]interface{}{row(0, nil, nil, nil), row(1, nil, nil, nil)}, wantGroups: 1},
+		{name: "nulls across level bytes", rows: many, wantGroups: 1},
+		{name: "multiple row groups", rows: many, flushEvery: 8, wantGroups: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			out, err := newParquetExport(&buf, exportDataset{Columns: columns})
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := out.(*parquetExport)
+			for i, values := range tt.rows {
+				if err := e.Write(values); err != nil {
+					t.Fatal(err)
+				}
+				if tt.flushEvery > 0 && (i+1)%tt.flushEvery == 0 {
+					if err := e.flush(); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if err := e.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			file := readParquet(t, buf.Bytes())
+			wantColumns := []decodedColumn{
+				{Name: "collected_at", Type: 2, ConvertedType: 9},
+				{Name: "route_name", Type: 6, Optional: true, ConvertedType: 0},
+				{Name: "speed", Type: 2, Optional: true, ConvertedType: -1},
+				{Name: "latitude", Type: 5, Optional: true, ConvertedType: -1},
+				{Name: "grade", Type: 2, ConvertedType: -1},
+			}
+			if !reflect.DeepEqual(file.Columns, wantColumns) {
+				t.Errorf("schema = %+v, want %+v", file.Columns, wantColumns)
+			}
+			if file.NumRows != int64(len(tt.rows)) {
+				t.Errorf("num_rows = %d, want %d", file.NumRows, len(tt.rows))
+			}
+			if file.RowGroups != tt.wantGroups {
+				t.Errorf("row groups = %d, want %d", file.RowGroups, tt.wantGroups)
+			}
+			if len(file.Rows) != len(tt.rows) {
+				t.Fatalf("decoded %d rows, want %d", len(file.Rows), len(tt.rows))
+			}
+			for i, want := range tt.rows {
+				got := file.Rows[i]
+				if !got[0].(time.Time).Equal(want[0].(time.Time)) {
+					t.Errorf("row %d collected_at = %v, want %v", i, got[0], want[0])
+				}
+				if !reflect.DeepEqual(got[1:], want[1:]) {
+					t.Errorf("row %d = %v, want %v", i, got[1:], want[1:])
+				}
+			}
+		})
+	}
+}
+
+func TestParquetRejectsNullInRequiredColumn(t *testing.T) {
+	out, err := newParquetExport(io.Discard, exportDataset{Columns: []exportColumn{{Name: "grade", Type: intColumn}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Write([]interface{}{nil}); err == nil {
+		t.Fatal("expected an error for NULL in a required column")
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Verdicts, ordered from best to worst
+const (
+	verdictGreen  = "green"
+	verdictYellow = "yellow"
+	verdictRed    = "red"
+)
+
+// DurationThreshold turns an age into a verdict: yellow after Warn, red after Crit
+type DurationThreshold struct {
+	Warn time.Duration
+	Crit time.Duration
+}
+
+func (t DurationThreshold) verdict(age time.Duration) string {
+	switch {
+	case age >= t.Crit:
+		return verdictRed
+	case age >= t.Warn:
+		return verdictYellow
+	default:
+		return verdictGreen
+	}
+}
+
+// CountThreshold turns a backlog size into a verdict: yellow from Warn, red from Crit
+type CountThreshold struct {
+	Warn int64
+	Crit int64
+}
+
+func (t CountThreshold) verdict(n int64) string {
+	switch {
+	case n >= t.Crit:
+		return verdictRed
+	case n >= t.Warn:
+		return verdictYellow
+	default:
+		return verdictGreen
+	}
+}
+
+// PipelineConfig holds the staleness thresholds behind /health/pipeline
+type PipelineConfig struct {
+	Accidents          DurationThreshold
+	Tollgate           DurationThreshold
+	RoadStatus         DurationThreshold
+	RouteSummary       DurationThreshold
+	StreamLag          CountThreshold
+	ExpectedSourceMode string
+}
+
+// parseDurationThreshold reads "warn,crit" durations (e.g. "5m,30m") from env, keeping def
+// when unset or invalid
+func parseDurationThreshold(env string, def DurationThreshold) DurationThreshold {
+	warn, crit, ok := strings.Cut(os.Getenv(env), ",")
+	if !ok {
+		return def
+	}
+	w, err1 := time.ParseDuration(strings.TrimSpace(warn))
+	c, err2 := time.ParseDuration(strings.TrimSpace(crit))
+	if err1 != nil || err2 != nil || w <= 0 || c < w {
+		return def
+	}
+	return DurationThreshold{Warn: w, Crit: c}
+}
+
+// parseCountThreshold reads "warn,crit" counts (e.g. "100,1000") from env, keeping def
+// when unset or invalid
+func parseCountThreshold(env string, def CountThreshold) CountThreshold {
+	warn, crit, ok := strings.Cut(os.Getenv(env), ",")
+	if !ok {
+		return def
+	}
+	w, err1 := strconv.ParseInt(strings.TrimSpace(warn), 10, 64)
+	c, err2 := strconv.ParseInt(strings.TrimSpace(crit), 10, 64)
+	if err1 != nil || err2 != nil || w <= 0 || c < w {
+		return def
+	}
+	return CountThreshold{Warn: w, Crit: c}
+}
+
+// PipelineCheck is one item of the pipeline health report. Table checks fill the age
+// fields, the stream check the backlog fields and the source check Mode.
+type PipelineCheck struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	Latest     *time.Time `json:"latest,omitempty"`
+	AgeSeconds *float64   `json:"ageSeconds,omitempty"`
+	WarnAfter  string     `json:"warnAfter,omitempty"`
+	CritAfter  string     `json:"critAfter,omitempty"`
+	Length     *int64     `json:"length,omitempty"`
+	Lag        *int64     `json:"lag,omitempty"`
+	Pending    *int64     `json:"pending,omitempty"`
+	WarnAt     *int64     `json:"warnAt,omitempty"`
+	CritAt     *int64     `json:"critAt,omitempty"`
+	Mode       string     `json:"mode,omitempty"`
+	Expected   string     `json:"expected,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// PipelineHealth is the /health/pipeline response; Status is the worst item verdict
+type PipelineHealth struct {
+	Status     string          `json:"status"`
+	SourceMode string          `json:"sourceMode"`
+	CheckedAt  time.Time       `json:"checkedAt"`
+	Checks     []PipelineCheck `json:"checks"`
+}
+
+// checkTableFreshness reports the age of the newest row of table by column
+func (s *Server) checkTableFreshness(ctx context.Context, table, column string, threshold DurationThreshold) PipelineCheck {
+	check := PipelineCheck{
+		Name:      table,
+		WarnAfter: threshold.Warn.String(),
+		CritAfter: threshold.Crit.String(),
+	}
+
+	var latest sql.NullTime
+	query := fmt.Sprintf("SELECT MAX(%s) FROM %s", column, table)
+	if err := s.queryRow(ctx, "/health/pipeline", query).Scan(&latest); err != nil {
+		check.Status = verdictRed
+		check.Error = err.Error()
+		return check
+	}
+	if !latest.Valid {
+		check.Status = verdictRed
+		check.Error = "table is empty"
+		return check
+	}
+
+	age := time.Since(latest.Time)
+	ageSeconds := age.Round(time.Second).Seconds()
+	check.Latest = &latest.Time
+	check.AgeSeconds = &ageSeconds
+	check.Status = threshold.verdict(age)
+	return check
+}
+
+// checkStream reports the stream length and the processor group's backlog. The verdict
+// follows the larger of lag (not yet delivered) and pending (delivered, not acked).
+func (s *Server) checkStream(ctx context.Context) PipelineCheck {
+	threshold := s.config.Pipeline.StreamLag
+	check := PipelineCheck{
+		Name:   s.config.StreamKey,
+		WarnAt: &threshold.Warn,
+		CritAt: &threshold.Crit,
+	}
+
+	length, err := s.redisClient.XLen(ctx, s.config.StreamKey).Result()
+	if err != nil {
+		check.Status = verdictRed
+		check.Error = err.Error()
+		return check
+	}
+	check.Length = &length
+
+	groups, err := s.redisClient.XInfoGroups(ctx, s.config.StreamKey).Result()
+	if err != nil {
+		check.Status = verdictRed
+		check.Error = err.Error()
+		return check
+	}
+	for _, g := range groups {
+		if g.Name != s.config.ConsumerGroup {
+			continue
+		}
+		// Lag needs Redis 7; it reads as 0 on older servers
+		lag, pending := g.Lag, g.Pending
+		check.Lag = &lag
+		check.Pending = &pending
+		check.Status = threshold.verdict(max(lag, pending))
+		return check
+	}
+
+	check.Status = verdictRed
+	check.Error = fmt.Sprintf("consumer group %s not found", s.config.ConsumerGroup)
+	return check
+}
+
+// checkSourceMode reports the source mode (real or sim) of the newest stream message,
+// which is the mode the active collector is running in
+func (s *Server) checkSourceMode(ctx context.Context) PipelineCheck {
+	check := PipelineCheck{
+		Name:     "source_mode",
+		Expected: s.config.Pipeline.ExpectedSourceMode,
+	}
+
+	msgs, err := s.redisClient.XRevRangeN(ctx, s.config.StreamKey, "+", "-", 1).Result()
+	if err != nil {
+		check.Status = verdictRed
+		check.Error = err.Error()
+		return check
+	}
+	if len(msgs) == 0 {
+		check.Status = verdictRed
+		check.Error = "no messages in stream"
+		return check
+	}
+
+	check.Mode, _ = msgs[0].Values["source"].(string)
+	if check.Mode == check.Expected {
+		check.Status = verdictGreen
+	} else {
+		check.Status = verdictYellow
+	}
+	return check
+}
+
+// pipelineHealthHandler reports data freshness from collection to aggregation with a
+// green/yellow/red verdict per item. It answers 503 when any item is red.
+func (s *Server) pipelineHealthHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	cfg := s.config.Pipeline
+	source := s.checkSourceMode(ctx)
+	report := PipelineHealth{
+		Status:     verdictGreen,
+		SourceMode: source.Mode,
+		CheckedAt:  time.Now(),
+		Checks: []PipelineCheck{
+			s.checkTableFreshness(ctx, "traffic_accidents", "created_at", cfg.Accidents),
+			s.checkTableFreshness(ctx, "tollgate_traffic_history", "collected_at", cfg.Tollgate),
+			s.checkTableFreshness(ctx, "road_traffic_status", "collected_at", cfg.RoadStatus),
+			s.checkTableFreshness(ctx, "road_route_summary", "collected_at", cfg.RouteSummary),
+			s.checkStream(ctx),
+			source,
+		},
+	}
+
+	rank := map[string]int{verdictGreen: 0, verdictYellow: 1, verdictRed: 2}
+	for _, c := range report.Checks {
+		if rank[c.Status] > rank[report.Status] {
+			report.Status = c.Status
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == verdictRed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationThresholdVerdict(t *testing.T) {
+	threshold := DurationThreshold{Warn: 5 * time.Minute, Crit: 30 * time.Minute}
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{age: 0, want: verdictGreen},
+		{age: 5*time.Minute - time.Second, want: verdictGreen},
+		{age: 5 * time.Minute, want: verdictYellow},
+		{age: 30 * time.Minute, want: verdictRed},
+		{age: 48 * time.Hour, want: verdictRed},
+	}
+	for _, tt := range tests {
+		if got := threshold.verdict(tt.age); got != tt.want {
+			t.Errorf("verdict(%v) = %s, want %s", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestCountThresholdVerdict(t *testing.T) {
+	threshold := CountThreshold{Warn: 100, Crit: 1000}
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{n: 0, want: verdictGreen},
+		{n: 99, want: verdictGreen},
+		{n: 100, want: verdictYellow},
+		{n: 1000, want: verdictRed},
+	}
+	for _, tt := range tests {
+		if got := threshold.verdict(tt.n); got != tt.want {
+			t.Errorf("verdict(%d) = %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestParseDurationThreshold(t *testing.T) {
+	def := DurationThreshold{Warn: time.Minute, Crit: time.Hour}
+	tests := []struct {
+		value string
+		want  DurationThreshold
+	}{
+		{value: "", want: def},
+		{value: "5m,30m", want: DurationThreshold{Warn: 5 * time.Minute, Crit: 30 * time.Minute}},
+		{value: " 10m , 10m ", want: DurationThreshold{Warn: 10 * time.Minute, Crit: 10 * time.Minute}},
+		{value: "30m,5m", want: def},
+		{value: "0s,5m", want: def},
+		{value: "5m", want: def},
+		{value: "five,ten", want: def},
+	}
+	for _, tt := range tests {
+		t.Setenv("TEST_THRESHOLD", tt.value)
+		if got := parseDurationThreshold("TEST_THRESHOLD", def); got != tt.want {
+			t.Errorf("parseDurationThreshold(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseCountThreshold(t *testing.T) {
+	def := CountThreshold{Warn: 100, Crit: 1000}
+	tests := []struct {
+		value string
+		want  CountThreshold
+	}{
+		{value: "", want: def},
+		{value: "10,50", want: CountThreshold{Warn: 10, Crit: 50}},
+		{value: "50,10", want: def},
+		{value: "0,10", want: def},
+		{value: "1.5,10", want: def},
+	}
+	for _, tt := range tests {
+		t.Setenv("TEST_THRESHOLD", tt.value)
+		if got := parseCountThreshold("TEST_THRESHOLD", def); got != tt.want {
+			t.Errorf("parseCountThreshold(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
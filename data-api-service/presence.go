@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// presenceKeyPrefix namespaces the instance heartbeats read by data-api-service's
+// /api/cluster/status; keys are <prefix><service>:<pod>
+const presenceKeyPrefix = "cluster-status:instance:"
+
+// presenceInterval is how often an instance refreshes its heartbeat; the key expires after
+// three missed beats, so a dead cluster disappears from the status within ~30s
+const presenceInterval = 10 * time.Second
+
+// Presence is the heartbeat an instance publishes so the status API can show which
+// cluster is serving each service
+type Presence struct {
+	Service   string    `json:"service"`
+	Cluster   string    `json:"cluster"`
+	Pod       string    `json:"pod"`
+	ID        string    `json:"id,omitempty"` // leader ID or consumer name, when the service has one
+	StartedAt time.Time `json:"startedAt"`
+}
+
+func newPresence(service, id string) Presence {
+	pod, _ := os.Hostname()
+	return Presence{
+		Service:   service,
+		Cluster:   os.Getenv("CLUSTER_NAME"),
+		Pod:       pod,
+		ID:        id,
+		StartedAt: time.Now(),
+	}
+}
+
+// startPresence heartbeats p until ctx is cancelled, then removes it so the status API
+// shows the instance gone immediately rather than after the key expires. The returned
+// func waits for the removal.
+func startPresence(ctx context.Context, rdb *redis.Client, p Presence) (wait func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runPresence(ctx, rdb, p)
+	}()
+	return func() { <-done }
+}
+
+func runPresence(ctx context.Context, rdb *redis.Client, p Presence) {
+	key := presenceKeyPrefix + p.Service + ":" + p.Pod
+	value, _ := json.Marshal(p)
+
+	beat := func() {
+		if err := rdb.Set(ctx, key, value, 3*presenceInterval).Err(); err != nil && ctx.Err() == nil {
+			slog.Warn("presence heartbeat failed", "error", err)
+		}
+	}
+
+	beat()
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			delCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			rdb.Del(delCtx, key)
+			cancel()
+			return
+		case <-ticker.C:
+			beat()
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// InstanceStatus is one running service instance as reported by its presence heartbeat
+type InstanceStatus struct {
+	Presence
+	Leader bool `json:"leader,omitempty"`
+}
+
+// LeaderStatus describes the current data-collector lease holder
+type LeaderStatus struct {
+	ID           string  `json:"id"`
+	FencingToken int64   `json:"fencingToken"`
+	TTLSeconds   float64 `json:"ttlSeconds"`
+	Cluster      string  `json:"cluster,omitempty"`
+	Pod          string  `json:"pod,omitempty"`
+}
+
+// ConsumerStatus is one data-processor consumer from XINFO CONSUMERS
+type ConsumerStatus struct {
+	Name        string  `json:"name"`
+	Pending     int64   `json:"pending"`
+	IdleSeconds float64 `json:"idleSeconds"`
+	Cluster     string  `json:"cluster,omitempty"`
+	Pod         string  `json:"pod,omitempty"`
+}
+
+// DependencyStatus is the reachability of a shared backend
+type DependencyStatus struct {
+	Reachable bool    `json:"reachable"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ClusterStatus is the /api/cluster/status response
+type ClusterStatus struct {
+	CheckedAt    time.Time                   `json:"checkedAt"`
+	Instances    []InstanceStatus            `json:"instances"`
+	Leader       *LeaderStatus               `json:"leader"`
+	Consumers    []ConsumerStatus            `json:"consumers"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+	Errors       []string                    `json:"errors,omitempty"`
+}
+
+// ping times fn as a reachability check
+func ping(fn func() error) DependencyStatus {
+	start := time.Now()
+	err := fn()
+	status := DependencyStatus{
+		Reachable: err == nil,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// listInstances reads every live presence heartbeat
+func (s *Server) listInstances(ctx context.Context) ([]InstanceStatus, error) {
+	var keys []string
+	iter := s.redisClient.Scan(ctx, 0, presenceKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []InstanceStatus{}, nil
+	}
+
+	values, err := s.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]InstanceStatus, 0, len(values))
+	for _, v := range values {
+		raw, ok := v.(string) // nil when the key expired between SCAN and MGET
+		if !ok {
+			continue
+		}
+		var inst InstanceStatus
+		if err := json.Unmarshal([]byte(raw), &inst.Presence); err == nil {
+			instances = append(instances, inst)
+		}
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		return a.Pod < b.Pod
+	})
+	return instances, nil
+}
+
+// leaderStatus reads the data-collector lease ("<holder>|<token>"); nil when nobody holds it
+func (s *Server) leaderStatus(ctx context.Context) (*LeaderStatus, error) {
+	value, err := s.redisClient.Get(ctx, s.config.LeaderKey).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	id, tokenStr, _ := strings.Cut(value, "|")
+	token, _ := strconv.ParseInt(tokenStr, 10, 64)
+	leader := &LeaderStatus{ID: id, FencingToken: token}
+	if ttl, err := s.redisClient.PTTL(ctx, s.config.LeaderKey).Result(); err == nil && ttl > 0 {
+		leader.TTLSeconds = ttl.Seconds()
+	}
+	return leader, nil
+}
+
+// consumerStatus lists the processor group's consumers
+func (s *Server) consumerStatus(ctx context.Context) ([]ConsumerStatus, error) {
+	consumers, err := s.redisClient.XInfoConsumers(ctx, s.config.StreamKey, s.config.ConsumerGroup).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ConsumerStatus, 0, len(consumers))
+	for _, c := range consumers {
+		result = append(result, ConsumerStatus{
+			Name:        c.Name,
+			Pending:     c.Pending,
+			IdleSeconds: c.Idle.Seconds(),
+		})
+	}
+	return result, nil
+}
+
+// clusterStatusHandler shows where each service is running, who leads data collection,
+// which processor consumers are live and whether Redis and MariaDB are reachable, so the
+// failover demo reflects real state. Partial failures are listed in errors.
+func (s *Server) clusterStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	status := ClusterStatus{
+		CheckedAt: time.Now(),
+		Instances: []InstanceStatus{},
+		Consumers: []ConsumerStatus{},
+		Dependencies: map[string]DependencyStatus{
+			"redis":   ping(func() error { return s.redisClient.Ping(ctx).Err() }),
+			"mariadb": ping(func() error { return s.db.PingContext(ctx) }),
+		},
+	}
+
+	if !status.Dependencies["redis"].Reachable {
+		status.Errors = append(status.Errors, "redis unreachable: instances, leader and consumers unknown")
+	} else {
+		var err error
+		if status.Instances, err = s.listInstances(ctx); err != nil {
+			status.Instances = []InstanceStatus{}
+			status.Errors = append(status.Errors, "instances: "+err.Error())
+		}
+		if status.Leader, err = s.leaderStatus(ctx); err != nil {
+			status.Errors = append(status.Errors, "leader: "+err.Error())
+		}
+		if consumers, err := s.consumerStatus(ctx); err != nil {
+			status.Errors = append(status.Errors, "consumers: "+err.Error())
+		} else {
+			status.Consumers = consumers
+		}
+	}
+
+	// Place the leader and consumers on their clusters via the heartbeat IDs
+	for i := range status.Instances {
+		inst := &status.Instances[i]
+		if status.Leader != nil && inst.Service == "data-collector" && inst.ID == status.Leader.ID {
+			inst.Leader = true
+			status.Leader.Cluster, status.Leader.Pod = inst.Cluster, inst.Pod
+		}
+		for j := range status.Consumers {
+			if inst.Service == "data-processor" && inst.ID == status.Consumers[j].Name {
+				status.Consumers[j].Cluster, status.Consumers[j].Pod = inst.Cluster, inst.Pod
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		loggerFrom(r.Context()).Error("encode failed", "error", err)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		err  error
+		want DependencyStatus
+	}{
+		{err: nil, want: DependencyStatus{Reachable: true}},
+		{err: errors.New("connection refused"), want: DependencyStatus{Error: "connection refused"}},
+	}
+	for _, tt := range tests {
+		got := ping(func() error { return tt.err })
+		if got.Reachable != tt.want.Reachable || got.Error != tt.want.Error || got.LatencyMs < 0 {
+			t.Errorf("ping(%v) = %+v, want %+v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNewPresence(t *testing.T) {
+	t.Setenv("CLUSTER_NAME", "member1")
+	p := newPresence("data-processor", "consumer-1")
+	if p.Service != "data-processor" || p.Cluster != "member1" || p.ID != "consumer-1" || p.Pod == "" {
+		t.Errorf("presence = %+v", p)
+	}
+	if time.Since(p.StartedAt) > time.Minute {
+		t.Errorf("started at %v", p.StartedAt)
+	}
+}
+
+// With Redis and MariaDB both down the handler still answers, listing what is unknown
+func TestClusterStatusDependenciesDown(t *testing.T) {
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/traffic?timeout=100ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	defer rdb.Close()
+
+	s := &Server{db: db, redisClient: rdb}
+	w := httptest.NewRecorder()
+	s.clusterStatusHandler(w, httptest.NewRequest(http.MethodGet, "/api/v1/cluster/status", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	var status ClusterStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"redis", "mariadb"} {
+		if dep := status.Dependencies[name]; dep.Reachable || dep.Error == "" {
+			t.Errorf("%s = %+v, want unreachable with an error", name, dep)
+		}
+	}
+	if status.Instances == nil || status.Consumers == nil || status.Leader != nil || len(status.Errors) != 1 {
+		t.Errorf("status = %+v, want empty lists and one error", status)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanRecorder installs a tracer provider that keeps finished spans in memory. The
+// package tracer binds to the first provider set, so it is installed once per test binary.
+var spanRecorder = sync.OnceValue(func() *tracetest.SpanRecorder {
+	os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	os.Unsetenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if _, err := initTracing(context.Background(), "data-api-service"); err != nil {
+		panic(err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return recorder
+})
+
+// endedSpans returns the spans with the given name finished after the first skip spans
+func endedSpans(skip int, name string) []sdktrace.ReadOnlySpan {
+	var spans []sdktrace.ReadOnlySpan
+	for _, s := range spanRecorder().Ended()[skip:] {
+		if s.Name() == name {
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+func TestInstrumentContinuesCallerTrace(t *testing.T) {
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name      string
+		path      string
+		traced    bool
+		requestID string
+	}{
+		{name: "API request", path: "/api/v1/accidents", traced: true, requestID: "req-1"},
+		{name: "health probe", path: "/health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skip := len(spanRecorder().Ended())
+			h := instrument(tt.path, func(w http.ResponseWriter, r *http.Request) {})
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.Header.Set("traceparent", parent)
+			r.Header.Set(requestIDHeader, tt.requestID)
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			spans := endedSpans(skip, "GET "+tt.path)
+			if !tt.traced {
+				if len(spans) != 0 {
+					t.Errorf("recorded %d spans, want none", len(spans))
+				}
+				return
+			}
+			if len(spans) != 1 {
+				t.Fatalf("recorded %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.SpanKind() != trace.SpanKindServer {
+				t.Errorf("span kind = %v, want server", span.SpanKind())
+			}
+			if got := span.Parent().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("trace ID = %s, want the caller's", got)
+			}
+			var requestID string
+			for _, a := range span.Attributes() {
+				if a.Key == "http.request_id" {
+					requestID = a.Value.AsString()
+				}
+			}
+			if requestID != tt.requestID {
+				t.Errorf("request ID attribute = %q, want %q", requestID, tt.requestID)
+			}
+		})
+	}
+}
+
+func TestStartQuerySpan(t *testing.T) {
+	skip := len(spanRecorder().Ended())
+
+	_, done := startQuery(context.Background(), "/api/v1/accidents")
+	done(errors.New("connection refused"))
+
+	spans := endedSpans(skip, "db.query /api/v1/accidents")
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span kind = %v, want client", span.SpanKind())
+	}
+	if span.Status().Code != codes.Error || len(span.Events()) == 0 {
+		t.Errorf("status = %+v with %d events, want the error recorded", span.Status(), len(span.Events()))
+	}
+}
@@ -0,0 +1,4 @@
+// Package trafficpb holds the generated protobuf and gRPC code of the TrafficData API.
+package trafficpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative traffic.proto
@@ -0,0 +1,1637 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: traffic.proto
+
+// Traffic data API for internal Go services. Mirrors the /api/v1 JSON handlers of
+// data-api-service; the Watch RPCs stream updates as new collections land.
+
+package trafficpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Accident struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccDate    string                 `protobuf:"bytes,2,opt,name=acc_date,json=accDate,proto3" json:"acc_date,omitempty"` // YYYYMMDD
+	AccHour    string                 `protobuf:"bytes,3,opt,name=acc_hour,json=accHour,proto3" json:"acc_hour,omitempty"` // HHMM
+	AccPointNm string                 `protobuf:"bytes,4,opt,name=acc_point_nm,json=accPointNm,proto3" json:"acc_point_nm,omitempty"`
+	RoadNm     string                 `protobuf:"bytes,5,opt,name=road_nm,json=roadNm,proto3" json:"road_nm,omitempty"`
+	NosunNm    string                 `protobuf:"bytes,6,opt,name=nosun_nm,json=nosunNm,proto3" json:"nosun_nm,omitempty"` // route name
+	SmsText    string                 `protobuf:"bytes,7,opt,name=sms_text,json=smsText,proto3" json:"sms_text,omitempty"`
+	AccType    string                 `protobuf:"bytes,8,opt,name=acc_type,json=accType,proto3" json:"acc_type,omitempty"`
+	Latitude   *float64               `protobuf:"fixed64,9,opt,name=latitude,proto3,oneof" json:"latitude,omitempty"`
+	Longitude  *float64               `protobuf:"fixed64,10,opt,name=longitude,proto3,oneof" json:"longitude,omitempty"` // "altitude" in the JSON API, named after the source API
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Accident) Reset() {
+	*x = Accident{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Accident) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Accident) ProtoMessage() {}
+
+func (x *Accident) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Accident.ProtoReflect.Descriptor instead.
+func (*Accident) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Accident) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Accident) GetAccDate() string {
+	if x != nil {
+		return x.AccDate
+	}
+	return ""
+}
+
+func (x *Accident) GetAccHour() string {
+	if x != nil {
+		return x.AccHour
+	}
+	return ""
+}
+
+func (x *Accident) GetAccPointNm() string {
+	if x != nil {
+		return x.AccPointNm
+	}
+	return ""
+}
+
+func (x *Accident) GetRoadNm() string {
+	if x != nil {
+		return x.RoadNm
+	}
+	return ""
+}
+
+func (x *Accident) GetNosunNm() string {
+	if x != nil {
+		return x.NosunNm
+	}
+	return ""
+}
+
+func (x *Accident) GetSmsText() string {
+	if x != nil {
+		return x.SmsText
+	}
+	return ""
+}
+
+func (x *Accident) GetAccType() string {
+	if x != nil {
+		return x.AccType
+	}
+	return ""
+}
+
+func (x *Accident) GetLatitude() float64 {
+	if x != nil && x.Latitude != nil {
+		return *x.Latitude
+	}
+	return 0
+}
+
+func (x *Accident) GetLongitude() float64 {
+	if x != nil && x.Longitude != nil {
+		return *x.Longitude
+	}
+	return 0
+}
+
+func (x *Accident) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type RoadStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RouteNo        string                 `protobuf:"bytes,1,opt,name=route_no,json=routeNo,proto3" json:"route_no,omitempty"`
+	RouteName      string                 `protobuf:"bytes,2,opt,name=route_name,json=routeName,proto3" json:"route_name,omitempty"`
+	ConzoneId      string                 `protobuf:"bytes,3,opt,name=conzone_id,json=conzoneId,proto3" json:"conzone_id,omitempty"`
+	ConzoneName    string                 `protobuf:"bytes,4,opt,name=conzone_name,json=conzoneName,proto3" json:"conzone_name,omitempty"`
+	VdsId          string                 `protobuf:"bytes,5,opt,name=vds_id,json=vdsId,proto3" json:"vds_id,omitempty"`
+	UpdownTypeCode string                 `protobuf:"bytes,6,opt,name=updown_type_code,json=updownTypeCode,proto3" json:"updown_type_code,omitempty"` // S: towards the start, E: towards the end
+	TrafficAmount  int32                  `protobuf:"varint,7,opt,name=traffic_amount,json=trafficAmount,proto3" json:"traffic_amount,omitempty"`
+	Speed          int32                  `protobuf:"varint,8,opt,name=speed,proto3" json:"speed,omitempty"` // km/h
+	ShareRatio     int32                  `protobuf:"varint,9,opt,name=share_ratio,json=shareRatio,proto3" json:"share_ratio,omitempty"`
+	TimeAvg        int32                  `protobuf:"varint,10,opt,name=time_avg,json=timeAvg,proto3" json:"time_avg,omitempty"`
+	Grade          int32                  `protobuf:"varint,11,opt,name=grade,proto3" json:"grade,omitempty"` // 0: unknown, 1: smooth, 2: slow, 3: congested
+	CollectedAt    *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`
+	// Conzone polyline and VDS location, set once the road network has been imported
+	Geometry     []*LonLat `protobuf:"bytes,13,rep,name=geometry,proto3" json:"geometry,omitempty"`
+	VdsLatitude  *float64  `protobuf:"fixed64,14,opt,name=vds_latitude,json=vdsLatitude,proto3,oneof" json:"vds_latitude,omitempty"`
+	VdsLongitude *float64  `protobuf:"fixed64,15,opt,name=vds_longitude,json=vdsLongitude,proto3,oneof" json:"vds_longitude,omitempty"`
+}
+
+func (x *RoadStatus) Reset() {
+	*x = RoadStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoadStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoadStatus) ProtoMessage() {}
+
+func (x *RoadStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoadStatus.ProtoReflect.Descriptor instead.
+func (*RoadStatus) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RoadStatus) GetRouteNo() string {
+	if x != nil {
+		return x.RouteNo
+	}
+	return ""
+}
+
+func (x *RoadStatus) GetRouteName() string {
+	if x != nil {
+		return x.RouteName
+	}
+	return ""
+}
+
+func (x *RoadStatus) GetConzoneId() string {
+	if x != nil {
+		return x.ConzoneId
+	}
+	return ""
+}
+
+func (x *RoadStatus) GetConzoneName() string {
+	if x != nil {
+		return x.ConzoneName
+	}
+	return ""
+}
+
+func (x *RoadStatus) GetVdsId() string {
+	if x != nil {
+		return x.VdsId
+	}
+	return ""
+}
+
+func (x *RoadStatus) GetUpdownTypeCode() string {
+	if x != nil {
+		return x.UpdownTypeCode
+	}
+	return ""
+}
+
+func (x *RoadStatus) GetTrafficAmount() int32 {
+	if x != nil {
+		return x.TrafficAmount
+	}
+	return 0
+}
+
+func (x *RoadStatus) GetSpeed() int32 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *RoadStatus) GetShareRatio() int32 {
+	if x != nil {
+		return x.ShareRatio
+	}
+	return 0
+}
+
+func (x *RoadStatus) GetTimeAvg() int32 {
+	if x != nil {
+		return x.TimeAvg
+	}
+	return 0
+}
+
+func (x *RoadStatus) GetGrade() int32 {
+	if x != nil {
+		return x.Grade
+	}
+	return 0
+}
+
+func (x *RoadStatus) GetCollectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CollectedAt
+	}
+	return nil
+}
+
+func (x *RoadStatus) GetGeometry() []*LonLat {
+	if x != nil {
+		return x.Geometry
+	}
+	return nil
+}
+
+func (x *RoadStatus) GetVdsLatitude() float64 {
+	if x != nil && x.VdsLatitude != nil {
+		return *x.VdsLatitude
+	}
+	return 0
+}
+
+func (x *RoadStatus) GetVdsLongitude() float64 {
+	if x != nil && x.VdsLongitude != nil {
+		return *x.VdsLongitude
+	}
+	return 0
+}
+
+// A WGS84 coordinate
+type LonLat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Longitude float64 `protobuf:"fixed64,1,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Latitude  float64 `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
+}
+
+func (x *LonLat) Reset() {
+	*x = LonLat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LonLat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LonLat) ProtoMessage() {}
+
+func (x *LonLat) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LonLat.ProtoReflect.Descriptor instead.
+func (*LonLat) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LonLat) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *LonLat) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+type RoadRouteSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RouteNo           string                 `protobuf:"bytes,1,opt,name=route_no,json=routeNo,proto3" json:"route_no,omitempty"`
+	RouteName         string                 `protobuf:"bytes,2,opt,name=route_name,json=routeName,proto3" json:"route_name,omitempty"`
+	TotalSections     int32                  `protobuf:"varint,3,opt,name=total_sections,json=totalSections,proto3" json:"total_sections,omitempty"`
+	SmoothSections    int32                  `protobuf:"varint,4,opt,name=smooth_sections,json=smoothSections,proto3" json:"smooth_sections,omitempty"`
+	SlowSections      int32                  `protobuf:"varint,5,opt,name=slow_sections,json=slowSections,proto3" json:"slow_sections,omitempty"`
+	CongestedSections int32                  `protobuf:"varint,6,opt,name=congested_sections,json=congestedSections,proto3" json:"congested_sections,omitempty"`
+	AvgSpeed          float64                `protobuf:"fixed64,7,opt,name=avg_speed,json=avgSpeed,proto3" json:"avg_speed,omitempty"`
+	AvgTrafficAmount  float64                `protobuf:"fixed64,8,opt,name=avg_traffic_amount,json=avgTrafficAmount,proto3" json:"avg_traffic_amount,omitempty"`
+	CollectedAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`
+}
+
+func (x *RoadRouteSummary) Reset() {
+	*x = RoadRouteSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoadRouteSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoadRouteSummary) ProtoMessage() {}
+
+func (x *RoadRouteSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoadRouteSummary.ProtoReflect.Descriptor instead.
+func (*RoadRouteSummary) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RoadRouteSummary) GetRouteNo() string {
+	if x != nil {
+		return x.RouteNo
+	}
+	return ""
+}
+
+func (x *RoadRouteSummary) GetRouteName() string {
+	if x != nil {
+		return x.RouteName
+	}
+	return ""
+}
+
+func (x *RoadRouteSummary) GetTotalSections() int32 {
+	if x != nil {
+		return x.TotalSections
+	}
+	return 0
+}
+
+func (x *RoadRouteSummary) GetSmoothSections() int32 {
+	if x != nil {
+		return x.SmoothSections
+	}
+	return 0
+}
+
+func (x *RoadRouteSummary) GetSlowSections() int32 {
+	if x != nil {
+		return x.SlowSections
+	}
+	return 0
+}
+
+func (x *RoadRouteSummary) GetCongestedSections() int32 {
+	if x != nil {
+		return x.CongestedSections
+	}
+	return 0
+}
+
+func (x *RoadRouteSummary) GetAvgSpeed() float64 {
+	if x != nil {
+		return x.AvgSpeed
+	}
+	return 0
+}
+
+func (x *RoadRouteSummary) GetAvgTrafficAmount() float64 {
+	if x != nil {
+		return x.AvgTrafficAmount
+	}
+	return 0
+}
+
+func (x *RoadRouteSummary) GetCollectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CollectedAt
+	}
+	return nil
+}
+
+type TollgateTrafficData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CollectedAt   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=collected_at,json=collectedAt,proto3" json:"collected_at,omitempty"`
+	TrafficAmount int32                  `protobuf:"varint,2,opt,name=traffic_amount,json=trafficAmount,proto3" json:"traffic_amount,omitempty"`
+}
+
+func (x *TollgateTrafficData) Reset() {
+	*x = TollgateTrafficData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TollgateTrafficData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TollgateTrafficData) ProtoMessage() {}
+
+func (x *TollgateTrafficData) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TollgateTrafficData.ProtoReflect.Descriptor instead.
+func (*TollgateTrafficData) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TollgateTrafficData) GetCollectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CollectedAt
+	}
+	return nil
+}
+
+func (x *TollgateTrafficData) GetTrafficAmount() int32 {
+	if x != nil {
+		return x.TrafficAmount
+	}
+	return 0
+}
+
+type TollgateTraffic struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UnitCode    string                 `protobuf:"bytes,1,opt,name=unit_code,json=unitCode,proto3" json:"unit_code,omitempty"`
+	UnitName    string                 `protobuf:"bytes,2,opt,name=unit_name,json=unitName,proto3" json:"unit_name,omitempty"`
+	ExDivName   string                 `protobuf:"bytes,3,opt,name=ex_div_name,json=exDivName,proto3" json:"ex_div_name,omitempty"`
+	TrafficData []*TollgateTrafficData `protobuf:"bytes,4,rep,name=traffic_data,json=trafficData,proto3" json:"traffic_data,omitempty"`
+	LastUpdated *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+}
+
+func (x *TollgateTraffic) Reset() {
+	*x = TollgateTraffic{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TollgateTraffic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TollgateTraffic) ProtoMessage() {}
+
+func (x *TollgateTraffic) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TollgateTraffic.ProtoReflect.Descriptor instead.
+func (*TollgateTraffic) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TollgateTraffic) GetUnitCode() string {
+	if x != nil {
+		return x.UnitCode
+	}
+	return ""
+}
+
+func (x *TollgateTraffic) GetUnitName() string {
+	if x != nil {
+		return x.UnitName
+	}
+	return ""
+}
+
+func (x *TollgateTraffic) GetExDivName() string {
+	if x != nil {
+		return x.ExDivName
+	}
+	return ""
+}
+
+func (x *TollgateTraffic) GetTrafficData() []*TollgateTrafficData {
+	if x != nil {
+		return x.TrafficData
+	}
+	return nil
+}
+
+func (x *TollgateTraffic) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+type GetLatestAccidentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // 1-1000, default 100
+}
+
+func (x *GetLatestAccidentsRequest) Reset() {
+	*x = GetLatestAccidentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLatestAccidentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestAccidentsRequest) ProtoMessage() {}
+
+func (x *GetLatestAccidentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestAccidentsRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestAccidentsRequest) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetLatestAccidentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetLatestAccidentsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Accidents []*Accident `protobuf:"bytes,1,rep,name=accidents,proto3" json:"accidents,omitempty"`
+}
+
+func (x *GetLatestAccidentsResponse) Reset() {
+	*x = GetLatestAccidentsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLatestAccidentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestAccidentsResponse) ProtoMessage() {}
+
+func (x *GetLatestAccidentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestAccidentsResponse.ProtoReflect.Descriptor instead.
+func (*GetLatestAccidentsResponse) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetLatestAccidentsResponse) GetAccidents() []*Accident {
+	if x != nil {
+		return x.Accidents
+	}
+	return nil
+}
+
+type GetRoadStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetRoadStatusRequest) Reset() {
+	*x = GetRoadStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRoadStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoadStatusRequest) ProtoMessage() {}
+
+func (x *GetRoadStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoadStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetRoadStatusRequest) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{8}
+}
+
+type GetRoadStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RoadStatuses []*RoadStatus `protobuf:"bytes,1,rep,name=road_statuses,json=roadStatuses,proto3" json:"road_statuses,omitempty"`
+}
+
+func (x *GetRoadStatusResponse) Reset() {
+	*x = GetRoadStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRoadStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoadStatusResponse) ProtoMessage() {}
+
+func (x *GetRoadStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoadStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetRoadStatusResponse) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetRoadStatusResponse) GetRoadStatuses() []*RoadStatus {
+	if x != nil {
+		return x.RoadStatuses
+	}
+	return nil
+}
+
+type GetRoadRouteSummaryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetRoadRouteSummaryRequest) Reset() {
+	*x = GetRoadRouteSummaryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRoadRouteSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoadRouteSummaryRequest) ProtoMessage() {}
+
+func (x *GetRoadRouteSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoadRouteSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetRoadRouteSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{10}
+}
+
+type GetRoadRouteSummaryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Summaries []*RoadRouteSummary `protobuf:"bytes,1,rep,name=summaries,proto3" json:"summaries,omitempty"`
+}
+
+func (x *GetRoadRouteSummaryResponse) Reset() {
+	*x = GetRoadRouteSummaryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRoadRouteSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoadRouteSummaryResponse) ProtoMessage() {}
+
+func (x *GetRoadRouteSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoadRouteSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetRoadRouteSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetRoadRouteSummaryResponse) GetSummaries() []*RoadRouteSummary {
+	if x != nil {
+		return x.Summaries
+	}
+	return nil
+}
+
+type GetTollgateTrafficRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetTollgateTrafficRequest) Reset() {
+	*x = GetTollgateTrafficRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTollgateTrafficRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTollgateTrafficRequest) ProtoMessage() {}
+
+func (x *GetTollgateTrafficRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTollgateTrafficRequest.ProtoReflect.Descriptor instead.
+func (*GetTollgateTrafficRequest) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{12}
+}
+
+type GetTollgateTrafficResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tollgates []*TollgateTraffic `protobuf:"bytes,1,rep,name=tollgates,proto3" json:"tollgates,omitempty"`
+}
+
+func (x *GetTollgateTrafficResponse) Reset() {
+	*x = GetTollgateTrafficResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTollgateTrafficResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTollgateTrafficResponse) ProtoMessage() {}
+
+func (x *GetTollgateTrafficResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTollgateTrafficResponse.ProtoReflect.Descriptor instead.
+func (*GetTollgateTrafficResponse) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetTollgateTrafficResponse) GetTollgates() []*TollgateTraffic {
+	if x != nil {
+		return x.Tollgates
+	}
+	return nil
+}
+
+type WatchAccidentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchAccidentsRequest) Reset() {
+	*x = WatchAccidentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchAccidentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAccidentsRequest) ProtoMessage() {}
+
+func (x *WatchAccidentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAccidentsRequest.ProtoReflect.Descriptor instead.
+func (*WatchAccidentsRequest) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{14}
+}
+
+type WatchRoadStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchRoadStatusRequest) Reset() {
+	*x = WatchRoadStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRoadStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRoadStatusRequest) ProtoMessage() {}
+
+func (x *WatchRoadStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRoadStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchRoadStatusRequest) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{15}
+}
+
+type WatchRoadRouteSummaryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchRoadRouteSummaryRequest) Reset() {
+	*x = WatchRoadRouteSummaryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRoadRouteSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRoadRouteSummaryRequest) ProtoMessage() {}
+
+func (x *WatchRoadRouteSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRoadRouteSummaryRequest.ProtoReflect.Descriptor instead.
+func (*WatchRoadRouteSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{16}
+}
+
+type WatchTollgateTrafficRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchTollgateTrafficRequest) Reset() {
+	*x = WatchTollgateTrafficRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_traffic_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchTollgateTrafficRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTollgateTrafficRequest) ProtoMessage() {}
+
+func (x *WatchTollgateTrafficRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_traffic_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTollgateTrafficRequest.ProtoReflect.Descriptor instead.
+func (*WatchTollgateTrafficRequest) Descriptor() ([]byte, []int) {
+	return file_traffic_proto_rawDescGZIP(), []int{17}
+}
+
+var File_traffic_proto protoreflect.FileDescriptor
+
+var file_traffic_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0a, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf6, 0x02, 0x0a,
+	0x08, 0x41, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x63, 0x63,
+	0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63,
+	0x44, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x5f, 0x68, 0x6f, 0x75, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x48, 0x6f, 0x75, 0x72, 0x12,
+	0x20, 0x0a, 0x0c, 0x61, 0x63, 0x63, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x5f, 0x6e, 0x6d, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x4e,
+	0x6d, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x61, 0x64, 0x5f, 0x6e, 0x6d, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x72, 0x6f, 0x61, 0x64, 0x4e, 0x6d, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x6f,
+	0x73, 0x75, 0x6e, 0x5f, 0x6e, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x6f,
+	0x73, 0x75, 0x6e, 0x4e, 0x6d, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x6d, 0x73, 0x5f, 0x74, 0x65, 0x78,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x6d, 0x73, 0x54, 0x65, 0x78, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x08, 0x6c,
+	0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52,
+	0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09,
+	0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x48,
+	0x01, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6c,
+	0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6c, 0x6f, 0x6e, 0x67,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0xbc, 0x04, 0x0a, 0x0a, 0x52, 0x6f, 0x61, 0x64, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x6e, 0x6f,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x4e, 0x6f, 0x12,
+	0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x7a, 0x6f, 0x6e, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x7a, 0x6f, 0x6e, 0x65, 0x49, 0x64, 0x12, 0x21, 0x0a,
+	0x0c, 0x63, 0x6f, 0x6e, 0x7a, 0x6f, 0x6e, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x7a, 0x6f, 0x6e, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x15, 0x0a, 0x06, 0x76, 0x64, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x64, 0x73, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x75, 0x70, 0x64, 0x6f, 0x77,
+	0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x75, 0x70, 0x64, 0x6f, 0x77, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x66, 0x66,
+	0x69, 0x63, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x12, 0x1f,
+	0x0a, 0x0b, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x73, 0x68, 0x61, 0x72, 0x65, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12,
+	0x19, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x61, 0x76, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x41, 0x76, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72,
+	0x61, 0x64, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x2e, 0x0a, 0x08, 0x67, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x18, 0x0d, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x6f, 0x6e, 0x4c, 0x61, 0x74, 0x52, 0x08, 0x67, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x12,
+	0x26, 0x0a, 0x0c, 0x76, 0x64, 0x73, 0x5f, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x0b, 0x76, 0x64, 0x73, 0x4c, 0x61, 0x74, 0x69,
+	0x74, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x76, 0x64, 0x73, 0x5f, 0x6c,
+	0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01,
+	0x52, 0x0c, 0x76, 0x64, 0x73, 0x4c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x88, 0x01,
+	0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x76, 0x64, 0x73, 0x5f, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75,
+	0x64, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x76, 0x64, 0x73, 0x5f, 0x6c, 0x6f, 0x6e, 0x67, 0x69,
+	0x74, 0x75, 0x64, 0x65, 0x22, 0x42, 0x0a, 0x06, 0x4c, 0x6f, 0x6e, 0x4c, 0x61, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0xfa, 0x02, 0x0a, 0x10, 0x52, 0x6f, 0x61,
+	0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x19, 0x0a,
+	0x08, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x6e, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x4e, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x27,
+	0x0a, 0x0f, 0x73, 0x6d, 0x6f, 0x6f, 0x74, 0x68, 0x5f, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x73, 0x6d, 0x6f, 0x6f, 0x74, 0x68, 0x53,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6c, 0x6f, 0x77, 0x5f,
+	0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c,
+	0x73, 0x6c, 0x6f, 0x77, 0x53, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2d, 0x0a, 0x12,
+	0x63, 0x6f, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x63, 0x6f, 0x6e, 0x67, 0x65, 0x73,
+	0x74, 0x65, 0x64, 0x53, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x61,
+	0x76, 0x67, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x61, 0x76, 0x67, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x2c, 0x0a, 0x12, 0x61, 0x76, 0x67, 0x5f,
+	0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x61, 0x76, 0x67, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x3d, 0x0a, 0x0c, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x7b, 0x0a, 0x13, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74,
+	0x65, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x44, 0x61, 0x74, 0x61, 0x12, 0x3d, 0x0a, 0x0c,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x74,
+	0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x22, 0xee, 0x01, 0x0a, 0x0f, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x54,
+	0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x6e, 0x69, 0x74, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x6e, 0x69, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x6e, 0x69, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1e, 0x0a, 0x0b, 0x65, 0x78, 0x5f, 0x64, 0x69, 0x76, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x44, 0x69, 0x76, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x42, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x5f, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x66,
+	0x66, 0x69, 0x63, 0x44, 0x61, 0x74, 0x61, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x3d, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x64, 0x22, 0x31, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74,
+	0x41, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x50, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x74,
+	0x65, 0x73, 0x74, 0x41, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x61, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69,
+	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x61,
+	0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x16, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x52,
+	0x6f, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x22, 0x54, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0d, 0x72, 0x6f, 0x61,
+	0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f,
+	0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0c, 0x72, 0x6f, 0x61, 0x64, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x22, 0x1c, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x61,
+	0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x59, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x61, 0x64, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x09, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x61, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x52, 0x09, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x69, 0x65, 0x73, 0x22,
+	0x1b, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x54, 0x72,
+	0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x57, 0x0a, 0x1a,
+	0x47, 0x65, 0x74, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x66, 0x66,
+	0x69, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x09, 0x74, 0x6f,
+	0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6c, 0x6c, 0x67,
+	0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x09, 0x74, 0x6f, 0x6c, 0x6c,
+	0x67, 0x61, 0x74, 0x65, 0x73, 0x22, 0x17, 0x0a, 0x15, 0x57, 0x61, 0x74, 0x63, 0x68, 0x41, 0x63,
+	0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x18,
+	0x0a, 0x16, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x1e, 0x0a, 0x1c, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x6f, 0x61, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x1d, 0x0a, 0x1b, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x32, 0x97, 0x06, 0x0a, 0x0b, 0x54, 0x72, 0x61, 0x66,
+	0x66, 0x69, 0x63, 0x44, 0x61, 0x74, 0x61, 0x12, 0x63, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x4c, 0x61,
+	0x74, 0x65, 0x73, 0x74, 0x41, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x2e,
+	0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x61,
+	0x74, 0x65, 0x73, 0x74, 0x41, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x41, 0x63, 0x63, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x0d,
+	0x47, 0x65, 0x74, 0x52, 0x6f, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x20, 0x2e,
+	0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f,
+	0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x21, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x52, 0x6f, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x66, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x61, 0x64, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x26, 0x2e, 0x74, 0x72, 0x61, 0x66,
+	0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x61, 0x64, 0x52, 0x6f,
+	0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x27, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x52, 0x6f, 0x61, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a, 0x12, 0x47, 0x65,
+	0x74, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x12, 0x25, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69,
+	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65,
+	0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4b, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x41, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x21, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x41, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76,
+	0x31, 0x2e, 0x41, 0x63, 0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x5a, 0x0a, 0x0f,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x22, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x6f, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x6c, 0x0a, 0x15, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x6f, 0x61, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x12, 0x28, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x6f, 0x61, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x74, 0x72,
+	0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x6f, 0x61, 0x64,
+	0x52, 0x6f, 0x75, 0x74, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x69, 0x0a, 0x14, 0x57, 0x61, 0x74, 0x63, 0x68, 0x54,
+	0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x12, 0x27,
+	0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69,
+	0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x6c, 0x6c, 0x67, 0x61, 0x74, 0x65,
+	0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30,
+	0x01, 0x42, 0x1c, 0x5a, 0x1a, 0x64, 0x61, 0x74, 0x61, 0x2d, 0x61, 0x70, 0x69, 0x2d, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x70, 0x62, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_traffic_proto_rawDescOnce sync.Once
+	file_traffic_proto_rawDescData = file_traffic_proto_rawDesc
+)
+
+func file_traffic_proto_rawDescGZIP() []byte {
+	file_traffic_proto_rawDescOnce.Do(func() {
+		file_traffic_proto_rawDescData = protoimpl.X.CompressGZIP(file_traffic_proto_rawDescData)
+	})
+	return file_traffic_proto_rawDescData
+}
+
+var file_traffic_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_traffic_proto_goTypes = []any{
+	(*Accident)(nil),                     // 0: traffic.v1.Accident
+	(*RoadStatus)(nil),                   // 1: traffic.v1.RoadStatus
+	(*LonLat)(nil),                       // 2: traffic.v1.LonLat
+	(*RoadRouteSummary)(nil),             // 3: traffic.v1.RoadRouteSummary
+	(*TollgateTrafficData)(nil),          // 4: traffic.v1.TollgateTrafficData
+	(*TollgateTraffic)(nil),              // 5: traffic.v1.TollgateTraffic
+	(*GetLatestAccidentsRequest)(nil),    // 6: traffic.v1.GetLatestAccidentsRequest
+	(*GetLatestAccidentsResponse)(nil),   // 7: traffic.v1.GetLatestAccidentsResponse
+	(*GetRoadStatusRequest)(nil),         // 8: traffic.v1.GetRoadStatusRequest
+	(*GetRoadStatusResponse)(nil),        // 9: traffic.v1.GetRoadStatusResponse
+	(*GetRoadRouteSummaryRequest)(nil),   // 10: traffic.v1.GetRoadRouteSummaryRequest
+	(*GetRoadRouteSummaryResponse)(nil),  // 11: traffic.v1.GetRoadRouteSummaryResponse
+	(*GetTollgateTrafficRequest)(nil),    // 12: traffic.v1.GetTollgateTrafficRequest
+	(*GetTollgateTrafficResponse)(nil),   // 13: traffic.v1.GetTollgateTrafficResponse
+	(*WatchAccidentsRequest)(nil),        // 14: traffic.v1.WatchAccidentsRequest
+	(*WatchRoadStatusRequest)(nil),       // 15: traffic.v1.WatchRoadStatusRequest
+	(*WatchRoadRouteSummaryRequest)(nil), // 16: traffic.v1.WatchRoadRouteSummaryRequest
+	(*WatchTollgateTrafficRequest)(nil),  // 17: traffic.v1.WatchTollgateTrafficRequest
+	(*timestamppb.Timestamp)(nil),        // 18: google.protobuf.Timestamp
+}
+var file_traffic_proto_depIdxs = []int32{
+	18, // 0: traffic.v1.Accident.created_at:type_name -> google.protobuf.Timestamp
+	18, // 1: traffic.v1.RoadStatus.collected_at:type_name -> google.protobuf.Timestamp
+	2,  // 2: traffic.v1.RoadStatus.geometry:type_name -> traffic.v1.LonLat
+	18, // 3: traffic.v1.RoadRouteSummary.collected_at:type_name -> google.protobuf.Timestamp
+	18, // 4: traffic.v1.TollgateTrafficData.collected_at:type_name -> google.protobuf.Timestamp
+	4,  // 5: traffic.v1.TollgateTraffic.traffic_data:type_name -> traffic.v1.TollgateTrafficData
+	18, // 6: traffic.v1.TollgateTraffic.last_updated:type_name -> google.protobuf.Timestamp
+	0,  // 7: traffic.v1.GetLatestAccidentsResponse.accidents:type_name -> traffic.v1.Accident
+	1,  // 8: traffic.v1.GetRoadStatusResponse.road_statuses:type_name -> traffic.v1.RoadStatus
+	3,  // 9: traffic.v1.GetRoadRouteSummaryResponse.summaries:type_name -> traffic.v1.RoadRouteSummary
+	5,  // 10: traffic.v1.GetTollgateTrafficResponse.tollgates:type_name -> traffic.v1.TollgateTraffic
+	6,  // 11: traffic.v1.TrafficData.GetLatestAccidents:input_type -> traffic.v1.GetLatestAccidentsRequest
+	8,  // 12: traffic.v1.TrafficData.GetRoadStatus:input_type -> traffic.v1.GetRoadStatusRequest
+	10, // 13: traffic.v1.TrafficData.GetRoadRouteSummary:input_type -> traffic.v1.GetRoadRouteSummaryRequest
+	12, // 14: traffic.v1.TrafficData.GetTollgateTraffic:input_type -> traffic.v1.GetTollgateTrafficRequest
+	14, // 15: traffic.v1.TrafficData.WatchAccidents:input_type -> traffic.v1.WatchAccidentsRequest
+	15, // 16: traffic.v1.TrafficData.WatchRoadStatus:input_type -> traffic.v1.WatchRoadStatusRequest
+	16, // 17: traffic.v1.TrafficData.WatchRoadRouteSummary:input_type -> traffic.v1.WatchRoadRouteSummaryRequest
+	17, // 18: traffic.v1.TrafficData.WatchTollgateTraffic:input_type -> traffic.v1.WatchTollgateTrafficRequest
+	7,  // 19: traffic.v1.TrafficData.GetLatestAccidents:output_type -> traffic.v1.GetLatestAccidentsResponse
+	9,  // 20: traffic.v1.TrafficData.GetRoadStatus:output_type -> traffic.v1.GetRoadStatusResponse
+	11, // 21: traffic.v1.TrafficData.GetRoadRouteSummary:output_type -> traffic.v1.GetRoadRouteSummaryResponse
+	13, // 22: traffic.v1.TrafficData.GetTollgateTraffic:output_type -> traffic.v1.GetTollgateTrafficResponse
+	0,  // 23: traffic.v1.TrafficData.WatchAccidents:output_type -> traffic.v1.Accident
+	9,  // 24: traffic.v1.TrafficData.WatchRoadStatus:output_type -> traffic.v1.GetRoadStatusResponse
+	11, // 25: traffic.v1.TrafficData.WatchRoadRouteSummary:output_type -> traffic.v1.GetRoadRouteSummaryResponse
+	13, // 26: traffic.v1.TrafficData.WatchTollgateTraffic:output_type -> traffic.v1.GetTollgateTrafficResponse
+	19, // [19:27] is the sub-list for method output_type
+	11, // [11:19] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_traffic_proto_init() }
+func file_traffic_proto_init() {
+	if File_traffic_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_traffic_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Accident); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*RoadStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*LonLat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*RoadRouteSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*TollgateTrafficData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*TollgateTraffic); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*GetLatestAccidentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*GetLatestAccidentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*GetRoadStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*GetRoadStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*GetRoadRouteSummaryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*GetRoadRouteSummaryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*GetTollgateTrafficRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*GetTollgateTrafficResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchAccidentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchRoadStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchRoadRouteSummaryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_traffic_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchTollgateTrafficRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_traffic_proto_msgTypes[0].OneofWrappers = []any{}
+	file_traffic_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_traffic_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_traffic_proto_goTypes,
+		DependencyIndexes: file_traffic_proto_depIdxs,
+		MessageInfos:      file_traffic_proto_msgTypes,
+	}.Build()
+	File_traffic_proto = out.File
+	file_traffic_proto_rawDesc = nil
+	file_traffic_proto_goTypes = nil
+	file_traffic_proto_depIdxs = nil
+}
@@ -0,0 +1,419 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: traffic.proto
+
+// Traffic data API for internal Go services. Mirrors the /api/v1 JSON handlers of
+// data-api-service; the Watch RPCs stream updates as new collections land.
+
+package trafficpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TrafficData_GetLatestAccidents_FullMethodName    = "/traffic.v1.TrafficData/GetLatestAccidents"
+	TrafficData_GetRoadStatus_FullMethodName         = "/traffic.v1.TrafficData/GetRoadStatus"
+	TrafficData_GetRoadRouteSummary_FullMethodName   = "/traffic.v1.TrafficData/GetRoadRouteSummary"
+	TrafficData_GetTollgateTraffic_FullMethodName    = "/traffic.v1.TrafficData/GetTollgateTraffic"
+	TrafficData_WatchAccidents_FullMethodName        = "/traffic.v1.TrafficData/WatchAccidents"
+	TrafficData_WatchRoadStatus_FullMethodName       = "/traffic.v1.TrafficData/WatchRoadStatus"
+	TrafficData_WatchRoadRouteSummary_FullMethodName = "/traffic.v1.TrafficData/WatchRoadRouteSummary"
+	TrafficData_WatchTollgateTraffic_FullMethodName  = "/traffic.v1.TrafficData/WatchTollgateTraffic"
+)
+
+// TrafficDataClient is the client API for TrafficData service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TrafficDataClient interface {
+	// Accidents of the last 24 hours, newest first (GET /api/v1/accidents/latest)
+	GetLatestAccidents(ctx context.Context, in *GetLatestAccidentsRequest, opts ...grpc.CallOption) (*GetLatestAccidentsResponse, error)
+	// Latest traffic status per route and conzone (GET /api/v1/road/status)
+	GetRoadStatus(ctx context.Context, in *GetRoadStatusRequest, opts ...grpc.CallOption) (*GetRoadStatusResponse, error)
+	// Latest congestion summary per route (GET /api/v1/road/summary)
+	GetRoadRouteSummary(ctx context.Context, in *GetRoadRouteSummaryRequest, opts ...grpc.CallOption) (*GetRoadRouteSummaryResponse, error)
+	// Tollgate traffic of the 3 hours up to the latest collection (GET /api/v1/tollgate/traffic)
+	GetTollgateTraffic(ctx context.Context, in *GetTollgateTrafficRequest, opts ...grpc.CallOption) (*GetTollgateTrafficResponse, error)
+	// Streams accidents of the last 24 hours, then each accident as it is recorded
+	WatchAccidents(ctx context.Context, in *WatchAccidentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Accident], error)
+	// Streams the current road status, then a new snapshot after every collection
+	WatchRoadStatus(ctx context.Context, in *WatchRoadStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetRoadStatusResponse], error)
+	// Streams the current route summaries, then a new snapshot after every aggregation
+	WatchRoadRouteSummary(ctx context.Context, in *WatchRoadRouteSummaryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetRoadRouteSummaryResponse], error)
+	// Streams the current tollgate traffic, then a new snapshot after every collection
+	WatchTollgateTraffic(ctx context.Context, in *WatchTollgateTrafficRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetTollgateTrafficResponse], error)
+}
+
+type trafficDataClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrafficDataClient(cc grpc.ClientConnInterface) TrafficDataClient {
+	return &trafficDataClient{cc}
+}
+
+func (c *trafficDataClient) GetLatestAccidents(ctx context.Context, in *GetLatestAccidentsRequest, opts ...grpc.CallOption) (*GetLatestAccidentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLatestAccidentsResponse)
+	err := c.cc.Invoke(ctx, TrafficData_GetLatestAccidents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trafficDataClient) GetRoadStatus(ctx context.Context, in *GetRoadStatusRequest, opts ...grpc.CallOption) (*GetRoadStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoadStatusResponse)
+	err := c.cc.Invoke(ctx, TrafficData_GetRoadStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trafficDataClient) GetRoadRouteSummary(ctx context.Context, in *GetRoadRouteSummaryRequest, opts ...grpc.CallOption) (*GetRoadRouteSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoadRouteSummaryResponse)
+	err := c.cc.Invoke(ctx, TrafficData_GetRoadRouteSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trafficDataClient) GetTollgateTraffic(ctx context.Context, in *GetTollgateTrafficRequest, opts ...grpc.CallOption) (*GetTollgateTrafficResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTollgateTrafficResponse)
+	err := c.cc.Invoke(ctx, TrafficData_GetTollgateTraffic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trafficDataClient) WatchAccidents(ctx context.Context, in *WatchAccidentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Accident], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrafficData_ServiceDesc.Streams[0], TrafficData_WatchAccidents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAccidentsRequest, Accident]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrafficData_WatchAccidentsClient = grpc.ServerStreamingClient[Accident]
+
+func (c *trafficDataClient) WatchRoadStatus(ctx context.Context, in *WatchRoadStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetRoadStatusResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrafficData_ServiceDesc.Streams[1], TrafficData_WatchRoadStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRoadStatusRequest, GetRoadStatusResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrafficData_WatchRoadStatusClient = grpc.ServerStreamingClient[GetRoadStatusResponse]
+
+func (c *trafficDataClient) WatchRoadRouteSummary(ctx context.Context, in *WatchRoadRouteSummaryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetRoadRouteSummaryResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrafficData_ServiceDesc.Streams[2], TrafficData_WatchRoadRouteSummary_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRoadRouteSummaryRequest, GetRoadRouteSummaryResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrafficData_WatchRoadRouteSummaryClient = grpc.ServerStreamingClient[GetRoadRouteSummaryResponse]
+
+func (c *trafficDataClient) WatchTollgateTraffic(ctx context.Context, in *WatchTollgateTrafficRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetTollgateTrafficResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrafficData_ServiceDesc.Streams[3], TrafficData_WatchTollgateTraffic_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTollgateTrafficRequest, GetTollgateTrafficResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrafficData_WatchTollgateTrafficClient = grpc.ServerStreamingClient[GetTollgateTrafficResponse]
+
+// TrafficDataServer is the server API for TrafficData service.
+// All implementations must embed UnimplementedTrafficDataServer
+// for forward compatibility.
+type TrafficDataServer interface {
+	// Accidents of the last 24 hours, newest first (GET /api/v1/accidents/latest)
+	GetLatestAccidents(context.Context, *GetLatestAccidentsRequest) (*GetLatestAccidentsResponse, error)
+	// Latest traffic status per route and conzone (GET /api/v1/road/status)
+	GetRoadStatus(context.Context, *GetRoadStatusRequest) (*GetRoadStatusResponse, error)
+	// Latest congestion summary per route (GET /api/v1/road/summary)
+	GetRoadRouteSummary(context.Context, *GetRoadRouteSummaryRequest) (*GetRoadRouteSummaryResponse, error)
+	// Tollgate traffic of the 3 hours up to the latest collection (GET /api/v1/tollgate/traffic)
+	GetTollgateTraffic(context.Context, *GetTollgateTrafficRequest) (*GetTollgateTrafficResponse, error)
+	// Streams accidents of the last 24 hours, then each accident as it is recorded
+	WatchAccidents(*WatchAccidentsRequest, grpc.ServerStreamingServer[Accident]) error
+	// Streams the current road status, then a new snapshot after every collection
+	WatchRoadStatus(*WatchRoadStatusRequest, grpc.ServerStreamingServer[GetRoadStatusResponse]) error
+	// Streams the current route summaries, then a new snapshot after every aggregation
+	WatchRoadRouteSummary(*WatchRoadRouteSummaryRequest, grpc.ServerStreamingServer[GetRoadRouteSummaryResponse]) error
+	// Streams the current tollgate traffic, then a new snapshot after every collection
+	WatchTollgateTraffic(*WatchTollgateTrafficRequest, grpc.ServerStreamingServer[GetTollgateTrafficResponse]) error
+	mustEmbedUnimplementedTrafficDataServer()
+}
+
+// UnimplementedTrafficDataServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTrafficDataServer struct{}
+
+func (UnimplementedTrafficDataServer) GetLatestAccidents(context.Context, *GetLatestAccidentsRequest) (*GetLatestAccidentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestAccidents not implemented")
+}
+func (UnimplementedTrafficDataServer) GetRoadStatus(context.Context, *GetRoadStatusRequest) (*GetRoadStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoadStatus not implemented")
+}
+func (UnimplementedTrafficDataServer) GetRoadRouteSummary(context.Context, *GetRoadRouteSummaryRequest) (*GetRoadRouteSummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoadRouteSummary not implemented")
+}
+func (UnimplementedTrafficDataServer) GetTollgateTraffic(context.Context, *GetTollgateTrafficRequest) (*GetTollgateTrafficResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTollgateTraffic not implemented")
+}
+func (UnimplementedTrafficDataServer) WatchAccidents(*WatchAccidentsRequest, grpc.ServerStreamingServer[Accident]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAccidents not implemented")
+}
+func (UnimplementedTrafficDataServer) WatchRoadStatus(*WatchRoadStatusRequest, grpc.ServerStreamingServer[GetRoadStatusResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRoadStatus not implemented")
+}
+func (UnimplementedTrafficDataServer) WatchRoadRouteSummary(*WatchRoadRouteSummaryRequest, grpc.ServerStreamingServer[GetRoadRouteSummaryResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRoadRouteSummary not implemented")
+}
+func (UnimplementedTrafficDataServer) WatchTollgateTraffic(*WatchTollgateTrafficRequest, grpc.ServerStreamingServer[GetTollgateTrafficResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTollgateTraffic not implemented")
+}
+func (UnimplementedTrafficDataServer) mustEmbedUnimplementedTrafficDataServer() {}
+func (UnimplementedTrafficDataServer) testEmbeddedByValue()                     {}
+
+// UnsafeTrafficDataServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrafficDataServer will
+// result in compilation errors.
+type UnsafeTrafficDataServer interface {
+	mustEmbedUnimplementedTrafficDataServer()
+}
+
+func RegisterTrafficDataServer(s grpc.ServiceRegistrar, srv TrafficDataServer) {
+	// If the following call pancis, it indicates UnimplementedTrafficDataServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TrafficData_ServiceDesc, srv)
+}
+
+func _TrafficData_GetLatestAccidents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestAccidentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrafficDataServer).GetLatestAccidents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrafficData_GetLatestAccidents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrafficDataServer).GetLatestAccidents(ctx, req.(*GetLatestAccidentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrafficData_GetRoadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoadStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrafficDataServer).GetRoadStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrafficData_GetRoadStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrafficDataServer).GetRoadStatus(ctx, req.(*GetRoadStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrafficData_GetRoadRouteSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoadRouteSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrafficDataServer).GetRoadRouteSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrafficData_GetRoadRouteSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrafficDataServer).GetRoadRouteSummary(ctx, req.(*GetRoadRouteSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrafficData_GetTollgateTraffic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTollgateTrafficRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrafficDataServer).GetTollgateTraffic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrafficData_GetTollgateTraffic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrafficDataServer).GetTollgateTraffic(ctx, req.(*GetTollgateTrafficRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrafficData_WatchAccidents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAccidentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrafficDataServer).WatchAccidents(m, &grpc.GenericServerStream[WatchAccidentsRequest, Accident]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrafficData_WatchAccidentsServer = grpc.ServerStreamingServer[Accident]
+
+func _TrafficData_WatchRoadStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRoadStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrafficDataServer).WatchRoadStatus(m, &grpc.GenericServerStream[WatchRoadStatusRequest, GetRoadStatusResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrafficData_WatchRoadStatusServer = grpc.ServerStreamingServer[GetRoadStatusResponse]
+
+func _TrafficData_WatchRoadRouteSummary_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRoadRouteSummaryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrafficDataServer).WatchRoadRouteSummary(m, &grpc.GenericServerStream[WatchRoadRouteSummaryRequest, GetRoadRouteSummaryResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrafficData_WatchRoadRouteSummaryServer = grpc.ServerStreamingServer[GetRoadRouteSummaryResponse]
+
+func _TrafficData_WatchTollgateTraffic_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTollgateTrafficRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrafficDataServer).WatchTollgateTraffic(m, &grpc.GenericServerStream[WatchTollgateTrafficRequest, GetTollgateTrafficResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrafficData_WatchTollgateTrafficServer = grpc.ServerStreamingServer[GetTollgateTrafficResponse]
+
+// TrafficData_ServiceDesc is the grpc.ServiceDesc for TrafficData service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TrafficData_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "traffic.v1.TrafficData",
+	HandlerType: (*TrafficDataServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLatestAccidents",
+			Handler:    _TrafficData_GetLatestAccidents_Handler,
+		},
+		{
+			MethodName: "GetRoadStatus",
+			Handler:    _TrafficData_GetRoadStatus_Handler,
+		},
+		{
+			MethodName: "GetRoadRouteSummary",
+			Handler:    _TrafficData_GetRoadRouteSummary_Handler,
+		},
+		{
+			MethodName: "GetTollgateTraffic",
+			Handler:    _TrafficData_GetTollgateTraffic_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAccidents",
+			Handler:       _TrafficData_WatchAccidents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchRoadStatus",
+			Handler:       _TrafficData_WatchRoadStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchRoadRouteSummary",
+			Handler:       _TrafficData_WatchRoadRouteSummary_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchTollgateTraffic",
+			Handler:       _TrafficData_WatchTollgateTraffic_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "traffic.proto",
+}
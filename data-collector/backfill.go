@@ -0,0 +1,425 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gapTable describes a history table collected on a fixed upstream cadence
+type gapTable struct {
+	Name         string
+	Cadence      time.Duration
+	Backfillable bool // upstream API accepts a past interval
+}
+
+var gapTables = map[string]gapTable{
+	"tollgate": {
+		Name:         "tollgate_traffic_history",
+		Cadence:      15 * time.Minute,
+		Backfillable: true, // trafficIc accepts sumDate/sumTm
+	},
+	"road_status": {
+		Name:    "road_traffic_status",
+		Cadence: 5 * time.Minute,
+		// trafficAmountByRealtime only serves the current snapshot
+		Backfillable: false,
+	},
+}
+
+// publishLag is how long after an interval ends the upstream is expected to have published it
+const publishLag = 30 * time.Minute
+
+// Gap is a run of consecutive missing intervals in one history table
+type Gap struct {
+	Table        string    `json:"table"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"` // last missing interval (inclusive)
+	Missing      int       `json:"missing"`
+	Backfillable bool      `json:"backfillable"`
+}
+
+// BackfillResult summarises a backfill run for one table
+type BackfillResult struct {
+	Table   string `json:"table"`
+	Missing int    `json:"missing"`
+	Filled  int    `json:"filled"`
+	Failed  int    `json:"failed"`
+	Saved   int    `json:"records_saved"`
+	Skipped string `json:"skipped,omitempty"`
+}
+
+func kstLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		loc = time.FixedZone("KST", 9*60*60)
+	}
+	return loc
+}
+
+// missingIntervals returns every cadence-aligned interval in [from, to] with no rows in t
+func (c *Collector) missingIntervals(ctx context.Context, t gapTable, from, to time.Time) ([]time.Time, error) {
+	loc := kstLocation()
+	from = from.In(loc).Truncate(t.Cadence)
+	to = to.In(loc).Truncate(t.Cadence)
+
+	rows, err := c.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT DISTINCT collected_at FROM %s WHERE collected_at BETWEEN ? AND ?`, t.Name),
+		from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s intervals: %w", t.Name, err)
+	}
+	defer rows.Close()
+
+	present := make(map[int64]bool)
+	for rows.Next() {
+		var at time.Time
+		if err := rows.Scan(&at); err != nil {
+			return nil, fmt.Errorf("failed to scan %s interval: %w", t.Name, err)
+		}
+		present[at.Truncate(t.Cadence).Unix()] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var missing []time.Time
+	for at := from; !at.After(to); at = at.Add(t.Cadence) {
+		if !present[at.Unix()] {
+			missing = append(missing, at)
+		}
+	}
+	return missing, nil
+}
+
+// DetectGaps groups missing intervals of the given tables since the given time into runs
+func (c *Collector) DetectGaps(ctx context.Context, tables []string, since time.Time) ([]Gap, error) {
+	to := time.Now().Add(-publishLag)
+
+	var gaps []Gap
+	for _, key := range tables {
+		t := gapTables[key]
+		missing, err := c.missingIntervals(ctx, t, since, to)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, at := range missing {
+			if i > 0 && at.Sub(missing[i-1]) == t.Cadence {
+				gaps[len(gaps)-1].To = at
+				gaps[len(gaps)-1].Missing++
+				continue
+			}
+			gaps = append(gaps, Gap{Table: t.Name, From: at, To: at, Missing: 1, Backfillable: t.Backfillable})
+		}
+	}
+	return gaps, nil
+}
+
+// backfillTollgateInterval re-requests every page of one past tollgate interval
+func (c *Collector) backfillTollgateInterval(ctx context.Context, at time.Time) (int, error) {
+	sumDate, sumTm := at.Format("20060102"), at.Format("1504")
+
+	fetch := func(ctx context.Context, pageNo int) (*TollgateAPIResponse, error) {
+		page, err := c.fetchTollgateTrafficAt(ctx, pageNo, sumDate, sumTm)
+		if err == nil && page.Code != "SUCCESS" {
+			return nil, fmt.Errorf("API error: %s - %s", page.Code, page.Message)
+		}
+		return page, err
+	}
+
+	firstPage, _, err := fetchWithRetry(ctx, c.config.TollgatePagePool, 1, fetch)
+	if err != nil {
+		return 0, err
+	}
+
+	// Guard against the upstream ignoring the interval parameters and returning the latest data
+	for _, t := range firstPage.TrafficIc {
+		if t.SumDate != sumDate || t.SumTm != sumTm {
+			return 0, fmt.Errorf("upstream returned interval %s %s instead of %s %s",
+				t.SumDate, t.SumTm, sumDate, sumTm)
+		}
+	}
+
+	var mu sync.Mutex
+	saved, firstErr := c.saveTollgatePage(ctx, 1, firstPage)
+
+	pages := make([]int, 0, firstPage.PageSize)
+	for pageNo := 2; pageNo <= firstPage.PageSize; pageNo++ {
+		pages = append(pages, pageNo)
+	}
+
+	report := fetchPages(ctx, c.config.TollgatePagePool, pages, fetch,
+		func(ctx context.Context, pageNo int, pageData *TollgateAPIResponse) error {
+			n, err := c.saveTollgatePage(ctx, pageNo, pageData)
+			mu.Lock()
+			saved += n
+			mu.Unlock()
+			return err
+		})
+
+	if firstErr != nil {
+		report.Failed++
+		report.FailedPages = append([]int{1}, report.FailedPages...)
+	}
+	if report.Failed > 0 {
+		return saved, fmt.Errorf("incomplete: %s", report)
+	}
+	return saved, nil
+}
+
+// errLostLeadership stops a backfill once another replica has taken over the lease
+var errLostLeadership = errors.New("leadership lost, backfill stopped")
+
+// Backfill re-requests missing intervals since the given time from the upstream API.
+// Tables whose API has no history access are reported as skipped. leading, when set, is
+// checked before every interval so a backfill started by a leader stops once it is not.
+func (c *Collector) Backfill(ctx context.Context, tables []string, since time.Time, leading func() bool) ([]BackfillResult, error) {
+	to := time.Now().Add(-publishLag)
+
+	var results []BackfillResult
+	for _, key := range tables {
+		t := gapTables[key]
+		missing, err := c.missingIntervals(ctx, t, since, to)
+		if err != nil {
+			return results, err
+		}
+
+		result := BackfillResult{Table: t.Name, Missing: len(missing)}
+		if !t.Backfillable {
+			if len(missing) > 0 {
+				result.Skipped = "upstream API has no historical access"
+				componentLogger("backfill").Warn("missing intervals cannot be backfilled (realtime-only API)",
+					"table", t.Name, "missing", len(missing))
+			}
+			results = append(results, result)
+			continue
+		}
+
+		for _, at := range missing {
+			if ctx.Err() != nil {
+				return append(results, result), ctx.Err()
+			}
+			if leading != nil && !leading() {
+				return append(results, result), errLostLeadership
+			}
+
+			saved, err := c.backfillTollgateInterval(ctx, at)
+			result.Saved += saved
+			if err != nil {
+				result.Failed++
+				componentLogger("backfill").Error("interval backfill failed",
+					"table", t.Name, "interval", at.Format("2006-01-02 15:04"), "error", err)
+				continue
+			}
+			result.Filled++
+			componentLogger("backfill").Info("interval filled",
+				"table", t.Name, "interval", at.Format("2006-01-02 15:04"), "records", saved)
+		}
+
+		componentLogger("backfill").Info("backfill finished", "table", t.Name, "missing", result.Missing,
+			"filled", result.Filled, "failed", result.Failed, "records_saved", result.Saved)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// parseGapTables maps the table parameter ("tollgate", "road_status" or "all")
+func parseGapTables(name string) ([]string, error) {
+	switch name {
+	case "", "all":
+		return []string{"tollgate", "road_status"}, nil
+	case "tollgate", "road_status":
+		return []string{name}, nil
+	}
+	return nil, fmt.Errorf("unknown table %q (tollgate, road_status or all)", name)
+}
+
+// parseGapWindow reads since as a duration back from now (default 24h)
+func parseGapWindow(since string) (time.Time, error) {
+	window := 24 * time.Hour
+	if since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil || d <= 0 {
+			return time.Time{}, fmt.Errorf("invalid since %q", since)
+		}
+		window = d
+	}
+	return time.Now().Add(-window), nil
+}
+
+// backfillRunning guards against overlapping backfills started over HTTP
+var backfillRunning sync.Mutex
+
+// gapsHandler serves GET /gaps?table=all&since=24h
+func (c *Collector) gapsHandler(w http.ResponseWriter, r *http.Request) {
+	tables, err := parseGapTables(r.URL.Query().Get("table"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	since, err := parseGapWindow(r.URL.Query().Get("since"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	gaps, err := c.DetectGaps(r.Context(), tables, since)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if gaps == nil {
+		gaps = []Gap{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"since": since,
+		"gaps":  gaps,
+	})
+}
+
+// leadingTerm returns a check that stays true while this instance holds the lease of the
+// term it holds now; a lease lost and won back is a new term with a new fencing token
+func (c *Collector) leadingTerm() func() bool {
+	term := c.elector.Token()
+	return func() bool {
+		return c.elector.IsLeader() && c.elector.Token() == term
+	}
+}
+
+// backfillHandler serves POST /backfill?table=all&since=24h with an
+// "Authorization: Bearer <BACKFILL_TOKEN>" header. Only the lease holder runs a backfill, so
+// the in-process lock is enough to keep replicas from overlapping; standbys answer 409.
+// The run continues in the background under ctx, stops when the collector shuts down or
+// loses the lease, and is tracked in c.backfills so shutdown can wait for it.
+func (c *Collector) backfillHandler(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.serveBackfill(ctx, w, r)
+	}
+}
+
+func (c *Collector) serveBackfill(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if c.config.BackfillToken == "" {
+		http.Error(w, "backfill over HTTP is disabled; set BACKFILL_TOKEN", http.StatusForbidden)
+		return
+	}
+	if !validBearer(r, c.config.BackfillToken) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !c.elector.IsLeader() {
+		http.Error(w, "not the leader; send the request to the leader replica (see /leader)", http.StatusConflict)
+		return
+	}
+
+	tables, err := parseGapTables(r.URL.Query().Get("table"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	since, err := parseGapWindow(r.URL.Query().Get("since"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !backfillRunning.TryLock() {
+		http.Error(w, "backfill already running", http.StatusConflict)
+		return
+	}
+
+	leading := c.leadingTerm()
+	c.backfills.Add(1)
+	go func() {
+		defer c.backfills.Done()
+		defer backfillRunning.Unlock()
+		if _, err := c.Backfill(ctx, tables, since, leading); err != nil {
+			componentLogger("backfill").Error("backfill failed", "error", err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "started",
+		"since":  since,
+		"tables": tables,
+	})
+}
+
+// validBearer checks the request's bearer token in constant time
+func validBearer(r *http.Request, token string) bool {
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(auth[len(prefix):]), []byte(token)) == 1
+}
+
+// runBackfillCommand implements: data-collector backfill [-table all] [-since 24h] [-dry-run]
+func runBackfillCommand(config Config, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	table := fs.String("table", "all", "tollgate, road_status or all")
+	since := fs.String("since", "24h", "look back this far from now")
+	dryRun := fs.Bool("dry-run", false, "only report gaps")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tables, err := parseGapTables(*table)
+	if err != nil {
+		return err
+	}
+	from, err := parseGapWindow(*since)
+	if err != nil {
+		return err
+	}
+
+	collector, err := NewCollector(config)
+	if err != nil {
+		return err
+	}
+	defer collector.Close()
+
+	ctx := context.Background()
+
+	gaps, err := collector.DetectGaps(ctx, tables, from)
+	if err != nil {
+		return err
+	}
+	for _, g := range gaps {
+		fmt.Fprintf(os.Stdout, "%-26s %s ~ %s  %4d missing  backfillable=%v\n",
+			g.Table, g.From.Format("2006-01-02 15:04"), g.To.Format("2006-01-02 15:04"), g.Missing, g.Backfillable)
+	}
+	if len(gaps) == 0 {
+		fmt.Fprintln(os.Stdout, "No gaps found")
+	}
+
+	if *dryRun || len(gaps) == 0 {
+		return nil
+	}
+
+	// Run by an operator outside the election, so there is no lease to lose
+	results, err := collector.Backfill(ctx, tables, from, nil)
+	for _, r := range results {
+		fmt.Fprintf(os.Stdout, "%-26s missing=%d filled=%d failed=%d saved=%d %s\n",
+			r.Table, r.Missing, r.Filled, r.Failed, r.Saved, r.Skipped)
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseGapTables(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "", want: []string{"tollgate", "road_status"}},
+		{name: "all", want: []string{"tollgate", "road_status"}},
+		{name: "tollgate", want: []string{"tollgate"}},
+		{name: "road_status", want: []string{"road_status"}},
+		{name: "tollgate_traffic_history", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseGapTables(tt.name)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseGapTables(%q) = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseGapWindow(t *testing.T) {
+	tests := []struct {
+		since   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{since: "", want: 24 * time.Hour},
+		{since: "90m", want: 90 * time.Minute},
+		{since: "0s", wantErr: true},
+		{since: "-1h", wantErr: true},
+		{since: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseGapWindow(tt.since)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseGapWindow(%q) error = %v, want error %v", tt.since, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if window := time.Since(got); window < tt.want || window > tt.want+time.Minute {
+			t.Errorf("parseGapWindow(%q) = %v ago, want %v", tt.since, window, tt.want)
+		}
+	}
+}
+
+func TestValidBearer(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{header: "Bearer s3cret", want: true},
+		{header: "bearer s3cret", want: true},
+		{header: "Bearer s3cret2", want: false},
+		{header: "Bearer ", want: false},
+		{header: "Basic s3cret", want: false},
+		{header: "s3cret", want: false},
+		{header: "", want: false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/backfill", nil)
+		if tt.header != "" {
+			r.Header.Set("Authorization", tt.header)
+		}
+		if got := validBearer(r, "s3cret"); got != tt.want {
+			t.Errorf("validBearer(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+// Every case is rejected before a backfill could start, so no database is needed
+func TestServeBackfillRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		header string
+		query  string
+		leader bool
+		want   int
+	}{
+		{name: "GET", token: "s3cret", method: http.MethodGet, header: "Bearer s3cret", want: http.StatusMethodNotAllowed},
+		{name: "disabled", method: http.MethodPost, header: "Bearer ", want: http.StatusForbidden},
+		{name: "no token", token: "s3cret", method: http.MethodPost, want: http.StatusUnauthorized},
+		{name: "wrong token", token: "s3cret", method: http.MethodPost, header: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "standby", token: "s3cret", method: http.MethodPost, header: "Bearer s3cret", want: http.StatusConflict},
+		{name: "bad table", token: "s3cret", method: http.MethodPost, header: "Bearer s3cret", query: "?table=x", leader: true, want: http.StatusBadRequest},
+		{name: "bad since", token: "s3cret", method: http.MethodPost, header: "Bearer s3cret", query: "?since=x", leader: true, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elector := NewLeaderElector(LeaderConfig{}, nil)
+			elector.leading = tt.leader
+			c := &Collector{config: Config{BackfillToken: tt.token}, elector: elector}
+
+			r := httptest.NewRequest(tt.method, "/backfill"+tt.query, nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			c.backfillHandler(context.Background())(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Error("401 without a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestLeadingTerm(t *testing.T) {
+	tests := []struct {
+		name    string
+		leading bool
+		token   int64 // fencing token once the backfill is running
+		want    bool
+	}{
+		{name: "same term", leading: true, token: 7, want: true},
+		{name: "lease lost", leading: false, token: 7, want: false},
+		{name: "lease won back", leading: true, token: 9, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elector := NewLeaderElector(LeaderConfig{}, nil)
+			elector.leading, elector.token = true, 7
+			c := &Collector{elector: elector}
+
+			leading := c.leadingTerm()
+			elector.leading, elector.token = tt.leading, tt.token
+
+			if got := leading(); got != tt.want {
+				t.Errorf("leading() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Election disabled: the sole collector leads with token 0 for its whole life
+func TestLeadingTermUnfenced(t *testing.T) {
+	elector := NewLeaderElector(LeaderConfig{}, nil)
+	elector.leading = true
+	c := &Collector{elector: elector}
+
+	if !c.leadingTerm()() {
+		t.Error("leading() = false for the sole collector")
+	}
+}
+
+// The backfill started over HTTP is tracked so shutdown can wait for it before closing the DB
+func TestServeBackfillTracksRun(t *testing.T) {
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/traffic?timeout=100ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	elector := NewLeaderElector(LeaderConfig{}, nil)
+	elector.leading = true
+	c := &Collector{config: Config{BackfillToken: "s3cret"}, elector: elector, db: db}
+
+	r := httptest.NewRequest(http.MethodPost, "/backfill?table=tollgate&since=1h", nil)
+	r.Header.Set("Authorization", "Bearer s3cret")
+	w := httptest.NewRecorder()
+	c.backfillHandler(context.Background())(w, r)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusAccepted, w.Body)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.backfills.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("backfill was not tracked to completion")
+	}
+	if !backfillRunning.TryLock() {
+		t.Fatal("backfill lock still held after the run finished")
+	}
+	backfillRunning.Unlock()
+}
@@ -0,0 +1,320 @@
+// This is the canonical copy of the quota governor. openapi-collector/governor.go is a
+// byte-for-byte copy kept in sync by hand (the services are separate modules). Make
+// changes here first, then copy the file over.
+
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrQuotaExhausted is returned by Governor.Wait when the shared daily budget is used up
+var ErrQuotaExhausted = errors.New("daily OpenAPI quota exhausted")
+
+// governorScript is an atomic token bucket plus daily quota counter.
+// KEYS[1] bucket hash, KEYS[2] daily counter
+// ARGV rate (tokens/s), burst, now (ms), daily limit (0 = unlimited), counter TTL (s)
+// Returns {1, used} when allowed, {0, waitMs} when throttled, {-1, used} when over quota.
+var governorScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local limit = tonumber(ARGV[4])
+local ttl = tonumber(ARGV[5])
+
+local used = tonumber(redis.call('GET', KEYS[2]) or '0')
+if limit > 0 and used >= limit then
+	return {-1, used}
+end
+
+local b = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(b[1])
+local ts = tonumber(b[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+if tokens < 1 then
+	redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+	redis.call('PEXPIRE', KEYS[1], 60000)
+	return {0, math.ceil((1 - tokens) * 1000 / rate)}
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens - 1, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], 60000)
+used = redis.call('INCR', KEYS[2])
+if used == 1 then
+	redis.call('EXPIRE', KEYS[2], ttl)
+end
+return {1, used}
+`)
+
+// GovernorConfig configures the shared OpenAPI rate limiter
+type GovernorConfig struct {
+	Rate       float64 // requests per second per API key, across all collectors
+	Burst      int     // bucket size
+	DailyQuota int64   // requests per API key per KST day (0 = unlimited)
+	KeyPrefix  string  // Redis key prefix shared by all collectors
+}
+
+// endpointStats counts governor decisions per upstream endpoint
+type endpointStats struct {
+	allowed   int64
+	throttled int64
+	rejected  int64
+}
+
+// localBudget is the in-process fallback used when Redis is not reachable
+type localBudget struct {
+	day    string
+	used   int64
+	tokens float64
+	ts     time.Time
+}
+
+// Governor rate-limits and budgets calls to data.ex.co.kr. Buckets and daily counters
+// live in Redis per API key, so every collector using the same key shares one budget.
+// If Redis is unavailable it fails open to a process-local bucket and counter.
+type Governor struct {
+	config GovernorConfig
+	rdb    *redis.Client
+
+	mu     sync.Mutex
+	stats  map[string]*endpointStats
+	used   map[string]int64 // keyID -> last known usage today
+	local  map[string]*localBudget
+	keyIDs map[string]bool // API keys seen so far (for metrics)
+}
+
+func NewGovernor(config GovernorConfig, rdb *redis.Client) *Governor {
+	if config.Rate <= 0 {
+		config.Rate = 5
+	}
+	if config.Burst <= 0 {
+		config.Burst = 1
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "openapi:governor"
+	}
+
+	return &Governor{
+		config: config,
+		rdb:    rdb,
+		stats:  make(map[string]*endpointStats),
+		used:   make(map[string]int64),
+		local:  make(map[string]*localBudget),
+		keyIDs: make(map[string]bool),
+	}
+}
+
+// apiKeyID returns a short, non-reversible identifier for an API key
+func apiKeyID(apiKey string) string {
+	sum := sha1.Sum([]byte(apiKey))
+	return hex.EncodeToString(sum[:4])
+}
+
+// quotaDay returns the current quota day in KST (the upstream resets on the Korean calendar)
+func quotaDay(now time.Time) string {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		loc = time.FixedZone("KST", 9*60*60)
+	}
+	return now.In(loc).Format("20060102")
+}
+
+func (g *Governor) bucketKey(keyID string) string {
+	return fmt.Sprintf("%s:%s:bucket", g.config.KeyPrefix, keyID)
+}
+
+func (g *Governor) quotaKey(keyID, day string) string {
+	return fmt.Sprintf("%s:%s:quota:%s", g.config.KeyPrefix, keyID, day)
+}
+
+func (g *Governor) record(endpoint string, fn func(s *endpointStats)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.stats[endpoint]
+	if !ok {
+		s = &endpointStats{}
+		g.stats[endpoint] = s
+	}
+	fn(s)
+}
+
+// acquire tries to take one token for keyID; it returns the wait before retrying when throttled
+func (g *Governor) acquire(ctx context.Context, keyID string, now time.Time) (bool, time.Duration, error) {
+	day := quotaDay(now)
+
+	if g.rdb != nil {
+		res, err := governorScript.Run(ctx, g.rdb,
+			[]string{g.bucketKey(keyID), g.quotaKey(keyID, day)},
+			g.config.Rate, g.config.Burst, now.UnixMilli(), g.config.DailyQuota, int((48 * time.Hour).Seconds()),
+		).Int64Slice()
+		if err == nil && len(res) == 2 {
+			switch res[0] {
+			case 1:
+				g.mu.Lock()
+				g.used[keyID] = res[1]
+				g.mu.Unlock()
+				return true, 0, nil
+			case -1:
+				g.mu.Lock()
+				g.used[keyID] = res[1]
+				g.mu.Unlock()
+				return false, 0, ErrQuotaExhausted
+			default:
+				return false, time.Duration(res[1]) * time.Millisecond, nil
+			}
+		}
+		if ctx.Err() != nil {
+			return false, 0, ctx.Err()
+		}
+		if err == nil {
+			err = fmt.Errorf("unexpected script result %v", res)
+		}
+		componentLogger("governor").Warn("Redis unavailable, using local limiter", "error", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b, ok := g.local[keyID]
+	if !ok {
+		b = &localBudget{tokens: float64(g.config.Burst), ts: now}
+		g.local[keyID] = b
+	}
+	if b.day != day {
+		b.day = day
+		b.used = 0
+	}
+	if g.config.DailyQuota > 0 && b.used >= g.config.DailyQuota {
+		return false, 0, ErrQuotaExhausted
+	}
+
+	elapsed := now.Sub(b.ts).Seconds()
+	b.ts = now
+	b.tokens = math.Min(float64(g.config.Burst), b.tokens+math.Max(0, elapsed)*g.config.Rate)
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / g.config.Rate * float64(time.Second)), nil
+	}
+
+	b.tokens--
+	b.used++
+	g.used[keyID] = b.used
+	return true, 0, nil
+}
+
+// Wait blocks until a request to endpoint with apiKey may be sent, the context ends,
+// or the daily quota for apiKey is exhausted
+func (g *Governor) Wait(ctx context.Context, apiKey, endpoint string) error {
+	keyID := apiKeyID(apiKey)
+
+	g.mu.Lock()
+	g.keyIDs[keyID] = true
+	g.mu.Unlock()
+
+	throttled := false
+	for {
+		ok, wait, err := g.acquire(ctx, keyID, time.Now())
+		if errors.Is(err, ErrQuotaExhausted) {
+			g.record(endpoint, func(s *endpointStats) { s.rejected++ })
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if ok {
+			g.record(endpoint, func(s *endpointStats) { s.allowed++ })
+			return nil
+		}
+
+		if !throttled {
+			throttled = true
+			g.record(endpoint, func(s *endpointStats) { s.throttled++ })
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// quotaUsed returns today's shared usage for keyID (falls back to the last known value)
+func (g *Governor) quotaUsed(ctx context.Context, keyID string) int64 {
+	if g.rdb != nil {
+		used, err := g.rdb.Get(ctx, g.quotaKey(keyID, quotaDay(time.Now()))).Int64()
+		if err == nil || errors.Is(err, redis.Nil) {
+			return used
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.used[keyID]
+}
+
+var (
+	quotaLimitDesc = prometheus.NewDesc("openapi_quota_limit",
+		"Daily OpenAPI quota per API key (0 = unlimited).", nil, nil)
+	rateLimitDesc = prometheus.NewDesc("openapi_rate_limit",
+		"Shared request rate limit per API key (requests/second).", nil, nil)
+	quotaUsedDesc = prometheus.NewDesc("openapi_quota_used",
+		"Requests counted against today's shared OpenAPI quota.", []string{"key_id"}, nil)
+	governorRequestsDesc = prometheus.NewDesc("openapi_governor_requests_total",
+		"Governor decisions per upstream endpoint.", []string{"endpoint", "result"}, nil)
+)
+
+// Describe implements prometheus.Collector
+func (g *Governor) Describe(ch chan<- *prometheus.Desc) {
+	ch <- quotaLimitDesc
+	ch <- rateLimitDesc
+	ch <- quotaUsedDesc
+	ch <- governorRequestsDesc
+}
+
+// Collect implements prometheus.Collector; quota usage is read from Redis at scrape time
+func (g *Governor) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	g.mu.Lock()
+	keyIDs := make([]string, 0, len(g.keyIDs))
+	for id := range g.keyIDs {
+		keyIDs = append(keyIDs, id)
+	}
+	snapshot := make(map[string]endpointStats, len(g.stats))
+	for e, s := range g.stats {
+		snapshot[e] = *s
+	}
+	g.mu.Unlock()
+
+	ch <- prometheus.MustNewConstMetric(quotaLimitDesc, prometheus.GaugeValue, float64(g.config.DailyQuota))
+	ch <- prometheus.MustNewConstMetric(rateLimitDesc, prometheus.GaugeValue, g.config.Rate)
+
+	for _, id := range keyIDs {
+		ch <- prometheus.MustNewConstMetric(quotaUsedDesc, prometheus.GaugeValue, float64(g.quotaUsed(ctx, id)), id)
+	}
+
+	for e, s := range snapshot {
+		ch <- prometheus.MustNewConstMetric(governorRequestsDesc, prometheus.CounterValue, float64(s.allowed), e, "allowed")
+		ch <- prometheus.MustNewConstMetric(governorRequestsDesc, prometheus.CounterValue, float64(s.throttled), e, "throttled")
+		ch <- prometheus.MustNewConstMetric(governorRequestsDesc, prometheus.CounterValue, float64(s.rejected), e, "quota_exhausted")
+	}
+}
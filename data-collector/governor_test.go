@@ -0,0 +1,144 @@
+// Canonical copy; openapi-collector/governor_test.go mirrors it (see governor.go).
+
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestQuotaDay(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{now: time.Date(2025, 11, 17, 14, 59, 0, 0, time.UTC), want: "20251117"},
+		{now: time.Date(2025, 11, 17, 15, 0, 0, 0, time.UTC), want: "20251118"}, // KST midnight
+		{now: time.Date(2025, 12, 31, 20, 0, 0, 0, time.UTC), want: "20260101"},
+	}
+	for _, tt := range tests {
+		if got := quotaDay(tt.now); got != tt.want {
+			t.Errorf("quotaDay(%v) = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestAPIKeyID(t *testing.T) {
+	id := apiKeyID("secret-key")
+	if len(id) != 8 || id != apiKeyID("secret-key") || id == apiKeyID("other-key") {
+		t.Errorf("apiKeyID = %q: want a stable 8 character ID per key", id)
+	}
+}
+
+// The local limiter is what runs without Redis; the shared Lua script implements the
+// same bucket in Redis
+func TestLocalBudget(t *testing.T) {
+	start := time.Date(2025, 11, 17, 3, 0, 0, 0, time.UTC)
+
+	type step struct {
+		at       time.Duration // since start
+		ok       bool
+		wait     time.Duration
+		quotaErr bool
+	}
+	tests := []struct {
+		name   string
+		config GovernorConfig
+		steps  []step
+	}{
+		{
+			name:   "burst then throttle",
+			config: GovernorConfig{Rate: 2, Burst: 2},
+			steps: []step{
+				{at: 0, ok: true},
+				{at: 0, ok: true},
+				{at: 0, ok: false, wait: 500 * time.Millisecond},
+				{at: 250 * time.Millisecond, ok: false, wait: 250 * time.Millisecond},
+				{at: 500 * time.Millisecond, ok: true},
+			},
+		},
+		{
+			name:   "refill is capped at burst",
+			config: GovernorConfig{Rate: 10, Burst: 1},
+			steps: []step{
+				{at: 0, ok: true},
+				{at: time.Minute, ok: true},
+				{at: time.Minute, ok: false, wait: 100 * time.Millisecond},
+			},
+		},
+		{
+			name:   "daily quota",
+			config: GovernorConfig{Rate: 100, Burst: 100, DailyQuota: 2},
+			steps: []step{
+				{at: 0, ok: true},
+				{at: 0, ok: true},
+				{at: 0, quotaErr: true},
+				{at: 11 * time.Hour, quotaErr: true},
+				{at: 12 * time.Hour, ok: true}, // 15:00 UTC is the next KST day
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGovernor(tt.config, nil)
+			for i, s := range tt.steps {
+				ok, wait, err := g.acquire(context.Background(), "key", start.Add(s.at))
+				if s.quotaErr {
+					if !errors.Is(err, ErrQuotaExhausted) {
+						t.Fatalf("step %d: err = %v, want ErrQuotaExhausted", i, err)
+					}
+					continue
+				}
+				if err != nil || ok != s.ok || wait != s.wait {
+					t.Fatalf("step %d: acquire = %v, %v, %v; want %v, %v", i, ok, wait, err, s.ok, s.wait)
+				}
+			}
+		})
+	}
+}
+
+func TestGovernorFailsOpenWithoutRedis(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	defer rdb.Close()
+
+	g := NewGovernor(GovernorConfig{Rate: 1, Burst: 1, DailyQuota: 10}, rdb)
+	ok, _, err := g.acquire(context.Background(), "key", time.Now())
+	if err != nil || !ok {
+		t.Fatalf("acquire = %v, %v; want the local limiter to allow", ok, err)
+	}
+	if used := g.used["key"]; used != 1 {
+		t.Errorf("local usage = %d, want 1", used)
+	}
+}
+
+func TestGovernorWaitCountsDecisions(t *testing.T) {
+	g := NewGovernor(GovernorConfig{Rate: 1000, Burst: 1, DailyQuota: 2}, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := g.Wait(ctx, "key", "trafficIc"); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+	if err := g.Wait(ctx, "key", "trafficIc"); !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("third wait = %v, want ErrQuotaExhausted", err)
+	}
+
+	s := g.stats["trafficIc"]
+	if s.allowed != 2 || s.rejected != 1 {
+		t.Errorf("stats = %+v, want 2 allowed and 1 rejected", *s)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	g = NewGovernor(GovernorConfig{Rate: 0.001, Burst: 1}, nil)
+	g.Wait(ctx, "key", "trafficIc")
+	if err := g.Wait(cancelled, "key", "trafficIc"); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait on a cancelled context = %v, want context.Canceled", err)
+	}
+}
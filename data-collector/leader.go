@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// acquireLeaseScript takes or renews the leader lease.
+// KEYS[1] lease key, KEYS[2] fencing counter
+// ARGV holder id, lease TTL (ms)
+// The lease value is "<holder>|<token>". A new holder gets the next fencing token;
+// the current holder keeps its token and extends the TTL. Returns 0 if held by someone else.
+var acquireLeaseScript = redis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+if not cur then
+	local token = redis.call('INCR', KEYS[2])
+	if redis.call('SET', KEYS[1], ARGV[1] .. '|' .. token, 'NX', 'PX', ARGV[2]) then
+		return token
+	end
+	return 0
+end
+
+local sep = string.find(cur, '|', 1, true)
+if sep and string.sub(cur, 1, sep - 1) == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return tonumber(string.sub(cur, sep + 1))
+end
+return 0
+`)
+
+// releaseLeaseScript deletes the lease only if it still belongs to the caller
+var releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// LeaderConfig configures Redis lease based leader election
+type LeaderConfig struct {
+	Enabled  bool
+	ID       string        // unique per instance (defaults to hostname)
+	Key      string        // lease key; the fencing counter is Key + ":fencing"
+	LeaseTTL time.Duration // lease lifetime; renewed every LeaseTTL/3
+}
+
+// LeaderElector keeps at most one data-collector active across clusters. Every instance
+// runs as a warm standby and competes for the lease; the holder runs the collectors and
+// stamps its fencing token on published messages so data-processor can drop a stale leader.
+type LeaderElector struct {
+	config LeaderConfig
+	rdb    *redis.Client
+
+	mu        sync.Mutex
+	token     int64
+	leading   bool
+	lastRenew time.Time
+	since     time.Time
+}
+
+func NewLeaderElector(config LeaderConfig, rdb *redis.Client) *LeaderElector {
+	if config.LeaseTTL <= 0 {
+		config.LeaseTTL = 10 * time.Second
+	}
+	if config.Key == "" {
+		config.Key = "data-collector:leader"
+	}
+	return &LeaderElector{config: config, rdb: rdb}
+}
+
+// Token returns the fencing token of the current term, or 0 when not leading
+func (e *LeaderElector) Token() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.leading {
+		return 0
+	}
+	return e.token
+}
+
+// IsLeader reports whether this instance currently holds the lease
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leading
+}
+
+func (e *LeaderElector) tryAcquire(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.config.LeaseTTL/3)
+	defer cancel()
+
+	return acquireLeaseScript.Run(ctx, e.rdb,
+		[]string{e.config.Key, e.config.Key + ":fencing"},
+		e.config.ID, e.config.LeaseTTL.Milliseconds(),
+	).Int64()
+}
+
+func (e *LeaderElector) release() {
+	e.mu.Lock()
+	value := fmt.Sprintf("%s|%d", e.config.ID, e.token)
+	e.leading = false
+	e.mu.Unlock()
+	isLeader.Set(0)
+	fencingToken.Set(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := releaseLeaseScript.Run(ctx, e.rdb, []string{e.config.Key}, value).Err(); err != nil {
+		componentLogger("leader").Error("failed to release lease", "error", err)
+	}
+}
+
+// Run campaigns for the lease until stop is done. lead is started in its own goroutine
+// each time leadership is gained: its ctx is cancelled as soon as leadership is lost, while
+// stop tells it to finish the current cycle and return. On shutdown the lease is kept
+// renewed for up to drain while lead finishes, then released so a standby takes over at once.
+func (e *LeaderElector) Run(stop context.Context, drain time.Duration, lead func(ctx, stop context.Context)) {
+	if !e.config.Enabled {
+		componentLogger("leader").Info("leader election disabled, running as sole collector")
+		e.mu.Lock()
+		e.leading = true
+		e.since = time.Now()
+		e.mu.Unlock()
+		isLeader.Set(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			lead(ctx, stop)
+		}()
+
+		<-stop.Done()
+		select {
+		case <-done:
+		case <-time.After(drain):
+			componentLogger("leader").Warn("collectors did not finish in time, cancelling", "drain", drain)
+			cancel()
+			<-done
+		}
+		return
+	}
+
+	componentLogger("leader").Info("campaigning for leader lease",
+		"id", e.config.ID, "key", e.config.Key, "ttl", e.config.LeaseTTL)
+
+	// Redis calls must keep working while draining after stop
+	ctx := context.WithoutCancel(stop)
+
+	// term is the running lead goroutine of the current leadership term
+	type term struct {
+		cancel context.CancelFunc
+		done   chan struct{}
+	}
+	var current *term
+
+	stopLead := func() {
+		if current == nil {
+			return
+		}
+		current.cancel()
+		<-current.done
+		current = nil
+	}
+	stepDown := func(reason string) {
+		if current == nil {
+			return
+		}
+		componentLogger("leader").Warn("stepping down", "fencing_token", e.Token(), "reason", reason)
+		stopLead()
+		e.release()
+	}
+
+	ticker := time.NewTicker(e.config.LeaseTTL / 3)
+	defer ticker.Stop()
+
+	// renew refreshes the lease and reports whether this instance still holds it
+	renew := func() (int64, bool) {
+		token, err := e.tryAcquire(ctx)
+		now := time.Now()
+		if err != nil {
+			// Without Redis the lease cannot be confirmed; give up before it could have expired
+			e.mu.Lock()
+			valid := e.leading && now.Sub(e.lastRenew) <= e.config.LeaseTTL*2/3
+			e.mu.Unlock()
+			componentLogger("leader").Warn("lease check failed", "error", err)
+			return 0, valid
+		}
+		if token == 0 {
+			return 0, false
+		}
+
+		e.mu.Lock()
+		e.lastRenew = now
+		e.mu.Unlock()
+		return token, true
+	}
+
+	for stop.Err() == nil {
+		token, ok := renew()
+
+		switch {
+		case !ok:
+			stepDown("lease lost or held by another instance")
+		case token == 0:
+			// Redis unreachable but the lease cannot have expired yet
+		default:
+			e.mu.Lock()
+			newTerm := !e.leading || e.token != token
+			e.token = token
+			e.leading = true
+			if newTerm {
+				e.since = time.Now()
+			}
+			e.mu.Unlock()
+
+			if newTerm {
+				// A previous term may still be winding down if the lease was lost unnoticed
+				stopLead()
+				componentLogger("leader").Info("elected leader", "fencing_token", token)
+				isLeader.Set(1)
+				fencingToken.Set(float64(token))
+
+				leadCtx, cancel := context.WithCancel(ctx)
+				current = &term{cancel: cancel, done: make(chan struct{})}
+				go func(t *term) {
+					defer close(t.done)
+					lead(leadCtx, stop)
+				}(current)
+			}
+		}
+
+		select {
+		case <-stop.Done():
+		case <-ticker.C:
+		}
+	}
+
+	if current == nil {
+		return
+	}
+
+	// Shutdown: let the current cycle finish while still holding the lease
+	componentLogger("leader").Info("shutdown requested, draining current cycle", "drain", drain)
+	deadline := time.NewTimer(drain)
+	defer deadline.Stop()
+
+	for current != nil {
+		select {
+		case <-current.done:
+			current = nil
+			e.release()
+			componentLogger("leader").Info("drained, lease released")
+			return
+		case <-ticker.C:
+			if _, ok := renew(); !ok {
+				stepDown("lease lost while draining")
+			}
+		case <-deadline.C:
+			stepDown(fmt.Sprintf("drain timeout %v exceeded", drain))
+		}
+	}
+}
+
+// statusHandler serves GET /leader
+func (e *LeaderElector) statusHandler(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	status := map[string]interface{}{
+		"id":      e.config.ID,
+		"enabled": e.config.Enabled,
+		"leader":  e.leading,
+	}
+	if e.leading {
+		status["fencing_token"] = e.token
+		status["since"] = e.since
+	}
+	e.mu.Unlock()
+
+	if e.config.Enabled {
+		if holder, err := e.rdb.Get(r.Context(), e.config.Key).Result(); err == nil {
+			status["lease_holder"] = holder
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
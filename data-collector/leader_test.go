@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestLeaderElectorDefaults(t *testing.T) {
+	e := NewLeaderElector(LeaderConfig{ID: "a"}, nil)
+	if e.config.LeaseTTL != 10*time.Second || e.config.Key != "data-collector:leader" {
+		t.Errorf("config = %+v", e.config)
+	}
+	if e.IsLeader() || e.Token() != 0 {
+		t.Error("new elector is leading")
+	}
+
+	// The token is only handed out while leading
+	e.token = 5
+	if e.Token() != 0 {
+		t.Errorf("token = %d while standing by", e.Token())
+	}
+	e.leading = true
+	if e.Token() != 5 {
+		t.Errorf("token = %d, want 5", e.Token())
+	}
+}
+
+func TestRunWithoutElection(t *testing.T) {
+	tests := []struct {
+		name       string
+		ignoreStop bool // lead only returns once its ctx is cancelled
+	}{
+		{name: "lead finishes after stop"},
+		{name: "lead cancelled after drain", ignoreStop: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewLeaderElector(LeaderConfig{}, nil)
+			stop, cancel := context.WithCancel(context.Background())
+
+			started := make(chan struct{})
+			var cancelled bool
+			lead := func(ctx, stop context.Context) {
+				close(started)
+				if tt.ignoreStop {
+					<-ctx.Done()
+					cancelled = true
+					return
+				}
+				<-stop.Done()
+			}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				e.Run(stop, 20*time.Millisecond, lead)
+			}()
+
+			<-started
+			if !e.IsLeader() {
+				t.Error("sole collector is not leading")
+			}
+			cancel()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Run did not return after stop")
+			}
+			if cancelled != tt.ignoreStop {
+				t.Errorf("lead cancelled = %v, want %v", cancelled, tt.ignoreStop)
+			}
+		})
+	}
+}
+
+func TestRunWithoutRedisNeverLeads(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer rdb.Close()
+
+	e := NewLeaderElector(LeaderConfig{Enabled: true, ID: "a", LeaseTTL: 150 * time.Millisecond}, rdb)
+	stop, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	e.Run(stop, time.Second, func(ctx, stop context.Context) {
+		t.Error("lead started without a lease")
+	})
+	if e.IsLeader() {
+		t.Error("leading without a lease")
+	}
+}
+
+func TestLeaderStatusHandler(t *testing.T) {
+	since := time.Date(2025, 11, 17, 3, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		leading bool
+		want    map[string]interface{}
+	}{
+		{name: "standby", want: map[string]interface{}{"id": "a", "enabled": false, "leader": false}},
+		{name: "leader", leading: true, want: map[string]interface{}{
+			"id": "a", "enabled": false, "leader": true, "fencing_token": float64(9), "since": "2025-11-17T03:00:00Z",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewLeaderElector(LeaderConfig{ID: "a"}, nil)
+			e.leading, e.token, e.since = tt.leading, 9, since
+
+			w := httptest.NewRecorder()
+			e.statusHandler(w, httptest.NewRequest(http.MethodGet, "/leader", nil))
+
+			var got map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("status = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %v, want %v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
@@ -6,13 +6,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type RealTimeSMS struct {
@@ -96,6 +103,7 @@ type Config struct {
 	TollgateAPIURL          string
 	TollgateAPIKey          string
 	TollgateCollectInterval time.Duration // 15 minutes
+	TollgatePagePool        PagePoolConfig
 
 	// Road traffic status collection
 	RoadStatusAPIURL          string
@@ -107,13 +115,31 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+
+	// Shared OpenAPI rate limit / daily quota
+	Governor    GovernorConfig
+	MetricsPort string
+
+	// Leader election across clusters
+	Leader LeaderConfig
+
+	// Bearer token for POST /backfill; empty disables the endpoint
+	BackfillToken string
 }
 
+// shutdownTimeout bounds how long a running collection cycle may take to finish after
+// SIGTERM; it stays below the default 30s Kubernetes termination grace period
+const shutdownTimeout = 25 * time.Second
+
 type Collector struct {
 	config      Config
 	redisClient *redis.Client
 	db          *sql.DB
 	httpClient  *http.Client
+	governor    *Governor
+	elector     *LeaderElector
+
+	backfills sync.WaitGroup // backfills started over HTTP
 }
 
 func loadConfig() Config {
@@ -167,6 +193,27 @@ func loadConfig() Config {
 		}
 	}
 
+	tollgateWorkers := 4
+	if env := os.Getenv("TOLLGATE_WORKERS"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v > 0 {
+			tollgateWorkers = v
+		}
+	}
+
+	tollgateRetries := 3
+	if env := os.Getenv("TOLLGATE_MAX_RETRIES"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v >= 0 {
+			tollgateRetries = v
+		}
+	}
+
+	tollgateBackoff := 1 * time.Second
+	if env := os.Getenv("TOLLGATE_RETRY_BACKOFF"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d > 0 {
+			tollgateBackoff = d
+		}
+	}
+
 	// Road status API
 	roadStatusAPIURL := os.Getenv("ROAD_STATUS_API_URL")
 	if roadStatusAPIURL == "" {
@@ -206,16 +253,68 @@ func loadConfig() Config {
 		dbName = "trafficdb"
 	}
 
+	governorRate := 5.0
+	if env := os.Getenv("OPENAPI_RATE_LIMIT"); env != "" {
+		if v, err := strconv.ParseFloat(env, 64); err == nil && v > 0 {
+			governorRate = v
+		}
+	}
+
+	governorBurst := 10
+	if env := os.Getenv("OPENAPI_RATE_BURST"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v > 0 {
+			governorBurst = v
+		}
+	}
+
+	var dailyQuota int64 = 50000
+	if env := os.Getenv("OPENAPI_DAILY_QUOTA"); env != "" {
+		if v, err := strconv.ParseInt(env, 10, 64); err == nil && v >= 0 {
+			dailyQuota = v
+		}
+	}
+
+	governorPrefix := os.Getenv("OPENAPI_GOVERNOR_PREFIX")
+	if governorPrefix == "" {
+		governorPrefix = "openapi:governor"
+	}
+
+	leaderEnabled := os.Getenv("LEADER_ELECTION") != "false"
+
+	leaderID := os.Getenv("LEADER_ID")
+	if leaderID == "" {
+		hostname, _ := os.Hostname()
+		leaderID = fmt.Sprintf("collector-%s", hostname)
+	}
+
+	leaderTTL := 10 * time.Second
+	if env := os.Getenv("LEADER_LEASE_TTL"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d >= time.Second {
+			leaderTTL = d
+		}
+	}
+
+	metricsPort := os.Getenv("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9090"
+	}
+
 	return Config{
-		DataSourceMode:            mode,
-		RedisAddr:                 redisAddr,
-		SimulatorURL:              simulatorURL,
-		RealAPIURL:                realAPIURL,
-		RealAPIKey:                realAPIKey,
-		CollectInterval:           interval,
-		TollgateAPIURL:            tollgateAPIURL,
-		TollgateAPIKey:            tollgateAPIKey,
-		TollgateCollectInterval:   tollgateInterval,
+		DataSourceMode:          mode,
+		RedisAddr:               redisAddr,
+		SimulatorURL:            simulatorURL,
+		RealAPIURL:              realAPIURL,
+		RealAPIKey:              realAPIKey,
+		CollectInterval:         interval,
+		TollgateAPIURL:          tollgateAPIURL,
+		TollgateAPIKey:          tollgateAPIKey,
+		TollgateCollectInterval: tollgateInterval,
+		TollgatePagePool: PagePoolConfig{
+			Workers:     tollgateWorkers,
+			MaxRetries:  tollgateRetries,
+			BaseBackoff: tollgateBackoff,
+			MaxBackoff:  30 * time.Second,
+		},
 		RoadStatusAPIURL:          roadStatusAPIURL,
 		RoadStatusAPIKey:          roadStatusAPIKey,
 		RoadStatusCollectInterval: roadStatusInterval,
@@ -223,6 +322,20 @@ func loadConfig() Config {
 		DBUser:                    dbUser,
 		DBPassword:                dbPassword,
 		DBName:                    dbName,
+		Governor: GovernorConfig{
+			Rate:       governorRate,
+			Burst:      governorBurst,
+			DailyQuota: dailyQuota,
+			KeyPrefix:  governorPrefix,
+		},
+		MetricsPort: metricsPort,
+		Leader: LeaderConfig{
+			Enabled:  leaderEnabled,
+			ID:       leaderID,
+			Key:      os.Getenv("LEADER_KEY"),
+			LeaseTTL: leaderTTL,
+		},
+		BackfillToken: os.Getenv("BACKFILL_TOKEN"),
 	}
 }
 
@@ -238,7 +351,7 @@ func NewCollector(config Config) (*Collector, error) {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
-	log.Printf("Connected to Redis at %s", config.RedisAddr)
+	slog.Info("connected to Redis", "addr", config.RedisAddr)
 
 	// Connect to MariaDB
 	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true&loc=Asia%%2FSeoul",
@@ -257,7 +370,7 @@ func NewCollector(config Config) (*Collector, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Printf("Connected to MariaDB at %s", config.DBHost)
+	slog.Info("connected to MariaDB", "host", config.DBHost)
 
 	return &Collector{
 		config:      config,
@@ -266,22 +379,35 @@ func NewCollector(config Config) (*Collector, error) {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second, // Increased for tollgate API pagination
 		},
+		governor: NewGovernor(config.Governor, rdb),
+		elector:  NewLeaderElector(config.Leader, rdb),
 	}, nil
 }
 
-func (c *Collector) fetchData(ctx context.Context) (*APIResponse, error) {
+func (c *Collector) fetchData(ctx context.Context) (data *APIResponse, err error) {
 	var url string
+	endpoint := "simulator"
 
 	if c.config.DataSourceMode == "real" {
 		// 고속도로 공공데이터 포털 API
 		url = fmt.Sprintf("%s?key=%s&type=json&numOfRows=100&pageNo=1&sortType=desc&pagingYn=Y",
 			c.config.RealAPIURL, c.config.RealAPIKey)
-		log.Printf("Fetching from REAL OpenAPI (고속도로 공공데이터): %s", url)
+		componentLogger("accident").Debug("fetching from real OpenAPI (고속도로 공공데이터)", "endpoint", "realTimeSms")
+
+		// Only real OpenAPI calls count against the shared quota
+		if err := c.governor.Wait(ctx, c.config.RealAPIKey, "realTimeSms"); err != nil {
+			return nil, fmt.Errorf("rate limit: %w", err)
+		}
+		endpoint = "realTimeSms"
 	} else {
 		url = c.config.SimulatorURL
 		//log.Printf("Fetching from SIMULATOR: %s", url)
 	}
 
+	defer observeUpstream(endpoint, time.Now(), &err)
+	ctx, span := startUpstreamSpan(ctx, endpoint)
+	defer func() { endSpan(span, err) }()
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -323,13 +449,19 @@ func (c *Collector) publishToStream(ctx context.Context, data *APIResponse) erro
 	}
 
 	streamKey := "traffic-stream"
+	values := map[string]interface{}{
+		"data":      string(jsonData),
+		"timestamp": time.Now().Unix(),
+		"source":    c.config.DataSourceMode,
+		// data-processor drops messages carrying a token older than the newest it has seen
+		"fencing_token": c.elector.Token(),
+		"leader":        c.config.Leader.ID,
+	}
+	// data-processor continues the collection trace from traceparent
+	injectTraceContext(ctx, values)
 	args := &redis.XAddArgs{
 		Stream: streamKey,
-		Values: map[string]interface{}{
-			"data":      string(jsonData),
-			"timestamp": time.Now().Unix(),
-			"source":    c.config.DataSourceMode,
-		},
+		Values: values,
 	}
 
 	id, err := c.redisClient.XAdd(ctx, args).Result()
@@ -337,13 +469,22 @@ func (c *Collector) publishToStream(ctx context.Context, data *APIResponse) erro
 		return fmt.Errorf("failed to add to stream: %w", err)
 	}
 
-	log.Printf("Published %d accidents to stream %s (ID: %s, Mode: %s)",
-		len(data.RealTimeSMSList), streamKey, id, c.config.DataSourceMode)
+	componentLogger("accident").Info("published accidents to stream", "count", len(data.RealTimeSMSList), "stream", streamKey,
+		"message_id", id, "mode", c.config.DataSourceMode, "fencing_token", c.elector.Token())
 
 	return nil
 }
 
-func (c *Collector) collectOnce(ctx context.Context) error {
+func (c *Collector) collectOnce(ctx context.Context) (err error) {
+	start := time.Now()
+	published := 0
+	ctx, span := tracer.Start(ctx, "collect accident")
+	defer func() {
+		observeCycle("accident", start, published, err)
+		span.SetAttributes(attribute.Int("records", published))
+		endSpan(span, err)
+	}()
+
 	data, err := c.fetchData(ctx)
 	if err != nil {
 		return fmt.Errorf("fetch failed: %w", err)
@@ -352,30 +493,35 @@ func (c *Collector) collectOnce(ctx context.Context) error {
 	if err := c.publishToStream(ctx, data); err != nil {
 		return fmt.Errorf("publish failed: %w", err)
 	}
+	published = len(data.RealTimeSMSList)
 
 	return nil
 }
 
-func (c *Collector) Start(ctx context.Context) {
-	log.Printf("Data Collector started (Mode: %s, Interval: %v)",
-		c.config.DataSourceMode, c.config.CollectInterval)
+// Start runs the accident collector until ctx is cancelled (leadership lost) or stop is
+// done (shutdown); a cycle already running when stop fires is allowed to finish
+func (c *Collector) Start(ctx, stop context.Context) {
+	componentLogger("accident").Info("collector started", "mode", c.config.DataSourceMode, "interval", c.config.CollectInterval)
 
 	ticker := time.NewTicker(c.config.CollectInterval)
 	defer ticker.Stop()
 
 	// Collect immediately on start
 	if err := c.collectOnce(ctx); err != nil {
-		log.Printf("Initial collection failed: %v", err)
+		componentLogger("accident").Error("initial collection failed", "error", err)
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Collector stopped")
+			componentLogger("accident").Info("collector stopped")
+			return
+		case <-stop.Done():
+			componentLogger("accident").Info("collector stopped", "reason", "shutdown")
 			return
 		case <-ticker.C:
 			if err := c.collectOnce(ctx); err != nil {
-				log.Printf("Collection error: %v", err)
+				componentLogger("accident").Error("collection failed", "error", err)
 			}
 		}
 	}
@@ -390,8 +536,24 @@ func (c *Collector) Close() error {
 
 // Tollgate traffic collection functions
 func (c *Collector) fetchTollgateTraffic(ctx context.Context, pageNo int) (*TollgateAPIResponse, error) {
+	return c.fetchTollgateTrafficAt(ctx, pageNo, "", "")
+}
+
+// fetchTollgateTrafficAt fetches one page; sumDate/sumTm select a past interval (empty = latest)
+func (c *Collector) fetchTollgateTrafficAt(ctx context.Context, pageNo int, sumDate, sumTm string) (page *TollgateAPIResponse, err error) {
 	url := fmt.Sprintf("%s?key=%s&type=json&tmType=2&numOfRows=100&pageNo=%d&carType=1&inoutType=0&tcsType=2",
 		c.config.TollgateAPIURL, c.config.TollgateAPIKey, pageNo)
+	if sumDate != "" {
+		url += fmt.Sprintf("&sumDate=%s&sumTm=%s", sumDate, sumTm)
+	}
+
+	if err := c.governor.Wait(ctx, c.config.TollgateAPIKey, "trafficIc"); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+
+	defer observeUpstream("trafficIc", time.Now(), &err)
+	ctx, span := startUpstreamSpan(ctx, "trafficIc", attribute.Int("page", pageNo))
+	defer func() { endSpan(span, err) }()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -475,97 +637,155 @@ func (c *Collector) upsertTollgateMaster(ctx context.Context, traffic *TollgateT
 	return err
 }
 
-func (c *Collector) collectTollgateTrafficOnce(ctx context.Context) error {
-	log.Println("Starting tollgate traffic collection...")
-
-	// First page to get total count
-	firstPage, err := c.fetchTollgateTraffic(ctx, 1)
+// fetchTollgatePage fetches one page and treats a non-SUCCESS API code as a (retryable) error
+func (c *Collector) fetchTollgatePage(ctx context.Context, pageNo int) (*TollgateAPIResponse, error) {
+	page, err := c.fetchTollgateTraffic(ctx, pageNo)
 	if err != nil {
-		return fmt.Errorf("failed to fetch first page: %w", err)
+		return nil, err
 	}
-
-	if firstPage.Code != "SUCCESS" {
-		return fmt.Errorf("API error: %s - %s", firstPage.Code, firstPage.Message)
+	if page.Code != "SUCCESS" {
+		return nil, fmt.Errorf("API error: %s - %s", page.Code, page.Message)
 	}
+	return page, nil
+}
 
-	log.Printf("Total tollgate traffic records: %d (pages: %d)", firstPage.Count, firstPage.PageSize)
+// saveTollgatePage stores every record of a page and refreshes tollgate_master. It
+// returns how many records were saved and an error when any write of the page failed, so
+// the page counts as failed in the sweep report.
+func (c *Collector) saveTollgatePage(ctx context.Context, pageNo int, pageData *TollgateAPIResponse) (int, error) {
+	saved, failed := 0, 0
+	var firstErr error
+	for _, traffic := range pageData.TrafficIc {
+		if err := c.saveTollgateTraffic(ctx, &traffic); err != nil {
+			componentLogger("tollgate").Error("failed to save traffic data", "page", pageNo, "unit", traffic.UnitCode,
+				"interval", traffic.SumDate+" "+traffic.SumTm, "error", err)
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
 
+		// Update tollgate master
+		if err := c.upsertTollgateMaster(ctx, &traffic); err != nil {
+			componentLogger("tollgate").Error("failed to update tollgate master", "unit", traffic.UnitCode, "error", err)
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		saved++
+	}
+	if failed > 0 {
+		return saved, fmt.Errorf("page %d: %d writes failed: %w", pageNo, failed, firstErr)
+	}
+	return saved, nil
+}
+
+func (c *Collector) collectTollgateTrafficOnce(ctx context.Context) (err error) {
+	componentLogger("tollgate").Info("starting collection")
+	start := time.Now()
 	totalSaved := 0
+	ctx, span := tracer.Start(ctx, "collect tollgate")
+	defer func() {
+		observeCycle("tollgate", start, totalSaved, err)
+		span.SetAttributes(attribute.Int("records", totalSaved))
+		endSpan(span, err)
+	}()
+
+	// First page to get total count
+	firstPage, _, err := fetchWithRetry(ctx, c.config.TollgatePagePool, 1, c.fetchTollgatePage)
+	if err != nil {
+		return fmt.Errorf("failed to fetch first page: %w", err)
+	}
+
 	totalPages := firstPage.PageSize
+	componentLogger("tollgate").Info("collection sized", "records", firstPage.Count, "pages", totalPages,
+		"workers", c.config.TollgatePagePool.Workers)
 
-	// Process all pages
-	for pageNo := 1; pageNo <= totalPages; pageNo++ {
-		var pageData *TollgateAPIResponse
-
-		if pageNo == 1 {
-			pageData = firstPage
-		} else {
-			pageData, err = c.fetchTollgateTraffic(ctx, pageNo)
-			if err != nil {
-				log.Printf("Failed to fetch page %d: %v", pageNo, err)
-				continue
-			}
-		}
+	var mu sync.Mutex
+	totalSaved, firstErr := c.saveTollgatePage(ctx, 1, firstPage)
+	processed := 1
 
-		// Save each traffic record
-		for i, traffic := range pageData.TrafficIc {
-			// Debug: log first few records to see actual traffic amounts
-			if pageNo == 1 && i < 3 {
-				log.Printf("DEBUG: Unit=%s, Time=%s %s, TrafficAmount='%s'",
-					traffic.UnitName, traffic.SumDate, traffic.SumTm, traffic.TrafficAmount)
-			}
+	pages := make([]int, 0, totalPages)
+	for pageNo := 2; pageNo <= totalPages; pageNo++ {
+		pages = append(pages, pageNo)
+	}
 
-			if err := c.saveTollgateTraffic(ctx, &traffic); err != nil {
-				log.Printf("Failed to save traffic data (unit: %s, time: %s %s): %v",
-					traffic.UnitCode, traffic.SumDate, traffic.SumTm, err)
-				continue
-			}
+	report := fetchPages(ctx, c.config.TollgatePagePool, pages, c.fetchTollgatePage,
+		func(ctx context.Context, pageNo int, pageData *TollgateAPIResponse) error {
+			saved, err := c.saveTollgatePage(ctx, pageNo, pageData)
 
-			// Update tollgate master
-			if err := c.upsertTollgateMaster(ctx, &traffic); err != nil {
-				log.Printf("Failed to update tollgate master (unit: %s): %v", traffic.UnitCode, err)
+			mu.Lock()
+			defer mu.Unlock()
+			totalSaved += saved
+			processed++
+			if processed%10 == 0 || processed == totalPages {
+				componentLogger("tollgate").Debug("progress", "processed_pages", processed, "pages", totalPages, "records_saved", totalSaved)
 			}
+			return err
+		})
+
+	// Page 1 was fetched before the pool started
+	report.Expected++
+	if firstErr != nil {
+		report.Failed++
+		report.FailedPages = append([]int{1}, report.FailedPages...)
+	} else {
+		report.Fetched++
+	}
+	report.Duration = time.Since(start)
+	observePages(report)
 
-			totalSaved++
-		}
-
-		if pageNo%10 == 0 || pageNo == totalPages {
-			log.Printf("Progress: %d/%d pages processed, %d records saved", pageNo, totalPages, totalSaved)
-		}
+	if !report.Complete() {
+		componentLogger("tollgate").Warn("collection incomplete", "records_saved", totalSaved, "report", report.String())
+		return fmt.Errorf("collection incomplete: %s", report)
 	}
 
-	log.Printf("Tollgate traffic collection completed: %d records saved", totalSaved)
+	componentLogger("tollgate").Info("collection completed", "records_saved", totalSaved, "report", report.String())
 	return nil
 }
 
-func (c *Collector) startTollgateCollection(ctx context.Context) {
-	log.Printf("Tollgate traffic collector started (Interval: %v)", c.config.TollgateCollectInterval)
+func (c *Collector) startTollgateCollection(ctx, stop context.Context) {
+	componentLogger("tollgate").Info("collector started", "interval", c.config.TollgateCollectInterval)
 
 	ticker := time.NewTicker(c.config.TollgateCollectInterval)
 	defer ticker.Stop()
 
 	// Collect immediately on start
 	if err := c.collectTollgateTrafficOnce(ctx); err != nil {
-		log.Printf("Initial tollgate collection failed: %v", err)
+		componentLogger("tollgate").Error("initial collection failed", "error", err)
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Tollgate collector stopped")
+			componentLogger("tollgate").Info("collector stopped")
+			return
+		case <-stop.Done():
+			componentLogger("tollgate").Info("collector stopped", "reason", "shutdown")
 			return
 		case <-ticker.C:
 			if err := c.collectTollgateTrafficOnce(ctx); err != nil {
-				log.Printf("Tollgate collection error: %v", err)
+				componentLogger("tollgate").Error("collection failed", "error", err)
 			}
 		}
 	}
 }
 
 // Road traffic status collection functions
-func (c *Collector) fetchRoadStatus(ctx context.Context) (*RoadStatusAPIResponse, error) {
+func (c *Collector) fetchRoadStatus(ctx context.Context) (status *RoadStatusAPIResponse, err error) {
 	url := fmt.Sprintf("%s?key=%s&type=json", c.config.RoadStatusAPIURL, c.config.RoadStatusAPIKey)
 
+	if err := c.governor.Wait(ctx, c.config.RoadStatusAPIKey, "trafficAmountByRealtime"); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+
+	defer observeUpstream("trafficAmountByRealtime", time.Now(), &err)
+	ctx, span := startUpstreamSpan(ctx, "trafficAmountByRealtime")
+	defer func() { endSpan(span, err) }()
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -693,97 +913,189 @@ func (c *Collector) aggregateRouteSummary(ctx context.Context) error {
 	}
 
 	rowsAffected, _ := result.RowsAffected()
-	log.Printf("Route summary aggregation completed: %d routes processed", rowsAffected)
+	componentLogger("road_status").Info("route summary aggregation completed", "routes", rowsAffected)
 	return nil
 }
 
-func (c *Collector) collectRoadStatusOnce(ctx context.Context) error {
-	log.Println("Starting road status collection...")
+func (c *Collector) collectRoadStatusOnce(ctx context.Context) (err error) {
+	componentLogger("road_status").Info("starting collection")
+	start := time.Now()
+	savedCount := 0
+	ctx, span := tracer.Start(ctx, "collect road_status")
+	defer func() {
+		observeCycle("road_status", start, savedCount, err)
+		span.SetAttributes(attribute.Int("records", savedCount))
+		endSpan(span, err)
+	}()
 
 	apiResp, err := c.fetchRoadStatus(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch road status: %w", err)
 	}
 
-	log.Printf("Total road status records: %d", apiResp.Count)
+	componentLogger("road_status").Info("collection sized", "records", apiResp.Count)
 
-	savedCount := 0
 	for _, status := range apiResp.List {
 		if err := c.saveRoadStatus(ctx, &status); err != nil {
-			log.Printf("Failed to save road status (VDS: %s): %v", status.VdsID, err)
+			componentLogger("road_status").Error("failed to save road status", "vds_id", status.VdsID, "error", err)
 			continue
 		}
 		savedCount++
 	}
 
-	log.Printf("Road status collection completed: %d/%d records saved", savedCount, len(apiResp.List))
+	componentLogger("road_status").Info("collection completed", "records_saved", savedCount, "records", len(apiResp.List))
 
 	// Aggregate by route after saving all status data
 	if err := c.aggregateRouteSummary(ctx); err != nil {
-		log.Printf("Failed to aggregate route summary: %v", err)
+		componentLogger("road_status").Error("failed to aggregate route summary", "error", err)
 		// Don't return error, as the main data collection was successful
 	}
 
 	return nil
 }
 
-func (c *Collector) startRoadStatusCollection(ctx context.Context) {
-	log.Printf("Road status collector started (Interval: %v)", c.config.RoadStatusCollectInterval)
+func (c *Collector) startRoadStatusCollection(ctx, stop context.Context) {
+	componentLogger("road_status").Info("collector started", "interval", c.config.RoadStatusCollectInterval)
 
 	ticker := time.NewTicker(c.config.RoadStatusCollectInterval)
 	defer ticker.Stop()
 
 	// Collect immediately on start
 	if err := c.collectRoadStatusOnce(ctx); err != nil {
-		log.Printf("Initial road status collection failed: %v", err)
+		componentLogger("road_status").Error("initial collection failed", "error", err)
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Road status collector stopped")
+			componentLogger("road_status").Info("collector stopped")
+			return
+		case <-stop.Done():
+			componentLogger("road_status").Info("collector stopped", "reason", "shutdown")
 			return
 		case <-ticker.C:
 			if err := c.collectRoadStatusOnce(ctx); err != nil {
-				log.Printf("Road status collection error: %v", err)
+				componentLogger("road_status").Error("collection failed", "error", err)
 			}
 		}
 	}
 }
 
 func main() {
+	setupLogging("data-collector")
 	config := loadConfig()
 
-	log.Printf("Configuration:")
-	log.Printf("  [Accident Data]")
-	log.Printf("    Data Source Mode: %s", config.DataSourceMode)
-	log.Printf("    Redis Address: %s", config.RedisAddr)
-	log.Printf("    Simulator URL: %s", config.SimulatorURL)
-	log.Printf("    Real API URL: %s", config.RealAPIURL)
-	log.Printf("    Collection Interval: %v", config.CollectInterval)
-	log.Printf("  [Tollgate Traffic]")
-	log.Printf("    API URL: %s", config.TollgateAPIURL)
-	log.Printf("    Collection Interval: %v", config.TollgateCollectInterval)
-	log.Printf("  [Road Traffic Status]")
-	log.Printf("    API URL: %s", config.RoadStatusAPIURL)
-	log.Printf("    Collection Interval: %v", config.RoadStatusCollectInterval)
-	log.Printf("  [Database]")
-	log.Printf("    Host: %s", config.DBHost)
-	log.Printf("    Database: %s", config.DBName)
+	// Gap detection / historical backfill: data-collector backfill [-table] [-since] [-dry-run]
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		if err := runBackfillCommand(config, os.Args[2:]); err != nil {
+			fatal("backfill failed", "error", err)
+		}
+		return
+	}
+
+	slog.Info("configuration",
+		slog.Group("accident",
+			"mode", config.DataSourceMode,
+			"redis_addr", config.RedisAddr,
+			"simulator_url", config.SimulatorURL,
+			"real_api_url", config.RealAPIURL,
+			"interval", config.CollectInterval),
+		slog.Group("tollgate",
+			"api_url", config.TollgateAPIURL,
+			"interval", config.TollgateCollectInterval,
+			"workers", config.TollgatePagePool.Workers,
+			"max_retries", config.TollgatePagePool.MaxRetries,
+			"retry_backoff", config.TollgatePagePool.BaseBackoff),
+		slog.Group("road_status",
+			"api_url", config.RoadStatusAPIURL,
+			"interval", config.RoadStatusCollectInterval),
+		slog.Group("database",
+			"host", config.DBHost,
+			"name", config.DBName),
+		slog.Group("governor",
+			"rate", config.Governor.Rate,
+			"burst", config.Governor.Burst,
+			"daily_quota", config.Governor.DailyQuota),
+		slog.Group("leader",
+			"enabled", config.Leader.Enabled,
+			"id", config.Leader.ID,
+			"lease_ttl", config.Leader.LeaseTTL),
+		"metrics_port", config.MetricsPort,
+	)
 
 	collector, err := NewCollector(config)
 	if err != nil {
-		log.Fatalf("Failed to create collector: %v", err)
+		fatal("failed to create collector", "error", err)
 	}
 	defer collector.Close()
 
-	ctx := context.Background()
+	shutdownTracing, err := initTracing(context.Background(), "data-collector")
+	if err != nil {
+		fatal("failed to initialise tracing", "error", err)
+	}
+
+	// stop is cancelled on SIGINT/SIGTERM; running cycles are allowed to finish
+	stop, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		slog.Info("shutdown signal received")
+		cancel()
+	}()
+
+	// Metrics, gap report and backfill trigger
+	prometheus.MustRegister(collector.governor)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/gaps", collector.gapsHandler)
+	mux.HandleFunc("/backfill", collector.backfillHandler(stop))
+	mux.HandleFunc("/leader", collector.elector.statusHandler)
+	server := &http.Server{Addr: ":" + config.MetricsPort, Handler: mux}
+
+	go func() {
+		slog.Info("metrics endpoint listening", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("metrics server failed", "error", err)
+		}
+	}()
+
+	// Report this instance (and its leader ID) to the cluster status API
+	waitPresence := startPresence(stop, collector.redisClient, newPresence("data-collector", config.Leader.ID))
+
+	// Every instance is a warm standby; only the lease holder runs the collectors
+	collector.elector.Run(stop, shutdownTimeout, func(ctx, stop context.Context) {
+		var wg sync.WaitGroup
+		for _, run := range []func(ctx, stop context.Context){
+			collector.Start,
+			collector.startTollgateCollection,
+			collector.startRoadStatusCollection,
+		} {
+			wg.Add(1)
+			go func(run func(ctx, stop context.Context)) {
+				defer wg.Done()
+				run(ctx, stop)
+			}(run)
+		}
+		wg.Wait()
+	})
 
-	// Start all collectors in separate goroutines
-	go collector.Start(ctx)
-	go collector.startTollgateCollection(ctx)
-	go collector.startRoadStatusCollection(ctx)
+	waitPresence()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("metrics server shutdown", "error", err)
+	}
+	// No new backfill can start once the server is down; a running one stops at its next
+	// interval and must finish before the deferred Close
+	collector.backfills.Wait()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("tracing shutdown", "error", err)
+	}
 
-	// Block forever
-	select {}
+	slog.Info("data collector stopped")
 }
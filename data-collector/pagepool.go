@@ -0,0 +1,176 @@
+// This is the canonical copy of the page pool. openapi-collector/pagepool.go is a copy
+// kept in sync by hand (the services are separate modules); it differs only in also
+// treating errNotModified as not retryable. Make changes here first, then mirror them.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PagePoolConfig bounds concurrent page fetching and per-page retries
+type PagePoolConfig struct {
+	Workers     int           // pages fetched in parallel
+	MaxRetries  int           // retries per page after the first attempt
+	BaseBackoff time.Duration // delay before the first retry, doubled each retry
+	MaxBackoff  time.Duration // upper bound for a single retry delay
+}
+
+// PageReport summarises one paginated sweep
+type PageReport struct {
+	Expected       int
+	Fetched        int
+	Failed         int
+	Retries        int
+	FailedPages    []int
+	QuotaExhausted bool
+	Duration       time.Duration
+}
+
+// Complete reports whether every expected page was fetched and handled
+func (r PageReport) Complete() bool {
+	return r.Failed == 0 && r.Fetched == r.Expected
+}
+
+func (r PageReport) String() string {
+	s := fmt.Sprintf("pages expected=%d fetched=%d failed=%d retries=%d in %v",
+		r.Expected, r.Fetched, r.Failed, r.Retries, r.Duration.Round(time.Millisecond))
+	if len(r.FailedPages) > 0 {
+		s += fmt.Sprintf(", failed pages %v", r.FailedPages)
+	}
+	if r.QuotaExhausted {
+		s += " (daily quota exhausted)"
+	}
+	return s
+}
+
+// backoff returns the delay before retry attempt (1-based), with up to 50% jitter
+func (p PagePoolConfig) backoff(attempt int) time.Duration {
+	d := p.BaseBackoff << (attempt - 1)
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryable reports whether a failed page fetch is worth another attempt
+func retryable(err error) bool {
+	return !errors.Is(err, ErrQuotaExhausted) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
+}
+
+// fetchPages fetches pages with a bounded worker pool. Each page is fetched with
+// exponential backoff retries and then passed to handle; a handle error fails the
+// page without retrying. Once the daily quota is exhausted no new pages are started.
+func fetchPages[T any](ctx context.Context, config PagePoolConfig, pages []int,
+	fetch func(ctx context.Context, pageNo int) (T, error),
+	handle func(ctx context.Context, pageNo int, data T) error,
+) PageReport {
+	start := time.Now()
+	report := PageReport{Expected: len(pages)}
+
+	workers := config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(pages) {
+		workers = len(pages)
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var mu sync.Mutex
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pageNo := range jobs {
+				// The dispatcher may still hand out a page after the quota stop
+				if ctx.Err() != nil {
+					mu.Lock()
+					report.Failed++
+					report.FailedPages = append(report.FailedPages, pageNo)
+					mu.Unlock()
+					continue
+				}
+
+				data, retries, err := fetchWithRetry(ctx, config, pageNo, fetch)
+				if err == nil {
+					err = handle(ctx, pageNo, data)
+				}
+
+				mu.Lock()
+				report.Retries += retries
+				if err != nil {
+					report.Failed++
+					report.FailedPages = append(report.FailedPages, pageNo)
+					if errors.Is(err, ErrQuotaExhausted) && !report.QuotaExhausted {
+						report.QuotaExhausted = true
+						stop()
+					}
+				} else {
+					report.Fetched++
+				}
+				mu.Unlock()
+
+				if err != nil && !errors.Is(err, context.Canceled) {
+					slog.Warn("page failed", "page", pageNo, "error", err)
+				}
+			}
+		}()
+	}
+
+	for i, pageNo := range pages {
+		select {
+		case jobs <- pageNo:
+			continue
+		case <-ctx.Done():
+		}
+		// Pages never started count as failed so the report stays complete
+		mu.Lock()
+		report.Failed += len(pages) - i
+		report.FailedPages = append(report.FailedPages, pages[i:]...)
+		mu.Unlock()
+		break
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Ints(report.FailedPages)
+	report.Duration = time.Since(start)
+	return report
+}
+
+func fetchWithRetry[T any](ctx context.Context, config PagePoolConfig, pageNo int,
+	fetch func(ctx context.Context, pageNo int) (T, error),
+) (T, int, error) {
+	var data T
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		data, err = fetch(ctx, pageNo)
+		if err == nil || !retryable(err) || attempt >= config.MaxRetries {
+			return data, attempt, err
+		}
+
+		timer := time.NewTimer(config.backoff(attempt + 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return data, attempt, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Coordinate reference systems of Korean road network files. Most are published in a
+// Korea 2000 Transverse Mercator projection on the GRS80 ellipsoid; Korea 2000 matches
+// WGS84 to well under a metre, so only the projection is undone.
+
+// transverseMercator is the parameter set of one TM projection
+type transverseMercator struct {
+	Lat0, Lon0 float64 // origin, degrees
+	K0         float64 // scale factor at the central meridian
+	FE, FN     float64 // false easting and northing, metres
+}
+
+// koreaCRS lists the supported EPSG codes; 4326 (longitude/latitude) needs no projection
+var koreaCRS = map[int]*transverseMercator{
+	4326: nil,
+	5179: {Lat0: 38, Lon0: 127.5, K0: 0.9996, FE: 1000000, FN: 2000000}, // Korea 2000 / Unified CS
+	5181: {Lat0: 38, Lon0: 127, K0: 1, FE: 200000, FN: 500000},          // Korea 2000 / Central Belt
+	5185: {Lat0: 38, Lon0: 125, K0: 1, FE: 200000, FN: 600000},          // Korea 2000 / West Belt 2010
+	5186: {Lat0: 38, Lon0: 127, K0: 1, FE: 200000, FN: 600000},          // Korea 2000 / Central Belt 2010
+	5187: {Lat0: 38, Lon0: 129, K0: 1, FE: 200000, FN: 600000},          // Korea 2000 / East Belt 2010
+	5188: {Lat0: 38, Lon0: 131, K0: 1, FE: 200000, FN: 600000},          // Korea 2000 / East Sea Belt 2010
+}
+
+// GRS80
+const (
+	grs80A = 6378137.0
+	grs80F = 1 / 298.257222101
+)
+
+// toLonLat returns a function converting (x, y) of the EPSG code to (longitude, latitude)
+func toLonLat(epsg int) (func(x, y float64) (float64, float64), error) {
+	tm, ok := koreaCRS[epsg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported CRS EPSG:%d (use 4326, 5179, 5181, 5185, 5186, 5187 or 5188)", epsg)
+	}
+	if tm == nil {
+		return func(x, y float64) (float64, float64) { return x, y }, nil
+	}
+	return tm.inverse, nil
+}
+
+// meridianArc is the distance from the equator to latitude phi (radians)
+func meridianArc(phi, e2 float64) float64 {
+	e4, e6 := e2*e2, e2*e2*e2
+	return grs80A * ((1-e2/4-3*e4/64-5*e6/256)*phi -
+		(3*e2/8+3*e4/32+45*e6/1024)*math.Sin(2*phi) +
+		(15*e4/256+45*e6/1024)*math.Sin(4*phi) -
+		(35*e6/3072)*math.Sin(6*phi))
+}
+
+// inverse projects easting x and northing y back to degrees (Snyder, Map Projections:
+// A Working Manual, eq. 8-12 to 8-18)
+func (tm *transverseMercator) inverse(x, y float64) (float64, float64) {
+	e2 := grs80F * (2 - grs80F)
+	ep2 := e2 / (1 - e2)
+	lat0 := tm.Lat0 * math.Pi / 180
+
+	m := meridianArc(lat0, e2) + (y-tm.FN)/tm.K0
+	mu := m / (grs80A * (1 - e2/4 - 3*e2*e2/64 - 5*e2*e2*e2/256))
+	e1 := (1 - math.Sqrt(1-e2)) / (1 + math.Sqrt(1-e2))
+	phi1 := mu + (3*e1/2-27*math.Pow(e1, 3)/32)*math.Sin(2*mu) +
+		(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+		(151*math.Pow(e1, 3)/96)*math.Sin(6*mu) +
+		(1097*math.Pow(e1, 4)/512)*math.Sin(8*mu)
+
+	sin, cos, tan := math.Sin(phi1), math.Cos(phi1), math.Tan(phi1)
+	c1 := ep2 * cos * cos
+	t1 := tan * tan
+	n1 := grs80A / math.Sqrt(1-e2*sin*sin)
+	r1 := grs80A * (1 - e2) / math.Pow(1-e2*sin*sin, 1.5)
+	d := (x - tm.FE) / (n1 * tm.K0)
+
+	lat := phi1 - (n1*tan/r1)*(d*d/2-
+		(5+3*t1+10*c1-4*c1*c1-9*ep2)*math.Pow(d, 4)/24+
+		(61+90*t1+298*c1+45*t1*t1-252*ep2-3*c1*c1)*math.Pow(d, 6)/720)
+	lon := (d - (1+2*t1+c1)*math.Pow(d, 3)/6 +
+		(5-2*c1+28*t1-3*c1*c1+8*ep2+24*t1*t1)*math.Pow(d, 5)/120) / cos
+
+	return tm.Lon0 + lon*180/math.Pi, lat * 180 / math.Pi
+}
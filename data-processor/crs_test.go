@@ -0,0 +1,92 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// forward projects degrees to easting and northing (Snyder eq. 8-9 to 8-11), to check
+// inverse against
+func (tm *transverseMercator) forward(lon, lat float64) (float64, float64) {
+	e2 := grs80F * (2 - grs80F)
+	ep2 := e2 / (1 - e2)
+	phi := lat * math.Pi / 180
+	sin, cos, tan := math.Sin(phi), math.Cos(phi), math.Tan(phi)
+	n := grs80A / math.Sqrt(1-e2*sin*sin)
+	t := tan * tan
+	c := ep2 * cos * cos
+	a := (lon - tm.Lon0) * math.Pi / 180 * cos
+	m, m0 := meridianArc(phi, e2), meridianArc(tm.Lat0*math.Pi/180, e2)
+
+	x := tm.K0 * n * (a + (1-t+c)*math.Pow(a, 3)/6 + (5-18*t+t*t+72*c-58*ep2)*math.Pow(a, 5)/120)
+	y := tm.K0 * (m - m0 + n*tan*(a*a/2+(5-t+9*c+4*c*c)*math.Pow(a, 4)/24+
+		(61-58*t+t*t+600*c-330*ep2)*math.Pow(a, 6)/720))
+	return x + tm.FE, y + tm.FN
+}
+
+func TestToLonLat(t *testing.T) {
+	tests := []struct {
+		name     string
+		epsg     int
+		x, y     float64
+		lon, lat float64
+	}{
+		{name: "WGS84 is passed through", epsg: 4326, x: 126.9779692, y: 37.566535, lon: 126.9779692, lat: 37.566535},
+		{name: "origin of the Unified CS", epsg: 5179, x: 1000000, y: 2000000, lon: 127.5, lat: 38},
+		{name: "origin of the Central Belt", epsg: 5181, x: 200000, y: 500000, lon: 127, lat: 38},
+		{name: "origin of the West Belt 2010", epsg: 5185, x: 200000, y: 600000, lon: 125, lat: 38},
+		{name: "origin of the Central Belt 2010", epsg: 5186, x: 200000, y: 600000, lon: 127, lat: 38},
+		{name: "origin of the East Belt 2010", epsg: 5187, x: 200000, y: 600000, lon: 129, lat: 38},
+		{name: "origin of the East Sea Belt 2010", epsg: 5188, x: 200000, y: 600000, lon: 131, lat: 38},
+		// One degree of latitude is about 111 km at 38°N
+		{name: "north along the central meridian", epsg: 5186, x: 200000, y: 600000 + 111050, lon: 127, lat: 39},
+	}
+	for _, tt := range tests {
+		project, err := toLonLat(tt.epsg)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		lon, lat := project(tt.x, tt.y)
+		if math.Abs(lon-tt.lon) > 1e-9 || math.Abs(lat-tt.lat) > 0.01 {
+			t.Errorf("%s: (%v, %v) = (%v, %v), want (%v, %v)", tt.name, tt.x, tt.y, lon, lat, tt.lon, tt.lat)
+		}
+	}
+
+	for _, epsg := range []int{0, 3857, 2097} {
+		if _, err := toLonLat(epsg); err == nil {
+			t.Errorf("EPSG:%d accepted", epsg)
+		}
+	}
+}
+
+// inverse undoes forward to well under a centimetre across the peninsula
+func TestTransverseMercatorRoundTrip(t *testing.T) {
+	points := [][2]float64{
+		{126.9779692, 37.566535},  // Seoul City Hall
+		{129.0756416, 35.1795543}, // Busan
+		{126.5311884, 33.4996213}, // Jeju
+		{128.8760574, 37.7519133}, // Gangneung
+		{124.7, 37.9},             // Baengnyeongdo, far west of the belts
+	}
+	for epsg, tm := range koreaCRS {
+		if tm == nil {
+			continue
+		}
+		project, err := toLonLat(epsg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range points {
+			// Points far from a belt's meridian are still converted, less exactly
+			tolerance := 1e-7
+			if math.Abs(p[0]-tm.Lon0) > 3 {
+				tolerance = 1e-5
+			}
+			x, y := tm.forward(p[0], p[1])
+			lon, lat := project(x, y)
+			if math.Abs(lon-p[0]) > tolerance || math.Abs(lat-p[1]) > tolerance {
+				t.Errorf("EPSG:%d: %v -> (%.1f, %.1f) -> (%v, %v)", epsg, p, x, y, lon, lat)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fencingScript records the highest collector fencing token seen and rejects older ones.
+// KEYS[1] highest accepted token, ARGV[1] token of the message (0 when it has none)
+// Returns 1 when the token is current (or newer), 0 when it is stale. An unfenced message
+// is stale as soon as any fenced leader has been seen.
+var fencingScript = redis.NewScript(`
+local highest = tonumber(redis.call('GET', KEYS[1]) or '0')
+local token = tonumber(ARGV[1])
+if token < highest then
+	return 0
+end
+if token > highest then
+	redis.call('SET', KEYS[1], token)
+end
+return 1
+`)
+
+// checkFencingToken reports whether a message may be written. The highest token is kept
+// in Redis so every processor replica fences against the same leader term. Messages
+// without a token (collectors running without leader election) are accepted only until a
+// fenced leader has been seen, or always with AcceptUnfenced.
+func (p *Processor) checkFencingToken(ctx context.Context, msg redis.XMessage) (bool, int64, error) {
+	var token int64
+	if raw, ok := msg.Values["fencing_token"]; ok {
+		var err error
+		token, err = strconv.ParseInt(fmt.Sprint(raw), 10, 64)
+		if err != nil {
+			return false, 0, fmt.Errorf("%w: invalid fencing token %v: %w", errInvalidMessage, raw, err)
+		}
+	}
+	if token <= 0 && p.config.AcceptUnfenced {
+		return true, 0, nil
+	}
+	if token < 0 {
+		token = 0
+	}
+
+	accepted, err := fencingScript.Run(ctx, p.redisClient, []string{p.config.FencingKey}, token).Int()
+	if err != nil {
+		return false, token, fmt.Errorf("failed to check fencing token: %w", err)
+	}
+	return accepted == 1, token, nil
+}
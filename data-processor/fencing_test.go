@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// The Lua script needs Redis; these cases are decided before it runs or fail closed
+// when it cannot
+func TestCheckFencingToken(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	defer rdb.Close()
+
+	tests := []struct {
+		name           string
+		values         map[string]interface{}
+		acceptUnfenced bool
+		wantOK         bool
+		wantToken      int64
+		wantErr        bool
+	}{
+		{name: "unfenced accepted when configured", values: map[string]interface{}{}, acceptUnfenced: true, wantOK: true},
+		{name: "zero token accepted when configured", values: map[string]interface{}{"fencing_token": "0"}, acceptUnfenced: true, wantOK: true},
+		{name: "negative token accepted when configured", values: map[string]interface{}{"fencing_token": "-3"}, acceptUnfenced: true, wantOK: true},
+		{name: "invalid token", values: map[string]interface{}{"fencing_token": "abc"}, acceptUnfenced: true, wantErr: true},
+		{name: "unfenced checked against Redis", values: map[string]interface{}{}, wantErr: true},
+		{name: "fenced checked against Redis", values: map[string]interface{}{"fencing_token": "7"}, acceptUnfenced: true, wantToken: 7, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Processor{
+				config:      Config{FencingKey: "test:fencing", AcceptUnfenced: tt.acceptUnfenced},
+				redisClient: rdb,
+			}
+			ok, token, err := p.checkFencingToken(context.Background(), redis.XMessage{ID: "1-0", Values: tt.values})
+			if (err != nil) != tt.wantErr || ok != tt.wantOK || token != tt.wantToken {
+				t.Errorf("checkFencingToken = %v, %d, %v; want %v, %d, error %v",
+					ok, token, err, tt.wantOK, tt.wantToken, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,659 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonas-p/go-shp"
+	"golang.org/x/text/encoding/korean"
+)
+
+// Road network geometry import: conzone polylines into road_conzone_geometry and VDS
+// locations into road_vds_location, from a Shapefile, GeoJSON or CSV file. The OpenAPIs
+// carry no geometry, so the operator loads published road network files by hand and
+// re-runs the import when the network changes.
+
+// geoRecord is one feature of an input file: its attributes by lower-cased name and its
+// coordinates in the file's CRS (one for a point, two or more for a line)
+type geoRecord struct {
+	Attrs  map[string]string
+	Coords [][2]float64
+}
+
+// roadFeature is one row to import, with coordinates as [longitude, latitude]
+type roadFeature struct {
+	ID        string
+	RouteNo   string
+	ConzoneID string // VDS only
+	Coords    [][2]float64
+}
+
+// geometryFields names the attributes to read; empty names fall back to common ones
+type geometryFields struct {
+	ID, Route, Conzone, Lon, Lat string
+}
+
+// attr returns the first attribute present among the override or the candidates
+func attr(attrs map[string]string, override string, candidates ...string) (string, bool) {
+	if override != "" {
+		candidates = []string{override}
+	}
+	for _, name := range candidates {
+		if v, ok := attrs[strings.ToLower(name)]; ok {
+			return strings.TrimSpace(v), true
+		}
+	}
+	return "", false
+}
+
+// textDecoder converts Shapefile attributes to UTF-8; Korean files are often CP949
+func textDecoder(encoding string) (func(string) string, error) {
+	switch strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(strings.TrimSpace(encoding))) {
+	case "", "utf8":
+		return func(s string) string { return s }, nil
+	case "cp949", "ms949", "euckr", "949":
+		decoder := korean.EUCKR.NewDecoder()
+		return func(s string) string {
+			if out, err := decoder.String(s); err == nil {
+				return out
+			}
+			return s
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q (use utf-8 or cp949)", encoding)
+}
+
+// readShapefile reads a .shp and its .dbf attribute table. The encoding defaults to the
+// .cpg file next to it.
+func readShapefile(path, encoding string) ([]geoRecord, error) {
+	if encoding == "" {
+		if cpg, err := os.ReadFile(strings.TrimSuffix(path, filepath.Ext(path)) + ".cpg"); err == nil {
+			encoding = string(cpg)
+		}
+	}
+	decode, err := textDecoder(encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := shp.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open shapefile: %w", err)
+	}
+	defer r.Close()
+
+	fields := r.Fields()
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no attribute table (.dbf) next to %s", path)
+	}
+
+	var records []geoRecord
+	for r.Next() {
+		n, shape := r.Shape()
+		rec := geoRecord{Attrs: make(map[string]string, len(fields))}
+		for i, f := range fields {
+			rec.Attrs[strings.ToLower(f.String())] = decode(strings.Trim(r.ReadAttribute(n, i), " \x00"))
+		}
+
+		var points []shp.Point
+		switch s := shape.(type) {
+		case *shp.Null:
+			continue
+		case *shp.Point:
+			points = []shp.Point{*s}
+		case *shp.PointZ:
+			points = []shp.Point{{X: s.X, Y: s.Y}}
+		case *shp.PointM:
+			points = []shp.Point{{X: s.X, Y: s.Y}}
+		case *shp.PolyLine:
+			points = s.Points
+		case *shp.PolyLineZ:
+			points = s.Points
+		case *shp.PolyLineM:
+			points = s.Points
+		default:
+			return nil, fmt.Errorf("record %d: unsupported shape type %T (use points or polylines)", n+1, shape)
+		}
+		// Parts of a polyline are joined in order
+		for _, p := range points {
+			rec.Coords = append(rec.Coords, [2]float64{p.X, p.Y})
+		}
+		records = append(records, rec)
+	}
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read shapefile: %w", err)
+	}
+	return records, nil
+}
+
+// readGeoJSON reads the Point, LineString and MultiLineString features of a
+// FeatureCollection
+func readGeoJSON(in io.Reader) ([]geoRecord, error) {
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry *struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(in).Decode(&fc); err != nil {
+		return nil, fmt.Errorf("failed to parse GeoJSON: %w", err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("GeoJSON must be a FeatureCollection, got %q", fc.Type)
+	}
+
+	records := make([]geoRecord, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		if f.Geometry == nil {
+			continue
+		}
+		rec := geoRecord{Attrs: make(map[string]string, len(f.Properties))}
+		for k, v := range f.Properties {
+			switch v := v.(type) {
+			case nil:
+				rec.Attrs[strings.ToLower(k)] = ""
+			case string:
+				rec.Attrs[strings.ToLower(k)] = v
+			case float64:
+				rec.Attrs[strings.ToLower(k)] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				rec.Attrs[strings.ToLower(k)] = fmt.Sprint(v)
+			}
+		}
+
+		var err error
+		switch f.Geometry.Type {
+		case "Point":
+			var p []float64
+			if err = json.Unmarshal(f.Geometry.Coordinates, &p); err == nil {
+				rec.Coords, err = positions([][]float64{p})
+			}
+		case "LineString":
+			var line [][]float64
+			if err = json.Unmarshal(f.Geometry.Coordinates, &line); err == nil {
+				rec.Coords, err = positions(line)
+			}
+		case "MultiLineString":
+			var lines [][][]float64
+			if err = json.Unmarshal(f.Geometry.Coordinates, &lines); err == nil {
+				for _, line := range lines {
+					var part [][2]float64
+					if part, err = positions(line); err != nil {
+						break
+					}
+					rec.Coords = append(rec.Coords, part...)
+				}
+			}
+		default:
+			err = fmt.Errorf("unsupported geometry type %q (use points or lines)", f.Geometry.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i+1, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// positions keeps x and y of GeoJSON positions, dropping any elevation
+func positions(coords [][]float64) ([][2]float64, error) {
+	out := make([][2]float64, 0, len(coords))
+	for _, c := range coords {
+		if len(c) < 2 {
+			return nil, fmt.Errorf("position needs x and y, got %v", c)
+		}
+		out = append(out, [2]float64{c[0], c[1]})
+	}
+	return out, nil
+}
+
+// readGeometryCSV reads one feature per row, with the geometry in a "wkt" column
+// (POINT, LINESTRING or MULTILINESTRING) or as a point in longitude/latitude columns.
+// Lines may also be given as one row per vertex sharing an ID, ordered by a "seq" column.
+func readGeometryCSV(in io.Reader, fields geometryFields) ([]geoRecord, error) {
+	cr := csv.NewReader(in)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	names := make([]string, len(header))
+	for i, name := range header {
+		names[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	}
+
+	var records []geoRecord
+	line := 1
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV line %d: %w", line, err)
+		}
+
+		rec := geoRecord{Attrs: make(map[string]string, len(names))}
+		for i, name := range names {
+			if i < len(row) {
+				rec.Attrs[name] = row[i]
+			}
+		}
+
+		if wkt, _ := attr(rec.Attrs, "", "wkt", "geometry", "geom"); wkt != "" {
+			if rec.Coords, err = parseWKT(wkt); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		} else {
+			lonStr, okLon := attr(rec.Attrs, fields.Lon, "longitude", "lon", "lng", "x")
+			latStr, okLat := attr(rec.Attrs, fields.Lat, "latitude", "lat", "y")
+			if !okLon || !okLat {
+				return nil, fmt.Errorf("CSV needs a wkt column or longitude/latitude columns")
+			}
+			lon, err1 := strconv.ParseFloat(lonStr, 64)
+			lat, err2 := strconv.ParseFloat(latStr, 64)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("line %d: invalid coordinates %q, %q", line, lonStr, latStr)
+			}
+			rec.Coords = [][2]float64{{lon, lat}}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// parseWKT reads the vertices of a POINT, LINESTRING or MULTILINESTRING (parts joined in
+// order), ignoring Z and M values
+func parseWKT(wkt string) ([][2]float64, error) {
+	wkt = strings.TrimSpace(wkt)
+	open := strings.Index(wkt, "(")
+	if open < 0 {
+		return nil, fmt.Errorf("invalid WKT %q", wkt)
+	}
+	kind := strings.Fields(strings.ToUpper(wkt[:open]))
+	if len(kind) == 0 || (kind[0] != "POINT" && kind[0] != "LINESTRING" && kind[0] != "MULTILINESTRING") {
+		return nil, fmt.Errorf("unsupported WKT geometry %q (use POINT, LINESTRING or MULTILINESTRING)", wkt[:open])
+	}
+
+	body := strings.NewReplacer("(", "", ")", "").Replace(wkt[open:])
+	var coords [][2]float64
+	for _, vertex := range strings.Split(body, ",") {
+		xy := strings.Fields(vertex)
+		if len(xy) < 2 {
+			return nil, fmt.Errorf("invalid WKT vertex %q", vertex)
+		}
+		x, err1 := strconv.ParseFloat(xy[0], 64)
+		y, err2 := strconv.ParseFloat(xy[1], 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid WKT vertex %q", vertex)
+		}
+		coords = append(coords, [2]float64{x, y})
+	}
+	return coords, nil
+}
+
+// inKorea rejects coordinates outside South Korea, which usually means a wrong -crs
+func inKorea(lon, lat float64) bool {
+	return lat >= 33 && lat <= 39 && lon >= 124 && lon <= 132
+}
+
+// roadFeatures maps records to rows of kind ("conzone" or "vds"). Records sharing an ID
+// are joined in file order, or by their "seq" attribute when present. Coordinates are
+// converted with project and features outside Korea are dropped.
+func roadFeatures(kind string, records []geoRecord, fields geometryFields,
+	project func(x, y float64) (float64, float64)) ([]roadFeature, error) {
+	idCandidates := []string{"conzone_id", "conzoneid", "cz_id"}
+	if kind == "vds" {
+		idCandidates = []string{"vds_id", "vdsid"}
+	}
+
+	type group struct {
+		feature roadFeature
+		records []geoRecord
+	}
+	var order []string
+	groups := make(map[string]*group)
+	missingID := 0
+	for _, rec := range records {
+		id, _ := attr(rec.Attrs, fields.ID, idCandidates...)
+		if id == "" {
+			missingID++
+			continue
+		}
+		g, ok := groups[id]
+		if !ok {
+			g = &group{feature: roadFeature{ID: id}}
+			groups[id] = g
+			order = append(order, id)
+		}
+		if route, _ := attr(rec.Attrs, fields.Route, "route_no", "routeno", "route_cd", "road_no"); route != "" {
+			g.feature.RouteNo = route
+		}
+		if kind == "vds" {
+			if conzone, _ := attr(rec.Attrs, fields.Conzone, "conzone_id", "conzoneid"); conzone != "" {
+				g.feature.ConzoneID = conzone
+			}
+		}
+		g.records = append(g.records, rec)
+	}
+
+	logger := componentLogger("geometry")
+	if missingID > 0 {
+		logger.Warn("skipped records without an ID", "records", missingID, "kind", kind)
+	}
+
+	features := make([]roadFeature, 0, len(order))
+	var outside, invalid int
+	for _, id := range order {
+		g := groups[id]
+		sort.SliceStable(g.records, func(i, j int) bool {
+			a, _ := strconv.ParseFloat(g.records[i].Attrs["seq"], 64)
+			b, _ := strconv.ParseFloat(g.records[j].Attrs["seq"], 64)
+			return a < b
+		})
+
+		f := g.feature
+		ok := true
+		for _, rec := range g.records {
+			for _, c := range rec.Coords {
+				lon, lat := project(c[0], c[1])
+				if !inKorea(lon, lat) {
+					ok = false
+				}
+				// Joined parts usually share their end vertex
+				if n := len(f.Coords); n > 0 && f.Coords[n-1] == [2]float64{lon, lat} {
+					continue
+				}
+				f.Coords = append(f.Coords, [2]float64{lon, lat})
+			}
+		}
+		switch {
+		case !ok:
+			outside++
+		case kind == "conzone" && len(f.Coords) < 2, kind == "vds" && len(f.Coords) != 1:
+			invalid++
+		default:
+			features = append(features, f)
+		}
+	}
+	if invalid > 0 {
+		logger.Warn("skipped features with the wrong geometry (conzones need lines, VDS points)",
+			"features", invalid, "kind", kind)
+	}
+	if outside > 0 {
+		logger.Warn("skipped features outside Korea", "features", outside, "kind", kind)
+		if len(features) == 0 {
+			return nil, fmt.Errorf("no feature lies in Korea; check -crs")
+		}
+	}
+	return features, nil
+}
+
+func lineWKT(coords [][2]float64) string {
+	var b strings.Builder
+	b.WriteString("LINESTRING(")
+	for i, c := range coords {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%.7f %.7f", c[0], c[1])
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// importRoadFeatures upserts features in one transaction, after deleting the table's rows
+// when replace is set, so readers never see a half-loaded network
+func importRoadFeatures(ctx context.Context, db *sql.DB, kind string, features []roadFeature, replace bool) (int, error) {
+	table := "road_conzone_geometry"
+	query := `INSERT INTO road_conzone_geometry (conzone_id, route_no, geom)
+		VALUES (?, ?, ST_GeomFromText(?))
+		ON DUPLICATE KEY UPDATE route_no = VALUES(route_no), geom = VALUES(geom)`
+	if kind == "vds" {
+		table = "road_vds_location"
+		query = `INSERT INTO road_vds_location (vds_id, route_no, conzone_id, latitude, longitude)
+			VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE route_no = VALUES(route_no), conzone_id = VALUES(conzone_id),
+			latitude = VALUES(latitude), longitude = VALUES(longitude)`
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if replace {
+		result, err := tx.ExecContext(ctx, "DELETE FROM "+table)
+		if err != nil {
+			return 0, fmt.Errorf("failed to clear %s: %w", table, err)
+		}
+		deleted, _ := result.RowsAffected()
+		componentLogger("geometry").Info("removed existing rows", "rows", deleted, "table", table)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, f := range features {
+		if kind == "vds" {
+			_, err = stmt.ExecContext(ctx, f.ID, f.RouteNo, f.ConzoneID, f.Coords[0][1], f.Coords[0][0])
+		} else {
+			_, err = stmt.ExecContext(ctx, f.ID, f.RouteNo, lineWKT(f.Coords))
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to import %s %s: %w", kind, f.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(features), nil
+}
+
+// completeFromStatus fills route numbers (and VDS conzones) the file did not carry from
+// the last week of road_traffic_status
+func completeFromStatus(ctx context.Context, db *sql.DB, kind string) (int64, error) {
+	query := `UPDATE road_conzone_geometry g
+		JOIN (
+			SELECT conzone_id, MIN(route_no) as route_no
+			FROM road_traffic_status
+			WHERE collected_at >= NOW() - INTERVAL 7 DAY
+			GROUP BY conzone_id
+		) r ON r.conzone_id = g.conzone_id
+		SET g.route_no = r.route_no
+		WHERE g.route_no = ''`
+	if kind == "vds" {
+		query = `UPDATE road_vds_location v
+			JOIN (
+				SELECT vds_id, MIN(route_no) as route_no, MIN(conzone_id) as conzone_id
+				FROM road_traffic_status
+				WHERE collected_at >= NOW() - INTERVAL 7 DAY
+				GROUP BY vds_id
+			) r ON r.vds_id = v.vds_id
+			SET v.route_no = IF(v.route_no = '', r.route_no, v.route_no),
+				v.conzone_id = IF(v.conzone_id = '', r.conzone_id, v.conzone_id)
+			WHERE v.route_no = '' OR v.conzone_id = ''`
+	}
+	result, err := db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// geometryCoverage counts the conzones or VDSs reported in the last day and how many of
+// them now have geometry, to catch ID mismatches between the file and the OpenAPI
+func geometryCoverage(ctx context.Context, db *sql.DB, kind string) (total, located int, err error) {
+	query := `SELECT COUNT(DISTINCT r.conzone_id), COUNT(DISTINCT g.conzone_id)
+		FROM road_traffic_status r
+		LEFT JOIN road_conzone_geometry g ON g.conzone_id = r.conzone_id
+		WHERE r.collected_at >= NOW() - INTERVAL 1 DAY`
+	if kind == "vds" {
+		query = `SELECT COUNT(DISTINCT r.vds_id), COUNT(DISTINCT v.vds_id)
+			FROM road_traffic_status r
+			LEFT JOIN road_vds_location v ON v.vds_id = r.vds_id
+			WHERE r.collected_at >= NOW() - INTERVAL 1 DAY`
+	}
+	err = db.QueryRowContext(ctx, query).Scan(&total, &located)
+	return total, located, err
+}
+
+// geometryFormat resolves the file format from the -format flag or the file extension
+func geometryFormat(format, path string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".shp":
+			format = "shp"
+		case ".csv":
+			format = "csv"
+		default:
+			format = "geojson"
+		}
+	}
+
+	format = strings.ToLower(format)
+	if format != "shp" && format != "geojson" && format != "csv" {
+		return "", fmt.Errorf("unsupported format %q (use shp, geojson or csv)", format)
+	}
+	return format, nil
+}
+
+func geometryUsage() {
+	fmt.Fprintln(os.Stderr, `Usage: data-processor geometry <command> [flags]
+
+Commands:
+  import    Load conzone polylines into road_conzone_geometry or VDS points into
+            road_vds_location from a Shapefile (.shp with .dbf), GeoJSON or CSV file
+  match     Assign accidents to the nearest route, conzone and direction
+
+Run 'data-processor geometry <command> -h' for command flags.`)
+}
+
+// runGeometryCommand implements the "geometry" subcommand family
+func runGeometryCommand(config Config, args []string) error {
+	if len(args) == 0 {
+		geometryUsage()
+		return fmt.Errorf("missing geometry command")
+	}
+
+	switch args[0] {
+	case "import":
+		return runGeometryImport(config, args[1:])
+	case "match":
+		return runGeometryMatch(config, args[1:])
+	}
+	geometryUsage()
+	return fmt.Errorf("unknown geometry command %q", args[0])
+}
+
+// runGeometryImport implements "geometry import"
+func runGeometryImport(config Config, args []string) error {
+	fs := flag.NewFlagSet("geometry import", flag.ExitOnError)
+	kind := fs.String("kind", "", "conzone (polylines) or vds (points)")
+	path := fs.String("in", "", "input file: .shp, .geojson/.json or .csv")
+	format := fs.String("format", "", "shp, geojson or csv (default: from file extension)")
+	epsg := fs.Int("crs", 4326, "EPSG code of the coordinates: 4326 (lon/lat), 5179, 5181, 5185-5188 (Korea 2000 TM)")
+	encoding := fs.String("encoding", "", "Shapefile attribute encoding, utf-8 or cp949 (default: from .cpg, else utf-8)")
+	var fields geometryFields
+	fs.StringVar(&fields.ID, "id-field", "", "attribute with the conzone or VDS ID (default: conzone_id or vds_id)")
+	fs.StringVar(&fields.Route, "route-field", "", "attribute with the route number (default: route_no)")
+	fs.StringVar(&fields.Conzone, "conzone-field", "", "attribute with a VDS's conzone ID (default: conzone_id)")
+	fs.StringVar(&fields.Lon, "lon-field", "", "CSV longitude or x column (default: longitude, lon, lng or x)")
+	fs.StringVar(&fields.Lat, "lat-field", "", "CSV latitude or y column (default: latitude, lat or y)")
+	replace := fs.Bool("replace", false, "delete the table's existing rows before importing")
+	dryRun := fs.Bool("dry-run", false, "parse and validate the file without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *kind != "conzone" && *kind != "vds" {
+		return fmt.Errorf("-kind must be conzone or vds")
+	}
+	if *path == "" {
+		return fmt.Errorf("-in is required")
+	}
+	fmtName, err := geometryFormat(*format, *path)
+	if err != nil {
+		return err
+	}
+	project, err := toLonLat(*epsg)
+	if err != nil {
+		return err
+	}
+
+	var records []geoRecord
+	if fmtName == "shp" {
+		records, err = readShapefile(*path, *encoding)
+	} else {
+		f, openErr := os.Open(*path)
+		if openErr != nil {
+			return fmt.Errorf("failed to open input file: %w", openErr)
+		}
+		defer f.Close()
+		if fmtName == "csv" {
+			records, err = readGeometryCSV(f, fields)
+		} else {
+			records, err = readGeoJSON(f)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	features, err := roadFeatures(*kind, records, fields, project)
+	if err != nil {
+		return err
+	}
+	logger := componentLogger("geometry")
+	logger.Info("parsed features", "kind", *kind, "format", fmtName, "crs", *epsg,
+		"records", len(records), "features", len(features))
+	if *dryRun || len(features) == 0 {
+		return nil
+	}
+
+	db, err := openDB(config)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	imported, err := importRoadFeatures(ctx, db, *kind, features, *replace)
+	if err != nil {
+		return err
+	}
+	completed, err := completeFromStatus(ctx, db, *kind)
+	if err != nil {
+		logger.Warn("failed to fill route numbers from road_traffic_status", "error", err)
+	}
+	logger.Info("imported features", "kind", *kind, "features", imported, "completed_from_status", completed)
+
+	if total, located, err := geometryCoverage(ctx, db, *kind); err != nil {
+		logger.Warn("failed to check coverage", "error", err)
+	} else {
+		logger.Info("coverage of the last day's road status", "kind", *kind, "reported", total, "located", located)
+	}
+	return nil
+}
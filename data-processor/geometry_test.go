@@ -0,0 +1,293 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/korean"
+)
+
+func TestAttr(t *testing.T) {
+	attrs := map[string]string{"conzone_id": " 0010CZE010 ", "route_no": "0010", "empty": ""}
+	tests := []struct {
+		override   string
+		candidates []string
+		want       string
+		wantOK     bool
+	}{
+		{candidates: []string{"conzoneid", "conzone_id"}, want: "0010CZE010", wantOK: true},
+		{candidates: []string{"CONZONE_ID"}, want: "0010CZE010", wantOK: true},
+		{override: "Route_No", candidates: []string{"conzone_id"}, want: "0010", wantOK: true},
+		{override: "missing", candidates: []string{"conzone_id"}},
+		{candidates: []string{"empty"}, wantOK: true},
+		{candidates: []string{"vds_id"}},
+	}
+	for _, tt := range tests {
+		got, ok := attr(attrs, tt.override, tt.candidates...)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("attr(%q, %v) = %q, %v; want %q, %v", tt.override, tt.candidates, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestTextDecoder(t *testing.T) {
+	cp949, err := korean.EUCKR.NewEncoder().String("경부선")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		encoding string
+		in       string
+		want     string
+		wantErr  bool
+	}{
+		{encoding: "", in: "경부선", want: "경부선"},
+		{encoding: "UTF-8", in: "경부선", want: "경부선"},
+		{encoding: "cp949", in: cp949, want: "경부선"},
+		{encoding: "EUC-KR", in: cp949, want: "경부선"},
+		{encoding: "latin1", wantErr: true},
+	}
+	for _, tt := range tests {
+		decode, err := textDecoder(tt.encoding)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("textDecoder(%q) error = %v, want error %v", tt.encoding, err, tt.wantErr)
+			continue
+		}
+		if err == nil && decode(tt.in) != tt.want {
+			t.Errorf("textDecoder(%q) decoded %q, want %q", tt.encoding, decode(tt.in), tt.want)
+		}
+	}
+}
+
+func TestParseWKT(t *testing.T) {
+	tests := []struct {
+		wkt     string
+		want    [][2]float64
+		wantErr bool
+	}{
+		{wkt: "POINT(127.1 37.5)", want: [][2]float64{{127.1, 37.5}}},
+		{wkt: " point ( 127.1 37.5 ) ", want: [][2]float64{{127.1, 37.5}}},
+		{wkt: "POINT Z (127.1 37.5 12)", want: [][2]float64{{127.1, 37.5}}},
+		{wkt: "LINESTRING(127 37, 127.1 37.1, 127.2 37.15)", want: [][2]float64{{127, 37}, {127.1, 37.1}, {127.2, 37.15}}},
+		{wkt: "MULTILINESTRING((127 37, 127.1 37.1), (127.1 37.1, 127.2 37.2))", want: [][2]float64{{127, 37}, {127.1, 37.1}, {127.1, 37.1}, {127.2, 37.2}}},
+		{wkt: "LINESTRING ZM (953898.2 1952035.4 0 1, 954000 1952100 0 2)", want: [][2]float64{{953898.2, 1952035.4}, {954000, 1952100}}},
+		{wkt: "127 37", wantErr: true},
+		{wkt: "POLYGON((127 37, 128 37, 128 38, 127 37))", wantErr: true},
+		{wkt: "(127 37)", wantErr: true},
+		{wkt: "POINT(127)", wantErr: true},
+		{wkt: "LINESTRING(127 37, east 37)", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseWKT(tt.wkt)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseWKT(%q) = %v, %v; want %v, error %v", tt.wkt, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLineWKT(t *testing.T) {
+	got := lineWKT([][2]float64{{127, 37.5}, {127.12345678, 37.6}})
+	if want := "LINESTRING(127.0000000 37.5000000, 127.1234568 37.6000000)"; got != want {
+		t.Errorf("lineWKT = %s, want %s", got, want)
+	}
+	if coords, err := parseWKT(got); err != nil || len(coords) != 2 {
+		t.Errorf("lineWKT output does not parse back: %v, %v", coords, err)
+	}
+}
+
+func TestReadGeoJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []geoRecord
+		wantErr bool
+	}{
+		{
+			name: "points and lines",
+			in: `{"type":"FeatureCollection","features":[
+				{"type":"Feature","geometry":{"type":"Point","coordinates":[127.1,37.5,30]},"properties":{"VDS_ID":"0010VDE01","seq":2,"flag":true,"note":null}},
+				{"type":"Feature","geometry":{"type":"LineString","coordinates":[[127,37],[127.1,37.1]]},"properties":{"conzone_id":"CZ1"}},
+				{"type":"Feature","geometry":{"type":"MultiLineString","coordinates":[[[127,37],[127.1,37.1]],[[127.1,37.1],[127.2,37.2]]]},"properties":{}},
+				{"type":"Feature","geometry":null,"properties":{"conzone_id":"skipped"}}
+			]}`,
+			want: []geoRecord{
+				{Attrs: map[string]string{"vds_id": "0010VDE01", "seq": "2", "flag": "true", "note": ""}, Coords: [][2]float64{{127.1, 37.5}}},
+				{Attrs: map[string]string{"conzone_id": "CZ1"}, Coords: [][2]float64{{127, 37}, {127.1, 37.1}}},
+				{Attrs: map[string]string{}, Coords: [][2]float64{{127, 37}, {127.1, 37.1}, {127.1, 37.1}, {127.2, 37.2}}},
+			},
+		},
+		{name: "not a collection", in: `{"type":"Feature"}`, wantErr: true},
+		{name: "invalid JSON", in: `{"type":`, wantErr: true},
+		{name: "polygon", in: `{"type":"FeatureCollection","features":[{"geometry":{"type":"Polygon","coordinates":[]}}]}`, wantErr: true},
+		{name: "short position", in: `{"type":"FeatureCollection","features":[{"geometry":{"type":"Point","coordinates":[127]}}]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := readGeoJSON(strings.NewReader(tt.in))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: records = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadGeometryCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		fields  geometryFields
+		want    []geoRecord
+		wantErr bool
+	}{
+		{
+			name: "wkt column",
+			in:   "\ufeffConzone_ID,WKT\nCZ1,\"LINESTRING(127 37, 127.1 37.1)\"\n",
+			want: []geoRecord{{Attrs: map[string]string{"conzone_id": "CZ1", "wkt": "LINESTRING(127 37, 127.1 37.1)"}, Coords: [][2]float64{{127, 37}, {127.1, 37.1}}}},
+		},
+		{
+			name: "longitude and latitude columns",
+			in:   "vds_id,lng,lat\nV1,127.1,37.5\n",
+			want: []geoRecord{{Attrs: map[string]string{"vds_id": "V1", "lng": "127.1", "lat": "37.5"}, Coords: [][2]float64{{127.1, 37.5}}}},
+		},
+		{
+			name:   "named coordinate columns",
+			in:     "vds_id,px,py\nV1,127.1,37.5\n",
+			fields: geometryFields{Lon: "px", Lat: "py"},
+			want:   []geoRecord{{Attrs: map[string]string{"vds_id": "V1", "px": "127.1", "py": "37.5"}, Coords: [][2]float64{{127.1, 37.5}}}},
+		},
+		{name: "no geometry columns", in: "vds_id\nV1\n", wantErr: true},
+		{name: "invalid coordinates", in: "vds_id,lon,lat\nV1,east,37.5\n", wantErr: true},
+		{name: "invalid wkt", in: "id,wkt\nV1,POLYGON((1 1))\n", wantErr: true},
+		{name: "empty file", in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := readGeometryCSV(strings.NewReader(tt.in), tt.fields)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: records = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRoadFeatures(t *testing.T) {
+	identity := func(x, y float64) (float64, float64) { return x, y }
+	rec := func(coords [][2]float64, attrs ...string) geoRecord {
+		r := geoRecord{Attrs: map[string]string{}, Coords: coords}
+		for i := 0; i+1 < len(attrs); i += 2 {
+			r.Attrs[attrs[i]] = attrs[i+1]
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		kind    string
+		records []geoRecord
+		fields  geometryFields
+		want    []roadFeature
+		wantErr bool
+	}{
+		{
+			name: "conzone parts joined by seq without the shared vertex",
+			kind: "conzone",
+			records: []geoRecord{
+				rec([][2]float64{{127.1, 37.1}, {127.2, 37.2}}, "conzone_id", "CZ1", "seq", "2"),
+				rec([][2]float64{{127, 37}, {127.1, 37.1}}, "conzone_id", "CZ1", "seq", "1", "route_no", "0010"),
+				rec([][2]float64{{128, 36}, {128.1, 36.1}}, "cz_id", "CZ2"),
+			},
+			want: []roadFeature{
+				{ID: "CZ1", RouteNo: "0010", Coords: [][2]float64{{127, 37}, {127.1, 37.1}, {127.2, 37.2}}},
+				{ID: "CZ2", Coords: [][2]float64{{128, 36}, {128.1, 36.1}}},
+			},
+		},
+		{
+			name: "records without an ID or with the wrong geometry are skipped",
+			kind: "conzone",
+			records: []geoRecord{
+				rec([][2]float64{{127, 37}, {127.1, 37.1}}),
+				rec([][2]float64{{127, 37}}, "conzone_id", "point"),
+				rec([][2]float64{{127, 37}, {127.1, 37.1}}, "conzone_id", "CZ1"),
+			},
+			want: []roadFeature{{ID: "CZ1", Coords: [][2]float64{{127, 37}, {127.1, 37.1}}}},
+		},
+		{
+			name: "VDS points with their conzone",
+			kind: "vds",
+			records: []geoRecord{
+				rec([][2]float64{{127, 37}}, "vds_id", "V1", "conzone_id", "CZ1", "route_no", "0010"),
+				rec([][2]float64{{127, 37}, {127.1, 37.1}}, "vds_id", "line"),
+			},
+			want: []roadFeature{{ID: "V1", RouteNo: "0010", ConzoneID: "CZ1", Coords: [][2]float64{{127, 37}}}},
+		},
+		{
+			name:    "named ID field",
+			kind:    "vds",
+			records: []geoRecord{rec([][2]float64{{127, 37}}, "station", "S1")},
+			fields:  geometryFields{ID: "station"},
+			want:    []roadFeature{{ID: "S1", Coords: [][2]float64{{127, 37}}}},
+		},
+		{
+			name: "outside Korea is dropped",
+			kind: "conzone",
+			records: []geoRecord{
+				rec([][2]float64{{127, 37}, {127.1, 37.1}}, "conzone_id", "CZ1"),
+				rec([][2]float64{{37, 127}, {37.1, 127.1}}, "conzone_id", "swapped"),
+			},
+			want: []roadFeature{{ID: "CZ1", Coords: [][2]float64{{127, 37}, {127.1, 37.1}}}},
+		},
+		{
+			name:    "nothing in Korea means a wrong CRS",
+			kind:    "conzone",
+			records: []geoRecord{rec([][2]float64{{953898, 1952035}, {954000, 1952100}}, "conzone_id", "CZ1")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := roadFeatures(tt.kind, tt.records, tt.fields, identity)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: features = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	// Projected input is converted before the Korea check
+	project, err := toLonLat(5179)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := roadFeatures("vds", []geoRecord{rec([][2]float64{{953898, 1952035}}, "vds_id", "V1")}, geometryFields{}, project)
+	if err != nil || len(got) != 1 || !inKorea(got[0].Coords[0][0], got[0].Coords[0][1]) {
+		t.Errorf("EPSG:5179 features = %+v, %v", got, err)
+	}
+}
+
+func TestGeometryFormat(t *testing.T) {
+	tests := []struct {
+		format, path string
+		want         string
+		wantErr      bool
+	}{
+		{path: "roads.shp", want: "shp"},
+		{path: "ROADS.SHP", want: "shp"},
+		{path: "vds.csv", want: "csv"},
+		{path: "roads.geojson", want: "geojson"},
+		{path: "roads.json", want: "geojson"},
+		{format: "CSV", path: "roads.txt", want: "csv"},
+		{format: "kml", path: "roads.kml", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := geometryFormat(tt.format, tt.path)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("geometryFormat(%q, %q) = %q, %v; want %q, error %v", tt.format, tt.path, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setupLogging installs a structured logger as the slog default (and behind the log package).
+// LOG_LEVEL selects the minimum level (debug, info, warn, error; default info) and LOG_FORMAT
+// selects json (default) or text. Every record carries the service, cluster and pod so logs
+// from member1 and member2 can be merged and filtered in one place.
+func setupLogging(service string) {
+	level := slog.LevelInfo
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		if err := level.UnmarshalText([]byte(v)); err != nil {
+			level = slog.LevelInfo
+		}
+	}
+
+	opts := &slog.HandlerOptions{
+		Level: level,
+		// Durations read better as "15m0s" than as nanoseconds
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Value.Kind() == slog.KindDuration {
+				return slog.String(a.Key, a.Value.Duration().String())
+			}
+			return a
+		},
+	}
+	var handler slog.Handler
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "text") {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
+
+	// Pod hostnames are the pod names; CLUSTER_NAME is set per member cluster by Karmada
+	pod, _ := os.Hostname()
+	slog.SetDefault(slog.New(handler).With(
+		"service", service,
+		"cluster", os.Getenv("CLUSTER_NAME"),
+		"pod", pod,
+	))
+}
+
+// fatal logs at error level and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// componentLogger returns the default logger tagged with a component name
+func componentLogger(component string) *slog.Logger {
+	return slog.Default().With("component", component)
+}
+
+// traceLogger adds the trace ID of the span in ctx, if any, so log lines can be matched
+// with the trace started by data-collector
+func traceLogger(ctx context.Context, logger *slog.Logger) *slog.Logger {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return logger.With("trace_id", sc.TraceID().String())
+	}
+	return logger
+}
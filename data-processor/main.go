@@ -4,15 +4,23 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type RealTimeSMS struct {
@@ -36,6 +44,21 @@ type Config struct {
 	StreamKey    string
 	ConsumerGroup string
 	ConsumerName  string
+	FencingKey    string // highest collector fencing token accepted
+	MetricsPort   string
+
+	// AcceptUnfenced accepts messages without a fencing token even after a fenced leader
+	// has been seen, e.g. while migrating collectors to leader election
+	AcceptUnfenced bool
+
+	ClaimMinIdle time.Duration // pending entries idle this long are claimed from other consumers
+
+	// MaxDeliveries is how often a message may fail before it is moved to DeadLetterKey
+	MaxDeliveries int
+	DeadLetterKey string
+
+	MatchInterval    time.Duration // accident map matching period
+	MatchMaxDistance float64       // metres from a conzone line for a geometry match
 }
 
 type Processor struct {
@@ -76,6 +99,50 @@ func loadConfig() Config {
 		consumerName = fmt.Sprintf("processor-%s", hostname)
 	}
 
+	fencingKey := os.Getenv("FENCING_KEY")
+	if fencingKey == "" {
+		fencingKey = "data-collector:leader:accepted"
+	}
+
+	metricsPort := os.Getenv("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9090"
+	}
+
+	matchInterval := time.Minute
+	if env := os.Getenv("MATCH_INTERVAL"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d > 0 {
+			matchInterval = d
+		}
+	}
+
+	matchMaxDistance := 300.0
+	if env := os.Getenv("MATCH_MAX_DISTANCE"); env != "" {
+		if m, err := strconv.ParseFloat(env, 64); err == nil && m > 0 {
+			matchMaxDistance = m
+		}
+	}
+
+	// Consumer names follow pod names, so a killed pod's pending entries are never read
+	// again by their owner; another replica claims them once they have been idle this long
+	claimMinIdle := 5 * time.Minute
+	if env := os.Getenv("CLAIM_MIN_IDLE"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d > 0 {
+			claimMinIdle = d
+		}
+	}
+
+	// A message that keeps failing (e.g. its rows are rejected by the database) would
+	// otherwise be claimed again every ClaimMinIdle forever
+	maxDeliveries := 5
+	if env := os.Getenv("MAX_DELIVERIES"); env != "" {
+		if n, err := strconv.Atoi(env); err == nil && n > 0 {
+			maxDeliveries = n
+		}
+	}
+
+	acceptUnfenced, _ := strconv.ParseBool(os.Getenv("ACCEPT_UNFENCED"))
+
 	return Config{
 		DBHost:        dbHost,
 		DBUser:        dbUser,
@@ -85,11 +152,21 @@ func loadConfig() Config {
 		StreamKey:     "traffic-stream",
 		ConsumerGroup: "processor-group",
 		ConsumerName:  consumerName,
+		FencingKey:    fencingKey,
+		MetricsPort:   metricsPort,
+		ClaimMinIdle:  claimMinIdle,
+		MaxDeliveries: maxDeliveries,
+		DeadLetterKey: "traffic-stream:dead-letter",
+
+		AcceptUnfenced: acceptUnfenced,
+
+		MatchInterval:    matchInterval,
+		MatchMaxDistance: matchMaxDistance,
 	}
 }
 
-func NewProcessor(config Config) (*Processor, error) {
-	// Connect to MariaDB
+// openDB connects to MariaDB; the geometry command needs the database alone
+func openDB(config Config) (*sql.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true",
 		config.DBUser, config.DBPassword, config.DBHost, config.DBName)
 
@@ -106,10 +183,23 @@ func NewProcessor(config Config) (*Processor, error) {
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Printf("Connected to MariaDB at %s", config.DBHost)
+	slog.Info("connected to MariaDB", "host", config.DBHost)
+	return db, nil
+}
+
+func NewProcessor(config Config) (*Processor, error) {
+	// Connect to MariaDB
+	db, err := openDB(config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
 	// Connect to Redis
 	rdb := redis.NewClient(&redis.Options{
@@ -120,7 +210,7 @@ func NewProcessor(config Config) (*Processor, error) {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
-	log.Printf("Connected to Redis at %s", config.RedisAddr)
+	slog.Info("connected to Redis", "addr", config.RedisAddr)
 
 	processor := &Processor{
 		config:      config,
@@ -130,7 +220,7 @@ func NewProcessor(config Config) (*Processor, error) {
 
 	// Initialize consumer group
 	if err := processor.initConsumerGroup(ctx); err != nil {
-		log.Printf("Warning: Failed to initialize consumer group: %v", err)
+		slog.Warn("failed to initialize consumer group", "error", err)
 	}
 
 	return processor, nil
@@ -143,8 +233,7 @@ func (p *Processor) initConsumerGroup(ctx context.Context) error {
 		return err
 	}
 
-	log.Printf("Consumer group '%s' initialized for stream '%s'",
-		p.config.ConsumerGroup, p.config.StreamKey)
+	slog.Info("consumer group initialized", "group", p.config.ConsumerGroup, "stream", p.config.StreamKey)
 
 	return nil
 }
@@ -191,8 +280,8 @@ func (p *Processor) insertAccident(ctx context.Context, accident RealTimeSMS) er
 		return fmt.Errorf("failed to insert accident: %w", err)
 	}
 
-	log.Printf("Inserted/Updated: %s - %s at %s (road: %s)",
-		accident.AccType, accident.AccPointNM, accDate+" "+accHour, accident.RoadNM)
+	traceLogger(ctx, componentLogger("stream")).Debug("accident upserted", "type", accident.AccType,
+		"point", accident.AccPointNM, "time", accDate+" "+accHour, "road", accident.RoadNM)
 
 	return nil
 }
@@ -229,40 +318,77 @@ func formatHour(hour string) string {
 	return cleaned
 }
 
-func (p *Processor) processMessage(ctx context.Context, msg redis.XMessage) error {
+// errInvalidMessage marks a message that can never be processed, however often it is
+// retried; it is acked and dropped instead of being left pending
+var errInvalidMessage = errors.New("invalid message")
+
+func (p *Processor) processMessage(ctx context.Context, msg redis.XMessage) (err error) {
+	// Continue the trace started by the collector cycle that published this message
+	ctx, span := tracer.Start(extractTraceContext(ctx, msg), "process "+p.config.StreamKey,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "redis"),
+			attribute.String("messaging.destination.name", p.config.StreamKey),
+			attribute.String("messaging.message.id", msg.ID),
+		))
+	defer func() { endSpan(span, err) }()
+
 	dataStr, ok := msg.Values["data"].(string)
 	if !ok {
-		return fmt.Errorf("invalid message format: missing 'data' field")
+		return fmt.Errorf("%w: missing 'data' field", errInvalidMessage)
 	}
 
 	var accidents []RealTimeSMS
 	if err := json.Unmarshal([]byte(dataStr), &accidents); err != nil {
-		return fmt.Errorf("failed to unmarshal accidents: %w", err)
+		return fmt.Errorf("%w: failed to unmarshal accidents: %w", errInvalidMessage, err)
 	}
 
 	source := msg.Values["source"]
-	log.Printf("Processing message ID %s with %d accidents (source: %v)",
-		msg.ID, len(accidents), source)
+	logger := traceLogger(ctx, componentLogger("stream")).With("message_id", msg.ID)
+
+	// Drop writes from a collector whose leadership term has already been superseded
+	current, token, err := p.checkFencingToken(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if !current {
+		if token == 0 {
+			logger.Warn("dropping unfenced message; a fenced leader is active (ACCEPT_UNFENCED=true to allow)", "leader", msg.Values["leader"])
+		} else {
+			logger.Warn("dropping message from stale leader", "leader", msg.Values["leader"], "fencing_token", token)
+		}
+		messagesTotal.WithLabelValues("stale").Inc()
+		span.SetAttributes(attribute.Bool("fencing.stale", true))
+		return nil
+	}
+
+	logger.Info("processing message", "accidents", len(accidents), "source", source, "fencing_token", token)
 
 	processedCount := 0
 
 	for _, accident := range accidents {
 		// Always insert or update to refresh created_at timestamp
 		if err := p.insertAccident(ctx, accident); err != nil {
-			log.Printf("Error upserting accident %s: %v", accident.AccPointNM, err)
+			logger.Error("failed to upsert accident", "point", accident.AccPointNM, "error", err)
+			accidentsUpserted.WithLabelValues("failed").Inc()
 			continue
 		}
 		processedCount++
+		accidentsUpserted.WithLabelValues("upserted").Inc()
 	}
+	messagesTotal.WithLabelValues("processed").Inc()
+	span.SetAttributes(
+		attribute.Int("accidents.total", len(accidents)),
+		attribute.Int("accidents.upserted", processedCount),
+	)
 
-	log.Printf("Processed message %s: %d upserted, %d total",
-		msg.ID, processedCount, len(accidents))
+	logger.Info("processed message", "upserted", processedCount, "accidents", len(accidents))
 
 	return nil
 }
 
 func (p *Processor) aggregateDailyStats(ctx context.Context, date string) error {
-	log.Printf("Starting daily aggregation for date: %s", date)
+	componentLogger("aggregation").Info("starting daily aggregation", "date", date)
 
 	// Aggregate accident counts by type for the given date
 	query := `
@@ -285,7 +411,7 @@ func (p *Processor) aggregateDailyStats(ctx context.Context, date string) error
 	}
 
 	rowsAffected, _ := result.RowsAffected()
-	log.Printf("Daily aggregation completed for %s: %d records processed", date, rowsAffected)
+	componentLogger("aggregation").Info("daily aggregation completed", "date", date, "records", rowsAffected)
 
 	return nil
 }
@@ -296,8 +422,8 @@ func (p *Processor) runDailyAggregation(ctx context.Context) {
 	nextMidnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
 	durationUntilMidnight := nextMidnight.Sub(now)
 
-	log.Printf("Next daily aggregation scheduled at: %s (in %s)",
-		nextMidnight.Format("2006-01-02 15:04:05"), durationUntilMidnight)
+	componentLogger("aggregation").Info("next daily aggregation scheduled",
+		"at", nextMidnight.Format("2006-01-02 15:04:05"), "in", durationUntilMidnight)
 
 	ticker := time.NewTicker(24 * time.Hour)
 	defer ticker.Stop()
@@ -309,13 +435,13 @@ func (p *Processor) runDailyAggregation(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Daily aggregation routine stopped")
+			componentLogger("aggregation").Info("daily aggregation routine stopped")
 			return
 		case <-timer.C:
 			// First execution at midnight
 			yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
 			if err := p.aggregateDailyStats(ctx, yesterday); err != nil {
-				log.Printf("Error in daily aggregation: %v", err)
+				componentLogger("aggregation").Error("daily aggregation failed", "error", err)
 			}
 			// Reset timer to run every 24 hours
 			timer.Reset(24 * time.Hour)
@@ -323,29 +449,193 @@ func (p *Processor) runDailyAggregation(ctx context.Context) {
 			// Subsequent executions
 			yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
 			if err := p.aggregateDailyStats(ctx, yesterday); err != nil {
-				log.Printf("Error in daily aggregation: %v", err)
+				componentLogger("aggregation").Error("daily aggregation failed", "error", err)
+			}
+		}
+	}
+}
+
+// handleMessage processes and acknowledges one message. It runs detached from shutdown
+// so a message that has been read is always finished and acked, never left half-written.
+// Invalid messages are acked at once; other failures stay pending for claimStale to retry
+// until MaxDeliveries is reached, then they are dead-lettered.
+func (p *Processor) handleMessage(ctx context.Context, msg redis.XMessage) {
+	ctx = context.WithoutCancel(ctx)
+	start := time.Now()
+	defer func() { messageDuration.Observe(time.Since(start).Seconds()) }()
+
+	if err := p.processMessage(ctx, msg); err != nil {
+		if errors.Is(err, errInvalidMessage) {
+			componentLogger("stream").Warn("dropping invalid message", "message_id", msg.ID, "error", err)
+			messagesTotal.WithLabelValues("invalid").Inc()
+		} else {
+			componentLogger("stream").Error("failed to process message", "message_id", msg.ID, "error", err)
+			messagesTotal.WithLabelValues("failed").Inc()
+			if !p.deadLetter(ctx, msg, err) {
+				return
+			}
+		}
+	}
+
+	// Acknowledge the message
+	if err := p.redisClient.XAck(ctx, p.config.StreamKey, p.config.ConsumerGroup, msg.ID).Err(); err != nil {
+		componentLogger("stream").Error("failed to acknowledge message", "message_id", msg.ID, "error", err)
+	} else {
+		componentLogger("stream").Debug("acknowledged message", "message_id", msg.ID)
+	}
+}
+
+// deadLetter copies a message that has been delivered MaxDeliveries times to DeadLetterKey
+// and reports whether it did, in which case the caller acks it. Below the limit, or when
+// the copy fails, the message stays pending.
+func (p *Processor) deadLetter(ctx context.Context, msg redis.XMessage, cause error) bool {
+	pending, err := p.redisClient.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: p.config.StreamKey,
+		Group:  p.config.ConsumerGroup,
+		Start:  msg.ID,
+		End:    msg.ID,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		componentLogger("stream").Error("failed to read delivery count", "message_id", msg.ID, "error", err)
+		return false
+	}
+	if len(pending) == 0 || pending[0].RetryCount < int64(p.config.MaxDeliveries) {
+		return false
+	}
+
+	values := make(map[string]interface{}, len(msg.Values)+3)
+	for k, v := range msg.Values {
+		values[k] = v
+	}
+	values["original_id"] = msg.ID
+	values["deliveries"] = pending[0].RetryCount
+	values["error"] = cause.Error()
+	if err := p.redisClient.XAdd(ctx, &redis.XAddArgs{Stream: p.config.DeadLetterKey, Values: values}).Err(); err != nil {
+		componentLogger("stream").Error("failed to dead-letter message", "message_id", msg.ID, "error", err)
+		return false
+	}
+
+	componentLogger("stream").Warn("dead-lettered message", "message_id", msg.ID,
+		"deliveries", pending[0].RetryCount, "stream", p.config.DeadLetterKey)
+	messagesTotal.WithLabelValues("dead_lettered").Inc()
+	return true
+}
+
+// recoverPending re-processes messages this consumer read but never acked, e.g. because
+// the previous pod was killed mid-message. Inserts are upserts, so replaying is safe.
+func (p *Processor) recoverPending(ctx context.Context) {
+	recovered := 0
+	start := "0"
+
+	for ctx.Err() == nil {
+		streams, err := p.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    p.config.ConsumerGroup,
+			Consumer: p.config.ConsumerName,
+			Streams:  []string{p.config.StreamKey, start},
+			Count:    10,
+		}).Result()
+		if err != nil {
+			if err != redis.Nil {
+				componentLogger("stream").Error("failed to read pending messages", "error", err)
 			}
+			break
+		}
+		if len(streams) == 0 || len(streams[0].Messages) == 0 {
+			break
+		}
+
+		for _, msg := range streams[0].Messages {
+			p.handleMessage(ctx, msg)
+			start = msg.ID
+			recovered++
 		}
 	}
+
+	if recovered > 0 {
+		componentLogger("stream").Info("recovered pending messages", "messages", recovered, "consumer", p.config.ConsumerName)
+	}
+
+	p.claimStale(ctx)
+}
+
+// claimStale takes over messages other consumers read but have not acked for ClaimMinIdle,
+// typically those of a pod that was replaced and whose consumer name will not come back
+func (p *Processor) claimStale(ctx context.Context) {
+	claimed := 0
+	start := "0-0"
+
+	for ctx.Err() == nil {
+		msgs, next, err := p.redisClient.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   p.config.StreamKey,
+			Group:    p.config.ConsumerGroup,
+			Consumer: p.config.ConsumerName,
+			MinIdle:  p.config.ClaimMinIdle,
+			Start:    start,
+			Count:    10,
+		}).Result()
+		if err != nil {
+			if err != redis.Nil {
+				componentLogger("stream").Error("failed to claim stale messages", "error", err)
+			}
+			break
+		}
+
+		for _, msg := range msgs {
+			p.handleMessage(ctx, msg)
+			claimed++
+		}
+		if next == "0-0" || next == "" {
+			break
+		}
+		start = next
+	}
+
+	if claimed > 0 {
+		componentLogger("stream").Info("claimed stale pending messages", "messages", claimed,
+			"consumer", p.config.ConsumerName, "min_idle", p.config.ClaimMinIdle)
+	}
 }
 
 func (p *Processor) Start(ctx context.Context) error {
-	log.Printf("Data Processor started (Consumer: %s, Group: %s)",
-		p.config.ConsumerName, p.config.ConsumerGroup)
+	slog.Info("data processor started", "consumer", p.config.ConsumerName, "group", p.config.ConsumerGroup)
+
+	// Background routines are waited for so Close does not pull the DB out from under them
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for _, run := range []func(ctx context.Context){
+		p.runDailyAggregation,
+		p.runAccidentMatching,
+	} {
+		wg.Add(1)
+		go func(run func(ctx context.Context)) {
+			defer wg.Done()
+			run(ctx)
+		}(run)
+	}
+
+	p.recoverPending(ctx)
+	lastClaim := time.Now()
 
-	// Start daily aggregation routine in a separate goroutine
-	go p.runDailyAggregation(ctx)
+	// Reads are not cancelled by shutdown: a message delivered to a cancelled read would
+	// sit unacked in the pending list. Shutdown is noticed within one Block interval.
+	readCtx := context.WithoutCancel(ctx)
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("Processor stopped")
+			slog.Info("processor stopped")
 			return nil
 		default:
 		}
 
+		if time.Since(lastClaim) >= p.config.ClaimMinIdle {
+			p.claimStale(ctx)
+			lastClaim = time.Now()
+		}
+
 		// Read from stream using consumer group
-		streams, err := p.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+		streams, err := p.redisClient.XReadGroup(readCtx, &redis.XReadGroupArgs{
 			Group:    p.config.ConsumerGroup,
 			Consumer: p.config.ConsumerName,
 			Streams:  []string{p.config.StreamKey, ">"},
@@ -358,24 +648,14 @@ func (p *Processor) Start(ctx context.Context) error {
 				// No new messages, continue
 				continue
 			}
-			log.Printf("Error reading from stream: %v", err)
+			componentLogger("stream").Error("failed to read from stream", "error", err)
 			time.Sleep(1 * time.Second)
 			continue
 		}
 
 		for _, stream := range streams {
 			for _, msg := range stream.Messages {
-				if err := p.processMessage(ctx, msg); err != nil {
-					log.Printf("Error processing message %s: %v", msg.ID, err)
-					continue
-				}
-
-				// Acknowledge the message
-				if err := p.redisClient.XAck(ctx, p.config.StreamKey, p.config.ConsumerGroup, msg.ID).Err(); err != nil {
-					log.Printf("Error acknowledging message %s: %v", msg.ID, err)
-				} else {
-					log.Printf("Acknowledged message %s", msg.ID)
-				}
+				p.handleMessage(ctx, msg)
 			}
 		}
 	}
@@ -383,31 +663,50 @@ func (p *Processor) Start(ctx context.Context) error {
 
 func (p *Processor) Close() error {
 	if err := p.db.Close(); err != nil {
-		log.Printf("Error closing database: %v", err)
+		slog.Warn("failed to close database", "error", err)
 	}
 	if err := p.redisClient.Close(); err != nil {
-		log.Printf("Error closing Redis client: %v", err)
+		slog.Warn("failed to close Redis client", "error", err)
 	}
 	return nil
 }
 
 func main() {
+	setupLogging("data-processor")
 	config := loadConfig()
 
-	log.Printf("Configuration:")
-	log.Printf("  DB Host: %s", config.DBHost)
-	log.Printf("  DB Name: %s", config.DBName)
-	log.Printf("  Redis Address: %s", config.RedisAddr)
-	log.Printf("  Stream Key: %s", config.StreamKey)
-	log.Printf("  Consumer Group: %s", config.ConsumerGroup)
-	log.Printf("  Consumer Name: %s", config.ConsumerName)
+	// Road network import and accident matching: data-processor geometry import|match
+	if len(os.Args) > 1 && os.Args[1] == "geometry" {
+		if err := runGeometryCommand(config, os.Args[2:]); err != nil {
+			fatal("geometry command failed", "error", err)
+		}
+		return
+	}
+
+	slog.Info("configuration",
+		"db_host", config.DBHost,
+		"db_name", config.DBName,
+		"redis_addr", config.RedisAddr,
+		"stream", config.StreamKey,
+		"group", config.ConsumerGroup,
+		"consumer", config.ConsumerName,
+		"fencing_key", config.FencingKey,
+		"accept_unfenced", config.AcceptUnfenced,
+		"max_deliveries", config.MaxDeliveries,
+		"metrics_port", config.MetricsPort,
+	)
 
 	processor, err := NewProcessor(config)
 	if err != nil {
-		log.Fatalf("Failed to create processor: %v", err)
+		fatal("failed to create processor", "error", err)
 	}
 	defer processor.Close()
 
+	shutdownTracing, err := initTracing(context.Background(), "data-processor")
+	if err != nil {
+		fatal("failed to initialise tracing", "error", err)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -417,11 +716,36 @@ func main() {
 
 	go func() {
 		<-sigChan
-		log.Println("Shutdown signal received")
+		slog.Info("shutdown signal received")
 		cancel()
 	}()
 
+	prometheus.MustRegister(streamCollector{p: processor})
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: ":" + config.MetricsPort, Handler: mux}
+
+	go func() {
+		slog.Info("metrics endpoint listening", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("metrics server failed", "error", err)
+		}
+	}()
+
+	// Report this instance (and its consumer name) to the cluster status API
+	waitPresence := startPresence(ctx, processor.redisClient, newPresence("data-processor", config.ConsumerName))
+
 	if err := processor.Start(ctx); err != nil {
-		log.Fatalf("Processor failed: %v", err)
+		fatal("processor failed", "error", err)
+	}
+	waitPresence()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("metrics server shutdown", "error", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("tracing shutdown", "error", err)
 	}
 }
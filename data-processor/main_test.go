@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeStream answers the stream commands the processor sends, so the consumer loop can be
+// tested without Redis. Commands it does not know fail.
+type fakeStream struct {
+	mu sync.Mutex
+
+	pending   []redis.XMessage // returned once by XREADGROUP with an explicit ID
+	claimable []redis.XMessage // returned once by XAUTOCLAIM
+	fresh     []redis.XMessage // returned once by XREADGROUP ">"
+	retries   int64            // delivery count reported by XPENDING
+	fencing   error            // result of the fencing script, nil when current
+
+	onFresh func() // called when the fresh messages are handed out
+
+	acked        []string
+	ackCtxErrs   []error
+	deadLettered []map[string]interface{}
+}
+
+func (f *fakeStream) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (f *fakeStream) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (f *fakeStream) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		args := cmd.Args()
+		switch c := cmd.(type) {
+		case *redis.XStreamSliceCmd:
+			var msgs []redis.XMessage
+			if args[len(args)-1] == ">" {
+				msgs, f.fresh = f.fresh, nil
+				if msgs != nil && f.onFresh != nil {
+					f.onFresh()
+				}
+			} else {
+				msgs, f.pending = f.pending, nil
+			}
+			if len(msgs) == 0 {
+				return redis.Nil
+			}
+			c.SetVal([]redis.XStream{{Stream: "traffic-stream", Messages: msgs}})
+		case *redis.XAutoClaimCmd:
+			c.SetVal(f.claimable, "0-0")
+			f.claimable = nil
+		case *redis.XPendingExtCmd:
+			c.SetVal([]redis.XPendingExt{{ID: fmt.Sprint(args[3]), RetryCount: f.retries}})
+		case *redis.IntCmd:
+			if c.Name() != "xack" {
+				return fmt.Errorf("unexpected command %v", args)
+			}
+			f.acked = append(f.acked, fmt.Sprint(args[3]))
+			f.ackCtxErrs = append(f.ackCtxErrs, ctx.Err())
+		case *redis.StringCmd:
+			if c.Name() != "xadd" {
+				return fmt.Errorf("unexpected command %v", args)
+			}
+			values := map[string]interface{}{}
+			for i := 3; i+1 < len(args); i += 2 {
+				values[fmt.Sprint(args[i])] = args[i+1]
+			}
+			f.deadLettered = append(f.deadLettered, values)
+		case *redis.Cmd:
+			if !strings.HasPrefix(c.Name(), "eval") {
+				return fmt.Errorf("unexpected command %v", args)
+			}
+			if f.fencing != nil {
+				return f.fencing
+			}
+			c.SetVal(int64(1))
+		default:
+			return fmt.Errorf("unexpected command %v", args)
+		}
+		return nil
+	}
+}
+
+func newTestProcessor(t *testing.T, f *fakeStream) *Processor {
+	t.Helper()
+	rdb := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	rdb.AddHook(f)
+	db, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:1)/traffic?timeout=100ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		rdb.Close()
+		db.Close()
+	})
+	return &Processor{
+		config: Config{
+			StreamKey:     "traffic-stream",
+			ConsumerGroup: "processor-group",
+			ConsumerName:  "processor-test",
+			FencingKey:    "test:fencing",
+			ClaimMinIdle:  time.Hour,
+			MaxDeliveries: 3,
+			DeadLetterKey: "traffic-stream:dead-letter",
+			MatchInterval: time.Hour,
+		},
+		db:          db,
+		redisClient: rdb,
+	}
+}
+
+// metricValue scrapes /metrics and returns the value of one series, 0 when absent
+func metricValue(t *testing.T, series string) float64 {
+	t.Helper()
+	w := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), series+" "); ok {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return v
+		}
+	}
+	return 0
+}
+
+// An empty batch needs no database; a failing fencing script stands in for a transient error
+func TestHandleMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     map[string]interface{}
+		retries    int64
+		fencing    error
+		wantResult string
+		wantAcked  bool
+		wantDead   bool
+	}{
+		{name: "processed", values: map[string]interface{}{"data": "[]"}, wantResult: "processed", wantAcked: true},
+		{name: "missing data", values: map[string]interface{}{}, wantResult: "invalid", wantAcked: true},
+		{name: "bad json", values: map[string]interface{}{"data": "{"}, wantResult: "invalid", wantAcked: true},
+		{name: "invalid fencing token", values: map[string]interface{}{"data": "[]", "fencing_token": "abc"}, wantResult: "invalid", wantAcked: true},
+		{name: "transient failure stays pending", values: map[string]interface{}{"data": "[]"}, retries: 2,
+			fencing: errors.New("LOADING"), wantResult: "failed"},
+		{name: "transient failure dead-lettered", values: map[string]interface{}{"data": "[]"}, retries: 3,
+			fencing: errors.New("LOADING"), wantResult: "failed", wantAcked: true, wantDead: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeStream{retries: tt.retries, fencing: tt.fencing}
+			p := newTestProcessor(t, f)
+			series := fmt.Sprintf(`processor_messages_total{result=%q}`, tt.wantResult)
+			before := metricValue(t, series)
+
+			p.handleMessage(context.Background(), redis.XMessage{ID: "5-0", Values: tt.values})
+
+			if got := metricValue(t, series) - before; got != 1 {
+				t.Errorf("%s grew by %v, want 1", series, got)
+			}
+			if acked := len(f.acked) == 1 && f.acked[0] == "5-0"; acked != tt.wantAcked {
+				t.Errorf("acked = %v, want %v", f.acked, tt.wantAcked)
+			}
+			if (len(f.deadLettered) == 1) != tt.wantDead {
+				t.Fatalf("dead-lettered = %v, want %v", f.deadLettered, tt.wantDead)
+			}
+			if tt.wantDead {
+				dl := f.deadLettered[0]
+				if dl["original_id"] != "5-0" || dl["data"] != "[]" || fmt.Sprint(dl["deliveries"]) != "3" {
+					t.Errorf("dead-letter entry = %v", dl)
+				}
+			}
+		})
+	}
+}
+
+func TestRecoverPending(t *testing.T) {
+	f := &fakeStream{
+		pending:   []redis.XMessage{{ID: "1-0", Values: map[string]interface{}{"data": "[]"}}, {ID: "2-0", Values: map[string]interface{}{}}},
+		claimable: []redis.XMessage{{ID: "3-0", Values: map[string]interface{}{"data": "[]"}}},
+	}
+	p := newTestProcessor(t, f)
+
+	p.recoverPending(context.Background())
+
+	want := []string{"1-0", "2-0", "3-0"}
+	if fmt.Sprint(f.acked) != fmt.Sprint(want) {
+		t.Errorf("acked = %v, want %v", f.acked, want)
+	}
+}
+
+func TestClaimStale(t *testing.T) {
+	tests := []struct {
+		name      string
+		claimable []redis.XMessage
+		cancelled bool
+		wantAcked []string
+	}{
+		{name: "nothing idle"},
+		{name: "claims and handles", claimable: []redis.XMessage{
+			{ID: "1-0", Values: map[string]interface{}{"data": "[]"}},
+			{ID: "2-0", Values: map[string]interface{}{"data": "not json"}},
+		}, wantAcked: []string{"1-0", "2-0"}},
+		{name: "skipped after shutdown", claimable: []redis.XMessage{{ID: "1-0", Values: map[string]interface{}{"data": "[]"}}},
+			cancelled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeStream{claimable: tt.claimable}
+			p := newTestProcessor(t, f)
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancelled {
+				cancel()
+			}
+			defer cancel()
+
+			p.claimStale(ctx)
+
+			if fmt.Sprint(f.acked) != fmt.Sprint(tt.wantAcked) {
+				t.Errorf("acked = %v, want %v", f.acked, tt.wantAcked)
+			}
+		})
+	}
+}
+
+// A message read just before shutdown is still processed and acked with a live context,
+// then Start returns
+func TestStartDrainsOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := &fakeStream{
+		fresh:   []redis.XMessage{{ID: "9-0", Values: map[string]interface{}{"data": "[]"}}},
+		onFresh: cancel,
+	}
+	p := newTestProcessor(t, f)
+
+	done := make(chan error, 1)
+	go func() { done <- p.Start(ctx) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Start = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return after shutdown")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.acked) != 1 || f.acked[0] != "9-0" {
+		t.Fatalf("acked = %v, want [9-0]", f.acked)
+	}
+	if f.ackCtxErrs[0] != nil {
+		t.Errorf("ack ran with a cancelled context: %v", f.ackCtxErrs[0])
+	}
+}
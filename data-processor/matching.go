@@ -0,0 +1,506 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Accident map matching: assigns each accident in traffic_accidents to a road_traffic_status
+// section (route_no, conzone_id, updown_type_code) in accident_road_match.
+//
+// Accidents with coordinates go to the nearest conzone line of road_conzone_geometry within
+// the maximum distance, preferring the route named by road_nm. Others fall back to names:
+// road_nm to route_name, and acc_point_nm to the node names of conzone_name ("양재IC-판교JC").
+// The direction comes from "...방향" in the message. Conzone names and lines are assumed to
+// run from the route's start (기점) to its end (종점), so heading to the node at the end of
+// the line is E and heading back is S. Accidents that match nothing are stored with method
+// "none" so they are not retried on every run; they and route-only matches are retried
+// when a new road network is imported.
+
+const (
+	matchGeometry = "geometry"
+	matchName     = "name"
+	matchNone     = "none"
+
+	networkRefresh = time.Hour // how long a loaded road network is reused
+
+	// unlocatedRematchWindow is how far back accidents without a conzone are retried after
+	// a geometry import
+	unlocatedRematchWindow = 30 * 24 * time.Hour
+)
+
+// matchScope selects which accidents of the window matchAccidents (re)matches
+type matchScope int
+
+const (
+	matchNew       matchScope = iota // accidents without a match
+	matchUnlocated                   // also none and route-only matches
+	matchAll                         // every accident
+)
+
+// pendingAccident is an accident to match
+type pendingAccident struct {
+	ID         int64
+	RoadNM     string
+	AccPointNM string
+	SmsText    string
+	Latitude   sql.NullFloat64
+	Longitude  sql.NullFloat64 // altitude column, longitude in Korean highway API
+	OccurredAt sql.NullTime
+}
+
+// accidentMatch is the section assigned to one accident
+type accidentMatch struct {
+	RouteNo        string
+	ConzoneID      string
+	UpdownTypeCode string // S, E or empty when the direction is unknown
+	Method         string
+	Distance       sql.NullFloat64 // metres from the conzone line, geometry matches only
+}
+
+// conzoneInfo is one conzone of the road network
+type conzoneInfo struct {
+	ID      string
+	RouteNo string
+	Nodes   [2]string    // node names at the start and end, normalized by nodeKey
+	Line    [][2]float64 // [longitude, latitude], nil without geometry
+}
+
+// roadNetwork indexes the conzones seen in road_traffic_status and road_conzone_geometry
+type roadNetwork struct {
+	routes   map[string]string // routeKey(route_name) to route_no
+	conzones []*conzoneInfo    // ordered by conzone ID
+	byRoute  map[string][]*conzoneInfo
+}
+
+var (
+	directionPattern = regexp.MustCompile(`(\S+)방향`)
+	nodeSuffixes     = []string{"나들목", "분기점", "휴게소", "요금소", "IC", "JC", "TG", "SA"}
+)
+
+// routeKey normalizes a route name so "경부선", "경부고속도로" and "경부 고속도로" agree
+func routeKey(name string) string {
+	name = strings.Join(strings.Fields(name), "")
+	for _, suffix := range []string{"고속도로", "고속국도", "선"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return name
+}
+
+// nodeKey normalizes a node name so "서울TG", "서울 요금소" and "서울" agree
+func nodeKey(name string) string {
+	name = strings.ToUpper(strings.Join(strings.Fields(name), ""))
+	if i := strings.IndexAny(name, "(["); i > 0 {
+		name = name[:i]
+	}
+	for _, suffix := range nodeSuffixes {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != "" {
+			name = trimmed
+		}
+	}
+	return name
+}
+
+// conzoneNodes splits a conzone name such as "양재IC-판교JC" into its end nodes
+func conzoneNodes(name string) [2]string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '~' || r == '→' })
+	if len(parts) != 2 {
+		return [2]string{}
+	}
+	return [2]string{nodeKey(parts[0]), nodeKey(parts[1])}
+}
+
+// loadRoadNetwork reads the conzones reported in the last day and every conzone line
+func loadRoadNetwork(ctx context.Context, db *sql.DB) (*roadNetwork, error) {
+	n := &roadNetwork{routes: make(map[string]string), byRoute: make(map[string][]*conzoneInfo)}
+	byID := make(map[string]*conzoneInfo)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT DISTINCT route_no, route_name, conzone_id, conzone_name
+		FROM road_traffic_status
+		WHERE collected_at >= NOW() - INTERVAL 1 DAY`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query road sections: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var routeNo, routeName, conzoneID, conzoneName string
+		if err := rows.Scan(&routeNo, &routeName, &conzoneID, &conzoneName); err != nil {
+			return nil, fmt.Errorf("failed to scan road section: %w", err)
+		}
+		if key := routeKey(routeName); key != "" {
+			n.routes[key] = routeNo
+		}
+		if byID[conzoneID] == nil {
+			byID[conzoneID] = &conzoneInfo{ID: conzoneID, RouteNo: routeNo, Nodes: conzoneNodes(conzoneName)}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	lines, err := db.QueryContext(ctx, `SELECT conzone_id, route_no, ST_AsText(geom) FROM road_conzone_geometry`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query conzone geometry: %w", err)
+	}
+	defer lines.Close()
+
+	for lines.Next() {
+		var conzoneID, routeNo, wkt string
+		if err := lines.Scan(&conzoneID, &routeNo, &wkt); err != nil {
+			return nil, fmt.Errorf("failed to scan conzone geometry: %w", err)
+		}
+		line, err := parseWKT(wkt)
+		if err != nil || len(line) < 2 {
+			componentLogger("matching").Warn("invalid conzone geometry", "conzone_id", conzoneID, "error", err)
+			continue
+		}
+		c := byID[conzoneID]
+		if c == nil {
+			c = &conzoneInfo{ID: conzoneID, RouteNo: routeNo}
+			byID[conzoneID] = c
+		}
+		c.Line = line
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, c := range byID {
+		n.conzones = append(n.conzones, c)
+	}
+	sort.Slice(n.conzones, func(i, j int) bool { return n.conzones[i].ID < n.conzones[j].ID })
+	for _, c := range n.conzones {
+		n.byRoute[c.RouteNo] = append(n.byRoute[c.RouteNo], c)
+	}
+	return n, nil
+}
+
+// networkCache keeps the road network between matching runs
+type networkCache struct {
+	network  *roadNetwork
+	loadedAt time.Time
+}
+
+func (c *networkCache) get(ctx context.Context, db *sql.DB) (*roadNetwork, error) {
+	if c.network == nil || time.Since(c.loadedAt) > networkRefresh {
+		network, err := loadRoadNetwork(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		c.network, c.loadedAt = network, time.Now()
+	}
+	return c.network, nil
+}
+
+// distanceToLine is the distance in metres from p to the polyline, on a local
+// equirectangular projection (accurate to well under a percent at matching distances)
+func distanceToLine(p [2]float64, line [][2]float64) float64 {
+	kx := 111320 * math.Cos(p[1]*math.Pi/180)
+	const ky = 110574.0
+
+	best := math.Inf(1)
+	for i := 1; i < len(line); i++ {
+		ax, ay := (line[i-1][0]-p[0])*kx, (line[i-1][1]-p[1])*ky
+		bx, by := (line[i][0]-p[0])*kx, (line[i][1]-p[1])*ky
+		dx, dy := bx-ax, by-ay
+		t := 0.0
+		if l2 := dx*dx + dy*dy; l2 > 0 {
+			t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l2))
+		}
+		best = math.Min(best, math.Hypot(ax+t*dx, ay+t*dy))
+	}
+	return best
+}
+
+// direction resolves the heading towards dest (a nodeKey) on conzone c: E when dest lies
+// beyond the end of c, S when beyond its start, empty when unknown
+func (n *roadNetwork) direction(c *conzoneInfo, dest string) string {
+	switch {
+	case dest == "":
+		return ""
+	case c.Nodes[1] == dest:
+		return "E"
+	case c.Nodes[0] == dest:
+		return "S"
+	case c.Line == nil:
+		return ""
+	}
+
+	// Locate the destination node on another conzone of the route and compare it with the
+	// direction the line of c runs in
+	for _, o := range n.byRoute[c.RouteNo] {
+		if o == c || o.Line == nil || (o.Nodes[0] != dest && o.Nodes[1] != dest) {
+			continue
+		}
+		target := o.Line[0]
+		if o.Nodes[1] == dest {
+			target = o.Line[len(o.Line)-1]
+		}
+		start, end := c.Line[0], c.Line[len(c.Line)-1]
+		kx := math.Cos(start[1] * math.Pi / 180)
+		mid := [2]float64{(start[0] + end[0]) / 2, (start[1] + end[1]) / 2}
+		dot := (end[0]-start[0])*(target[0]-mid[0])*kx*kx + (end[1]-start[1])*(target[1]-mid[1])
+		if dot > 0 {
+			return "E"
+		}
+		return "S"
+	}
+	return ""
+}
+
+// match assigns one accident, by geometry within maxDistance metres or else by names
+func (n *roadNetwork) match(acc pendingAccident, maxDistance float64) accidentMatch {
+	routeNo := n.routes[routeKey(acc.RoadNM)]
+	var dest string
+	if m := directionPattern.FindStringSubmatch(acc.SmsText + " " + acc.AccPointNM); m != nil {
+		dest = nodeKey(m[1])
+	}
+
+	if acc.Latitude.Valid && acc.Longitude.Valid && inKorea(acc.Longitude.Float64, acc.Latitude.Float64) {
+		p := [2]float64{acc.Longitude.Float64, acc.Latitude.Float64}
+		var best, bestOnRoute *conzoneInfo
+		bestDist, bestOnRouteDist := math.Inf(1), math.Inf(1)
+		for _, c := range n.conzones {
+			if c.Line == nil {
+				continue
+			}
+			d := distanceToLine(p, c.Line)
+			if d < bestDist {
+				best, bestDist = c, d
+			}
+			if c.RouteNo == routeNo && d < bestOnRouteDist {
+				bestOnRoute, bestOnRouteDist = c, d
+			}
+		}
+		// Near junctions another route may be closer than the one the accident is on
+		if bestOnRoute != nil && bestOnRouteDist <= maxDistance {
+			best, bestDist = bestOnRoute, bestOnRouteDist
+		}
+		if best != nil && bestDist <= maxDistance {
+			return accidentMatch{
+				RouteNo:        best.RouteNo,
+				ConzoneID:      best.ID,
+				UpdownTypeCode: n.direction(best, dest),
+				Method:         matchGeometry,
+				Distance:       sql.NullFloat64{Float64: math.Round(bestDist*10) / 10, Valid: true},
+			}
+		}
+	}
+
+	// Name lookup: the conzone whose node names appear in the accident point, both ends
+	// ahead of one end, searching the whole network when the route is unknown. At a node
+	// shared by two conzones the one leading into it in the direction of travel wins.
+	point := acc.AccPointNM
+	if point == "" {
+		point = directionPattern.ReplaceAllString(acc.SmsText, "")
+	}
+	point = strings.ToUpper(strings.Join(strings.Fields(point), ""))
+	candidates := n.conzones
+	if routeNo != "" {
+		candidates = n.byRoute[routeNo]
+	}
+	var best *conzoneInfo
+	bestScore := 0
+	for _, c := range candidates {
+		score := 0
+		for i, node := range c.Nodes {
+			if len([]rune(node)) < 2 || !strings.Contains(point, node) {
+				continue
+			}
+			score += 2
+			if d := n.direction(c, dest); (d == "E" && i == 1) || (d == "S" && i == 0) {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	if best != nil {
+		return accidentMatch{
+			RouteNo:        best.RouteNo,
+			ConzoneID:      best.ID,
+			UpdownTypeCode: n.direction(best, dest),
+			Method:         matchName,
+		}
+	}
+	if routeNo != "" {
+		return accidentMatch{RouteNo: routeNo, Method: matchName}
+	}
+	return accidentMatch{Method: matchNone}
+}
+
+// matchAccidents matches the accidents upserted within since that scope selects. It
+// returns how many accidents were processed and how many of them were placed on a conzone.
+func matchAccidents(ctx context.Context, db *sql.DB, networks *networkCache, since time.Duration,
+	maxDistance float64, scope matchScope) (total, located int, err error) {
+	// acc_date is stored as YYYYMMDD and acc_hour as HHMM
+	rows, err := db.QueryContext(ctx, `
+		SELECT a.id, COALESCE(a.road_nm, ''), COALESCE(a.acc_point_nm, ''), a.sms_text,
+		       a.latitude, a.altitude, STR_TO_DATE(CONCAT(a.acc_date, a.acc_hour), '%Y%m%d%H%i')
+		FROM traffic_accidents a
+		LEFT JOIN accident_road_match m ON m.accident_id = a.id
+		WHERE a.created_at >= NOW() - INTERVAL ? SECOND
+		  AND (m.accident_id IS NULL OR ? OR (? AND m.conzone_id = ''))
+		ORDER BY a.id`, int64(since.Seconds()), scope == matchAll, scope == matchUnlocated)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query accidents: %w", err)
+	}
+	var pending []pendingAccident
+	for rows.Next() {
+		var acc pendingAccident
+		if err := rows.Scan(&acc.ID, &acc.RoadNM, &acc.AccPointNM, &acc.SmsText,
+			&acc.Latitude, &acc.Longitude, &acc.OccurredAt); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan accident: %w", err)
+		}
+		pending = append(pending, acc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+	if len(pending) == 0 {
+		return 0, 0, nil
+	}
+
+	network, err := networks.get(ctx, db)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO accident_road_match
+		(accident_id, route_no, conzone_id, updown_type_code, match_method, distance_m, occurred_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE route_no = VALUES(route_no), conzone_id = VALUES(conzone_id),
+		updown_type_code = VALUES(updown_type_code), match_method = VALUES(match_method),
+		distance_m = VALUES(distance_m), occurred_at = VALUES(occurred_at)`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, acc := range pending {
+		m := network.match(acc, maxDistance)
+		if _, err := stmt.ExecContext(ctx, acc.ID, m.RouteNo, m.ConzoneID, m.UpdownTypeCode,
+			m.Method, m.Distance, acc.OccurredAt); err != nil {
+			return 0, 0, fmt.Errorf("failed to store match of accident %d: %w", acc.ID, err)
+		}
+		accidentMatches.WithLabelValues(m.Method).Inc()
+		if m.ConzoneID != "" {
+			located++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return len(pending), located, nil
+}
+
+// geometryVersion identifies the imported road network by the row count and last update
+// of road_conzone_geometry
+func geometryVersion(ctx context.Context, db *sql.DB) (string, error) {
+	var version string
+	err := db.QueryRowContext(ctx, `SELECT CONCAT_WS('/', COUNT(*), MAX(updated_at)) FROM road_conzone_geometry`).Scan(&version)
+	if err != nil {
+		return "", fmt.Errorf("failed to query geometry version: %w", err)
+	}
+	return version, nil
+}
+
+// runAccidentMatching matches newly upserted accidents of the last 24 hours every
+// MatchInterval. When the road network changes (and once at startup, in case an import
+// ran while no processor was up) the cached network is reloaded and accidents of the
+// last 30 days that were not placed on a conzone are matched again. Every processor
+// instance runs it; the upsert makes overlaps harmless.
+func (p *Processor) runAccidentMatching(ctx context.Context) {
+	logger := componentLogger("matching")
+	networks := &networkCache{}
+	ticker := time.NewTicker(p.config.MatchInterval)
+	defer ticker.Stop()
+
+	var version string
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("accident matching routine stopped")
+			return
+		case <-ticker.C:
+			current, err := geometryVersion(ctx, p.db)
+			if err != nil {
+				logger.Error("accident matching failed", "error", err)
+				continue
+			}
+			if current != version {
+				networks.network = nil
+				total, located, err := matchAccidents(ctx, p.db, networks, unlocatedRematchWindow,
+					p.config.MatchMaxDistance, matchUnlocated)
+				if err != nil {
+					logger.Error("re-matching after geometry change failed", "error", err)
+					continue
+				}
+				version = current
+				logger.Info("re-matched unlocated accidents for the current road network",
+					"geometry_version", current, "accidents", total, "located", located)
+				continue
+			}
+
+			total, located, err := matchAccidents(ctx, p.db, networks, 24*time.Hour, p.config.MatchMaxDistance, matchNew)
+			if err != nil {
+				logger.Error("accident matching failed", "error", err)
+				continue
+			}
+			if total > 0 {
+				logger.Info("matched accidents", "accidents", total, "located", located)
+			}
+		}
+	}
+}
+
+// runGeometryMatch implements "geometry match", re-running the matching after a geometry
+// import
+func runGeometryMatch(config Config, args []string) error {
+	fs := flag.NewFlagSet("geometry match", flag.ExitOnError)
+	since := fs.Duration("since", 30*24*time.Hour, "match accidents upserted within this window")
+	maxDistance := fs.Float64("max-distance", config.MatchMaxDistance, "maximum distance in metres from a conzone line")
+	rematch := fs.Bool("rematch", false, "also re-match accidents already placed on a conzone")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	scope := matchUnlocated
+	if *rematch {
+		scope = matchAll
+	}
+
+	db, err := openDB(config)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	total, located, err := matchAccidents(ctx, db, &networkCache{}, *since, *maxDistance, scope)
+	if err != nil {
+		return err
+	}
+	componentLogger("matching").Info("matched accidents", "accidents", total, "located", located, "since", *since)
+	return nil
+}
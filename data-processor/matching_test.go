@@ -0,0 +1,242 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestRouteKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "경부선", want: "경부"},
+		{name: "경부고속도로", want: "경부"},
+		{name: "경부 고속도로", want: "경부"},
+		{name: " 서울외곽순환고속국도 ", want: "서울외곽순환"},
+		{name: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := routeKey(tt.name); got != tt.want {
+			t.Errorf("routeKey(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNodeKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "서울TG", want: "서울"},
+		{name: "서울 요금소", want: "서울"},
+		{name: "서울", want: "서울"},
+		{name: "양재ic", want: "양재"},
+		{name: "신갈JC(영동)", want: "신갈"},
+		{name: "죽전휴게소[부산방향]", want: "죽전"},
+		{name: "판교분기점", want: "판교"},
+		{name: "IC", want: "IC"}, // never trimmed to nothing
+	}
+	for _, tt := range tests {
+		if got := nodeKey(tt.name); got != tt.want {
+			t.Errorf("nodeKey(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConzoneNodes(t *testing.T) {
+	tests := []struct {
+		name string
+		want [2]string
+	}{
+		{name: "양재IC-판교JC", want: [2]string{"양재", "판교"}},
+		{name: "서울TG~신갈JC", want: [2]string{"서울", "신갈"}},
+		{name: "수원IC→오산IC", want: [2]string{"수원", "오산"}},
+		{name: "양재IC", want: [2]string{}},
+		{name: "A-B-C", want: [2]string{}},
+	}
+	for _, tt := range tests {
+		if got := conzoneNodes(tt.name); got != tt.want {
+			t.Errorf("conzoneNodes(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDistanceToLine(t *testing.T) {
+	line := [][2]float64{{127, 37.5}, {127, 37.4}, {127.1, 37.4}}
+	kx := 111320 * math.Cos(37.45*math.Pi/180)
+	tests := []struct {
+		name string
+		p    [2]float64
+		want float64
+	}{
+		{name: "on a vertex", p: [2]float64{127, 37.4}, want: 0},
+		{name: "on a segment", p: [2]float64{127, 37.45}, want: 0},
+		{name: "beside a segment", p: [2]float64{127.001, 37.45}, want: 0.001 * kx},
+		{name: "beyond the start", p: [2]float64{127, 37.501}, want: 110.574},
+		{name: "nearest to the second segment", p: [2]float64{127.05, 37.401}, want: 110.574},
+	}
+	for _, tt := range tests {
+		if got := distanceToLine(tt.p, line); math.Abs(got-tt.want) > 0.5 {
+			t.Errorf("%s: distance = %.2f m, want %.2f m", tt.name, got, tt.want)
+		}
+	}
+	if got := distanceToLine([2]float64{127, 37.5}, [][2]float64{{127, 37.501}, {127, 37.501}}); math.Abs(got-110.574) > 0.5 {
+		t.Errorf("degenerate segment distance = %.2f m", got)
+	}
+}
+
+// testNetwork is a straight stretch of 경부선 running south from 서울 with 영동선
+// branching east at 신갈, and one conzone without geometry
+func testNetwork() *roadNetwork {
+	n := &roadNetwork{
+		routes:  map[string]string{"경부": "0010", "영동": "0500"},
+		byRoute: map[string][]*conzoneInfo{},
+	}
+	for _, c := range []struct {
+		id, routeNo, name string
+		line              [][2]float64
+	}{
+		{id: "CZ1", routeNo: "0010", name: "서울TG-신갈JC", line: [][2]float64{{127, 37.5}, {127, 37.45}}},
+		{id: "CZ2", routeNo: "0010", name: "신갈JC-수원IC", line: [][2]float64{{127, 37.45}, {127, 37.4}}},
+		{id: "CZ3", routeNo: "0010", name: "수원IC-오산IC", line: [][2]float64{{127, 37.4}, {127, 37.35}}},
+		{id: "CZ4", routeNo: "0010", name: "오산IC-안성IC"},
+		{id: "CZ9", routeNo: "0500", name: "신갈JC-용인IC", line: [][2]float64{{127, 37.45}, {127.1, 37.45}}},
+	} {
+		n.conzones = append(n.conzones, &conzoneInfo{ID: c.id, RouteNo: c.routeNo, Nodes: conzoneNodes(c.name), Line: c.line})
+	}
+	sort.Slice(n.conzones, func(i, j int) bool { return n.conzones[i].ID < n.conzones[j].ID })
+	for _, c := range n.conzones {
+		n.byRoute[c.RouteNo] = append(n.byRoute[c.RouteNo], c)
+	}
+	return n
+}
+
+func TestDirection(t *testing.T) {
+	n := testNetwork()
+	byID := map[string]*conzoneInfo{}
+	for _, c := range n.conzones {
+		byID[c.ID] = c
+	}
+	tests := []struct {
+		conzone, dest string
+		want          string
+	}{
+		{conzone: "CZ2", dest: "수원", want: "E"},
+		{conzone: "CZ2", dest: "신갈", want: "S"},
+		{conzone: "CZ2", dest: "오산", want: "E"}, // beyond the end, found on CZ3
+		{conzone: "CZ2", dest: "서울", want: "S"}, // beyond the start, found on CZ1
+		{conzone: "CZ3", dest: "서울", want: "S"},
+		{conzone: "CZ2", dest: "부산", want: ""}, // not on the route
+		{conzone: "CZ2", dest: "용인", want: ""}, // on another route
+		{conzone: "CZ4", dest: "안성", want: "E"},
+		{conzone: "CZ4", dest: "서울", want: ""}, // no line to compare with
+		{conzone: "CZ2", dest: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := n.direction(byID[tt.conzone], tt.dest); got != tt.want {
+			t.Errorf("direction(%s, %q) = %q, want %q", tt.conzone, tt.dest, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	at := func(lon, lat float64) (sql.NullFloat64, sql.NullFloat64) {
+		return sql.NullFloat64{Float64: lon, Valid: true}, sql.NullFloat64{Float64: lat, Valid: true}
+	}
+	accident := func(roadNM, point, sms string, coords ...float64) pendingAccident {
+		acc := pendingAccident{RoadNM: roadNM, AccPointNM: point, SmsText: sms}
+		if len(coords) == 2 {
+			acc.Longitude, acc.Latitude = at(coords[0], coords[1])
+		}
+		return acc
+	}
+
+	tests := []struct {
+		name     string
+		acc      pendingAccident
+		want     accidentMatch
+		distance float64 // metres, geometry matches only
+	}{
+		{
+			name:     "nearest line with the direction from the message",
+			acc:      accident("경부선", "", "오산방향 2차로 사고", 127.0005, 37.42),
+			want:     accidentMatch{RouteNo: "0010", ConzoneID: "CZ2", UpdownTypeCode: "E", Method: matchGeometry},
+			distance: 44.2,
+		},
+		{
+			name:     "heading back to the start",
+			acc:      accident("경부선", "", "서울방향 사고", 127.0005, 37.42),
+			want:     accidentMatch{RouteNo: "0010", ConzoneID: "CZ2", UpdownTypeCode: "S", Method: matchGeometry},
+			distance: 44.2,
+		},
+		{
+			name:     "the named route wins near a junction",
+			acc:      accident("영동고속도로", "", "", 127.0001, 37.4503),
+			want:     accidentMatch{RouteNo: "0500", ConzoneID: "CZ9", Method: matchGeometry},
+			distance: 33.2,
+		},
+		{
+			name:     "the nearest route without a road name",
+			acc:      accident("", "", "", 127.0001, 37.4503),
+			want:     accidentMatch{RouteNo: "0010", ConzoneID: "CZ1", Method: matchGeometry},
+			distance: 8.8,
+		},
+		{
+			name: "too far falls back to names, preferring the conzone leading into the node",
+			acc:  accident("경부선", "수원IC", "오산방향 사고", 127.05, 37.42),
+			want: accidentMatch{RouteNo: "0010", ConzoneID: "CZ2", UpdownTypeCode: "E", Method: matchName},
+		},
+		{
+			name: "the other way leads into the node from the next conzone",
+			acc:  accident("경부선", "수원IC", "서울방향 사고", 127.05, 37.42),
+			want: accidentMatch{RouteNo: "0010", ConzoneID: "CZ3", UpdownTypeCode: "S", Method: matchName},
+		},
+		{
+			name: "both nodes named",
+			acc:  accident("경부선", "신갈JC-수원IC 부근", ""),
+			want: accidentMatch{RouteNo: "0010", ConzoneID: "CZ2", Method: matchName},
+		},
+		{
+			name: "point read from the message without its direction",
+			acc:  accident("경부선", "", "수원 부근 오산방향 사고"),
+			want: accidentMatch{RouteNo: "0010", ConzoneID: "CZ2", UpdownTypeCode: "E", Method: matchName},
+		},
+		{
+			name: "unknown route searches the whole network",
+			acc:  accident("", "용인IC 부근", ""),
+			want: accidentMatch{RouteNo: "0500", ConzoneID: "CZ9", Method: matchName},
+		},
+		{
+			name: "coordinates outside Korea are ignored",
+			acc:  accident("영동선", "용인IC", "", 0, 0),
+			want: accidentMatch{RouteNo: "0500", ConzoneID: "CZ9", Method: matchName},
+		},
+		{
+			name: "route only",
+			acc:  accident("경부선", "어딘가", ""),
+			want: accidentMatch{RouteNo: "0010", Method: matchName},
+		},
+		{
+			name: "nothing matches",
+			acc:  accident("국도1호선", "어딘가", ""),
+			want: accidentMatch{Method: matchNone},
+		},
+	}
+
+	n := testNetwork()
+	for _, tt := range tests {
+		got := n.match(tt.acc, 200)
+		if tt.distance > 0 {
+			if !got.Distance.Valid || math.Abs(got.Distance.Float64-tt.distance) > 0.5 {
+				t.Errorf("%s: distance = %v, want about %.1f m", tt.name, got.Distance, tt.distance)
+			}
+			got.Distance = sql.NullFloat64{}
+		}
+		if got != tt.want {
+			t.Errorf("%s: match = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
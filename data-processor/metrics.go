@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	messageDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "processor_message_duration_seconds",
+		Help:    "Time to process and acknowledge one stream message.",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	})
+
+	messagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "processor_messages_total",
+		Help: "Stream messages handled per result (processed, stale, invalid, failed, dead_lettered).",
+	}, []string{"result"})
+
+	accidentsUpserted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "processor_accidents_total",
+		Help: "Accident records written per result (upserted, failed).",
+	}, []string{"result"})
+
+	accidentMatches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "processor_accident_matches_total",
+		Help: "Accidents assigned to a road section per method (geometry, name, none).",
+	}, []string{"method"})
+)
+
+var (
+	streamLengthDesc = prometheus.NewDesc("processor_stream_length",
+		"Entries currently in the stream.", []string{"stream"}, nil)
+	streamLagDesc = prometheus.NewDesc("processor_stream_lag",
+		"Entries not yet delivered to the consumer group.", []string{"stream", "group"}, nil)
+	groupPendingDesc = prometheus.NewDesc("processor_stream_pending",
+		"Entries delivered to the consumer group but not acknowledged.", []string{"stream", "group"}, nil)
+	consumerPendingDesc = prometheus.NewDesc("processor_consumer_pending",
+		"Unacknowledged entries per consumer.", []string{"stream", "group", "consumer"}, nil)
+	consumerIdleDesc = prometheus.NewDesc("processor_consumer_idle_seconds",
+		"Time since each consumer last interacted with the stream.", []string{"stream", "group", "consumer"}, nil)
+)
+
+// streamCollector reads consumer group state from Redis at scrape time, so every
+// replica reports the same group-wide lag alongside the per-consumer pending counts
+type streamCollector struct {
+	p *Processor
+}
+
+// Describe implements prometheus.Collector
+func (s streamCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- streamLengthDesc
+	ch <- streamLagDesc
+	ch <- groupPendingDesc
+	ch <- consumerPendingDesc
+	ch <- consumerIdleDesc
+}
+
+// Collect implements prometheus.Collector
+func (s streamCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	rdb := s.p.redisClient
+	stream, group := s.p.config.StreamKey, s.p.config.ConsumerGroup
+
+	if length, err := rdb.XLen(ctx, stream).Result(); err == nil {
+		ch <- prometheus.MustNewConstMetric(streamLengthDesc, prometheus.GaugeValue, float64(length), stream)
+	} else {
+		slog.Warn("metrics: failed to read stream length", "error", err)
+	}
+
+	groups, err := rdb.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		slog.Warn("metrics: failed to read consumer groups", "error", err)
+		return
+	}
+	for _, g := range groups {
+		if g.Name != group {
+			continue
+		}
+		// Lag needs Redis 7; it reads as 0 on older servers or when it cannot be computed
+		ch <- prometheus.MustNewConstMetric(streamLagDesc, prometheus.GaugeValue, float64(g.Lag), stream, group)
+		ch <- prometheus.MustNewConstMetric(groupPendingDesc, prometheus.GaugeValue, float64(g.Pending), stream, group)
+	}
+
+	consumers, err := rdb.XInfoConsumers(ctx, stream, group).Result()
+	if err != nil {
+		slog.Warn("metrics: failed to read consumers", "error", err)
+		return
+	}
+	for _, c := range consumers {
+		ch <- prometheus.MustNewConstMetric(consumerPendingDesc, prometheus.GaugeValue, float64(c.Pending), stream, group, c.Name)
+		ch <- prometheus.MustNewConstMetric(consumerIdleDesc, prometheus.GaugeValue, c.Idle.Seconds(), stream, group, c.Name)
+	}
+}
@@ -79,3 +79,11 @@ CREATE TABLE IF NOT EXISTS road_traffic_status_cache (
     INDEX idx_route_no (route_no),
     INDEX idx_std_date_hour (std_date, std_hour)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Collector Watermarks: the newest interval of each API whose pages were all saved, so a
+-- restarted openapi-collector resumes after it instead of after a partly saved interval
+CREATE TABLE IF NOT EXISTS collector_watermarks (
+    name VARCHAR(32) PRIMARY KEY,
+    completed_interval CHAR(12) NOT NULL COMMENT 'YYYYMMDDHHMM (KST)',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
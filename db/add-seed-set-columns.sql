@@ -0,0 +1,20 @@
+-- Simulator seed data: versioned seed sets and dedupe key
+-- 기존 simulator_seed_data 테이블에 seed set 구분과 중복 방지 키 추가
+
+ALTER TABLE simulator_seed_data
+    ADD COLUMN IF NOT EXISTS seed_set VARCHAR(50) NOT NULL DEFAULT 'default' COMMENT 'Seed 세트 이름 (버전)' AFTER id,
+    ADD COLUMN IF NOT EXISTS dedupe_key CHAR(40) NULL COMMENT '중복 방지 키 (SHA1: acc_type|road_nm|acc_point_nm|sms_text)' AFTER seed_set;
+
+-- 기존 행의 dedupe_key 채우기
+UPDATE simulator_seed_data
+SET dedupe_key = SHA1(CONCAT_WS('|', IFNULL(acc_type, ''), IFNULL(road_nm, ''), IFNULL(acc_point_nm, ''), sms_text))
+WHERE dedupe_key IS NULL;
+
+-- 같은 세트 안에서 중복된 행 정리 (가장 오래된 행만 유지)
+DELETE s1 FROM simulator_seed_data s1
+INNER JOIN simulator_seed_data s2
+    ON s1.seed_set = s2.seed_set AND s1.dedupe_key = s2.dedupe_key AND s1.id > s2.id;
+
+ALTER TABLE simulator_seed_data
+    ADD UNIQUE KEY IF NOT EXISTS uk_seed_set_dedupe (seed_set, dedupe_key),
+    ADD INDEX IF NOT EXISTS idx_seed_set (seed_set);
@@ -84,6 +84,10 @@ CREATE TABLE IF NOT EXISTS daily_accident_stats (
 CREATE TABLE IF NOT EXISTS simulator_seed_data (
   id BIGINT AUTO_INCREMENT PRIMARY KEY,
 
+  -- Seed set (version) and dedupe key
+  seed_set VARCHAR(50) NOT NULL DEFAULT 'default' COMMENT 'Seed 세트 이름 (버전)',
+  dedupe_key CHAR(40) NULL COMMENT '중복 방지 키 (SHA1: acc_type|road_nm|acc_point_nm|sms_text)',
+
   -- Lane information
   lane_yn1 CHAR(1) COMMENT '1차로 여부',
   lane_yn2 CHAR(1) COMMENT '2차로 여부',
@@ -123,7 +127,11 @@ CREATE TABLE IF NOT EXISTS simulator_seed_data (
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP COMMENT '생성일시',
   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '수정일시',
 
+  -- Unique constraint to prevent duplicates within a seed set
+  UNIQUE KEY uk_seed_set_dedupe (seed_set, dedupe_key),
+
   -- Indexes for performance
+  INDEX idx_seed_set (seed_set),
   INDEX idx_acc_type (acc_type),
   INDEX idx_road (road_nm)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='시뮬레이터용 실제 데이터 Seed';
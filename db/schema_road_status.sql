@@ -19,9 +19,56 @@ CREATE TABLE IF NOT EXISTS road_traffic_status (
     UNIQUE KEY uk_traffic_status (vds_id, std_date, std_hour),
     INDEX idx_route (route_no, route_name),
     INDEX idx_collected_at (collected_at),
+    INDEX idx_conzone_collected (conzone_id, collected_at),
     INDEX idx_grade (grade)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='고속도로 실시간 소통정보';
 
+-- 기존 테이블에 구간별 조회 인덱스 추가 (사고 전후 속도 비교용)
+ALTER TABLE road_traffic_status ADD INDEX IF NOT EXISTS idx_conzone_collected (conzone_id, collected_at);
+
+-- 콘존 구간 형상 (지도 레이어, 벡터 타일용)
+-- data-processor geometry import -kind conzone 으로 적재하며, 직접 넣을 때는
+-- X=경도, Y=위도 (WGS84) 순서의 LINESTRING, 예:
+-- INSERT INTO road_conzone_geometry (conzone_id, route_no, geom)
+-- VALUES ('0010CZE010', '0010', ST_GeomFromText('LINESTRING(127.0276 37.4979, 127.0391 37.4830)'));
+CREATE TABLE IF NOT EXISTS road_conzone_geometry (
+    conzone_id VARCHAR(20) NOT NULL COMMENT '콘존ID (road_traffic_status.conzone_id)',
+    route_no VARCHAR(10) NOT NULL COMMENT '노선번호',
+    geom LINESTRING NOT NULL COMMENT '구간 형상 (X=경도, Y=위도)',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (conzone_id),
+    INDEX idx_route (route_no),
+    SPATIAL INDEX sp_geom (geom)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='콘존 구간 형상';
+
+-- VDS(차량검지기) 위치 (data-processor geometry import -kind vds 로 적재)
+CREATE TABLE IF NOT EXISTS road_vds_location (
+    vds_id VARCHAR(20) NOT NULL COMMENT 'VDS_ID (road_traffic_status.vds_id)',
+    route_no VARCHAR(10) NOT NULL COMMENT '노선번호',
+    conzone_id VARCHAR(20) NOT NULL COMMENT '콘존ID',
+    latitude DECIMAL(10,7) NOT NULL COMMENT '위도',
+    longitude DECIMAL(10,7) NOT NULL COMMENT '경도',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (vds_id),
+    INDEX idx_conzone (conzone_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='VDS 위치';
+
+-- 사고 지점의 구간 매칭 (data-processor가 주기적으로 채움, geometry match 로 재매칭)
+-- match_method: geometry(콘존 형상 최근접), name(노선명·지점명), none(매칭 실패)
+CREATE TABLE IF NOT EXISTS accident_road_match (
+    accident_id BIGINT NOT NULL COMMENT 'traffic_accidents.id',
+    route_no VARCHAR(10) NOT NULL COMMENT '노선번호 (매칭 실패 시 빈 값)',
+    conzone_id VARCHAR(20) NOT NULL COMMENT '콘존ID (노선만 매칭되면 빈 값)',
+    updown_type_code CHAR(1) NOT NULL COMMENT '방향(S:기점/E:종점, 알 수 없으면 빈 값)',
+    match_method VARCHAR(10) NOT NULL COMMENT '매칭 방법',
+    distance_m DOUBLE NULL COMMENT '콘존 형상까지 거리(m), geometry 매칭만',
+    occurred_at DATETIME NULL COMMENT '사고 발생 시각 (acc_date + acc_hour)',
+    matched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (accident_id),
+    INDEX idx_conzone (conzone_id, occurred_at),
+    INDEX idx_occurred_at (occurred_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='사고-구간 매칭';
+
 -- 최근 데이터만 유지 (1시간 이상 된 데이터 삭제 위한 이벤트)
 -- 필요시 활성화
 -- CREATE EVENT IF NOT EXISTS cleanup_road_traffic_status
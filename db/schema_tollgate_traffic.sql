@@ -97,6 +97,10 @@ CREATE TABLE IF NOT EXISTS tollgate_master (
     ex_div_code VARCHAR(10) NOT NULL COMMENT '도공/민자 구분코드',
     ex_div_name VARCHAR(50) NOT NULL COMMENT '도공/민자 구분명',
 
+    -- 위치 (WGS84, 운영자 관리, GeoJSON 지도 레이어용)
+    latitude DECIMAL(10,7) NULL COMMENT '위도',
+    longitude DECIMAL(10,7) NULL COMMENT '경도',
+
     -- 메타 정보
     is_active BOOLEAN DEFAULT TRUE COMMENT '활성 여부',
     first_collected_at DATETIME COMMENT '최초 수집 시각',
@@ -112,3 +116,25 @@ CREATE TABLE IF NOT EXISTS tollgate_master (
     INDEX idx_is_active (is_active)
 
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='요금소(영업소) 마스터';
+
+-- 기존 테이블에 위치 컬럼 추가 (OpenAPI에는 좌표가 없으므로 운영자가 관리)
+-- 예: UPDATE tollgate_master SET latitude = 37.4425, longitude = 127.0076 WHERE unit_code = '101';
+ALTER TABLE tollgate_master
+    ADD COLUMN IF NOT EXISTS latitude DECIMAL(10,7) NULL COMMENT '위도' AFTER ex_div_name,
+    ADD COLUMN IF NOT EXISTS longitude DECIMAL(10,7) NULL COMMENT '경도' AFTER latitude;
+
+-- ================================================
+-- 노선-요금소 매핑 (GraphQL RouteSummary.tollgates)
+-- ================================================
+-- OpenAPI에는 노선과 요금소의 관계가 없으므로 운영자가 관리
+-- 예: INSERT INTO tollgate_route (route_no, unit_code, seq) VALUES ('0010', '101', 1);
+CREATE TABLE IF NOT EXISTS tollgate_route (
+    route_no VARCHAR(10) NOT NULL COMMENT '노선번호 (road_route_summary.route_no)',
+    unit_code VARCHAR(10) NOT NULL COMMENT '영업소 코드 (tollgate_master.unit_code)',
+    seq INT NOT NULL DEFAULT 0 COMMENT '노선 기점 기준 순서',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (route_no, unit_code),
+    INDEX idx_unit_code (unit_code)
+
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='노선별 요금소 매핑';
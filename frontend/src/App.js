@@ -5,6 +5,7 @@ import StatsPanel from './components/StatsPanel';
 import TollgateTrafficPanel from './components/TollgateTrafficPanel';
 import RoadRouteSummaryPanel from './components/RoadRouteSummaryPanel';
 import DashboardPanel from './components/DashboardPanel';
+import ClusterStatus from './components/ClusterStatus';
 import { fetchWithRetry } from './utils/fetchWithRetry';
 import { healthMonitor } from './utils/healthCheck';
 
@@ -23,7 +24,7 @@ function App() {
   // Initial load: fetch recent accidents (3 hours worth, typically 20-50 accidents)
   const fetchInitialAccidents = async () => {
     try {
-      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/accidents/latest?limit=100`, {
+      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/v1/accidents/latest?limit=100`, {
         timeout: 15000,
       }, 3);
       const data = await response.json();
@@ -47,7 +48,7 @@ function App() {
   // Polling: check for new accidents
   const fetchAccidents = async () => {
     try {
-      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/accidents/latest?limit=1`, {
+      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/v1/accidents/latest?limit=1`, {
         timeout: 10000,
       }, 2);
       const data = await response.json();
@@ -78,7 +79,7 @@ function App() {
 
   const fetchStats = async () => {
     try {
-      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/accidents/stats`, {
+      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/v1/accidents/stats`, {
         timeout: 10000,
       }, 2);
       const data = await response.json();
@@ -261,8 +262,9 @@ function App() {
                 {stats && <StatsPanel stats={stats} />}
               </div>
 
-              {/* Right Column - Accidents List */}
+              {/* Right Column - Cluster Status and Accidents List */}
               <div className="space-y-6">
+                <ClusterStatus />
                 <AccidentList accidents={accidents} />
               </div>
             </div>
@@ -1,40 +1,76 @@
-import React, { useState, useEffect } from 'react';
+import React, { useState, useEffect, useCallback } from 'react';
+
+const API_GATEWAY_URL = process.env.REACT_APP_API_GATEWAY_URL || '';
+
+// Member clusters as registered in Karmada
+const CLUSTERS = [
+  { id: 'cp-plugfest-member1', name: 'Naver Cloud', color: 'naver', label: 'Member1' },
+  { id: 'cp-plugfest-member2', name: 'NHN Cloud', color: 'nhn', label: 'Member2' },
+];
+
+// Services expected in every active cluster
+const SERVICES = ['api-gateway', 'data-api-service', 'data-collector', 'data-processor'];
+
+// Consumers idle longer than this are shown as stale (their pod is likely gone)
+const STALE_CONSUMER_SECONDS = 60;
+
+const formatIdle = (seconds) => {
+  if (seconds < 60) return `${Math.round(seconds)}초`;
+  if (seconds < 3600) return `${Math.round(seconds / 60)}분`;
+  return `${Math.round(seconds / 3600)}시간`;
+};
 
 const ClusterStatus = () => {
   const [currentTime, setCurrentTime] = useState(new Date());
+  const [status, setStatus] = useState(null);
+  const [error, setError] = useState(null);
+
+  const fetchStatus = useCallback(async () => {
+    try {
+      const response = await fetch(`${API_GATEWAY_URL}/api/v1/cluster/status`, {
+        cache: 'no-cache',
+        signal: AbortSignal.timeout(5000),
+      });
+      if (!response.ok) {
+        throw new Error(`HTTP ${response.status}`);
+      }
+      setStatus(await response.json());
+      setError(null);
+    } catch (err) {
+      console.error('Failed to fetch cluster status:', err);
+      setError(err.message);
+    }
+  }, []);
 
   useEffect(() => {
-    const interval = setInterval(() => {
+    const clock = setInterval(() => {
       setCurrentTime(new Date());
     }, 1000);
 
-    return () => clearInterval(interval);
-  }, []);
+    // Poll often enough to show a failover as it happens
+    fetchStatus();
+    const poll = setInterval(fetchStatus, 5000);
+
+    return () => {
+      clearInterval(clock);
+      clearInterval(poll);
+    };
+  }, [fetchStatus]);
+
+  const instances = status?.instances || [];
+  const consumers = status?.consumers || [];
+  const leader = status?.leader;
+
+  const clusterHealth = (clusterId) => {
+    const running = new Set(
+      instances.filter((i) => i.cluster === clusterId).map((i) => i.service)
+    );
+    if (running.size === 0) return 'down';
+    return SERVICES.every((s) => running.has(s)) ? 'healthy' : 'degraded';
+  };
 
-  // Simulated cluster health (in production, this would come from actual health checks)
-  const clusters = [
-    {
-      name: 'Naver Cloud',
-      status: 'healthy',
-      color: 'naver',
-      region: 'KR-Seoul-1',
-      pods: '4/4',
-      cpu: '45%',
-      memory: '62%',
-    },
-    {
-      name: 'NHN Cloud',
-      status: 'healthy',
-      color: 'nhn',
-      region: 'KR-Seoul-2',
-      pods: '4/4',
-      cpu: '38%',
-      memory: '58%',
-    },
-  ];
-
-  const getStatusBadge = (status) => {
-    switch (status) {
+  const getStatusBadge = (health) => {
+    switch (health) {
       case 'healthy':
         return (
           <span className="flex items-center text-green-400">
@@ -61,6 +97,17 @@ const ClusterStatus = () => {
     }
   };
 
+  const dependencyBadge = (name, dep) => (
+    <div className="bg-slate-800/50 p-2 rounded flex items-center justify-between">
+      <span className="text-slate-400">{name}</span>
+      {dep?.reachable ? (
+        <span className="font-bold text-green-400">{dep.latencyMs.toFixed(1)}ms</span>
+      ) : (
+        <span className="font-bold text-red-400" title={dep?.error}>연결 실패</span>
+      )}
+    </div>
+  );
+
   return (
     <div className="bg-slate-800/50 backdrop-blur-sm rounded-lg shadow-xl p-6 border border-slate-700">
       <h2 className="text-xl font-bold mb-4 flex items-center">
@@ -76,60 +123,92 @@ const ClusterStatus = () => {
         </div>
       </div>
 
+      {error && (
+        <div className="mb-4 bg-red-900/20 border border-red-500 text-red-300 px-3 py-2 rounded text-xs">
+          상태 조회 실패: {error}
+        </div>
+      )}
+
       {/* Cluster Cards */}
       <div className="space-y-4">
-        {clusters.map((cluster, idx) => (
-          <div
-            key={idx}
-            className="bg-slate-700/30 rounded-lg p-4 border border-slate-600 hover:border-slate-500 transition-all"
-          >
-            <div className="flex items-center justify-between mb-3">
-              <div>
-                <h3 className={`font-bold text-${cluster.color}`}>
-                  {cluster.name}
-                </h3>
-                <p className="text-xs text-slate-400">{cluster.region}</p>
+        {CLUSTERS.map((cluster) => {
+          const clusterInstances = instances.filter((i) => i.cluster === cluster.id);
+          return (
+            <div
+              key={cluster.id}
+              className="bg-slate-700/30 rounded-lg p-4 border border-slate-600 hover:border-slate-500 transition-all"
+            >
+              <div className="flex items-center justify-between mb-3">
+                <div>
+                  <h3 className={`font-bold text-${cluster.color}`}>
+                    {cluster.name}
+                  </h3>
+                  <p className="text-xs text-slate-400">{cluster.label}</p>
+                </div>
+                <div className="text-sm">{status && getStatusBadge(clusterHealth(cluster.id))}</div>
               </div>
-              <div className="text-sm">{getStatusBadge(cluster.status)}</div>
-            </div>
 
-            <div className="grid grid-cols-3 gap-2 text-xs">
-              <div className="bg-slate-800/50 p-2 rounded">
-                <div className="text-slate-400">Pods</div>
-                <div className="font-bold text-green-400">{cluster.pods}</div>
-              </div>
-              <div className="bg-slate-800/50 p-2 rounded">
-                <div className="text-slate-400">CPU</div>
-                <div className="font-bold text-blue-400">{cluster.cpu}</div>
-              </div>
-              <div className="bg-slate-800/50 p-2 rounded">
-                <div className="text-slate-400">Memory</div>
-                <div className="font-bold text-purple-400">{cluster.memory}</div>
+              <div className="grid grid-cols-2 gap-2 text-xs">
+                {SERVICES.map((service) => {
+                  const pods = clusterInstances.filter((i) => i.service === service);
+                  const isLeader = pods.some((p) => p.leader);
+                  return (
+                    <div key={service} className="bg-slate-800/50 p-2 rounded">
+                      <div className="text-slate-400">{service}</div>
+                      <div className={`font-bold ${pods.length > 0 ? 'text-green-400' : 'text-slate-500'}`}>
+                        {pods.length > 0 ? `${pods.length} pod` : '-'}
+                        {isLeader && <span className="ml-1 text-yellow-300">★ 리더</span>}
+                      </div>
+                    </div>
+                  );
+                })}
               </div>
             </div>
+          );
+        })}
+      </div>
+
+      {/* Collector Leader */}
+      <div className="mt-6 p-4 bg-slate-700/30 border border-slate-600 rounded-lg text-xs">
+        <h4 className="font-bold text-slate-200 mb-2 text-sm">📡 수집 리더</h4>
+        {leader ? (
+          <div className="space-y-1 text-slate-300">
+            <div>{leader.id}</div>
+            <div className="text-slate-400">
+              {leader.cluster || '클러스터 미확인'} · fencing token {leader.fencingToken} · 리스 {leader.ttlSeconds.toFixed(1)}s
+            </div>
           </div>
-        ))}
+        ) : (
+          <div className="text-yellow-400">리더 없음 (리스 만료, 승계 대기 중)</div>
+        )}
       </div>
 
-      {/* Architecture Info */}
-      <div className="mt-6 p-4 bg-blue-900/20 border border-blue-500/30 rounded-lg">
-        <h4 className="font-bold text-blue-300 mb-2 text-sm">🏗️ 아키텍처</h4>
-        <ul className="text-xs text-slate-300 space-y-1">
-          <li>• Active-Active 이중화</li>
-          <li>• Karmada 멀티클러스터</li>
-          <li>• Istio Service Mesh</li>
-          <li>• Redis Stream 파이프라인</li>
-          <li>• MariaDB 중앙 DB</li>
-        </ul>
+      {/* Processor Consumers */}
+      <div className="mt-4 p-4 bg-slate-700/30 border border-slate-600 rounded-lg text-xs">
+        <h4 className="font-bold text-slate-200 mb-2 text-sm">⚙️ Stream Consumer</h4>
+        {consumers.length === 0 ? (
+          <div className="text-slate-400">등록된 consumer 없음</div>
+        ) : (
+          <ul className="space-y-1">
+            {consumers.map((c) => (
+              <li key={c.name} className="flex justify-between">
+                <span className={c.idleSeconds > STALE_CONSUMER_SECONDS ? 'text-slate-500' : 'text-slate-300'}>
+                  {c.name}
+                  {c.cluster && <span className="text-slate-500"> ({c.cluster})</span>}
+                </span>
+                <span className="text-slate-400">
+                  대기 {c.pending} · 유휴 {formatIdle(c.idleSeconds)}
+                </span>
+              </li>
+            ))}
+          </ul>
+        )}
       </div>
 
-      {/* Failover Indicator */}
-      <div className="mt-4 text-center">
-        <div className="inline-block bg-green-900/20 border border-green-500/30 px-4 py-2 rounded-full">
-          <span className="text-xs text-green-300">
-            ✓ 자동 페일오버 활성화
-          </span>
-        </div>
+      {/* Shared Backends */}
+      <div className="mt-4 grid grid-cols-2 gap-2 text-xs">
+        {dependencyBadge('Redis', status?.dependencies?.redis)}
+        {dependencyBadge('MariaDB', status?.dependencies?.mariadb)}
       </div>
     </div>
   );
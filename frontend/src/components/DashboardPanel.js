@@ -39,7 +39,7 @@ const DashboardPanel = ({ onNavigate, accidents, stats }) => {
   // Fetch tollgate traffic data and get top 10
   const fetchTollgateTop10 = useCallback(async () => {
     try {
-      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/tollgate/traffic`, {
+      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/v1/tollgate/traffic`, {
         timeout: 15000,
       }, 3);
 
@@ -72,7 +72,7 @@ const DashboardPanel = ({ onNavigate, accidents, stats }) => {
   // Fetch route summary and get top 12
   const fetchRouteTop12 = useCallback(async () => {
     try {
-      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/road/summary`, {
+      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/v1/road/summary`, {
         timeout: 15000,
       }, 3);
 
@@ -1,6 +1,9 @@
 import React, { useEffect, useRef } from 'react';
 import L from 'leaflet';
 import 'leaflet/dist/leaflet.css';
+import { fetchWithRetry } from '../utils/fetchWithRetry';
+
+const API_GATEWAY_URL = process.env.REACT_APP_API_GATEWAY_URL || '';
 
 // Fix Leaflet default icon issue with webpack
 delete L.Icon.Default.prototype._getIconUrl;
@@ -14,6 +17,7 @@ const KoreaMap = ({ accidents, miniMode = false }) => {
   const mapRef = useRef(null);
   const mapInstanceRef = useRef(null);
   const markersRef = useRef([]);
+  const sectionsLayerRef = useRef(null);
 
   useEffect(() => {
     // Initialize map only once
@@ -116,6 +120,63 @@ const KoreaMap = ({ accidents, miniMode = false }) => {
     };
   }, [accidents]);
 
+  // Road sections colored by congestion grade (full mode only)
+  useEffect(() => {
+    if (miniMode) return undefined;
+
+    const gradeLabels = { 1: '원활', 2: '서행', 3: '정체' };
+
+    const fetchSections = async () => {
+      try {
+        const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/v1/geo/conzones`, {
+          timeout: 15000,
+        }, 3);
+        const data = await response.json();
+        if (!mapInstanceRef.current) return;
+
+        if (sectionsLayerRef.current) sectionsLayerRef.current.remove();
+        sectionsLayerRef.current = L.geoJSON(data, {
+          style: (feature) => ({
+            color: feature.properties.color,
+            weight: 4,
+            opacity: 0.8,
+          }),
+          onEachFeature: (feature, layer) => {
+            const p = feature.properties;
+            layer.bindPopup(`
+              <div style="font-family: sans-serif; min-width: 180px;">
+                <h3 style="margin: 0 0 8px 0; color: ${p.color}; font-size: 14px; font-weight: bold;">
+                  ${p.routeName} ${p.conzoneName}
+                </h3>
+                <p style="margin: 4px 0; font-size: 12px;">
+                  <strong>소통:</strong> ${gradeLabels[p.grade] || '판정불가'} (${p.speed}km/h)
+                </p>
+                <p style="margin: 4px 0; font-size: 12px;">
+                  <strong>교통량:</strong> ${p.trafficAmount}대
+                </p>
+              </div>
+            `);
+          },
+        }).addTo(mapInstanceRef.current);
+      } catch (err) {
+        console.error('Failed to fetch road sections:', err);
+      }
+    };
+
+    fetchSections();
+
+    // Auto-refresh every 5 minutes, like the road status panel
+    const interval = setInterval(fetchSections, 5 * 60 * 1000);
+
+    return () => {
+      clearInterval(interval);
+      if (sectionsLayerRef.current) {
+        sectionsLayerRef.current.remove();
+        sectionsLayerRef.current = null;
+      }
+    };
+  }, [miniMode]);
+
   // Cleanup on unmount
   useEffect(() => {
     return () => {
@@ -185,6 +246,18 @@ const KoreaMap = ({ accidents, miniMode = false }) => {
             <div className="w-3 h-3 rounded-full bg-purple-500 mr-2"></div>
             <span>장애물</span>
           </div>
+          <div className="flex items-center">
+            <div className="w-4 h-1 bg-green-500 mr-2"></div>
+            <span>원활</span>
+          </div>
+          <div className="flex items-center">
+            <div className="w-4 h-1 bg-yellow-500 mr-2"></div>
+            <span>서행</span>
+          </div>
+          <div className="flex items-center">
+            <div className="w-4 h-1 bg-red-500 mr-2"></div>
+            <span>정체</span>
+          </div>
         </div>
       )}
 
@@ -186,7 +186,7 @@ const RoadRouteSummaryPanel = () => {
 
   const fetchRouteSummary = useCallback(async () => {
     try {
-      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/road/summary`, {
+      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/v1/road/summary`, {
         timeout: 15000, // 15 second timeout
       }, 3); // Retry 3 times
 
@@ -12,7 +12,7 @@ const RoadStatusPanel = () => {
   // Fetch road status data
   const fetchRoadStatus = useCallback(async () => {
     try {
-      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/road/status`, {
+      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/v1/road/status`, {
         timeout: 15000,
       }, 3);
 
@@ -15,7 +15,7 @@ const TollgateTrafficPanel = () => {
   // Fetch and organize data by time periods
   const fetchTollgateData = useCallback(async () => {
     try {
-      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/tollgate/traffic`, {
+      const response = await fetchWithRetry(`${API_GATEWAY_URL}/api/v1/tollgate/traffic`, {
         timeout: 15000,
       }, 3);
 
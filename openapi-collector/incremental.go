@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Upstream publishing cadence of each API
+const (
+	tollgateCadence   = 15 * time.Minute // sum_date/sum_tm advance every 15 minutes (tmType=2)
+	roadStatusCadence = 5 * time.Minute  // std_date/std_hour advance every 5 minutes
+)
+
+// errNotModified is returned by fetchers when the upstream answered 304 Not Modified
+var errNotModified = errors.New("upstream not modified")
+
+// intervalWatermark tracks the newest upstream interval already stored in the cache,
+// the HTTP validators of the last response, and which pages of an interval that is
+// still being fetched have been saved
+type intervalWatermark struct {
+	mu sync.Mutex
+
+	name    string
+	cadence time.Duration
+	latest  time.Time // newest interval fully saved
+
+	etag         string
+	lastModified string
+
+	pending   time.Time    // interval currently being fetched
+	donePages map[int]bool // pages of pending already saved
+}
+
+func newIntervalWatermark(name string, cadence time.Duration) *intervalWatermark {
+	return &intervalWatermark{
+		name:      name,
+		cadence:   cadence,
+		donePages: make(map[int]bool),
+	}
+}
+
+// parseInterval converts an API date/time pair ("20251117", "1530") into a KST time
+func parseInterval(date, tm string) (time.Time, error) {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		loc = time.FixedZone("KST", 9*60*60)
+	}
+	return time.ParseInLocation("20060102 1504", date+" "+tm, loc)
+}
+
+// watermarkLayout is the format of collector_watermarks.completed_interval
+const watermarkLayout = "200601021504"
+
+// loadFromDB initialises the watermark from the last interval completeInterval recorded,
+// so a restarted collector does not re-download intervals it has already stored. The
+// newest cached row is not used: an interval interrupted halfway has rows too, and its
+// missing pages would be skipped.
+func (w *intervalWatermark) loadFromDB(ctx context.Context, db *sql.DB) error {
+	var latest sql.NullString
+	err := db.QueryRowContext(ctx,
+		`SELECT completed_interval FROM collector_watermarks WHERE name = ?`, w.name).Scan(&latest)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load %s watermark: %w", w.name, err)
+	}
+	if !latest.Valid || len(latest.String) != 12 {
+		return nil
+	}
+
+	t, err := parseInterval(latest.String[:8], latest.String[8:])
+	if err != nil {
+		return fmt.Errorf("failed to parse %s watermark %q: %w", w.name, latest.String, err)
+	}
+
+	w.mu.Lock()
+	w.latest = t
+	w.mu.Unlock()
+
+	componentLogger(w.name).Info("resuming after interval", "interval", t.Format("2006-01-02 15:04"))
+	return nil
+}
+
+// due reports whether the next interval could have been published by now
+func (w *intervalWatermark) due(now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.latest.IsZero() || !w.pending.IsZero() {
+		return true
+	}
+	return !now.Before(w.latest.Add(w.cadence))
+}
+
+// isNew reports whether interval t has not been fully saved yet
+func (w *intervalWatermark) isNew(t time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return t.After(w.latest)
+}
+
+// Latest returns the newest fully saved interval
+func (w *intervalWatermark) Latest() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.latest
+}
+
+// setConditional adds If-None-Match / If-Modified-Since from the previous response,
+// unless an interval is only partially saved and must be fetched again
+func (w *intervalWatermark) setConditional(req *http.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.pending.IsZero() {
+		return
+	}
+	if w.etag != "" {
+		req.Header.Set("If-None-Match", w.etag)
+	}
+	if w.lastModified != "" {
+		req.Header.Set("If-Modified-Since", w.lastModified)
+	}
+}
+
+// rememberValidators stores the ETag / Last-Modified of a 200 response
+func (w *intervalWatermark) rememberValidators(resp *http.Response) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.etag = resp.Header.Get("ETag")
+	w.lastModified = resp.Header.Get("Last-Modified")
+}
+
+// beginInterval starts (or resumes) fetching interval t and returns the pages already saved
+func (w *intervalWatermark) beginInterval(t time.Time) map[int]bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.pending.Equal(t) {
+		w.pending = t
+		w.donePages = make(map[int]bool)
+	}
+
+	done := make(map[int]bool, len(w.donePages))
+	for p := range w.donePages {
+		done[p] = true
+	}
+	return done
+}
+
+// markPage records that page of the pending interval has been saved
+func (w *intervalWatermark) markPage(page int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.donePages[page] = true
+}
+
+// complete advances the watermark once every page of the pending interval is saved
+func (w *intervalWatermark) complete(t time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if t.After(w.latest) {
+		w.latest = t
+	}
+	w.pending = time.Time{}
+	w.donePages = make(map[int]bool)
+}
+
+// latestTollgateInterval returns the newest sum_date/sum_tm among records
+func latestTollgateInterval(records []TollgateTraffic) (time.Time, bool) {
+	var latest time.Time
+	for _, t := range records {
+		ts, err := parseInterval(t.SumDate, t.SumTm)
+		if err != nil {
+			continue
+		}
+		if ts.After(latest) {
+			latest = ts
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// newTollgateRecords keeps only records newer than the watermark
+func (w *intervalWatermark) newTollgateRecords(records []TollgateTraffic) []TollgateTraffic {
+	var fresh []TollgateTraffic
+	for _, t := range records {
+		ts, err := parseInterval(t.SumDate, t.SumTm)
+		if err != nil || w.isNew(ts) {
+			// Unparseable rows are passed through; saveTollgateToCache logs and skips them
+			fresh = append(fresh, t)
+		}
+	}
+	return fresh
+}
+
+// latestRoadStatusInterval returns the newest std_date/std_hour among records
+func latestRoadStatusInterval(records []RoadTrafficStatus) (time.Time, bool) {
+	var latest time.Time
+	for _, s := range records {
+		ts, err := parseInterval(s.StdDate, s.StdHour)
+		if err != nil {
+			continue
+		}
+		if ts.After(latest) {
+			latest = ts
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// newRoadStatusRecords keeps only records newer than the watermark
+func (w *intervalWatermark) newRoadStatusRecords(records []RoadTrafficStatus) []RoadTrafficStatus {
+	var fresh []RoadTrafficStatus
+	for _, s := range records {
+		ts, err := parseInterval(s.StdDate, s.StdHour)
+		if err != nil || w.isNew(ts) {
+			fresh = append(fresh, s)
+		}
+	}
+	return fresh
+}
+
+// loadWatermarks initialises all watermarks from collector_watermarks
+func (c *Collector) loadWatermarks(ctx context.Context) {
+	for _, w := range []*intervalWatermark{c.tollgateMark, c.roadStatusMark} {
+		if err := w.loadFromDB(ctx, c.db); err != nil {
+			slog.Warn("failed to load watermark", "error", err)
+		}
+	}
+}
+
+// completeInterval advances w past interval t and records t in collector_watermarks. A
+// failed write only means t is downloaded again after a restart, so it is logged.
+func (c *Collector) completeInterval(ctx context.Context, w *intervalWatermark, t time.Time) {
+	w.complete(t)
+	_, err := c.db.ExecContext(ctx, `INSERT INTO collector_watermarks (name, completed_interval)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE
+			completed_interval = GREATEST(completed_interval, VALUES(completed_interval)),
+			updated_at = CURRENT_TIMESTAMP`,
+		w.name, t.Format(watermarkLayout))
+	if err != nil {
+		componentLogger(w.name).Warn("failed to record completed interval",
+			"interval", t.Format("2006-01-02 15:04"), "error", err)
+	}
+}
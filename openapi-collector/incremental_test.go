@@ -0,0 +1,171 @@
+package main
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustInterval(t *testing.T, date, tm string) time.Time {
+	t.Helper()
+	ts, err := parseInterval(date, tm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		date, tm string
+		want     string // RFC 3339
+		wantErr  bool
+	}{
+		{date: "20251117", tm: "1530", want: "2025-11-17T15:30:00+09:00"},
+		{date: "20251231", tm: "2345", want: "2025-12-31T23:45:00+09:00"},
+		{date: "20251117", tm: "2460", wantErr: true},
+		{date: "2025-11-17", tm: "1530", wantErr: true},
+		{date: "", tm: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseInterval(tt.date, tt.tm)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseInterval(%q, %q) error = %v, want error %v", tt.date, tt.tm, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Format(time.RFC3339) != tt.want {
+			t.Errorf("parseInterval(%q, %q) = %s, want %s", tt.date, tt.tm, got.Format(time.RFC3339), tt.want)
+		}
+	}
+}
+
+func TestWatermarkDue(t *testing.T) {
+	latest := mustInterval(t, "20251117", "1500")
+
+	tests := []struct {
+		name    string
+		latest  time.Time
+		pending time.Time
+		now     time.Time
+		want    bool
+	}{
+		{name: "nothing saved yet", now: latest, want: true},
+		{name: "before the next interval", latest: latest, now: latest.Add(14 * time.Minute), want: false},
+		{name: "next interval due", latest: latest, now: latest.Add(15 * time.Minute), want: true},
+		{name: "partial interval is retried", latest: latest, pending: latest.Add(15 * time.Minute), now: latest.Add(time.Minute), want: true},
+	}
+	for _, tt := range tests {
+		w := newIntervalWatermark("tollgate", tollgateCadence)
+		w.latest, w.pending = tt.latest, tt.pending
+		if got := w.due(tt.now); got != tt.want {
+			t.Errorf("%s: due = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWatermarkPages(t *testing.T) {
+	w := newIntervalWatermark("tollgate", tollgateCadence)
+	first := mustInterval(t, "20251117", "1500")
+	second := first.Add(tollgateCadence)
+
+	if done := w.beginInterval(first); len(done) != 0 {
+		t.Fatalf("new interval has done pages %v", done)
+	}
+	w.markPage(1)
+	w.markPage(3)
+
+	// Resuming the same interval keeps the saved pages and skips validators
+	if done := w.beginInterval(first); !reflect.DeepEqual(done, map[int]bool{1: true, 3: true}) {
+		t.Errorf("resumed done pages = %v", done)
+	}
+	w.etag = `"abc"`
+	req, _ := http.NewRequest(http.MethodGet, "http://example.test", nil)
+	w.setConditional(req)
+	if req.Header.Get("If-None-Match") != "" {
+		t.Error("conditional request sent while an interval is partially saved")
+	}
+
+	// A newer interval starts from scratch
+	if done := w.beginInterval(second); len(done) != 0 {
+		t.Errorf("next interval has done pages %v", done)
+	}
+
+	w.complete(second)
+	if !w.Latest().Equal(second) {
+		t.Errorf("latest = %v, want %v", w.Latest(), second)
+	}
+	w.complete(first) // an older interval never moves the watermark back
+	if !w.Latest().Equal(second) {
+		t.Errorf("latest moved back to %v", w.Latest())
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "http://example.test", nil)
+	w.setConditional(req)
+	if req.Header.Get("If-None-Match") != `"abc"` {
+		t.Errorf("If-None-Match = %q after the interval completed", req.Header.Get("If-None-Match"))
+	}
+}
+
+func TestRememberValidators(t *testing.T) {
+	w := newIntervalWatermark("road_status", roadStatusCadence)
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("ETag", `W/"v1"`)
+	resp.Header.Set("Last-Modified", "Mon, 17 Nov 2025 06:30:00 GMT")
+	w.rememberValidators(resp)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.test", nil)
+	w.setConditional(req)
+	if req.Header.Get("If-None-Match") != `W/"v1"` || req.Header.Get("If-Modified-Since") != "Mon, 17 Nov 2025 06:30:00 GMT" {
+		t.Errorf("conditional headers = %v", req.Header)
+	}
+}
+
+func TestNewTollgateRecords(t *testing.T) {
+	records := []TollgateTraffic{
+		{UnitCode: "101", SumDate: "20251117", SumTm: "1445"},
+		{UnitCode: "102", SumDate: "20251117", SumTm: "1500"},
+		{UnitCode: "103", SumDate: "20251117", SumTm: "1515"},
+		{UnitCode: "104", SumDate: "bad", SumTm: "x"},
+	}
+
+	latest, ok := latestTollgateInterval(records)
+	if !ok || !latest.Equal(mustInterval(t, "20251117", "1515")) {
+		t.Errorf("latest interval = %v, %v", latest, ok)
+	}
+	if _, ok := latestTollgateInterval(records[3:]); ok {
+		t.Error("latest interval found among unparseable records")
+	}
+
+	w := newIntervalWatermark("tollgate", tollgateCadence)
+	w.latest = mustInterval(t, "20251117", "1500")
+
+	var got []string
+	for _, r := range w.newTollgateRecords(records) {
+		got = append(got, r.UnitCode)
+	}
+	// Unparseable rows are passed through for the save step to log
+	if want := []string{"103", "104"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("new records = %v, want %v", got, want)
+	}
+}
+
+func TestNewRoadStatusRecords(t *testing.T) {
+	records := []RoadTrafficStatus{
+		{VdsID: "a", StdDate: "20251117", StdHour: "1500"},
+		{VdsID: "b", StdDate: "20251117", StdHour: "1505"},
+	}
+	latest, ok := latestRoadStatusInterval(records)
+	if !ok || !latest.Equal(mustInterval(t, "20251117", "1505")) {
+		t.Errorf("latest interval = %v, %v", latest, ok)
+	}
+
+	w := newIntervalWatermark("road_status", roadStatusCadence)
+	if got := w.newRoadStatusRecords(records); len(got) != 2 {
+		t.Errorf("empty watermark kept %d records, want 2", len(got))
+	}
+	w.latest = mustInterval(t, "20251117", "1500")
+	if got := w.newRoadStatusRecords(records); len(got) != 1 || got[0].VdsID != "b" {
+		t.Errorf("new records = %+v, want only b", got)
+	}
+}
@@ -4,15 +4,23 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 )
 
 // Traffic Accident structures
@@ -123,12 +131,31 @@ type Config struct {
 	AccidentInterval   time.Duration
 	TollgateInterval   time.Duration
 	RoadStatusInterval time.Duration
+
+	// Tollgate page fetching
+	TollgatePagePool PagePoolConfig
+
+	// Shared OpenAPI rate limit / daily quota (REDIS_ADDR empty = local only)
+	RedisAddr   string
+	Governor    GovernorConfig
+	MetricsPort string
 }
 
+// shutdownTimeout bounds how long a running collection cycle may take to finish after
+// SIGTERM; it stays below the default 30s Kubernetes termination grace period
+const shutdownTimeout = 25 * time.Second
+
 type Collector struct {
 	config     Config
 	db         *sql.DB
 	httpClient *http.Client
+	governor   *Governor
+
+	redisClient *redis.Client // nil without REDIS_ADDR
+
+	// Last upstream interval saved per API (incremental fetching)
+	tollgateMark   *intervalWatermark
+	roadStatusMark *intervalWatermark
 }
 
 func loadConfig() Config {
@@ -198,6 +225,58 @@ func loadConfig() Config {
 		}
 	}
 
+	tollgateWorkers := 4
+	if env := os.Getenv("TOLLGATE_WORKERS"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v > 0 {
+			tollgateWorkers = v
+		}
+	}
+
+	tollgateRetries := 3
+	if env := os.Getenv("TOLLGATE_MAX_RETRIES"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v >= 0 {
+			tollgateRetries = v
+		}
+	}
+
+	tollgateBackoff := 1 * time.Second
+	if env := os.Getenv("TOLLGATE_RETRY_BACKOFF"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d > 0 {
+			tollgateBackoff = d
+		}
+	}
+
+	governorRate := 5.0
+	if env := os.Getenv("OPENAPI_RATE_LIMIT"); env != "" {
+		if v, err := strconv.ParseFloat(env, 64); err == nil && v > 0 {
+			governorRate = v
+		}
+	}
+
+	governorBurst := 10
+	if env := os.Getenv("OPENAPI_RATE_BURST"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil && v > 0 {
+			governorBurst = v
+		}
+	}
+
+	var dailyQuota int64 = 50000
+	if env := os.Getenv("OPENAPI_DAILY_QUOTA"); env != "" {
+		if v, err := strconv.ParseInt(env, 10, 64); err == nil && v >= 0 {
+			dailyQuota = v
+		}
+	}
+
+	governorPrefix := os.Getenv("OPENAPI_GOVERNOR_PREFIX")
+	if governorPrefix == "" {
+		governorPrefix = "openapi:governor"
+	}
+
+	metricsPort := os.Getenv("METRICS_PORT")
+	if metricsPort == "" {
+		metricsPort = "9091"
+	}
+
 	return Config{
 		APIKey:             apiKey,
 		DBHost:             dbHost,
@@ -211,6 +290,20 @@ func loadConfig() Config {
 		AccidentInterval:   accidentInterval,
 		TollgateInterval:   tollgateInterval,
 		RoadStatusInterval: roadStatusInterval,
+		TollgatePagePool: PagePoolConfig{
+			Workers:     tollgateWorkers,
+			MaxRetries:  tollgateRetries,
+			BaseBackoff: tollgateBackoff,
+			MaxBackoff:  30 * time.Second,
+		},
+		RedisAddr: os.Getenv("REDIS_ADDR"),
+		Governor: GovernorConfig{
+			Rate:       governorRate,
+			Burst:      governorBurst,
+			DailyQuota: dailyQuota,
+			KeyPrefix:  governorPrefix,
+		},
+		MetricsPort: metricsPort,
 	}
 }
 
@@ -234,23 +327,48 @@ func NewCollector(config Config) (*Collector, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Printf("Connected to database at %s:%s/%s", config.DBHost, config.DBPort, config.DBName)
+	slog.Info("connected to database", "host", config.DBHost, "port", config.DBPort, "name", config.DBName)
 
-	return &Collector{
+	// Share the quota with data-collector through Redis when configured
+	var rdb *redis.Client
+	if config.RedisAddr != "" {
+		rdb = redis.NewClient(&redis.Options{Addr: config.RedisAddr})
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			slog.Warn("Redis not reachable, governor falls back to local limits", "addr", config.RedisAddr, "error", err)
+		} else {
+			slog.Info("connected to Redis (shared OpenAPI quota)", "addr", config.RedisAddr)
+		}
+	}
+
+	collector := &Collector{
 		config: config,
 		db:     db,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-	}, nil
+		tollgateMark:   newIntervalWatermark("tollgate", tollgateCadence),
+		roadStatusMark: newIntervalWatermark("road_status", roadStatusCadence),
+		governor:       NewGovernor(config.Governor, rdb),
+		redisClient:    rdb,
+	}
+
+	collector.loadWatermarks(ctx)
+
+	return collector, nil
 }
 
 // Traffic Accident Collection
-func (c *Collector) fetchAccidents(ctx context.Context) (*AccidentAPIResponse, error) {
+func (c *Collector) fetchAccidents(ctx context.Context) (data *AccidentAPIResponse, err error) {
 	url := fmt.Sprintf("%s?key=%s&type=json&numOfRows=10&pageNo=1&sortType=desc&pagingYn=Y",
 		c.config.AccidentAPIURL, c.config.APIKey)
 
-	log.Printf("[Accident] Fetching from: %s", url)
+	componentLogger("accident").Debug("fetching", "endpoint", "realTimeSms")
+
+	if err := c.governor.Wait(ctx, c.config.APIKey, "realTimeSms"); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+
+	defer observeUpstream("realTimeSms", time.Now(), &err)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -336,7 +454,7 @@ func (c *Collector) saveAccidentsToCache(ctx context.Context, accidents []Traffi
 			acc.SeriesNM, acc.ShldroadYn, collectedAt,
 		)
 		if err != nil {
-			log.Printf("[Accident] Failed to save: %v", err)
+			componentLogger("accident").Error("failed to save", "error", err)
 			continue
 		}
 		saved++
@@ -346,30 +464,41 @@ func (c *Collector) saveAccidentsToCache(ctx context.Context, accidents []Traffi
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	log.Printf("[Accident] Saved %d/%d records to cache", saved, len(accidents))
+	componentLogger("accident").Info("saved records to cache", "saved", saved, "records", len(accidents))
 	return nil
 }
 
-func (c *Collector) collectAccidents(ctx context.Context) error {
+func (c *Collector) collectAccidents(ctx context.Context) (err error) {
+	start := time.Now()
+	saved := 0
+	defer func() { observeCycle("accident", start, saved, err) }()
+
 	data, err := c.fetchAccidents(ctx)
 	if err != nil {
 		return fmt.Errorf("fetch failed: %w", err)
 	}
 
-	log.Printf("[Accident] Fetched %d records", len(data.RealTimeSMSList))
+	componentLogger("accident").Info("fetched records", "records", len(data.RealTimeSMSList))
 
 	if err := c.saveAccidentsToCache(ctx, data.RealTimeSMSList); err != nil {
 		return fmt.Errorf("save failed: %w", err)
 	}
+	saved = len(data.RealTimeSMSList)
 
 	return nil
 }
 
 // Tollgate Traffic Collection
-func (c *Collector) fetchTollgate(ctx context.Context, pageNo int) (*TollgateAPIResponse, error) {
+func (c *Collector) fetchTollgate(ctx context.Context, pageNo int) (page *TollgateAPIResponse, err error) {
 	url := fmt.Sprintf("%s?key=%s&type=json&tmType=2&numOfRows=100&pageNo=%d&carType=1&inoutType=0&tcsType=2",
 		c.config.TollgateAPIURL, c.config.APIKey, pageNo)
 
+	if err := c.governor.Wait(ctx, c.config.APIKey, "trafficIc"); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+
+	defer observeUpstream("trafficIc", time.Now(), &err)
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -378,12 +507,21 @@ func (c *Collector) fetchTollgate(ctx context.Context, pageNo int) (*TollgateAPI
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 	req.Header.Set("Accept", "application/json")
 
+	// Only the first page decides whether a new interval exists
+	if pageNo == 1 {
+		c.tollgateMark.setConditional(req)
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, errNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
@@ -394,12 +532,19 @@ func (c *Collector) fetchTollgate(ctx context.Context, pageNo int) (*TollgateAPI
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	if pageNo == 1 {
+		c.tollgateMark.rememberValidators(resp)
+	}
+
 	return &apiResp, nil
 }
 
-func (c *Collector) saveTollgateToCache(ctx context.Context, traffic []TollgateTraffic) error {
+// saveTollgateToCache writes records in one transaction and returns how many were
+// saved. When any write fails nothing is committed and an error is returned, so the page
+// is not marked done and is fetched again.
+func (c *Collector) saveTollgateToCache(ctx context.Context, traffic []TollgateTraffic) (int, error) {
 	if len(traffic) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	query := `INSERT INTO tollgate_traffic_cache (
@@ -414,13 +559,13 @@ func (c *Collector) saveTollgateToCache(ctx context.Context, traffic []TollgateT
 
 	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
@@ -430,7 +575,7 @@ func (c *Collector) saveTollgateToCache(ctx context.Context, traffic []TollgateT
 	for _, t := range traffic {
 		collectedAt, err := time.ParseInLocation("20060102 1504", t.SumDate+" "+t.SumTm, loc)
 		if err != nil {
-			log.Printf("[Tollgate] Failed to parse time: %v", err)
+			componentLogger("tollgate").Warn("failed to parse time", "error", err)
 			continue
 		}
 
@@ -445,25 +590,39 @@ func (c *Collector) saveTollgateToCache(ctx context.Context, traffic []TollgateT
 			t.SumDate, t.SumTm, collectedAt,
 		)
 		if err != nil {
-			log.Printf("[Tollgate] Failed to save: %v", err)
-			continue
+			return 0, fmt.Errorf("failed to save unit %s at %s %s: %w", t.UnitCode, t.SumDate, t.SumTm, err)
 		}
 		saved++
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	log.Printf("[Tollgate] Saved %d/%d records to cache", saved, len(traffic))
-	return nil
+	componentLogger("tollgate").Info("saved records to cache", "saved", saved, "records", len(traffic))
+	return saved, nil
 }
 
-func (c *Collector) collectTollgate(ctx context.Context) error {
-	log.Printf("[Tollgate] Starting collection...")
+func (c *Collector) collectTollgate(ctx context.Context) (err error) {
+	// Skip the cycle entirely while the next 15-minute interval cannot have been published
+	if !c.tollgateMark.due(time.Now()) {
+		componentLogger("tollgate").Debug("skipping cycle: next interval not due yet",
+			"latest", c.tollgateMark.Latest().Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	componentLogger("tollgate").Info("starting collection")
+
+	start := time.Now()
+	totalSaved := 0
+	defer func() { observeCycle("tollgate", start, totalSaved, err) }()
 
 	// Fetch first page to get total count
-	firstPage, err := c.fetchTollgate(ctx, 1)
+	firstPage, _, err := fetchWithRetry(ctx, c.config.TollgatePagePool, 1, c.fetchTollgate)
+	if errors.Is(err, errNotModified) {
+		componentLogger("tollgate").Info("upstream not modified, skipping cycle")
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to fetch first page: %w", err)
 	}
@@ -472,45 +631,105 @@ func (c *Collector) collectTollgate(ctx context.Context) error {
 		return fmt.Errorf("API error: %s - %s", firstPage.Code, firstPage.Message)
 	}
 
-	log.Printf("[Tollgate] Total %d records, %d pages", firstPage.Count, firstPage.PageSize)
+	interval, ok := latestTollgateInterval(firstPage.TrafficIc)
+	if ok && !c.tollgateMark.isNew(interval) {
+		componentLogger("tollgate").Info("no new interval published, skipping cycle",
+			"latest", interval.Format("2006-01-02 15:04"))
+		return nil
+	}
 
-	totalSaved := 0
+	componentLogger("tollgate").Info("collection sized",
+		"records", firstPage.Count, "pages", firstPage.PageSize, "interval", interval.Format("2006-01-02 15:04"))
 
-	// Process all pages
-	for pageNo := 1; pageNo <= firstPage.PageSize; pageNo++ {
-		var pageData *TollgateAPIResponse
+	// Pages already saved for this interval in an earlier, partially failed cycle
+	donePages := c.tollgateMark.beginInterval(interval)
+	if len(donePages) > 0 {
+		componentLogger("tollgate").Info("resuming interval", "pages_done", len(donePages))
+	}
 
-		if pageNo == 1 {
-			pageData = firstPage
-		} else {
-			pageData, err = c.fetchTollgate(ctx, pageNo)
-			if err != nil {
-				log.Printf("[Tollgate] Failed to fetch page %d: %v", pageNo, err)
-				continue
-			}
+	var mu sync.Mutex
+	processed := len(donePages)
+
+	savePage := func(ctx context.Context, pageNo int, pageData *TollgateAPIResponse) error {
+		// Only rows newer than the last saved interval are written
+		fresh := c.tollgateMark.newTollgateRecords(pageData.TrafficIc)
+		saved, err := c.saveTollgateToCache(ctx, fresh)
+		if err != nil {
+			return fmt.Errorf("failed to save page %d: %w", pageNo, err)
 		}
+		c.tollgateMark.markPage(pageNo)
+
+		mu.Lock()
+		defer mu.Unlock()
+		totalSaved += saved
+		processed++
+		if processed%10 == 0 || processed == firstPage.PageSize {
+			componentLogger("tollgate").Debug("progress", "processed_pages", processed, "pages", firstPage.PageSize, "records_saved", totalSaved)
+		}
+		return nil
+	}
 
-		if err := c.saveTollgateToCache(ctx, pageData.TrafficIc); err != nil {
-			log.Printf("[Tollgate] Failed to save page %d: %v", pageNo, err)
-			continue
+	// Page 1 is already in hand; the rest go through the worker pool
+	var pages []int
+	firstFailed := false
+	if !donePages[1] {
+		if err := savePage(ctx, 1, firstPage); err != nil {
+			componentLogger("tollgate").Error("first page failed", "error", err)
+			firstFailed = true
+		}
+	}
+	for pageNo := 2; pageNo <= firstPage.PageSize; pageNo++ {
+		if !donePages[pageNo] {
+			pages = append(pages, pageNo)
 		}
+	}
 
-		totalSaved += len(pageData.TrafficIc)
+	// A non-SUCCESS code on a later page is treated like a transport error and retried
+	fetchPage := func(ctx context.Context, pageNo int) (*TollgateAPIResponse, error) {
+		page, err := c.fetchTollgate(ctx, pageNo)
+		if err == nil && page.Code != "SUCCESS" {
+			return nil, fmt.Errorf("API error: %s - %s", page.Code, page.Message)
+		}
+		return page, err
+	}
+	report := fetchPages(ctx, c.config.TollgatePagePool, pages, fetchPage, savePage)
 
-		if pageNo%10 == 0 || pageNo == firstPage.PageSize {
-			log.Printf("[Tollgate] Progress: %d/%d pages, %d records saved", pageNo, firstPage.PageSize, totalSaved)
+	if !donePages[1] {
+		report.Expected++
+		if firstFailed {
+			report.Failed++
+			report.FailedPages = append([]int{1}, report.FailedPages...)
+		} else {
+			report.Fetched++
 		}
 	}
+	report.Duration = time.Since(start)
+	observePages(report)
+
+	if !report.Complete() {
+		// Failed pages are retried next cycle; the error marks this cycle failed
+		return fmt.Errorf("collection incomplete (%d records saved): %s", totalSaved, report)
+	}
+
+	if ok {
+		c.completeInterval(ctx, c.tollgateMark, interval)
+	}
 
-	log.Printf("[Tollgate] Collection completed: %d total records saved", totalSaved)
+	componentLogger("tollgate").Info("collection completed", "records_saved", totalSaved, "report", report.String())
 	return nil
 }
 
 // Road Status Collection
-func (c *Collector) fetchRoadStatus(ctx context.Context) (*RoadStatusAPIResponse, error) {
+func (c *Collector) fetchRoadStatus(ctx context.Context) (status *RoadStatusAPIResponse, err error) {
 	url := fmt.Sprintf("%s?key=%s&type=json", c.config.RoadStatusAPIURL, c.config.APIKey)
 
-	log.Printf("[RoadStatus] Fetching from: %s", url)
+	componentLogger("road_status").Debug("fetching", "endpoint", "trafficAmountByRealtime")
+
+	if err := c.governor.Wait(ctx, c.config.APIKey, "trafficAmountByRealtime"); err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+
+	defer observeUpstream("trafficAmountByRealtime", time.Now(), &err)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -519,6 +738,7 @@ func (c *Collector) fetchRoadStatus(ctx context.Context) (*RoadStatusAPIResponse
 
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 	req.Header.Set("Accept", "application/json")
+	c.roadStatusMark.setConditional(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -526,6 +746,10 @@ func (c *Collector) fetchRoadStatus(ctx context.Context) (*RoadStatusAPIResponse
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, errNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
@@ -536,12 +760,17 @@ func (c *Collector) fetchRoadStatus(ctx context.Context) (*RoadStatusAPIResponse
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	c.roadStatusMark.rememberValidators(resp)
+
 	return &apiResp, nil
 }
 
-func (c *Collector) saveRoadStatusToCache(ctx context.Context, statusList []RoadTrafficStatus) error {
+// saveRoadStatusToCache writes records in one transaction and returns how many were
+// saved. When any write fails nothing is committed and an error is returned, so the
+// interval is not completed and is fetched again.
+func (c *Collector) saveRoadStatusToCache(ctx context.Context, statusList []RoadTrafficStatus) (int, error) {
 	if len(statusList) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	query := `INSERT INTO road_traffic_status_cache (
@@ -559,13 +788,13 @@ func (c *Collector) saveRoadStatusToCache(ctx context.Context, statusList []Road
 
 	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
 	}
 	defer stmt.Close()
 
@@ -605,144 +834,253 @@ func (c *Collector) saveRoadStatusToCache(ctx context.Context, statusList []Road
 			status.StdDate, status.StdHour, collectedAt,
 		)
 		if err != nil {
-			log.Printf("[RoadStatus] Failed to save: %v", err)
-			continue
+			return 0, fmt.Errorf("failed to save VDS %s at %s %s: %w", status.VdsID, status.StdDate, status.StdHour, err)
 		}
 		saved++
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	log.Printf("[RoadStatus] Saved %d/%d records to cache", saved, len(statusList))
-	return nil
+	componentLogger("road_status").Info("saved records to cache", "saved", saved, "records", len(statusList))
+	return saved, nil
 }
 
-func (c *Collector) collectRoadStatus(ctx context.Context) error {
+func (c *Collector) collectRoadStatus(ctx context.Context) (err error) {
+	// Skip the cycle entirely while the next 5-minute interval cannot have been published
+	if !c.roadStatusMark.due(time.Now()) {
+		componentLogger("road_status").Debug("skipping cycle: next interval not due yet",
+			"latest", c.roadStatusMark.Latest().Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	start := time.Now()
+	saved := 0
+	defer func() { observeCycle("road_status", start, saved, err) }()
+
 	data, err := c.fetchRoadStatus(ctx)
+	if errors.Is(err, errNotModified) {
+		componentLogger("road_status").Info("upstream not modified, skipping cycle")
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("fetch failed: %w", err)
 	}
 
-	log.Printf("[RoadStatus] Fetched %d records", len(data.List))
+	componentLogger("road_status").Info("fetched records", "records", len(data.List))
+
+	interval, ok := latestRoadStatusInterval(data.List)
+	if ok && !c.roadStatusMark.isNew(interval) {
+		componentLogger("road_status").Info("no new interval published, skipping save",
+			"latest", interval.Format("2006-01-02 15:04"))
+		return nil
+	}
 
-	if err := c.saveRoadStatusToCache(ctx, data.List); err != nil {
+	fresh := c.roadStatusMark.newRoadStatusRecords(data.List)
+	if saved, err = c.saveRoadStatusToCache(ctx, fresh); err != nil {
 		return fmt.Errorf("save failed: %w", err)
 	}
 
+	if ok {
+		c.completeInterval(ctx, c.roadStatusMark, interval)
+	}
+
 	return nil
 }
 
 // Goroutine starters
-func (c *Collector) startAccidentCollector(ctx context.Context) {
-	log.Printf("[Accident] Collector started (Interval: %v)", c.config.AccidentInterval)
+func (c *Collector) startAccidentCollector(ctx, stop context.Context) {
+	componentLogger("accident").Info("collector started", "interval", c.config.AccidentInterval)
 
 	ticker := time.NewTicker(c.config.AccidentInterval)
 	defer ticker.Stop()
 
 	// Collect immediately
 	if err := c.collectAccidents(ctx); err != nil {
-		log.Printf("[Accident] Initial collection failed: %v", err)
+		componentLogger("accident").Error("initial collection failed", "error", err)
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("[Accident] Collector stopped")
+			componentLogger("accident").Info("collector stopped")
+			return
+		case <-stop.Done():
+			componentLogger("accident").Info("collector stopped", "reason", "shutdown")
 			return
 		case <-ticker.C:
 			if err := c.collectAccidents(ctx); err != nil {
-				log.Printf("[Accident] Collection error: %v", err)
+				componentLogger("accident").Error("collection failed", "error", err)
 			}
 		}
 	}
 }
 
-func (c *Collector) startTollgateCollector(ctx context.Context) {
-	log.Printf("[Tollgate] Collector started (Interval: %v)", c.config.TollgateInterval)
+func (c *Collector) startTollgateCollector(ctx, stop context.Context) {
+	componentLogger("tollgate").Info("collector started", "interval", c.config.TollgateInterval)
 
 	ticker := time.NewTicker(c.config.TollgateInterval)
 	defer ticker.Stop()
 
 	// Collect immediately
 	if err := c.collectTollgate(ctx); err != nil {
-		log.Printf("[Tollgate] Initial collection failed: %v", err)
+		componentLogger("tollgate").Error("initial collection failed", "error", err)
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("[Tollgate] Collector stopped")
+			componentLogger("tollgate").Info("collector stopped")
+			return
+		case <-stop.Done():
+			componentLogger("tollgate").Info("collector stopped", "reason", "shutdown")
 			return
 		case <-ticker.C:
 			if err := c.collectTollgate(ctx); err != nil {
-				log.Printf("[Tollgate] Collection error: %v", err)
+				componentLogger("tollgate").Error("collection failed", "error", err)
 			}
 		}
 	}
 }
 
-func (c *Collector) startRoadStatusCollector(ctx context.Context) {
-	log.Printf("[RoadStatus] Collector started (Interval: %v)", c.config.RoadStatusInterval)
+func (c *Collector) startRoadStatusCollector(ctx, stop context.Context) {
+	componentLogger("road_status").Info("collector started", "interval", c.config.RoadStatusInterval)
 
 	ticker := time.NewTicker(c.config.RoadStatusInterval)
 	defer ticker.Stop()
 
 	// Collect immediately
 	if err := c.collectRoadStatus(ctx); err != nil {
-		log.Printf("[RoadStatus] Initial collection failed: %v", err)
+		componentLogger("road_status").Error("initial collection failed", "error", err)
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("[RoadStatus] Collector stopped")
+			componentLogger("road_status").Info("collector stopped")
+			return
+		case <-stop.Done():
+			componentLogger("road_status").Info("collector stopped", "reason", "shutdown")
 			return
 		case <-ticker.C:
 			if err := c.collectRoadStatus(ctx); err != nil {
-				log.Printf("[RoadStatus] Collection error: %v", err)
+				componentLogger("road_status").Error("collection failed", "error", err)
 			}
 		}
 	}
 }
 
 func (c *Collector) Close() error {
+	if c.redisClient != nil {
+		c.redisClient.Close()
+	}
 	return c.db.Close()
 }
 
 func main() {
+	setupLogging("openapi-collector")
+
 	// Load .env file if exists
 	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables or defaults")
+		slog.Info("no .env file found, using environment variables or defaults")
 	} else {
-		log.Println("Loaded configuration from .env file")
+		slog.Info("loaded configuration from .env file")
 	}
 
 	config := loadConfig()
 
-	log.Printf("========================================")
-	log.Printf("OpenAPI Multi-Collector v2.0")
-	log.Printf("========================================")
-	log.Printf("Database: %s:%s/%s", config.DBHost, config.DBPort, config.DBName)
-	log.Printf("Accident API: %s (Interval: %v)", config.AccidentAPIURL, config.AccidentInterval)
-	log.Printf("Tollgate API: %s (Interval: %v)", config.TollgateAPIURL, config.TollgateInterval)
-	log.Printf("RoadStatus API: %s (Interval: %v)", config.RoadStatusAPIURL, config.RoadStatusInterval)
-	log.Printf("========================================")
+	slog.Info("OpenAPI Multi-Collector v2.0",
+		slog.Group("database",
+			"host", config.DBHost,
+			"port", config.DBPort,
+			"name", config.DBName),
+		slog.Group("accident",
+			"api_url", config.AccidentAPIURL,
+			"interval", config.AccidentInterval),
+		slog.Group("tollgate",
+			"api_url", config.TollgateAPIURL,
+			"interval", config.TollgateInterval,
+			"workers", config.TollgatePagePool.Workers),
+		slog.Group("road_status",
+			"api_url", config.RoadStatusAPIURL,
+			"interval", config.RoadStatusInterval),
+		slog.Group("governor",
+			"rate", config.Governor.Rate,
+			"burst", config.Governor.Burst,
+			"daily_quota", config.Governor.DailyQuota,
+			"redis_addr", config.RedisAddr),
+	)
 
 	collector, err := NewCollector(config)
 	if err != nil {
-		log.Fatalf("Failed to create collector: %v", err)
+		fatal("failed to create collector", "error", err)
 	}
 	defer collector.Close()
 
-	ctx := context.Background()
+	// stop is cancelled on SIGINT/SIGTERM so no new cycle starts; ctx is only cancelled
+	// if a running cycle does not finish within shutdownTimeout
+	stop, cancelStop := context.WithCancel(context.Background())
+	defer cancelStop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		slog.Info("shutdown signal received, finishing current collection cycles")
+		cancelStop()
+	}()
+
+	// Expose quota usage for monitoring
+	mux := http.NewServeMux()
+	prometheus.MustRegister(collector.governor)
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: ":" + config.MetricsPort, Handler: mux}
+
+	go func() {
+		slog.Info("metrics endpoint listening", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("metrics server failed", "error", err)
+		}
+	}()
 
 	// Start all collectors in parallel
-	go collector.startAccidentCollector(ctx)
-	go collector.startTollgateCollector(ctx)
-	go collector.startRoadStatusCollector(ctx)
+	var wg sync.WaitGroup
+	for _, run := range []func(ctx, stop context.Context){
+		collector.startAccidentCollector,
+		collector.startTollgateCollector,
+		collector.startRoadStatusCollector,
+	} {
+		wg.Add(1)
+		go func(run func(ctx, stop context.Context)) {
+			defer wg.Done()
+			run(ctx, stop)
+		}(run)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	<-stop.Done()
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		slog.Warn("collection did not finish in time, cancelling", "timeout", shutdownTimeout)
+		cancel()
+		<-done
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("metrics server shutdown", "error", err)
+	}
 
-	// Block forever
-	select {}
+	slog.Info("OpenAPI collector stopped")
 }
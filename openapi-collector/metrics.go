@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	upstreamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "collector_upstream_request_duration_seconds",
+		Help:    "Latency of upstream API requests per endpoint.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"endpoint"})
+
+	upstreamErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "collector_upstream_errors_total",
+		Help: "Failed upstream API requests per endpoint.",
+	}, []string{"endpoint"})
+
+	cycleDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "collector_cycle_duration_seconds",
+		Help:    "Duration of a full collection cycle.",
+		Buckets: []float64{0.1, 0.5, 1, 5, 15, 30, 60, 120, 300, 600},
+	}, []string{"collection"})
+
+	cycleRecords = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "collector_cycle_records_saved",
+		Help: "Records saved or published by the last collection cycle.",
+	}, []string{"collection"})
+
+	recordsSaved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "collector_records_saved_total",
+		Help: "Records saved or published since start.",
+	}, []string{"collection"})
+
+	cycleErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "collector_cycle_errors_total",
+		Help: "Collection cycles that ended with an error.",
+	}, []string{"collection"})
+
+	lastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "collector_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful collection cycle.",
+	}, []string{"collection"})
+
+	pagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "collector_pages_total",
+		Help: "Tollgate pages per result (fetched, failed) and retries.",
+	}, []string{"result"})
+)
+
+// observeUpstream records latency and failures of one upstream request; call it deferred
+// with a pointer to the request's named error result. 304 Not Modified is not a failure.
+func observeUpstream(endpoint string, start time.Time, err *error) {
+	upstreamDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+	if *err != nil && !errors.Is(*err, errNotModified) {
+		upstreamErrors.WithLabelValues(endpoint).Inc()
+	}
+}
+
+// observeCycle records the outcome of one collection cycle
+func observeCycle(collection string, start time.Time, saved int, err error) {
+	cycleDuration.WithLabelValues(collection).Observe(time.Since(start).Seconds())
+	if err != nil {
+		cycleErrors.WithLabelValues(collection).Inc()
+		return
+	}
+	cycleRecords.WithLabelValues(collection).Set(float64(saved))
+	recordsSaved.WithLabelValues(collection).Add(float64(saved))
+	lastSuccess.WithLabelValues(collection).SetToCurrentTime()
+}
+
+// observePages records a tollgate page sweep
+func observePages(r PageReport) {
+	pagesTotal.WithLabelValues("fetched").Add(float64(r.Fetched))
+	pagesTotal.WithLabelValues("failed").Add(float64(r.Failed))
+	pagesTotal.WithLabelValues("retried").Add(float64(r.Retries))
+}
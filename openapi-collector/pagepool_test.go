@@ -0,0 +1,189 @@
+// Copy of data-collector/pagepool_test.go, which is canonical, plus the errNotModified case.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPageReportComplete(t *testing.T) {
+	tests := []struct {
+		report PageReport
+		want   bool
+	}{
+		{report: PageReport{Expected: 3, Fetched: 3}, want: true},
+		{report: PageReport{Expected: 0}, want: true},
+		{report: PageReport{Expected: 3, Fetched: 2, Failed: 1}, want: false},
+		{report: PageReport{Expected: 3, Fetched: 2}, want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.report.Complete(); got != tt.want {
+			t.Errorf("%s: Complete = %v, want %v", tt.report, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	config := PagePoolConfig{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt int
+		base    time.Duration // the delay is jittered within [base/2, base]
+	}{
+		{attempt: 1, base: 100 * time.Millisecond},
+		{attempt: 2, base: 200 * time.Millisecond},
+		{attempt: 4, base: 800 * time.Millisecond},
+		{attempt: 5, base: time.Second},
+		{attempt: 80, base: time.Second}, // shift overflow falls back to the cap
+	}
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			if d := config.backoff(tt.attempt); d < tt.base/2 || d > tt.base {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", tt.attempt, d, tt.base/2, tt.base)
+			}
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: errors.New("connection reset"), want: true},
+		{err: fmt.Errorf("page 3: %w", ErrQuotaExhausted), want: false},
+		{err: context.Canceled, want: false},
+		{err: fmt.Errorf("request: %w", context.DeadlineExceeded), want: false},
+		{err: errNotModified, want: false},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.err); got != tt.want {
+			t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFetchPages(t *testing.T) {
+	config := PagePoolConfig{Workers: 3, MaxRetries: 2, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	errFlaky := errors.New("flaky")
+
+	tests := []struct {
+		name        string
+		pages       []int
+		fetch       func(attempts map[int]int, pageNo int) error
+		handleErr   map[int]bool
+		wantFetched int
+		wantFailed  []int
+		wantRetries int
+		wantQuota   bool
+	}{
+		{
+			name:        "all pages succeed",
+			pages:       []int{2, 3, 4, 5},
+			fetch:       func(map[int]int, int) error { return nil },
+			wantFetched: 4,
+		},
+		{
+			name:  "retries until success",
+			pages: []int{2, 3},
+			fetch: func(attempts map[int]int, pageNo int) error {
+				if pageNo == 3 && attempts[pageNo] < 3 {
+					return errFlaky
+				}
+				return nil
+			},
+			wantFetched: 2,
+			wantRetries: 2,
+		},
+		{
+			name:        "gives up after max retries",
+			pages:       []int{2, 3},
+			fetch:       func(_ map[int]int, pageNo int) error { return map[int]error{3: errFlaky}[pageNo] },
+			wantFetched: 1,
+			wantFailed:  []int{3},
+			wantRetries: 2,
+		},
+		{
+			name:        "handle errors fail the page without retry",
+			pages:       []int{2, 3, 4},
+			fetch:       func(map[int]int, int) error { return nil },
+			handleErr:   map[int]bool{4: true},
+			wantFetched: 2,
+			wantFailed:  []int{4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			attempts := make(map[int]int)
+			fetch := func(ctx context.Context, pageNo int) (int, error) {
+				mu.Lock()
+				attempts[pageNo]++
+				err := tt.fetch(attempts, pageNo)
+				mu.Unlock()
+				return pageNo * 10, err
+			}
+			handled := make(map[int]int)
+			handle := func(ctx context.Context, pageNo int, data int) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if tt.handleErr[pageNo] {
+					return errors.New("save failed")
+				}
+				handled[pageNo] = data
+				return nil
+			}
+
+			report := fetchPages(context.Background(), config, tt.pages, fetch, handle)
+			if report.Expected != len(tt.pages) || report.Fetched != tt.wantFetched ||
+				report.Failed != len(tt.wantFailed) || report.Retries != tt.wantRetries {
+				t.Errorf("report = %s", report)
+			}
+			if !reflect.DeepEqual(report.FailedPages, tt.wantFailed) {
+				t.Errorf("failed pages = %v, want %v", report.FailedPages, tt.wantFailed)
+			}
+			for page, data := range handled {
+				if data != page*10 {
+					t.Errorf("page %d handled data %d", page, data)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchPagesStopsOnQuota(t *testing.T) {
+	pages := make([]int, 20)
+	for i := range pages {
+		pages[i] = i + 2
+	}
+	var fetched atomic.Int32
+	fetch := func(ctx context.Context, pageNo int) (struct{}, error) {
+		if pageNo == 4 {
+			return struct{}{}, ErrQuotaExhausted
+		}
+		fetched.Add(1)
+		return struct{}{}, nil
+	}
+	handle := func(context.Context, int, struct{}) error { return nil }
+
+	report := fetchPages(context.Background(), PagePoolConfig{Workers: 1, MaxRetries: 3}, pages, fetch, handle)
+	if !report.QuotaExhausted {
+		t.Error("quota exhaustion not reported")
+	}
+	if report.Retries != 0 {
+		t.Errorf("retries = %d, quota errors must not be retried", report.Retries)
+	}
+	if report.Fetched != 2 || report.Fetched+report.Failed != len(pages) {
+		t.Errorf("report = %s: want 2 fetched and every other page failed", report)
+	}
+	if int(fetched.Load()) != report.Fetched {
+		t.Errorf("fetched %d pages after the quota ran out", int(fetched.Load())-report.Fetched)
+	}
+}
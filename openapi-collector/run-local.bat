@@ -115,6 +115,6 @@ echo Press Ctrl+C to stop
 echo.
 
 REM Run the collector
-go run main.go
+go run .
 
 endlocal
@@ -130,4 +130,4 @@ $env:TOLLGATE_COLLECT_INTERVAL = $TOLLGATE_COLLECT_INTERVAL
 $env:ROAD_STATUS_COLLECT_INTERVAL = $ROAD_STATUS_COLLECT_INTERVAL
 
 # Run the collector
-go run main.go
+go run .
@@ -107,4 +107,4 @@ export TOLLGATE_COLLECT_INTERVAL
 export ROAD_STATUS_COLLECT_INTERVAL
 
 # Run the collector
-go run main.go
+go run .
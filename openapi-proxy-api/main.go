@@ -5,13 +5,16 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // Traffic Accident structures
@@ -104,6 +107,10 @@ type RoadStatusAPIResponse struct {
 	List    []RoadTrafficStatus `json:"list"`
 }
 
+// shutdownTimeout bounds draining in-flight requests after SIGTERM; it stays below the
+// default 30s Kubernetes termination grace period
+const shutdownTimeout = 25 * time.Second
+
 type Config struct {
 	DBHost     string
 	DBPort     string
@@ -179,7 +186,7 @@ func NewProxyAPI(config Config) (*ProxyAPI, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Printf("Connected to database at %s:%s/%s", config.DBHost, config.DBPort, config.DBName)
+	slog.Info("connected to database", "host", config.DBHost, "port", config.DBPort, "name", config.DBName)
 
 	return &ProxyAPI{
 		config: config,
@@ -237,7 +244,7 @@ func (p *ProxyAPI) getAccidents(ctx context.Context, numOfRows, pageNo int, sort
 			&acc.SeriesNM, &acc.ShldroadYn,
 		)
 		if err != nil {
-			log.Printf("Failed to scan accident: %v", err)
+			componentLogger("accident").Error("failed to scan", "error", err)
 			continue
 		}
 
@@ -295,7 +302,7 @@ func (p *ProxyAPI) handleRealTimeSMS(w http.ResponseWriter, r *http.Request) {
 	// Get accidents from database
 	accidents, totalCount, err := p.getAccidents(ctx, numOfRows, pageNo, sortType)
 	if err != nil {
-		log.Printf("[Accident] Failed to get accidents: %v", err)
+		componentLogger("accident").Error("failed to get data", "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -323,10 +330,10 @@ func (p *ProxyAPI) handleRealTimeSMS(w http.ResponseWriter, r *http.Request) {
 
 	// Encode response
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("[Accident] Failed to encode response: %v", err)
+		componentLogger("accident").Error("failed to encode response", "error", err)
 	}
 
-	log.Printf("[Accident] Served %d accidents (page %d/%d, total %d)", len(accidents), pageNo, pageSize, totalCount)
+	componentLogger("accident").Debug("served records", "records", len(accidents), "page", pageNo, "pages", pageSize, "total", totalCount)
 }
 
 // Tollgate Traffic handlers
@@ -390,7 +397,7 @@ func (p *ProxyAPI) getTollgate(ctx context.Context, tmType, carType, inoutType,
 			&t.SumDate, &t.SumTm,
 		)
 		if err != nil {
-			log.Printf("[Tollgate] Failed to scan: %v", err)
+			componentLogger("tollgate").Error("failed to scan", "error", err)
 			continue
 		}
 
@@ -434,7 +441,7 @@ func (p *ProxyAPI) handleTollgateTraffic(w http.ResponseWriter, r *http.Request)
 	// Get tollgate data from database
 	traffic, totalCount, err := p.getTollgate(ctx, tmType, carType, inoutType, tcsType, numOfRows, pageNo)
 	if err != nil {
-		log.Printf("[Tollgate] Failed to get data: %v", err)
+		componentLogger("tollgate").Error("failed to get data", "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -462,10 +469,10 @@ func (p *ProxyAPI) handleTollgateTraffic(w http.ResponseWriter, r *http.Request)
 
 	// Encode response
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("[Tollgate] Failed to encode response: %v", err)
+		componentLogger("tollgate").Error("failed to encode response", "error", err)
 	}
 
-	log.Printf("[Tollgate] Served %d records (page %d/%d, total %d)", len(traffic), pageNo, pageSize, totalCount)
+	componentLogger("tollgate").Debug("served records", "records", len(traffic), "page", pageNo, "pages", pageSize, "total", totalCount)
 }
 
 // Road Traffic Status handlers
@@ -504,7 +511,7 @@ func (p *ProxyAPI) getRoadStatus(ctx context.Context) ([]RoadTrafficStatus, int,
 			&status.StdDate, &status.StdHour,
 		)
 		if err != nil {
-			log.Printf("[RoadStatus] Failed to scan: %v", err)
+			componentLogger("road_status").Error("failed to scan", "error", err)
 			continue
 		}
 
@@ -524,7 +531,7 @@ func (p *ProxyAPI) handleRoadStatus(w http.ResponseWriter, r *http.Request) {
 	// Get road status data from database
 	statusList, totalCount, err := p.getRoadStatus(ctx)
 	if err != nil {
-		log.Printf("[RoadStatus] Failed to get data: %v", err)
+		componentLogger("road_status").Error("failed to get data", "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -543,10 +550,10 @@ func (p *ProxyAPI) handleRoadStatus(w http.ResponseWriter, r *http.Request) {
 
 	// Encode response
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("[RoadStatus] Failed to encode response: %v", err)
+		componentLogger("road_status").Error("failed to encode response", "error", err)
 	}
 
-	log.Printf("[RoadStatus] Served %d records", len(statusList))
+	componentLogger("road_status").Debug("served records", "records", len(statusList))
 }
 
 // Health check handler
@@ -565,23 +572,28 @@ func (p *ProxyAPI) healthHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
 }
 
-func (p *ProxyAPI) Start() error {
+// Start serves until ctx is cancelled, then drains in-flight requests
+func (p *ProxyAPI) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 
 	// OpenAPI endpoints
-	mux.HandleFunc("/openapi/burstInfo/realTimeSms", p.handleRealTimeSMS)
-	mux.HandleFunc("/openapi/trafficapi/trafficIc", p.handleTollgateTraffic)
-	mux.HandleFunc("/openapi/odtraffic/trafficAmountByRealtime", p.handleRoadStatus)
+	mux.Handle("/openapi/burstInfo/realTimeSms",
+		instrument("/openapi/burstInfo/realTimeSms", p.handleRealTimeSMS))
+	mux.Handle("/openapi/trafficapi/trafficIc",
+		instrument("/openapi/trafficapi/trafficIc", p.handleTollgateTraffic))
+	mux.Handle("/openapi/odtraffic/trafficAmountByRealtime",
+		instrument("/openapi/odtraffic/trafficAmountByRealtime", p.handleRoadStatus))
 
-	// Health check endpoint
-	mux.HandleFunc("/health", p.healthHandler)
+	// Health check and metrics endpoints
+	mux.Handle("/health", instrument("/health", p.healthHandler))
+	mux.Handle("/metrics", promhttp.Handler())
 
 	addr := ":" + p.config.ServerPort
-	log.Printf("Starting OpenAPI Proxy Server v2.0")
-	log.Printf("  - Traffic Accident API: /openapi/burstInfo/realTimeSms")
-	log.Printf("  - Tollgate Traffic API: /openapi/trafficapi/trafficIc")
-	log.Printf("  - Road Status API: /openapi/odtraffic/trafficAmountByRealtime")
-	log.Printf("Listening on %s", addr)
+	slog.Info("OpenAPI Proxy Server listening", "addr", addr, "endpoints", []string{
+		"/openapi/burstInfo/realTimeSms",
+		"/openapi/trafficapi/trafficIc",
+		"/openapi/odtraffic/trafficAmountByRealtime",
+	})
 
 	server := &http.Server{
 		Addr:         addr,
@@ -591,7 +603,21 @@ func (p *ProxyAPI) Start() error {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	return server.ListenAndServe()
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, draining in-flight requests", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
 }
 
 func (p *ProxyAPI) Close() error {
@@ -599,22 +625,38 @@ func (p *ProxyAPI) Close() error {
 }
 
 func main() {
+	setupLogging("openapi-proxy-api")
 	config := loadConfig()
 
-	log.Printf("========================================")
-	log.Printf("OpenAPI Proxy Server v2.0")
-	log.Printf("========================================")
-	log.Printf("Database: %s:%s/%s", config.DBHost, config.DBPort, config.DBName)
-	log.Printf("Server Port: %s", config.ServerPort)
-	log.Printf("========================================")
+	slog.Info("OpenAPI Proxy Server v2.0",
+		"db_host", config.DBHost,
+		"db_port", config.DBPort,
+		"db_name", config.DBName,
+		"port", config.ServerPort,
+	)
 
 	api, err := NewProxyAPI(config)
 	if err != nil {
-		log.Fatalf("Failed to create proxy API: %v", err)
+		fatal("failed to create proxy API", "error", err)
 	}
 	defer api.Close()
 
-	if err := api.Start(); err != nil {
-		log.Fatalf("Server failed: %v", err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle shutdown gracefully
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		slog.Info("shutdown signal received")
+		cancel()
+	}()
+
+	if err := api.Start(ctx); err != nil {
+		fatal("server failed", "error", err)
 	}
+
+	slog.Info("OpenAPI Proxy Server stopped")
 }
@@ -49,7 +49,7 @@ run_service() {
         log_info "Loaded .env from ${service_dir}"
     fi
 
-    go run main.go > ../${service_name}.log 2>&1 &
+    go run . > ../${service_name}.log 2>&1 &
     local pid=$!
     echo ${pid} > ../.${service_name}.pid
     cd ..
@@ -179,6 +179,7 @@ case "${1:-all}" in
         export DB_NAME=trafficdb
         export STREAM_KEY=traffic-accidents
         export CONSUMER_GROUP=processor-group
+        export METRICS_PORT=9092  # data-collector already uses 9090
 
         run_service "data-processor" "data-processor" 0
         log_info "Data Processor running (no HTTP port)"
@@ -186,23 +187,27 @@ case "${1:-all}" in
 
     api)
         export PORT=8081
+        export GRPC_PORT=9093  # data-collector already uses 9090
         export DB_HOST=localhost:3306
         export DB_USER=trafficuser
         export DB_PASSWORD=trafficpass
         export DB_NAME=trafficdb
+        export REDIS_ADDR=localhost:6379
 
         run_service "data-api-service" "data-api-service" 8081
         log_info "Data API Service running on http://localhost:8081"
-        log_info "Test: curl http://localhost:8081/api/accidents/latest"
+        log_info "Test: curl http://localhost:8081/api/v1/accidents/latest"
+        log_info "  → gRPC on localhost:9093 (grpcurl -plaintext localhost:9093 list)"
         ;;
 
     gateway)
         export PORT=8080
         export DATA_API_SERVICE_URL=http://localhost:8081
+        export CORS_ALLOWED_ORIGINS=${CORS_ALLOWED_ORIGINS:-http://localhost:3000}  # frontend dev server
 
         run_service "api-gateway" "api-gateway" 8080
         log_info "API Gateway running on http://localhost:8080"
-        log_info "Test: curl http://localhost:8080/api/accidents/latest"
+        log_info "Test: curl http://localhost:8080/api/v1/accidents/latest"
         ;;
 
     frontend)
@@ -267,8 +272,8 @@ case "${1:-all}" in
         echo ""
         log_info "Service URLs:"
         log_info "  Frontend:          http://localhost:3000"
-        log_info "  API Gateway:       http://localhost:8080/api/accidents/latest"
-        log_info "  Data API Service:  http://localhost:8081/api/accidents/latest"
+        log_info "  API Gateway:       http://localhost:8080/api/v1/accidents/latest"
+        log_info "  Data API Service:  http://localhost:8081/api/v1/accidents/latest"
         log_info "  Traffic Simulator: http://localhost:8083/api/traffic"
         echo ""
         log_info "Logs: tail -f *.log"
@@ -87,7 +87,7 @@ echo ""
 
 # Get statistics
 echo -e "${YELLOW}[5] Current Statistics...${NC}"
-STATS=$(curl -s http://localhost:8081/api/accidents/stats 2>/dev/null)
+STATS=$(curl -s http://localhost:8081/api/v1/accidents/stats 2>/dev/null)
 if [ ! -z "$STATS" ]; then
     echo "$STATS" | jq -r '
         "  Total Accidents: \(.totalAccidents)",
@@ -5,13 +5,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type RealTimeSMS struct {
@@ -31,12 +35,22 @@ type Response struct {
 	RealTimeSMSList []RealTimeSMS `json:"realTimeSMSList"`
 }
 
+// shutdownTimeout bounds draining in-flight requests after SIGTERM; it stays below the
+// default 30s Kubernetes termination grace period
+const shutdownTimeout = 25 * time.Second
+
 type Config struct {
 	DBHost     string
 	DBUser     string
 	DBPassword string
 	DBName     string
 	Port       string
+	SeedSet    string // simulator_seed_data.seed_set to draw accidents from
+
+	// Generation mode: "random" (seed rows at random) or "model" (learned rate tables)
+	Mode         string
+	ModelHistory time.Duration // how far back traffic_accidents is used for learning
+	ModelRefresh time.Duration // how often the rate tables are relearned
 }
 
 type Simulator struct {
@@ -46,6 +60,9 @@ type Simulator struct {
 	currentTarget       int       // Current 5-minute target
 	currentCount        int       // How many generated in current 5-min window
 	windowStartTime     time.Time // When current 5-min window started
+
+	mu    sync.Mutex
+	model *accidentModel // nil until learned (model mode only)
 }
 
 func loadConfig() Config {
@@ -74,12 +91,40 @@ func loadConfig() Config {
 		port = "8083"
 	}
 
+	seedSet := os.Getenv("SEED_SET")
+	if seedSet == "" {
+		seedSet = "default"
+	}
+
+	mode := os.Getenv("SIM_MODE")
+	if mode != ModeModel {
+		mode = ModeRandom
+	}
+
+	modelHistory := 90 * 24 * time.Hour
+	if env := os.Getenv("SIM_MODEL_HISTORY"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil {
+			modelHistory = d
+		}
+	}
+
+	modelRefresh := time.Hour
+	if env := os.Getenv("SIM_MODEL_REFRESH"); env != "" {
+		if d, err := time.ParseDuration(env); err == nil && d > 0 {
+			modelRefresh = d
+		}
+	}
+
 	return Config{
-		DBHost:     dbHost,
-		DBUser:     dbUser,
-		DBPassword: dbPassword,
-		DBName:     dbName,
-		Port:       port,
+		DBHost:       dbHost,
+		DBUser:       dbUser,
+		DBPassword:   dbPassword,
+		DBName:       dbName,
+		Port:         port,
+		SeedSet:      seedSet,
+		Mode:         mode,
+		ModelHistory: modelHistory,
+		ModelRefresh: modelRefresh,
 	}
 }
 
@@ -103,7 +148,7 @@ func NewSimulator(config Config) (*Simulator, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Printf("Connected to MariaDB at %s", config.DBHost)
+	slog.Info("connected to MariaDB", "host", config.DBHost)
 
 	simulator := &Simulator{
 		config:          config,
@@ -126,7 +171,19 @@ func (s *Simulator) setNewTarget() {
 	s.currentTarget = weights[s.rand.Intn(len(weights))]
 	s.currentCount = 0
 	s.windowStartTime = time.Now()
-	log.Printf("New 5-minute window: target %d accidents", s.currentTarget)
+
+	// In model mode, scale the target by how busy this weekday/hour historically is
+	s.mu.Lock()
+	model := s.model
+	s.mu.Unlock()
+	if model != nil {
+		factor := model.intensity(s.windowStartTime)
+		s.currentTarget = int(float64(s.currentTarget)*factor + 0.5)
+		slog.Info("new 5-minute window", "target", s.currentTarget, "intensity", factor)
+		return
+	}
+
+	slog.Info("new 5-minute window", "target", s.currentTarget)
 }
 
 // getRemainingCount calculates how many accidents to generate in this call
@@ -182,11 +239,12 @@ func (s *Simulator) getRandomAccidents(count int) ([]RealTimeSMS, error) {
 	query := `
 		SELECT acc_point_nm, sms_text, acc_type, latitude, altitude, road_nm, nosun_nm
 		FROM simulator_seed_data
+		WHERE seed_set = ?
 		ORDER BY RAND()
 		LIMIT ?
 	`
 
-	rows, err := s.db.QueryContext(ctx, query, count)
+	rows, err := s.db.QueryContext(ctx, query, s.config.SeedSet, count)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query seed data: %w", err)
 	}
@@ -204,7 +262,7 @@ func (s *Simulator) getRandomAccidents(count int) ([]RealTimeSMS, error) {
 
 		err := rows.Scan(&acc.AccPointNM, &acc.AccInfo, &acc.AccType, &lat, &lon, &acc.RoadNM, &nosunNM)
 		if err != nil {
-			log.Printf("Error scanning row: %v", err)
+			slog.Error("failed to scan row", "error", err)
 			continue
 		}
 
@@ -232,6 +290,20 @@ func (s *Simulator) getRandomAccidents(count int) ([]RealTimeSMS, error) {
 	return accidents, nil
 }
 
+// generateAccidents uses the learned model when available and falls back to random seed rows
+func (s *Simulator) generateAccidents(count int) ([]RealTimeSMS, error) {
+	if s.config.Mode == ModeModel && count > 0 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.model != nil {
+			return s.model.generate(s.rand, time.Now(), count), nil
+		}
+		componentLogger("model").Warn("model not ready yet, using random seed rows")
+	}
+
+	return s.getRandomAccidents(count)
+}
+
 func (s *Simulator) trafficHandler(w http.ResponseWriter, r *http.Request) {
 	// Enable CORS
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -247,9 +319,9 @@ func (s *Simulator) trafficHandler(w http.ResponseWriter, r *http.Request) {
 	// Use intelligent distribution based on 5-minute windows
 	count := s.getRemainingCount()
 
-	accidents, err := s.getRandomAccidents(count)
+	accidents, err := s.generateAccidents(count)
 	if err != nil {
-		log.Printf("Error getting random accidents: %v", err)
+		slog.Error("failed to get random accidents", "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -262,12 +334,12 @@ func (s *Simulator) trafficHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding response: %v", err)
+		slog.Error("failed to encode response", "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("Generated %d accidents (window: %d/%d)", len(accidents), s.currentCount, s.currentTarget)
+	slog.Debug("generated accidents", "count", len(accidents), "window_count", s.currentCount, "window_target", s.currentTarget)
 }
 
 func (s *Simulator) healthHandler(w http.ResponseWriter, r *http.Request) {
@@ -275,15 +347,35 @@ func (s *Simulator) healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
-func (s *Simulator) Start() error {
-	http.HandleFunc("/api/traffic", s.trafficHandler)
-	http.HandleFunc("/health", s.healthHandler)
+// Start serves until ctx is cancelled, then drains in-flight requests
+func (s *Simulator) Start(ctx context.Context) error {
+	http.Handle("/api/traffic", instrument("/api/traffic", s.trafficHandler))
+	http.Handle("/health", instrument("/health", s.healthHandler))
+	http.Handle("/metrics", promhttp.Handler())
+
+	if s.config.Mode == ModeModel {
+		go s.runModelRefresh(ctx)
+	}
 
 	addr := ":" + s.config.Port
-	log.Printf("Traffic Simulator starting on %s", addr)
-	log.Printf("Generating 1-3 accidents per call (targeting 8-12 per 5 minutes)")
+	slog.Info("Traffic Simulator starting", "addr", addr, "mode", s.config.Mode)
+
+	server := &http.Server{Addr: addr}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+	}
 
-	return http.ListenAndServe(addr, nil)
+	slog.Info("shutting down, draining in-flight requests", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
 }
 
 func (s *Simulator) Close() error {
@@ -291,20 +383,51 @@ func (s *Simulator) Close() error {
 }
 
 func main() {
+	setupLogging("traffic-simulator")
 	config := loadConfig()
 
-	log.Printf("Configuration:")
-	log.Printf("  DB Host: %s", config.DBHost)
-	log.Printf("  DB Name: %s", config.DBName)
-	log.Printf("  Port: %s", config.Port)
+	// Seed data management: traffic-simulator seed <promote|export|import>
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := runSeedCommand(config, os.Args[2:]); err != nil {
+			fatal("seed command failed", "error", err)
+		}
+		return
+	}
+
+	attrs := []any{
+		"db_host", config.DBHost,
+		"db_name", config.DBName,
+		"port", config.Port,
+		"seed_set", config.SeedSet,
+		"mode", config.Mode,
+	}
+	if config.Mode == ModeModel {
+		attrs = append(attrs, "model_history", config.ModelHistory, "model_refresh", config.ModelRefresh)
+	}
+	slog.Info("configuration", attrs...)
 
 	simulator, err := NewSimulator(config)
 	if err != nil {
-		log.Fatalf("Failed to create simulator: %v", err)
+		fatal("failed to create simulator", "error", err)
 	}
 	defer simulator.Close()
 
-	if err := simulator.Start(); err != nil {
-		log.Fatalf("Simulator failed: %v", err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle shutdown gracefully
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-sigChan
+		slog.Info("shutdown signal received")
+		cancel()
+	}()
+
+	if err := simulator.Start(ctx); err != nil {
+		fatal("simulator failed", "error", err)
 	}
+
+	slog.Info("Traffic Simulator stopped")
 }
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Generation modes selected with SIM_MODE
+const (
+	ModeRandom = "random" // ORDER BY RAND() over seed rows (original behaviour)
+	ModeModel  = "model"  // rate tables learned from traffic_accidents
+)
+
+// seedPoint is a known accident location on a road, taken from the seed set
+type seedPoint struct {
+	AccPointNM string
+	NosunNM    string
+	AccType    string
+	SmsText    string
+	Latitude   float64
+	Longitude  float64
+}
+
+// accidentModel holds per-road, per-hour-of-day and per-weekday accident rates
+// learned from historical traffic_accidents rows
+type accidentModel struct {
+	roadHour    map[string]*[24]float64 // road_nm -> accidents per hour of day
+	roadTotal   map[string]float64      // road_nm -> total accidents
+	weekdayHour [7][24]float64          // weekday x hour -> accidents
+	meanRate    float64                 // mean of weekdayHour over all cells
+	points      map[string][]seedPoint  // road_nm -> seed locations
+	roads       []string                // roads that have both history and seed locations
+	learnedFrom int                     // number of historical rows used
+	builtAt     time.Time
+}
+
+// roadHourSmoothing mixes a road's overall rate into each hour so roads with sparse
+// history still get picked outside the hours they happened to be observed in
+const roadHourSmoothing = 0.5
+
+// smsTemplates are per acc_type message patterns modelled on the real realTimeSms feed.
+// Placeholders: {road} 노선명, {dir} 방향, {point} 지점명, {lane} 차로
+var smsTemplates = map[string][]string{
+	"사고": {
+		"[사고] {road} {dir} {point} {lane} 교통사고, 후방 추돌 주의",
+		"[사고] {road} {dir} {point} 부근 {lane} 차량 사고 처리중",
+		"[사고] {road} {dir} {point} 사고 발생, 서행 운전 바랍니다",
+	},
+	"고장": {
+		"[고장] {road} {dir} {point} {lane} 고장차량, 안전운전 바랍니다",
+		"[고장] {road} {dir} {point} 부근 {lane} 고장차량 처리중",
+	},
+	"공사": {
+		"[공사] {road} {dir} {point} {lane} 차단 공사중",
+		"[공사] {road} {dir} {point} 부근 도로 보수 작업, 감속 운행",
+	},
+	"작업": {
+		"[작업] {road} {dir} {point} {lane} 차단 작업중",
+		"[작업] {road} {dir} {point} 부근 노면 정비 작업중",
+	},
+	"기상": {
+		"[기상] {road} {dir} {point} 부근 안개로 시계 불량, 감속 운행",
+		"[기상] {road} {dir} {point} 노면 결빙 주의",
+	},
+	"기타": {
+		"[기타] {road} {dir} {point} {lane} 낙하물, 주의운전 바랍니다",
+		"[기타] {road} {dir} {point} 부근 보행자 출현, 주의운전",
+	},
+}
+
+var directionPattern = regexp.MustCompile(`(\S+방향)`)
+
+// buildAccidentModel learns rate tables from traffic_accidents within history and
+// attaches the seed locations of seedSet
+func buildAccidentModel(ctx context.Context, db *sql.DB, seedSet string, history time.Duration) (*accidentModel, error) {
+	since := time.Now().Add(-history).Format("20060102")
+
+	// acc_date is stored as YYYYMMDD and acc_hour as HHMM by data-processor
+	rows, err := db.QueryContext(ctx, `
+		SELECT road_nm,
+		       DAYOFWEEK(STR_TO_DATE(acc_date, '%Y%m%d')) - 1 AS weekday,
+		       CAST(SUBSTRING(acc_hour, 1, 2) AS UNSIGNED) AS hour,
+		       COUNT(*) AS cnt
+		FROM traffic_accidents
+		WHERE acc_date >= ? AND road_nm IS NOT NULL AND road_nm <> ''
+		GROUP BY road_nm, weekday, hour`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query accident history: %w", err)
+	}
+	defer rows.Close()
+
+	m := &accidentModel{
+		roadHour:  make(map[string]*[24]float64),
+		roadTotal: make(map[string]float64),
+		points:    make(map[string][]seedPoint),
+		builtAt:   time.Now(),
+	}
+
+	for rows.Next() {
+		var road string
+		var weekday, hour sql.NullInt64
+		var cnt int
+		if err := rows.Scan(&road, &weekday, &hour, &cnt); err != nil {
+			return nil, fmt.Errorf("failed to scan accident history: %w", err)
+		}
+		if !weekday.Valid || !hour.Valid || weekday.Int64 < 0 || weekday.Int64 > 6 || hour.Int64 > 23 {
+			continue
+		}
+
+		if m.roadHour[road] == nil {
+			m.roadHour[road] = &[24]float64{}
+		}
+		m.roadHour[road][hour.Int64] += float64(cnt)
+		m.roadTotal[road] += float64(cnt)
+		m.weekdayHour[weekday.Int64][hour.Int64] += float64(cnt)
+		m.learnedFrom += cnt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	total := 0.0
+	for d := 0; d < 7; d++ {
+		for h := 0; h < 24; h++ {
+			total += m.weekdayHour[d][h]
+		}
+	}
+	m.meanRate = total / (7 * 24)
+
+	if err := m.loadSeedPoints(ctx, db, seedSet); err != nil {
+		return nil, err
+	}
+
+	for road := range m.roadTotal {
+		if len(m.points[road]) > 0 {
+			m.roads = append(m.roads, road)
+		}
+	}
+	if len(m.roads) == 0 {
+		return nil, fmt.Errorf("no roads with both accident history and seed locations")
+	}
+
+	return m, nil
+}
+
+func (m *accidentModel) loadSeedPoints(ctx context.Context, db *sql.DB, seedSet string) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT road_nm, acc_point_nm, nosun_nm, acc_type, sms_text, latitude, altitude
+		FROM simulator_seed_data
+		WHERE seed_set = ? AND latitude IS NOT NULL AND altitude IS NOT NULL
+		  AND road_nm IS NOT NULL AND road_nm <> ''`, seedSet)
+	if err != nil {
+		return fmt.Errorf("failed to query seed locations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var road string
+		var p seedPoint
+		var pointNM, nosunNM, accType sql.NullString
+		if err := rows.Scan(&road, &pointNM, &nosunNM, &accType, &p.SmsText, &p.Latitude, &p.Longitude); err != nil {
+			return fmt.Errorf("failed to scan seed location: %w", err)
+		}
+		p.AccPointNM = pointNM.String
+		p.NosunNM = nosunNM.String
+		p.AccType = accType.String
+		m.points[road] = append(m.points[road], p)
+	}
+
+	return rows.Err()
+}
+
+// intensity returns how busy t is relative to the average weekday/hour, clamped to [0.25, 3]
+func (m *accidentModel) intensity(t time.Time) float64 {
+	if m.meanRate == 0 {
+		return 1
+	}
+	f := m.weekdayHour[int(t.Weekday())][t.Hour()] / m.meanRate
+	return math.Max(0.25, math.Min(3, f))
+}
+
+// pickRoad draws a road weighted by its smoothed rate for the hour of t
+func (m *accidentModel) pickRoad(r *rand.Rand, t time.Time) string {
+	hour := t.Hour()
+	weights := make([]float64, len(m.roads))
+	sum := 0.0
+	for i, road := range m.roads {
+		w := m.roadHour[road][hour] + roadHourSmoothing*m.roadTotal[road]/24
+		weights[i] = w
+		sum += w
+	}
+
+	x := r.Float64() * sum
+	for i, w := range weights {
+		if x < w {
+			return m.roads[i]
+		}
+		x -= w
+	}
+	return m.roads[len(m.roads)-1]
+}
+
+// jitterAlongRoad moves p a short random distance towards or away from the nearest other
+// seed location on the same road, plus a small perpendicular offset, so repeated
+// accidents do not stack on the exact same coordinate
+func jitterAlongRoad(r *rand.Rand, p seedPoint, road []seedPoint) (float64, float64) {
+	var nearest *seedPoint
+	best := math.MaxFloat64
+	for i := range road {
+		q := &road[i]
+		d := math.Hypot(q.Latitude-p.Latitude, q.Longitude-p.Longitude)
+		if d > 1e-5 && d < best {
+			best, nearest = d, q
+		}
+	}
+
+	if nearest == nil || best > 0.2 {
+		// No usable neighbour: roughly ±200m isotropic jitter
+		return p.Latitude + (r.Float64()-0.5)*0.004, p.Longitude + (r.Float64()-0.5)*0.004
+	}
+
+	dLat := nearest.Latitude - p.Latitude
+	dLon := nearest.Longitude - p.Longitude
+	along := (r.Float64() - 0.5) * 0.3 // up to 15% of the gap either way
+	across := (r.Float64() - 0.5) * 0.0004
+	norm := math.Hypot(dLat, dLon)
+
+	lat := p.Latitude + along*dLat + across*(-dLon/norm)
+	lon := p.Longitude + along*dLon + across*(dLat/norm)
+	return lat, lon
+}
+
+// renderSMS fills a template for accType, falling back to the seed text for unknown types
+func renderSMS(r *rand.Rand, accType string, road string, p seedPoint) string {
+	templates, ok := smsTemplates[accType]
+	if !ok {
+		return p.SmsText
+	}
+
+	dir := ""
+	if m := directionPattern.FindStringSubmatch(p.SmsText); m != nil {
+		dir = m[1]
+	}
+	roadName := p.NosunNM
+	if roadName == "" {
+		roadName = road
+	}
+
+	text := templates[r.Intn(len(templates))]
+	text = strings.NewReplacer(
+		"{road}", roadName,
+		"{dir}", dir,
+		"{point}", p.AccPointNM,
+		"{lane}", fmt.Sprintf("%d차로", r.Intn(3)+1),
+	).Replace(text)
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// generate produces count accidents at time now using the learned tables
+func (m *accidentModel) generate(r *rand.Rand, now time.Time, count int) []RealTimeSMS {
+	accDate := now.Format("2006.01.02")
+	accHour := now.Format("15:04:05")
+
+	accidents := make([]RealTimeSMS, 0, count)
+	for i := 0; i < count; i++ {
+		road := m.pickRoad(r, now)
+		points := m.points[road]
+		p := points[r.Intn(len(points))]
+		lat, lon := jitterAlongRoad(r, p, points)
+
+		accidents = append(accidents, RealTimeSMS{
+			AccDate:    accDate,
+			AccHour:    accHour,
+			AccPointNM: p.AccPointNM,
+			LinkID:     fmt.Sprintf("LINK%05d", r.Intn(99999)),
+			AccInfo:    renderSMS(r, p.AccType, road, p),
+			AccType:    p.AccType,
+			Latitude:   math.Round(lat*1e6) / 1e6,
+			Longitude:  math.Round(lon*1e6) / 1e6,
+			RoadNM:     road,
+			NosunNM:    p.NosunNM,
+		})
+	}
+
+	return accidents
+}
+
+// refreshModel rebuilds the model, keeping the previous one if learning fails
+func (s *Simulator) refreshModel(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	m, err := buildAccidentModel(ctx, s.db, s.config.SeedSet, s.config.ModelHistory)
+	if err != nil {
+		componentLogger("model").Error("failed to build accident model", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.model = m
+	s.mu.Unlock()
+
+	componentLogger("model").Info("learned rates", "accidents", m.learnedFrom,
+		"history", s.config.ModelHistory, "roads", len(m.roads))
+}
+
+// runModelRefresh periodically relearns the rate tables
+func (s *Simulator) runModelRefresh(ctx context.Context) {
+	s.refreshModel(ctx)
+
+	ticker := time.NewTicker(s.config.ModelRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshModel(ctx)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIntensity(t *testing.T) {
+	m := &accidentModel{meanRate: 2}
+	monday9 := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	m.weekdayHour[time.Monday][9] = 4
+	m.weekdayHour[time.Monday][3] = 0.1
+	m.weekdayHour[time.Monday][18] = 100
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want float64
+	}{
+		{name: "twice the mean", at: monday9, want: 2},
+		{name: "clamped low", at: monday9.Add(-6 * time.Hour), want: 0.25},
+		{name: "clamped high", at: monday9.Add(9 * time.Hour), want: 3},
+	}
+	for _, tt := range tests {
+		if got := m.intensity(tt.at); got != tt.want {
+			t.Errorf("%s: intensity = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if got := (&accidentModel{}).intensity(monday9); got != 1 {
+		t.Errorf("empty model intensity = %v, want 1", got)
+	}
+}
+
+func TestPickRoadFollowsHourlyRates(t *testing.T) {
+	m := &accidentModel{
+		roads:     []string{"경부선", "영동선"},
+		roadHour:  map[string]*[24]float64{"경부선": {}, "영동선": {}},
+		roadTotal: map[string]float64{"경부선": 24, "영동선": 24},
+	}
+	m.roadHour["경부선"][8] = 30
+	m.roadHour["영동선"][20] = 30
+
+	r := rand.New(rand.NewSource(1))
+	count := func(hour int) int {
+		at := time.Date(2024, 6, 3, hour, 0, 0, 0, time.UTC)
+		n := 0
+		for i := 0; i < 2000; i++ {
+			if m.pickRoad(r, at) == "경부선" {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Weights at 08h: 30.5 vs 0.5; smoothing keeps the quiet road possible
+	if n := count(8); n < 1900 || n == 2000 {
+		t.Errorf("경부선 picked %d/2000 times at 08h, want most but not all", n)
+	}
+	if n := count(20); n > 100 || n == 0 {
+		t.Errorf("경부선 picked %d/2000 times at 20h, want few but some", n)
+	}
+}
+
+func TestJitterAlongRoad(t *testing.T) {
+	p := seedPoint{Latitude: 37.0, Longitude: 127.0}
+	neighbour := seedPoint{Latitude: 37.1, Longitude: 127.0}
+	far := seedPoint{Latitude: 38.0, Longitude: 128.0}
+
+	tests := []struct {
+		name     string
+		road     []seedPoint
+		maxLat   float64 // bound on the latitude offset
+		maxLon   float64 // bound on the longitude offset
+		sameLine bool    // offsets mostly along the neighbour direction
+	}{
+		{name: "along the nearest neighbour", road: []seedPoint{p, neighbour, far}, maxLat: 0.015 + 1e-9, maxLon: 0.0002 + 1e-9, sameLine: true},
+		{name: "isolated point", road: []seedPoint{p, far}, maxLat: 0.002, maxLon: 0.002},
+		{name: "alone", road: []seedPoint{p}, maxLat: 0.002, maxLon: 0.002},
+	}
+
+	r := rand.New(rand.NewSource(7))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 500; i++ {
+				lat, lon := jitterAlongRoad(r, p, tt.road)
+				dLat, dLon := math.Abs(lat-p.Latitude), math.Abs(lon-p.Longitude)
+				if dLat > tt.maxLat || dLon > tt.maxLon {
+					t.Fatalf("offset (%v, %v) exceeds (%v, %v)", dLat, dLon, tt.maxLat, tt.maxLon)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderSMS(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	p := seedPoint{AccPointNM: "기흥IC", NosunNM: "경부선", SmsText: "[사고] 경부선 부산방향 기흥IC 부근 사고"}
+
+	for i := 0; i < 20; i++ {
+		text := renderSMS(r, "사고", "경부고속도로", p)
+		if !strings.HasPrefix(text, "[사고] 경부선 부산방향 기흥IC") {
+			t.Fatalf("rendered %q, want road, direction and point filled in", text)
+		}
+		if strings.Contains(text, "{") || strings.Contains(text, "  ") {
+			t.Fatalf("rendered %q has leftover placeholders or spaces", text)
+		}
+	}
+
+	// Without a direction in the seed text the placeholder collapses
+	noDir := p
+	noDir.SmsText = "사고 발생"
+	noDir.NosunNM = ""
+	if text := renderSMS(r, "공사", "중부선", noDir); !strings.HasPrefix(text, "[공사] 중부선 기흥IC") {
+		t.Errorf("rendered %q, want road name fallback and no direction", text)
+	}
+
+	if text := renderSMS(r, "알수없음", "경부선", p); text != p.SmsText {
+		t.Errorf("unknown type rendered %q, want the seed text", text)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	m := &accidentModel{
+		roads:     []string{"경부선"},
+		roadHour:  map[string]*[24]float64{"경부선": {}},
+		roadTotal: map[string]float64{"경부선": 1},
+		points: map[string][]seedPoint{"경부선": {
+			{AccPointNM: "서울TG", NosunNM: "경부선", AccType: "고장", SmsText: "[고장] 경부선 부산방향", Latitude: 37.4, Longitude: 127.1},
+		}},
+	}
+	now := time.Date(2024, 6, 3, 14, 5, 9, 0, time.UTC)
+
+	accidents := m.generate(rand.New(rand.NewSource(1)), now, 5)
+	if len(accidents) != 5 {
+		t.Fatalf("generated %d accidents, want 5", len(accidents))
+	}
+	for _, a := range accidents {
+		if a.AccDate != "2024.06.03" || a.AccHour != "14:05:09" {
+			t.Errorf("date/hour = %s %s", a.AccDate, a.AccHour)
+		}
+		if a.RoadNM != "경부선" || a.AccType != "고장" || !strings.HasPrefix(a.AccInfo, "[고장]") {
+			t.Errorf("accident = %+v", a)
+		}
+		if math.Round(a.Latitude*1e6) != a.Latitude*1e6 {
+			t.Errorf("latitude %v not rounded to 6 decimals", a.Latitude)
+		}
+	}
+}
@@ -0,0 +1,669 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SeedRecord mirrors one row of simulator_seed_data for promote/import/export
+type SeedRecord struct {
+	SeedSet             string     `json:"seedSet"`
+	LaneYn1             string     `json:"laneYn1"`
+	LaneYn2             string     `json:"laneYn2"`
+	LaneYn3             string     `json:"laneYn3"`
+	LaneYn4             string     `json:"laneYn4"`
+	LaneYn5             string     `json:"laneYn5"`
+	LaneYn6             string     `json:"laneYn6"`
+	LateLength          string     `json:"lateLength"`
+	AccHour             string     `json:"accHour"`
+	AccDate             string     `json:"accDate"`
+	AccTypeCode         string     `json:"accTypeCode"`
+	AccType             string     `json:"accType"`
+	StartEndTypeCode    string     `json:"startEndTypeCode"`
+	SmsText             string     `json:"smsText"`
+	AccProcessCode      string     `json:"accProcessCode"`
+	AccPointNM          string     `json:"accPointNM"`
+	NosunNM             string     `json:"nosunNM"`
+	RoadNM              string     `json:"roadNM"`
+	AccProcessNM        string     `json:"accProcessNM"`
+	Latitude            *float64   `json:"latitude"`
+	Altitude            *float64   `json:"altitude"`
+	SeriesNM            *int       `json:"seriesNM"`
+	ShldrRoadYn         string     `json:"shldrRoadYn"`
+	OriginalCollectedAt *time.Time `json:"originalCollectedAt"`
+}
+
+// seedCSVHeader is the column order used for CSV import/export
+var seedCSVHeader = []string{
+	"seed_set", "lane_yn1", "lane_yn2", "lane_yn3", "lane_yn4", "lane_yn5", "lane_yn6",
+	"late_length", "acc_hour", "acc_date", "acc_type_code", "acc_type",
+	"start_end_type_code", "sms_text", "acc_process_code", "acc_point_nm",
+	"nosun_nm", "road_nm", "acc_process_nm", "latitude", "altitude",
+	"series_nm", "shldr_road_yn", "original_collected_at",
+}
+
+// seedColumns is the shared column list of traffic_accidents, traffic_accidents_cache
+// and simulator_seed_data
+const seedColumns = `lane_yn1, lane_yn2, lane_yn3, lane_yn4, lane_yn5, lane_yn6,
+	late_length, acc_hour, acc_date, acc_type_code, acc_type,
+	start_end_type_code, sms_text, acc_process_code, acc_point_nm,
+	nosun_nm, road_nm, acc_process_nm, latitude, altitude,
+	series_nm, shldr_road_yn`
+
+// dedupeKey identifies the same real-world accident message regardless of when it was seen
+func (r *SeedRecord) dedupeKey() string {
+	h := sha1.Sum([]byte(strings.Join([]string{r.AccType, r.RoadNM, r.AccPointNM, r.SmsText}, "|")))
+	return hex.EncodeToString(h[:])
+}
+
+// accTypeKey groups records for category balancing (empty types share one bucket)
+func (r *SeedRecord) accTypeKey() string {
+	if r.AccType == "" {
+		return "(unknown)"
+	}
+	return r.AccType
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSeedRecord scans seedColumns followed by a timestamp column
+func scanSeedRecord(row rowScanner) (SeedRecord, error) {
+	var rec SeedRecord
+	var laneYn [6]sql.NullString
+	var lateLength, accTypeCode, accType, startEndTypeCode, accProcessCode sql.NullString
+	var accPointNM, nosunNM, roadNM, accProcessNM, shldrRoadYn sql.NullString
+	var lat, lon sql.NullFloat64
+	var seriesNM sql.NullInt64
+	var collectedAt sql.NullTime
+
+	err := row.Scan(
+		&laneYn[0], &laneYn[1], &laneYn[2], &laneYn[3], &laneYn[4], &laneYn[5],
+		&lateLength, &rec.AccHour, &rec.AccDate, &accTypeCode, &accType,
+		&startEndTypeCode, &rec.SmsText, &accProcessCode, &accPointNM,
+		&nosunNM, &roadNM, &accProcessNM, &lat, &lon,
+		&seriesNM, &shldrRoadYn, &collectedAt,
+	)
+	if err != nil {
+		return rec, err
+	}
+
+	rec.LaneYn1, rec.LaneYn2, rec.LaneYn3 = laneYn[0].String, laneYn[1].String, laneYn[2].String
+	rec.LaneYn4, rec.LaneYn5, rec.LaneYn6 = laneYn[3].String, laneYn[4].String, laneYn[5].String
+	rec.LateLength = lateLength.String
+	rec.AccTypeCode = accTypeCode.String
+	rec.AccType = accType.String
+	rec.StartEndTypeCode = startEndTypeCode.String
+	rec.AccProcessCode = accProcessCode.String
+	rec.AccPointNM = accPointNM.String
+	rec.NosunNM = nosunNM.String
+	rec.RoadNM = roadNM.String
+	rec.AccProcessNM = accProcessNM.String
+	rec.ShldrRoadYn = shldrRoadYn.String
+
+	if lat.Valid {
+		rec.Latitude = &lat.Float64
+	}
+	if lon.Valid {
+		rec.Altitude = &lon.Float64
+	}
+	if seriesNM.Valid {
+		n := int(seriesNM.Int64)
+		rec.SeriesNM = &n
+	}
+	if collectedAt.Valid {
+		rec.OriginalCollectedAt = &collectedAt.Time
+	}
+
+	return rec, nil
+}
+
+// nullIfEmpty stores empty strings as NULL so imported rows look like collected ones
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// insertSeedRecords inserts records into seedSet, skipping rows whose dedupe key already exists.
+// With replace the existing rows of seedSet are deleted in the same transaction, so a failed
+// import leaves the previous set in place.
+func (s *Simulator) insertSeedRecords(ctx context.Context, seedSet string, records []SeedRecord, replace bool) (int, error) {
+	if len(records) == 0 && !replace {
+		return 0, nil
+	}
+
+	query := `INSERT IGNORE INTO simulator_seed_data (
+		seed_set, dedupe_key,
+		lane_yn1, lane_yn2, lane_yn3, lane_yn4, lane_yn5, lane_yn6,
+		late_length, acc_hour, acc_date, acc_type_code, acc_type,
+		start_end_type_code, sms_text, acc_process_code, acc_point_nm,
+		nosun_nm, road_nm, acc_process_nm, latitude, altitude,
+		series_nm, shldr_road_yn, original_collected_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if replace {
+		result, err := tx.ExecContext(ctx, `DELETE FROM simulator_seed_data WHERE seed_set = ?`, seedSet)
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete seed set: %w", err)
+		}
+		deleted, _ := result.RowsAffected()
+		componentLogger("seed").Info("removing existing records", "records", deleted, "set", seedSet)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	inserted := 0
+	for _, r := range records {
+		if r.SmsText == "" || r.AccDate == "" || r.AccHour == "" {
+			componentLogger("seed").Warn("skipping record without sms_text/acc_date/acc_hour", "point", r.AccPointNM)
+			continue
+		}
+
+		result, err := stmt.ExecContext(ctx,
+			seedSet, r.dedupeKey(),
+			nullIfEmpty(r.LaneYn1), nullIfEmpty(r.LaneYn2), nullIfEmpty(r.LaneYn3),
+			nullIfEmpty(r.LaneYn4), nullIfEmpty(r.LaneYn5), nullIfEmpty(r.LaneYn6),
+			nullIfEmpty(r.LateLength), r.AccHour, r.AccDate, nullIfEmpty(r.AccTypeCode), nullIfEmpty(r.AccType),
+			nullIfEmpty(r.StartEndTypeCode), r.SmsText, nullIfEmpty(r.AccProcessCode), nullIfEmpty(r.AccPointNM),
+			nullIfEmpty(r.NosunNM), nullIfEmpty(r.RoadNM), nullIfEmpty(r.AccProcessNM), r.Latitude, r.Altitude,
+			r.SeriesNM, nullIfEmpty(r.ShldrRoadYn), r.OriginalCollectedAt,
+		)
+		if err != nil {
+			return inserted, fmt.Errorf("failed to insert seed record: %w", err)
+		}
+
+		if n, _ := result.RowsAffected(); n > 0 {
+			inserted++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// loadSeedState returns the dedupe keys and per-type counts already stored in seedSet
+func (s *Simulator) loadSeedState(ctx context.Context, seedSet string) (map[string]bool, map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT dedupe_key, COALESCE(acc_type, '') FROM simulator_seed_data WHERE seed_set = ?`, seedSet)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query existing seed data: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make(map[string]bool)
+	counts := make(map[string]int)
+
+	for rows.Next() {
+		var key sql.NullString
+		var accType string
+		if err := rows.Scan(&key, &accType); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan seed row: %w", err)
+		}
+		if key.Valid {
+			keys[key.String] = true
+		}
+		rec := SeedRecord{AccType: accType}
+		counts[rec.accTypeKey()]++
+	}
+
+	return keys, counts, rows.Err()
+}
+
+// loadPromotionCandidates reads recent real accidents from both the processed table and
+// the OpenAPI cache, newest first
+func (s *Simulator) loadPromotionCandidates(ctx context.Context, since time.Time) ([]SeedRecord, error) {
+	query := fmt.Sprintf(`
+		SELECT %[1]s, created_at AS seen_at FROM traffic_accidents WHERE created_at >= ?
+		UNION ALL
+		SELECT %[1]s, collected_at AS seen_at FROM traffic_accidents_cache WHERE collected_at >= ?
+		ORDER BY seen_at DESC`, seedColumns)
+
+	rows, err := s.db.QueryContext(ctx, query, since, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query real accidents: %w", err)
+	}
+	defer rows.Close()
+
+	var records []SeedRecord
+	for rows.Next() {
+		rec, err := scanSeedRecord(rows)
+		if err != nil {
+			componentLogger("seed").Error("failed to scan accident row", "error", err)
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	return records, rows.Err()
+}
+
+// balanceByType picks up to limit records, always taking the next record from the
+// accident type that currently has the fewest seed rows so rare types are not drowned out
+func balanceByType(candidates []SeedRecord, existing map[string]int, limit int) []SeedRecord {
+	buckets := make(map[string][]SeedRecord)
+	var types []string
+	for _, c := range candidates {
+		key := c.accTypeKey()
+		if _, ok := buckets[key]; !ok {
+			types = append(types, key)
+		}
+		buckets[key] = append(buckets[key], c)
+	}
+	sort.Strings(types)
+
+	counts := make(map[string]int, len(types))
+	for _, t := range types {
+		counts[t] = existing[t]
+	}
+
+	var selected []SeedRecord
+	for len(selected) < limit {
+		best := ""
+		for _, t := range types {
+			if len(buckets[t]) == 0 {
+				continue
+			}
+			if best == "" || counts[t] < counts[best] {
+				best = t
+			}
+		}
+		if best == "" {
+			break
+		}
+
+		selected = append(selected, buckets[best][0])
+		buckets[best] = buckets[best][1:]
+		counts[best]++
+	}
+
+	return selected
+}
+
+// promoteSeedData copies real accidents into seedSet with dedupe and category balancing
+func (s *Simulator) promoteSeedData(ctx context.Context, seedSet string, since time.Time, limit int) (int, error) {
+	keys, counts, err := s.loadSeedState(ctx, seedSet)
+	if err != nil {
+		return 0, err
+	}
+
+	candidates, err := s.loadPromotionCandidates(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+
+	// Drop records already in the set and duplicates between the two source tables
+	var fresh []SeedRecord
+	for _, c := range candidates {
+		key := c.dedupeKey()
+		if keys[key] {
+			continue
+		}
+		keys[key] = true
+		fresh = append(fresh, c)
+	}
+
+	componentLogger("seed").Info("promotion candidates", "candidates", len(candidates),
+		"since", since.Format("2006-01-02 15:04"), "new", len(fresh), "set", seedSet)
+
+	selected := balanceByType(fresh, counts, limit)
+	return s.insertSeedRecords(ctx, seedSet, selected, false)
+}
+
+// loadSeedSet returns every record of seedSet in insertion order
+func (s *Simulator) loadSeedSet(ctx context.Context, seedSet string) ([]SeedRecord, error) {
+	query := fmt.Sprintf(`SELECT %s, original_collected_at FROM simulator_seed_data
+		WHERE seed_set = ? ORDER BY id`, seedColumns)
+
+	rows, err := s.db.QueryContext(ctx, query, seedSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query seed set: %w", err)
+	}
+	defer rows.Close()
+
+	var records []SeedRecord
+	for rows.Next() {
+		rec, err := scanSeedRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan seed row: %w", err)
+		}
+		rec.SeedSet = seedSet
+		records = append(records, rec)
+	}
+
+	return records, rows.Err()
+}
+
+func writeSeedJSON(w io.Writer, records []SeedRecord) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if records == nil {
+		records = []SeedRecord{}
+	}
+	return enc.Encode(records)
+}
+
+func readSeedJSON(r io.Reader) ([]SeedRecord, error) {
+	var records []SeedRecord
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON seed file: %w", err)
+	}
+	return records, nil
+}
+
+func formatOptionalFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', 6, 64)
+}
+
+func writeSeedCSV(w io.Writer, records []SeedRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(seedCSVHeader); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		seriesNM := ""
+		if r.SeriesNM != nil {
+			seriesNM = strconv.Itoa(*r.SeriesNM)
+		}
+		collectedAt := ""
+		if r.OriginalCollectedAt != nil {
+			collectedAt = r.OriginalCollectedAt.Format(time.RFC3339)
+		}
+
+		row := []string{
+			r.SeedSet, r.LaneYn1, r.LaneYn2, r.LaneYn3, r.LaneYn4, r.LaneYn5, r.LaneYn6,
+			r.LateLength, r.AccHour, r.AccDate, r.AccTypeCode, r.AccType,
+			r.StartEndTypeCode, r.SmsText, r.AccProcessCode, r.AccPointNM,
+			r.NosunNM, r.RoadNM, r.AccProcessNM, formatOptionalFloat(r.Latitude), formatOptionalFloat(r.Altitude),
+			seriesNM, r.ShldrRoadYn, collectedAt,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func readSeedCSV(r io.Reader) ([]SeedRecord, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"acc_date", "acc_hour", "sms_text"} {
+		if _, ok := index[required]; !ok {
+			return nil, fmt.Errorf("CSV header missing required column %q", required)
+		}
+	}
+
+	var records []SeedRecord
+	line := 1
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV line %d: %w", line, err)
+		}
+
+		get := func(name string) string {
+			if i, ok := index[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+
+		rec := SeedRecord{
+			SeedSet:          get("seed_set"),
+			LaneYn1:          get("lane_yn1"),
+			LaneYn2:          get("lane_yn2"),
+			LaneYn3:          get("lane_yn3"),
+			LaneYn4:          get("lane_yn4"),
+			LaneYn5:          get("lane_yn5"),
+			LaneYn6:          get("lane_yn6"),
+			LateLength:       get("late_length"),
+			AccHour:          get("acc_hour"),
+			AccDate:          get("acc_date"),
+			AccTypeCode:      get("acc_type_code"),
+			AccType:          get("acc_type"),
+			StartEndTypeCode: get("start_end_type_code"),
+			SmsText:          get("sms_text"),
+			AccProcessCode:   get("acc_process_code"),
+			AccPointNM:       get("acc_point_nm"),
+			NosunNM:          get("nosun_nm"),
+			RoadNM:           get("road_nm"),
+			AccProcessNM:     get("acc_process_nm"),
+			ShldrRoadYn:      get("shldr_road_yn"),
+		}
+
+		if v := get("latitude"); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid latitude %q", line, v)
+			}
+			rec.Latitude = &f
+		}
+		if v := get("altitude"); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid altitude %q", line, v)
+			}
+			rec.Altitude = &f
+		}
+		if v := get("series_nm"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid series_nm %q", line, v)
+			}
+			rec.SeriesNM = &n
+		}
+		if v := get("original_collected_at"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid original_collected_at %q", line, v)
+			}
+			rec.OriginalCollectedAt = &t
+		}
+
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// seedFormat resolves the file format from the -format flag or the file extension
+func seedFormat(format, path string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "csv"
+		default:
+			format = "json"
+		}
+	}
+
+	format = strings.ToLower(format)
+	if format != "json" && format != "csv" {
+		return "", fmt.Errorf("unsupported format %q (use json or csv)", format)
+	}
+	return format, nil
+}
+
+func seedUsage() {
+	fmt.Fprintln(os.Stderr, `Usage: traffic-simulator seed <command> [flags]
+
+Commands:
+  promote   Copy real accidents from traffic_accidents/traffic_accidents_cache into a seed set
+  export    Write a seed set to a JSON or CSV file
+  import    Load a JSON or CSV file into a seed set
+
+Run 'traffic-simulator seed <command> -h' for command flags.`)
+}
+
+// runSeedCommand implements the "seed" subcommand family
+func runSeedCommand(config Config, args []string) error {
+	if len(args) == 0 {
+		seedUsage()
+		return fmt.Errorf("missing seed command")
+	}
+
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet("seed "+cmd, flag.ExitOnError)
+	seedSet := fs.String("set", config.SeedSet, "seed set name")
+
+	var (
+		since   *time.Duration
+		limit   *int
+		path    *string
+		format  *string
+		replace *bool
+	)
+
+	switch cmd {
+	case "promote":
+		since = fs.Duration("since", 30*24*time.Hour, "only promote accidents seen within this window")
+		limit = fs.Int("limit", 500, "maximum number of records to add")
+	case "export":
+		path = fs.String("out", "-", "output file ('-' for stdout)")
+		format = fs.String("format", "", "json or csv (default: from file extension, else json)")
+	case "import":
+		path = fs.String("in", "", "input file ('-' for stdin)")
+		format = fs.String("format", "", "json or csv (default: from file extension, else json)")
+		replace = fs.Bool("replace", false, "delete the existing seed set before importing")
+	default:
+		seedUsage()
+		return fmt.Errorf("unknown seed command %q", cmd)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	simulator, err := NewSimulator(config)
+	if err != nil {
+		return err
+	}
+	defer simulator.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	switch cmd {
+	case "promote":
+		inserted, err := simulator.promoteSeedData(ctx, *seedSet, time.Now().Add(-*since), *limit)
+		if err != nil {
+			return err
+		}
+		componentLogger("seed").Info("promoted records", "records", inserted, "set", *seedSet)
+
+	case "export":
+		fmtName, err := seedFormat(*format, *path)
+		if err != nil {
+			return err
+		}
+
+		records, err := simulator.loadSeedSet(ctx, *seedSet)
+		if err != nil {
+			return err
+		}
+
+		out := io.Writer(os.Stdout)
+		if *path != "-" && *path != "" {
+			f, err := os.Create(*path)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if fmtName == "csv" {
+			err = writeSeedCSV(out, records)
+		} else {
+			err = writeSeedJSON(out, records)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write seed set: %w", err)
+		}
+		componentLogger("seed").Info("exported records", "records", len(records), "set", *seedSet, "format", fmtName)
+
+	case "import":
+		if *path == "" {
+			return fmt.Errorf("-in is required")
+		}
+		fmtName, err := seedFormat(*format, *path)
+		if err != nil {
+			return err
+		}
+
+		in := io.Reader(os.Stdin)
+		if *path != "-" {
+			f, err := os.Open(*path)
+			if err != nil {
+				return fmt.Errorf("failed to open input file: %w", err)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		var records []SeedRecord
+		if fmtName == "csv" {
+			records, err = readSeedCSV(in)
+		} else {
+			records, err = readSeedJSON(in)
+		}
+		if err != nil {
+			return err
+		}
+
+		inserted, err := simulator.insertSeedRecords(ctx, *seedSet, records, *replace)
+		if err != nil {
+			return err
+		}
+		componentLogger("seed").Info("imported records (duplicates skipped)", "imported", inserted,
+			"records", len(records), "set", *seedSet)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedupeKey(t *testing.T) {
+	base := SeedRecord{AccType: "사고", RoadNM: "경부선", AccPointNM: "서울TG", SmsText: "[사고] 경부선 서울방향"}
+
+	tests := []struct {
+		name   string
+		modify func(r *SeedRecord)
+		same   bool
+	}{
+		{name: "identical", modify: func(r *SeedRecord) {}, same: true},
+		{name: "different collection time", modify: func(r *SeedRecord) {
+			now := time.Now()
+			r.OriginalCollectedAt = &now
+		}, same: true},
+		{name: "different date and hour", modify: func(r *SeedRecord) { r.AccDate, r.AccHour = "20240101", "0930" }, same: true},
+		{name: "different text", modify: func(r *SeedRecord) { r.SmsText += " 2차로" }, same: false},
+		{name: "different point", modify: func(r *SeedRecord) { r.AccPointNM = "기흥IC" }, same: false},
+		{name: "fields do not run together", modify: func(r *SeedRecord) {
+			r.RoadNM, r.AccPointNM = "경부선서울", "TG"
+		}, same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := base
+			tt.modify(&other)
+			if got := base.dedupeKey() == other.dedupeKey(); got != tt.same {
+				t.Errorf("same key = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestBalanceByType(t *testing.T) {
+	records := func(types ...string) []SeedRecord {
+		var out []SeedRecord
+		for i, typ := range types {
+			out = append(out, SeedRecord{AccType: typ, SmsText: strings.Repeat("x", i+1)})
+		}
+		return out
+	}
+	typesOf := func(records []SeedRecord) []string {
+		out := []string{}
+		for _, r := range records {
+			out = append(out, r.accTypeKey())
+		}
+		return out
+	}
+
+	tests := []struct {
+		name       string
+		candidates []SeedRecord
+		existing   map[string]int
+		limit      int
+		want       []string
+	}{
+		{
+			name:       "alternates between types",
+			candidates: records("사고", "사고", "사고", "공사", "공사"),
+			limit:      4,
+			want:       []string{"공사", "사고", "공사", "사고"},
+		},
+		{
+			name:       "existing rows favour the rare type",
+			candidates: records("사고", "사고", "공사"),
+			existing:   map[string]int{"사고": 10},
+			limit:      2,
+			want:       []string{"공사", "사고"},
+		},
+		{
+			name:       "limit above candidates takes everything",
+			candidates: records("사고", "고장"),
+			limit:      10,
+			want:       []string{"고장", "사고"},
+		},
+		{
+			name:       "empty type has its own bucket",
+			candidates: records("", "사고"),
+			limit:      2,
+			want:       []string{"(unknown)", "사고"},
+		},
+		{
+			name:       "zero limit",
+			candidates: records("사고"),
+			limit:      0,
+			want:       []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := typesOf(balanceByType(tt.candidates, tt.existing, tt.limit))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("types = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeedFormat(t *testing.T) {
+	tests := []struct {
+		format, path string
+		want         string
+		wantErr      bool
+	}{
+		{path: "seed.csv", want: "csv"},
+		{path: "SEED.CSV", want: "csv"},
+		{path: "seed.json", want: "json"},
+		{path: "seed", want: "json"},
+		{format: "CSV", path: "seed.json", want: "csv"},
+		{format: "xml", path: "seed.xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := seedFormat(tt.format, tt.path)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("seedFormat(%q, %q) = %q, %v; want %q, error %v", tt.format, tt.path, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSeedCSVRoundTrip(t *testing.T) {
+	lat, lon := 37.123456, 127.654321
+	series := 3
+	collected := time.Date(2024, 5, 1, 8, 15, 0, 0, time.UTC)
+	records := []SeedRecord{
+		{
+			SeedSet: "default", LaneYn1: "Y", AccHour: "0815", AccDate: "20240501", AccType: "사고",
+			SmsText: "[사고] 경부선 부산방향, \"2차로\" 통제", AccPointNM: "서울TG", RoadNM: "경부선",
+			Latitude: &lat, Altitude: &lon, SeriesNM: &series, OriginalCollectedAt: &collected,
+		},
+		{SeedSet: "default", AccHour: "1200", AccDate: "20240502", SmsText: "공사\n야간"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeSeedCSV(&buf, records); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readSeedCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("round trip = %+v, want %+v", got, records)
+	}
+}
+
+func TestReadSeedCSVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "missing required column", input: "acc_date,acc_hour\n20240101,0900\n"},
+		{name: "invalid latitude", input: "acc_date,acc_hour,sms_text,latitude\n20240101,0900,x,north\n"},
+		{name: "invalid series", input: "acc_date,acc_hour,sms_text,series_nm\n20240101,0900,x,first\n"},
+		{name: "invalid timestamp", input: "acc_date,acc_hour,sms_text,original_collected_at\n20240101,0900,x,yesterday\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readSeedCSV(strings.NewReader(tt.input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestSeedJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSeedJSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("empty export = %q, want []", buf.String())
+	}
+
+	lat := 35.5
+	records := []SeedRecord{{SeedSet: "s", AccDate: "20240101", AccHour: "0900", SmsText: "x", Latitude: &lat}}
+	buf.Reset()
+	if err := writeSeedJSON(&buf, records); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readSeedJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("round trip = %+v, want %+v", got, records)
+	}
+}